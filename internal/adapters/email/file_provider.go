@@ -0,0 +1,257 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"github.com/samber/do"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	mailboxDefaultPath   = "/var/lib/fluxend/mail"
+	mailboxFileExtension = ".eml"
+	mailboxTempPrefix    = ".capture-"
+)
+
+// Captured messages are named after the time they were saved, so names sort by age
+var mailboxIDPattern = regexp.MustCompile(`^[0-9]+-[0-9a-f]{8}$`)
+
+// ErrCapturedMessageNotFound is returned for IDs that don't name a captured message
+var ErrCapturedMessageNotFound = errors.New("captured message not found")
+
+// FileServiceImpl saves messages as .eml files in the mailbox instead of sending them, meant for
+// development and CI. Bcc recipients are kept as a header so the captured copy shows every recipient
+type FileServiceImpl struct {
+	mailbox *Mailbox
+}
+
+func NewFileProvider(injector *do.Injector) (Provider, error) {
+	mailbox, err := NewMailbox()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileServiceImpl{mailbox: mailbox}, nil
+}
+
+func (f *FileServiceImpl) Send(message Message) (string, error) {
+	from := message.From
+	if from == "" {
+		from = constants.EmailLocalDriverFrom
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", err
+	}
+
+	if message.MessageID == "" {
+		if message.MessageID, err = newMessageID(sender.Address); err != nil {
+			return "", err
+		}
+	}
+
+	content, err := message.Render(from)
+	if err != nil {
+		return "", err
+	}
+
+	var captured bytes.Buffer
+	writeAddressHeader(&captured, "Bcc", message.Bcc)
+	captured.Write(content)
+
+	if _, err = f.mailbox.Save(captured.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to capture email: %v", err)
+	}
+
+	return message.MessageID, nil
+}
+
+// Mailbox is the directory the FILE driver captures messages in, from MAIL_FILE_PATH
+type Mailbox struct {
+	path string
+}
+
+type MailboxEntry struct {
+	ID        string
+	Size      int64
+	CreatedAt time.Time
+}
+
+func NewMailbox() (*Mailbox, error) {
+	path := os.Getenv("MAIL_FILE_PATH")
+	if path == "" {
+		path = mailboxDefaultPath
+	}
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve mail path %q: %w", path, err)
+	}
+
+	if err = os.MkdirAll(absolutePath, 0o750); err != nil {
+		return nil, fmt.Errorf("unable to create mail path %q: %w", absolutePath, err)
+	}
+
+	return &Mailbox{path: absolutePath}, nil
+}
+
+// Save writes a message through a temporary file, so readers never see it half written
+func (m *Mailbox) Save(content []byte) (string, error) {
+	random := make([]byte, 4)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	id := fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(random))
+
+	temp, err := os.CreateTemp(m.path, mailboxTempPrefix)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err = temp.Write(content); err != nil {
+		temp.Close()
+		return "", err
+	}
+
+	if err = temp.Close(); err != nil {
+		return "", err
+	}
+
+	return id, os.Rename(temp.Name(), filepath.Join(m.path, id+mailboxFileExtension))
+}
+
+// List returns the captured messages, newest first
+func (m *Mailbox) List() ([]MailboxEntry, error) {
+	dirEntries, err := os.ReadDir(m.path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]MailboxEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		id, found := strings.CutSuffix(dirEntry.Name(), mailboxFileExtension)
+		if !found || !mailboxIDPattern.MatchString(id) {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			// Deleted since the directory was read
+			continue
+		}
+
+		entries = append(entries, MailboxEntry{ID: id, Size: info.Size(), CreatedAt: info.ModTime()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+
+	return entries, nil
+}
+
+func (m *Mailbox) Get(id string) (MailboxEntry, error) {
+	path, err := m.messagePath(id)
+	if err != nil {
+		return MailboxEntry{}, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return MailboxEntry{}, ErrCapturedMessageNotFound
+	}
+
+	if err != nil {
+		return MailboxEntry{}, err
+	}
+
+	return MailboxEntry{ID: id, Size: info.Size(), CreatedAt: info.ModTime()}, nil
+}
+
+func (m *Mailbox) Read(id string) ([]byte, error) {
+	path, err := m.messagePath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCapturedMessageNotFound
+	}
+
+	return content, err
+}
+
+// Open is Read for callers only after the headers of a message
+func (m *Mailbox) Open(id string) (io.ReadCloser, error) {
+	path, err := m.messagePath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCapturedMessageNotFound
+	}
+
+	return file, err
+}
+
+func (m *Mailbox) Delete(id string) error {
+	path, err := m.messagePath(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrCapturedMessageNotFound
+	}
+
+	return err
+}
+
+// Clear deletes every captured message and returns how many there were
+func (m *Mailbox) Clear() (int, error) {
+	entries, err := m.List()
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, entry := range entries {
+		if err = m.Delete(entry.ID); err != nil && !errors.Is(err, ErrCapturedMessageNotFound) {
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (m *Mailbox) messagePath(id string) (string, error) {
+	if !mailboxIDPattern.MatchString(id) {
+		return "", ErrCapturedMessageNotFound
+	}
+
+	return filepath.Join(m.path, id+mailboxFileExtension), nil
+}
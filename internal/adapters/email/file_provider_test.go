@@ -0,0 +1,144 @@
+package email
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMailbox(t *testing.T) (*FileServiceImpl, *Mailbox) {
+	t.Setenv("MAIL_FILE_PATH", t.TempDir())
+
+	provider, err := NewFileProvider(nil)
+	require.NoError(t, err)
+
+	mailbox, err := NewMailbox()
+	require.NoError(t, err)
+
+	return provider.(*FileServiceImpl), mailbox
+}
+
+func TestFileProvider_Send_Suite(t *testing.T) {
+	t.Run("Send: captured message reads back as sent", func(t *testing.T) {
+		provider, mailbox := newTestMailbox(t)
+
+		messageID, err := provider.Send(Message{
+			To:      []string{"Jane <jane@example.com>"},
+			Cc:      []string{"john@example.com"},
+			Bcc:     []string{"audit@example.com"},
+			ReplyTo: []string{"support@fluxend.app"},
+			Subject: "Your report — ready",
+			Text:    "See the attachment",
+			HTML:    "<p>See the <b>attachment</b></p>",
+			Headers: map[string]string{"X-Campaign": "reports"},
+			Attachments: []Attachment{
+				{Filename: "report.pdf", Content: []byte("%PDF-1.7 binary \x00\xff")},
+			},
+		})
+		require.NoError(t, err)
+
+		entries, err := mailbox.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		raw, err := mailbox.Read(entries[0].ID)
+		require.NoError(t, err)
+
+		parsed, err := ParseMessage(raw)
+		require.NoError(t, err)
+
+		assert.Equal(t, messageID, parsed.MessageID)
+		assert.Equal(t, `"Fluxend" <noreply@localhost>`, parsed.From)
+		assert.Equal(t, []string{`"Jane" <jane@example.com>`}, parsed.To)
+		assert.Equal(t, []string{"<john@example.com>"}, parsed.Cc)
+		assert.Equal(t, []string{"<audit@example.com>"}, parsed.Bcc)
+		assert.Equal(t, []string{"<support@fluxend.app>"}, parsed.ReplyTo)
+		assert.Equal(t, "Your report — ready", parsed.Subject)
+		assert.Equal(t, "See the attachment", parsed.Text)
+		assert.Equal(t, "<p>See the <b>attachment</b></p>", parsed.HTML)
+		assert.Equal(t, map[string]string{"X-Campaign": "reports"}, parsed.Headers)
+
+		require.Len(t, parsed.Attachments, 1)
+		assert.Equal(t, "report.pdf", parsed.Attachments[0].Filename)
+		assert.Equal(t, "application/pdf", parsed.Attachments[0].ContentType)
+		assert.Equal(t, []byte("%PDF-1.7 binary \x00\xff"), parsed.Attachments[0].Content)
+	})
+
+	t.Run("Send: invalid message isn't captured", func(t *testing.T) {
+		provider, mailbox := newTestMailbox(t)
+
+		_, err := provider.Send(NewTextMessage("not an address", "Hi", "Hello"))
+		assert.Error(t, err)
+
+		entries, err := mailbox.List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestMailbox_Suite(t *testing.T) {
+	t.Run("Mailbox: newest first, other files ignored", func(t *testing.T) {
+		_, mailbox := newTestMailbox(t)
+
+		first, err := mailbox.Save([]byte("Subject: first\r\n\r\nHello"))
+		require.NoError(t, err)
+
+		second, err := mailbox.Save([]byte("Subject: second\r\n\r\nHello"))
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(mailbox.path, "notes.txt"), []byte("hi"), 0o600))
+
+		entries, err := mailbox.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, second, entries[0].ID)
+		assert.Equal(t, first, entries[1].ID)
+
+		reader, err := mailbox.Open(first)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		header, err := ParseHeader(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "first", header.Subject)
+		assert.Empty(t, header.Text)
+	})
+
+	t.Run("Mailbox: delete and clear", func(t *testing.T) {
+		_, mailbox := newTestMailbox(t)
+
+		for range 3 {
+			_, err := mailbox.Save([]byte("Subject: hi\r\n\r\nHello"))
+			require.NoError(t, err)
+		}
+
+		entries, err := mailbox.List()
+		require.NoError(t, err)
+
+		require.NoError(t, mailbox.Delete(entries[0].ID))
+		assert.ErrorIs(t, mailbox.Delete(entries[0].ID), ErrCapturedMessageNotFound)
+
+		deleted, err := mailbox.Clear()
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		entries, err = mailbox.List()
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Mailbox: IDs can't leave the directory", func(t *testing.T) {
+		_, mailbox := newTestMailbox(t)
+
+		for _, id := range []string{"../secrets", "1-abcdef01/../../x", "", "notes"} {
+			_, err := mailbox.Read(id)
+			assert.ErrorIs(t, err, ErrCapturedMessageNotFound, id)
+
+			_, err = mailbox.Get(id)
+			assert.ErrorIs(t, err, ErrCapturedMessageNotFound, id)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package email
+
+import (
+	"fluxend/internal/config/constants"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"net/mail"
+)
+
+// LogServiceImpl writes messages to the log instead of sending them, meant for development and CI
+type LogServiceImpl struct{}
+
+func NewLogProvider(injector *do.Injector) (Provider, error) {
+	return &LogServiceImpl{}, nil
+}
+
+func (l *LogServiceImpl) Send(message Message) (string, error) {
+	from := message.From
+	if from == "" {
+		from = constants.EmailLocalDriverFrom
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	messageID := message.MessageID
+	if messageID == "" {
+		sender, _ := mail.ParseAddress(from)
+
+		var err error
+		if messageID, err = newMessageID(sender.Address); err != nil {
+			return "", err
+		}
+	}
+
+	attachments := make([]string, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		attachments[i] = attachment.Filename
+	}
+
+	log.Info().
+		Str("action", constants.ActionEmailLog).
+		Str("message_id", messageID).
+		Str("from", from).
+		Strs("to", message.To).
+		Strs("cc", message.Cc).
+		Strs("bcc", message.Bcc).
+		Strs("reply_to", message.ReplyTo).
+		Str("subject", message.Subject).
+		Str("text", message.Text).
+		Str("html", message.HTML).
+		Strs("attachments", attachments).
+		Msg("email not sent, logged by the LOG mail driver")
+
+	return messageID, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fluxend/internal/domain/setting"
 	"fmt"
 	"github.com/samber/do"
+	"strings"
 	"time"
 
 	"github.com/mailgun/mailgun-go/v4"
@@ -51,31 +52,58 @@ func NewMailgunProvider(injector *do.Injector) (Provider, error) {
 	}, nil
 }
 
-func (m *MailgunServiceImpl) Send(to, subject, body string) error {
-	from := m.settingService.GetValue("mailgunEmailSource")
+func (m *MailgunServiceImpl) Send(message Message) (string, error) {
+	from := message.From
 	if from == "" {
-		return fmt.Errorf("mailgunEmailSource is required")
+		from = m.settingService.GetValue("mailgunEmailSource")
 	}
 
-	message := mailgun.NewMessage(
+	if from == "" {
+		return "", fmt.Errorf("mailgunEmailSource is required")
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	mailgunMessage := mailgun.NewMessage(
 		from,
-		subject,
-		body,
-		to,
+		message.Subject,
+		message.Text,
+		message.To...,
 	)
 
+	for _, address := range message.Cc {
+		mailgunMessage.AddCC(address)
+	}
+
+	for _, address := range message.Bcc {
+		mailgunMessage.AddBCC(address)
+	}
+
+	if len(message.ReplyTo) > 0 {
+		mailgunMessage.SetReplyTo(strings.Join(message.ReplyTo, ", "))
+	}
+
+	if message.HTML != "" {
+		mailgunMessage.SetHtml(message.HTML)
+	}
+
+	for name, value := range message.Headers {
+		mailgunMessage.AddHeader(name, value)
+	}
+
+	for _, attachment := range message.Attachments {
+		mailgunMessage.AddBufferAttachment(attachment.Filename, attachment.Content)
+	}
+
 	backgroundContext, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	resp, _, err := m.client.Send(backgroundContext, message)
+	_, id, err := m.client.Send(backgroundContext, mailgunMessage)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
-	}
-
-	if resp != "" {
-		// Mailgun returns an empty string on success
-		return fmt.Errorf("unexpected response: %s", resp)
+		return "", fmt.Errorf("failed to send email: %v", err)
 	}
 
-	return nil
+	return id, nil
 }
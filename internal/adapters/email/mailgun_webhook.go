@@ -0,0 +1,87 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseMailgunFeedback verifies a webhook request with the HTTP webhook signing key and reads its permanent
+// failure or complaint
+func ParseMailgunFeedback(signingKey string, payload []byte) ([]Feedback, error) {
+	var webhook struct {
+		Signature struct {
+			Timestamp string `json:"timestamp"`
+			Token     string `json:"token"`
+			Signature string `json:"signature"`
+		} `json:"signature"`
+		EventData struct {
+			Event     string `json:"event"`
+			Severity  string `json:"severity"`
+			Recipient string `json:"recipient"`
+			Reason    string `json:"reason"`
+			Message   struct {
+				Headers struct {
+					MessageId string `json:"message-id"`
+				} `json:"headers"`
+			} `json:"message"`
+			DeliveryStatus struct {
+				Message     string `json:"message"`
+				Description string `json:"description"`
+			} `json:"delivery-status"`
+		} `json:"event-data"`
+	}
+
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("invalid Mailgun webhook: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(webhook.Signature.Timestamp + webhook.Signature.Token))
+
+	signature, err := hex.DecodeString(webhook.Signature.Signature)
+	if err != nil || !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	if err = checkWebhookTimestamp(webhook.Signature.Timestamp, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event := webhook.EventData
+
+	// Sending returns the ID in angle brackets, the events leave them out
+	providerMessageID := ""
+	if event.Message.Headers.MessageId != "" {
+		providerMessageID = "<" + strings.Trim(event.Message.Headers.MessageId, "<>") + ">"
+	}
+
+	switch {
+	// Temporary failures are retried by Mailgun itself
+	case event.Event == "failed" && event.Severity == "permanent":
+		details := event.DeliveryStatus.Description
+		if details == "" {
+			details = event.DeliveryStatus.Message
+		}
+
+		return []Feedback{{
+			Type:              constants.EmailSuppressionReasonBounce,
+			Address:           event.Recipient,
+			ProviderMessageID: providerMessageID,
+			Details:           details,
+		}}, nil
+	case event.Event == "complained":
+		return []Feedback{{
+			Type:              constants.EmailSuppressionReasonComplaint,
+			Address:           event.Recipient,
+			ProviderMessageID: providerMessageID,
+		}}, nil
+	}
+
+	return nil, nil
+}
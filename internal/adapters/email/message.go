@@ -0,0 +1,360 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with any number of recipients, addresses may carry a display name like "Jane <jane@example.com>".
+// From is optional and overrides the source address configured for the driver
+type Message struct {
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     []string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string
+	Attachments []Attachment
+	MessageID   string // Message-ID header of raw drivers, generated when empty
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string // detected from the file name when empty
+	Content     []byte
+}
+
+// Headers set from the fields of a message, they can't be overridden through Headers
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// NewTextMessage is a plain text message to a single recipient
+func NewTextMessage(to, subject, text string) Message {
+	return Message{To: []string{to}, Subject: subject, Text: text}
+}
+
+// Validate checks the message can be sent by any driver, with from being the address it's sent as
+func (m Message) Validate(from string) error {
+	if _, err := mail.ParseAddress(from); err != nil {
+		return fmt.Errorf("invalid sender %q: %v", from, err)
+	}
+
+	return m.ValidateContent()
+}
+
+// ValidateContent checks everything but the sender, which may only be known once a driver is picked
+func (m Message) ValidateContent() error {
+	if len(m.To)+len(m.Cc)+len(m.Bcc) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+
+	for _, addresses := range [][]string{m.To, m.Cc, m.Bcc, m.ReplyTo} {
+		for _, address := range addresses {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Errorf("invalid address %q: %v", address, err)
+			}
+		}
+	}
+
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.New("subject must not contain line breaks")
+	}
+
+	if m.Text == "" && m.HTML == "" {
+		return errors.New("either a text or an HTML body is required")
+	}
+
+	for name, value := range m.Headers {
+		if !isHeaderName(name) || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header %q", name)
+		}
+
+		if reservedHeaders[textproto.CanonicalMIMEHeaderKey(name)] {
+			return fmt.Errorf("header %q is set from the message", name)
+		}
+	}
+
+	size := 0
+	for _, attachment := range m.Attachments {
+		if attachment.Filename == "" || strings.ContainsAny(attachment.Filename, "\r\n") {
+			return errors.New("attachments need a file name without line breaks")
+		}
+
+		size += len(attachment.Content)
+	}
+
+	if size > constants.EmailMaxAttachmentsSize {
+		return fmt.Errorf("attachments must not exceed %d bytes in total", constants.EmailMaxAttachmentsSize)
+	}
+
+	return nil
+}
+
+// Recipients lists the bare addresses of every To, Cc and Bcc recipient, as needed for the envelope
+func (m Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	for _, addresses := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, address := range addresses {
+			parsed, err := mail.ParseAddress(address)
+			if err != nil {
+				continue
+			}
+
+			recipients = append(recipients, parsed.Address)
+		}
+	}
+
+	return recipients
+}
+
+// Render builds the RFC 5322 message sent by raw drivers. Bcc recipients are left out of the headers,
+// they are only part of the envelope
+func (m Message) Render(from string) ([]byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID := m.MessageID
+	if messageID == "" {
+		messageID, err = newMessageID(sender.Address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var message bytes.Buffer
+	writeHeader(&message, "From", sender.String())
+	writeAddressHeader(&message, "To", m.To)
+	writeAddressHeader(&message, "Cc", m.Cc)
+	writeAddressHeader(&message, "Reply-To", m.ReplyTo)
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&message, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&message, "Message-ID", messageID)
+	for name, value := range m.Headers {
+		writeHeader(&message, name, mime.QEncoding.Encode("utf-8", value))
+	}
+	writeHeader(&message, "MIME-Version", "1.0")
+
+	if len(m.Attachments) == 0 {
+		if err = m.writeBody(&message); err != nil {
+			return nil, err
+		}
+
+		return message.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&message)
+	writeHeader(&message, "Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": writer.Boundary()}))
+	message.WriteString("\r\n")
+
+	var body bytes.Buffer
+	if err = m.writeBody(&body); err != nil {
+		return nil, err
+	}
+
+	headers, content, _ := bytes.Cut(body.Bytes(), []byte("\r\n\r\n"))
+	part, err := writer.CreatePart(parsePartHeaders(headers))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = part.Write(content); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		if err = writeAttachment(writer, attachment); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return message.Bytes(), nil
+}
+
+// writeBody writes the Content-Type of the body and the body itself, either a single part or
+// text and HTML alternatives
+func (m Message) writeBody(buffer *bytes.Buffer) error {
+	if m.Text == "" || m.HTML == "" {
+		contentType, content := "text/plain", m.Text
+		if m.HTML != "" {
+			contentType, content = "text/html", m.HTML
+		}
+
+		return writeTextPart(buffer, contentType, content)
+	}
+
+	writer := multipart.NewWriter(buffer)
+	writeHeader(buffer, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": writer.Boundary()}))
+	buffer.WriteString("\r\n")
+
+	// Clients show the last alternative they support, so HTML goes last
+	for _, alternative := range [][2]string{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		var part bytes.Buffer
+		if err := writeTextPart(&part, alternative[0], alternative[1]); err != nil {
+			return err
+		}
+
+		headers, content, _ := bytes.Cut(part.Bytes(), []byte("\r\n\r\n"))
+		partWriter, err := writer.CreatePart(parsePartHeaders(headers))
+		if err != nil {
+			return err
+		}
+
+		if _, err = partWriter.Write(content); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeTextPart(buffer *bytes.Buffer, contentType, content string) error {
+	writeHeader(buffer, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(buffer, "Content-Transfer-Encoding", "quoted-printable")
+	buffer.WriteString("\r\n")
+
+	writer := quotedprintable.NewWriter(buffer)
+	if _, err := writer.Write([]byte(normalizeLineBreaks(content))); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func writeAttachment(writer *multipart.Writer, attachment Attachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(attachmentExtension(attachment.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	headers := textproto.MIMEHeader{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Transfer-Encoding", "base64")
+	headers.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+
+	part, err := writer.CreatePart(headers)
+	if err != nil {
+		return err
+	}
+
+	// Lines of base64 content are limited to 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		if _, err = part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+
+		encoded = encoded[76:]
+	}
+
+	_, err = part.Write([]byte(encoded))
+
+	return err
+}
+
+func writeHeader(buffer *bytes.Buffer, name, value string) {
+	buffer.WriteString(name + ": " + value + "\r\n")
+}
+
+func writeAddressHeader(buffer *bytes.Buffer, name string, addresses []string) {
+	if len(addresses) == 0 {
+		return
+	}
+
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			continue
+		}
+
+		formatted = append(formatted, parsed.String())
+	}
+
+	writeHeader(buffer, name, strings.Join(formatted, ", "))
+}
+
+func parsePartHeaders(raw []byte) textproto.MIMEHeader {
+	headers := textproto.MIMEHeader{}
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if name, value, found := strings.Cut(line, ": "); found {
+			headers.Set(name, value)
+		}
+	}
+
+	return headers
+}
+
+func normalizeLineBreaks(content string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+}
+
+func attachmentExtension(filename string) string {
+	if dot := strings.LastIndexByte(filename, '.'); dot != -1 {
+		return filename[dot:]
+	}
+
+	return ""
+}
+
+func isHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, char := range name {
+		if char <= ' ' || char >= 127 || char == ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newMessageID(from string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndexByte(from, '@'); at != -1 {
+		domain = from[at+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain), nil
+}
@@ -0,0 +1,124 @@
+package email
+
+import (
+	"bytes"
+	"fluxend/internal/config/constants"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	valid := Message{To: []string{"jane@example.com"}, Subject: "Hi", Text: "Hello"}
+	require.NoError(t, valid.Validate("noreply@fluxend.app"))
+
+	cases := map[string]func(m *Message){
+		"no recipients":         func(m *Message) { m.To = nil },
+		"invalid cc":            func(m *Message) { m.Cc = []string{"not an address"} },
+		"invalid reply-to":      func(m *Message) { m.ReplyTo = []string{"@example.com"} },
+		"line break in subject": func(m *Message) { m.Subject = "Hi\nBcc: everyone@example.com" },
+		"no body":               func(m *Message) { m.Text = "" },
+		"line break in header":  func(m *Message) { m.Headers = map[string]string{"X-Campaign": "a\r\nBcc: b"} },
+		"invalid header name":   func(m *Message) { m.Headers = map[string]string{"X Campaign": "a"} },
+		"reserved header":       func(m *Message) { m.Headers = map[string]string{"content-type": "text/html"} },
+		"unnamed attachment":    func(m *Message) { m.Attachments = []Attachment{{Content: []byte("a")}} },
+		"attachments too large": func(m *Message) {
+			m.Attachments = []Attachment{{Filename: "a.bin", Content: make([]byte, constants.EmailMaxAttachmentsSize+1)}}
+		},
+	}
+
+	for name, mutate := range cases {
+		message := valid
+		mutate(&message)
+
+		assert.Error(t, message.Validate("noreply@fluxend.app"), name)
+	}
+
+	assert.Error(t, valid.Validate("not an address"))
+}
+
+func TestMessage_Recipients(t *testing.T) {
+	message := Message{
+		To:  []string{"Jane <jane@example.com>"},
+		Cc:  []string{"john@example.com"},
+		Bcc: []string{"audit@example.com"},
+	}
+
+	assert.Equal(t, []string{"jane@example.com", "john@example.com", "audit@example.com"}, message.Recipients())
+}
+
+func TestMessage_Render(t *testing.T) {
+	message := Message{
+		To:      []string{"jane@example.com"},
+		Bcc:     []string{"audit@example.com"},
+		ReplyTo: []string{"Support <support@example.com>"},
+		Subject: "Your report",
+		Text:    "See attached",
+		HTML:    "<p>See attached</p>",
+		Headers: map[string]string{"X-Campaign": "reports"},
+		Attachments: []Attachment{
+			{Filename: "report.csv", Content: bytes.Repeat([]byte("a,b\n"), 100)},
+		},
+	}
+
+	content, err := message.Render("noreply@fluxend.app")
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Header.Get("Bcc"))
+	assert.Equal(t, `"Support" <support@example.com>`, parsed.Header.Get("Reply-To"))
+	assert.Equal(t, "reports", parsed.Header.Get("X-Campaign"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mixed := multipart.NewReader(parsed.Body, params["boundary"])
+
+	body, err := mixed.NextPart()
+	require.NoError(t, err)
+	mediaType, params, err = mime.ParseMediaType(body.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	alternatives := multipart.NewReader(body, params["boundary"])
+	for _, expected := range [][2]string{{"text/plain; charset=utf-8", "See attached"}, {"text/html; charset=utf-8", "<p>See attached</p>"}} {
+		part, err := alternatives.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, expected[0], part.Header.Get("Content-Type"))
+
+		// multipart.Reader decodes quoted-printable parts by itself
+		text, err := io.ReadAll(part)
+		require.NoError(t, err)
+		assert.Equal(t, expected[1], string(text))
+	}
+
+	attachment, err := mixed.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "report.csv", attachment.FileName())
+	assert.Contains(t, attachment.Header.Get("Content-Type"), "text/csv")
+	assert.Equal(t, "base64", attachment.Header.Get("Content-Transfer-Encoding"))
+
+	_, err = mixed.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMessage_Render_SinglePart(t *testing.T) {
+	content, err := Message{To: []string{"jane@example.com"}, Subject: "Hi", HTML: "<p>Hello</p>"}.Render("noreply@fluxend.app")
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", parsed.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hello</p>", string(body))
+}
@@ -0,0 +1,159 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ParseMessage reads a message back from its RFC 5322 form, as written by Render. Bodies are expected
+// in UTF-8, parts that are neither the text nor the HTML body become attachments
+func ParseMessage(raw []byte) (Message, error) {
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return Message{}, err
+	}
+
+	message := parseHeader(parsed.Header)
+	if err = parsePart(&message, textproto.MIMEHeader(parsed.Header), parsed.Body); err != nil {
+		return Message{}, err
+	}
+
+	return message, nil
+}
+
+// ParseHeader reads the addresses, subject and custom headers of a message and leaves its bodies out
+func ParseHeader(reader io.Reader) (Message, error) {
+	parsed, err := mail.ReadMessage(reader)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return parseHeader(parsed.Header), nil
+}
+
+func parseHeader(header mail.Header) Message {
+	message := Message{
+		To:        parseAddressHeader(header, "To"),
+		Cc:        parseAddressHeader(header, "Cc"),
+		Bcc:       parseAddressHeader(header, "Bcc"),
+		ReplyTo:   parseAddressHeader(header, "Reply-To"),
+		Subject:   decodeHeader(header.Get("Subject")),
+		MessageID: header.Get("Message-Id"),
+	}
+
+	if from := parseAddressHeader(header, "From"); len(from) > 0 {
+		message.From = from[0]
+	}
+
+	for name := range header {
+		if reservedHeaders[name] {
+			continue
+		}
+
+		if message.Headers == nil {
+			message.Headers = map[string]string{}
+		}
+
+		message.Headers[name] = decodeHeader(header.Get(name))
+	}
+
+	return message
+}
+
+func parsePart(message *Message, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err = parsePart(message, part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("unable to decode %s part: %v", mediaType, err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	if disposition != "attachment" {
+		switch {
+		case mediaType == "text/plain" && message.Text == "":
+			message.Text = string(content)
+			return nil
+		case mediaType == "text/html" && message.HTML == "":
+			message.HTML = string(content)
+			return nil
+		}
+	}
+
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+
+	message.Attachments = append(message.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: mediaType,
+		Content:     content,
+	})
+
+	return nil
+}
+
+func parseAddressHeader(header mail.Header, name string) []string {
+	addresses, err := header.AddressList(name)
+	if err != nil {
+		return nil
+	}
+
+	formatted := make([]string, len(addresses))
+	for i, address := range addresses {
+		formatted[i] = address.String()
+	}
+
+	return formatted
+}
+
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		// Line breaks between the encoded lines are skipped by the decoder
+		return base64.NewDecoder(base64.StdEncoding, body)
+	default:
+		return body
+	}
+}
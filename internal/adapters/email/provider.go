@@ -6,8 +6,9 @@ import (
 	"github.com/samber/do"
 )
 
+// Provider delivers messages, returning the ID the provider gave the message
 type Provider interface {
-	Send(to, subject, body string) error
+	Send(message Message) (string, error)
 }
 
 type Factory struct {
@@ -26,6 +27,12 @@ func (f *Factory) CreateProvider(providerType string) (Provider, error) {
 		return NewSendGridProvider(f.injector)
 	case constants.EmailDriverMailgun:
 		return NewMailgunProvider(f.injector)
+	case constants.EmailDriverSMTP:
+		return NewSMTPProvider(f.injector)
+	case constants.EmailDriverLog:
+		return NewLogProvider(f.injector)
+	case constants.EmailDriverFile:
+		return NewFileProvider(f.injector)
 	default:
 		return nil, fmt.Errorf("unsupported email provider: %s", providerType)
 	}
@@ -1,11 +1,13 @@
 package email
 
 import (
+	"encoding/base64"
 	"fluxend/internal/domain/setting"
 	"fmt"
 	"github.com/samber/do"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	netmail "net/mail"
 )
 
 type SendGridServiceImpl struct {
@@ -32,28 +34,90 @@ func NewSendGridProvider(injector *do.Injector) (Provider, error) {
 	}, nil
 }
 
-func (s *SendGridServiceImpl) Send(to, subject, body string) error {
-	from := s.settingService.GetValue("sendgridEmailSource")
+func (s *SendGridServiceImpl) Send(message Message) (string, error) {
+	from := message.From
 	if from == "" {
-		return fmt.Errorf("sendgridEmailSource is required")
+		from = s.settingService.GetValue("sendgridEmailSource")
 	}
 
-	message := mail.NewSingleEmail(
-		mail.NewEmail("", from),
-		subject,
-		mail.NewEmail("", to),
-		body,
-		"", // HTML content is empty as we're using plain text
-	)
+	if from == "" {
+		return "", fmt.Errorf("sendgridEmailSource is required")
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	sender, _ := netmail.ParseAddress(from)
+
+	email := mail.NewV3Mail()
+	email.SetFrom(mail.NewEmail(sender.Name, sender.Address))
+	email.Subject = message.Subject
+
+	personalization := mail.NewPersonalization()
+	personalization.AddTos(toSendGridEmails(message.To)...)
+	personalization.AddCCs(toSendGridEmails(message.Cc)...)
+	personalization.AddBCCs(toSendGridEmails(message.Bcc)...)
+	email.AddPersonalizations(personalization)
+
+	// SendGrid takes a single reply-to address
+	if len(message.ReplyTo) > 0 {
+		email.SetReplyTo(toSendGridEmails(message.ReplyTo[:1])[0])
+	}
+
+	// SendGrid expects the plain text content before the HTML one
+	if message.Text != "" {
+		email.AddContent(mail.NewContent("text/plain", message.Text))
+	}
+
+	if message.HTML != "" {
+		email.AddContent(mail.NewContent("text/html", message.HTML))
+	}
 
-	response, err := s.client.Send(message)
+	for name, value := range message.Headers {
+		email.SetHeader(name, value)
+	}
+
+	for _, attachment := range message.Attachments {
+		sendGridAttachment := mail.NewAttachment().
+			SetContent(base64.StdEncoding.EncodeToString(attachment.Content)).
+			SetFilename(attachment.Filename).
+			SetDisposition("attachment")
+
+		if attachment.ContentType != "" {
+			sendGridAttachment.SetType(attachment.ContentType)
+		}
+
+		email.AddAttachment(sendGridAttachment)
+	}
+
+	response, err := s.client.Send(email)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
+		return "", fmt.Errorf("failed to send email: %v", err)
 	}
 
 	if response.StatusCode >= 400 {
-		return fmt.Errorf("failed to send email: status code %d, body: %s", response.StatusCode, response.Body)
+		return "", fmt.Errorf("failed to send email: status code %d, body: %s", response.StatusCode, response.Body)
+	}
+
+	// SendGrid only hands out the ID as a response header
+	if ids := response.Headers["X-Message-Id"]; len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	return "", nil
+}
+
+func toSendGridEmails(addresses []string) []*mail.Email {
+	emails := make([]*mail.Email, 0, len(addresses))
+	for _, address := range addresses {
+		parsed, err := netmail.ParseAddress(address)
+		if err != nil {
+			continue
+		}
+
+		emails = append(emails, mail.NewEmail(parsed.Name, parsed.Address))
 	}
 
-	return nil
+	return emails
 }
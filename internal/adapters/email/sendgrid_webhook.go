@@ -0,0 +1,88 @@
+package email
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifySendGridSignature checks the ECDSA signature of a signed Event Webhook request, publicKey being the
+// base64 verification key shown by SendGrid
+func VerifySendGridSignature(publicKey string, payload []byte, signature, timestamp string) error {
+	decodedKey, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid SendGrid verification key: %v", err)
+	}
+
+	parsedKey, err := x509.ParsePKIXPublicKey(decodedKey)
+	if err != nil {
+		return fmt.Errorf("invalid SendGrid verification key: %v", err)
+	}
+
+	key, ok := parsedKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid SendGrid verification key: not an ECDSA key")
+	}
+
+	decodedSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	if err = checkWebhookTimestamp(timestamp, time.Now()); err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(append([]byte(timestamp), payload...))
+	if !ecdsa.VerifyASN1(key, hash[:], decodedSignature) {
+		return ErrInvalidWebhookSignature
+	}
+
+	return nil
+}
+
+// ParseSendGridFeedback reads the bounces and spam reports of an Event Webhook batch
+func ParseSendGridFeedback(payload []byte) ([]Feedback, error) {
+	var events []struct {
+		Email       string `json:"email"`
+		Event       string `json:"event"`
+		Type        string `json:"type"`
+		Reason      string `json:"reason"`
+		SgMessageId string `json:"sg_message_id"`
+	}
+
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, fmt.Errorf("invalid SendGrid events: %v", err)
+	}
+
+	var feedback []Feedback
+	for _, event := range events {
+		// The X-Message-Id returned when sending is the start of sg_message_id
+		providerMessageID, _, _ := strings.Cut(event.SgMessageId, ".filter")
+
+		switch {
+		// Blocked messages were refused for a temporary reason
+		case event.Event == "bounce" && event.Type != "blocked":
+			feedback = append(feedback, Feedback{
+				Type:              constants.EmailSuppressionReasonBounce,
+				Address:           event.Email,
+				ProviderMessageID: providerMessageID,
+				Details:           event.Reason,
+			})
+		case event.Event == "spamreport":
+			feedback = append(feedback, Feedback{
+				Type:              constants.EmailSuppressionReasonComplaint,
+				Address:           event.Email,
+				ProviderMessageID: providerMessageID,
+			})
+		}
+	}
+
+	return feedback, nil
+}
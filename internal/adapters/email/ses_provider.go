@@ -45,33 +45,38 @@ func NewSESProvider(injector *do.Injector) (Provider, error) {
 	}, nil
 }
 
-func (s *SESServiceImpl) Send(to, subject, body string) error {
-	from := s.settingService.GetValue("sesEmailSource")
+// Send delivers the message as raw MIME, the only way SES accepts attachments and custom headers
+func (s *SESServiceImpl) Send(message Message) (string, error) {
+	from := message.From
 	if from == "" {
-		return fmt.Errorf("sesEmailSource is required")
+		from = s.settingService.GetValue("sesEmailSource")
 	}
 
-	input := &ses.SendEmailInput{
-		Source: aws.String(from),
-		Destination: &types.Destination{
-			ToAddresses: []string{to},
-		},
-		Message: &types.Message{
-			Subject: &types.Content{
-				Data: aws.String(subject),
-			},
-			Body: &types.Body{
-				Text: &types.Content{
-					Data: aws.String(body),
-				},
-			},
+	if from == "" {
+		return "", fmt.Errorf("sesEmailSource is required")
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	content, err := message.Render(from)
+	if err != nil {
+		return "", err
+	}
+
+	input := &ses.SendRawEmailInput{
+		Source:       aws.String(from),
+		Destinations: message.Recipients(),
+		RawMessage: &types.RawMessage{
+			Data: content,
 		},
 	}
 
-	_, err := s.client.SendEmail(context.Background(), input)
+	output, err := s.client.SendRawEmail(context.Background(), input)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
+		return "", fmt.Errorf("failed to send email: %v", err)
 	}
 
-	return nil
+	return aws.ToString(output.MessageId), nil
 }
@@ -0,0 +1,261 @@
+package email
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const snsCertificateMaxSize = 64 * 1024
+
+// SNS signs with certificates and confirms subscriptions from its own regional endpoints only
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSMessage is an Amazon SNS HTTP notification, SES reports bounces and complaints through them
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageId        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// SNSVerifier checks SNS signatures, keeping the signing certificates it fetched
+type SNSVerifier struct {
+	client       *http.Client
+	mutex        sync.Mutex
+	certificates map[string]*x509.Certificate
+	fetch        func(certificateURL string) (*x509.Certificate, error)
+}
+
+func NewSNSVerifier() *SNSVerifier {
+	verifier := &SNSVerifier{
+		client:       &http.Client{Timeout: constants.EmailWebhookTimeout},
+		certificates: map[string]*x509.Certificate{},
+	}
+	verifier.fetch = verifier.fetchCertificate
+
+	return verifier
+}
+
+func (v *SNSVerifier) Verify(message SNSMessage) error {
+	var algorithm x509.SignatureAlgorithm
+	switch message.SignatureVersion {
+	case "1":
+		algorithm = x509.SHA1WithRSA
+	case "2":
+		algorithm = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("%w: unsupported signature version %q", ErrInvalidWebhookSignature, message.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	certificate, err := v.certificate(message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	if err = certificate.CheckSignature(algorithm, []byte(message.signedString()), signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	return nil
+}
+
+// ConfirmSubscription visits the subscribe URL of a verified subscription confirmation, after which SNS
+// starts sending notifications
+func (v *SNSVerifier) ConfirmSubscription(message SNSMessage) error {
+	subscribeURL, err := snsURL(message.SubscribeURL)
+	if err != nil {
+		return err
+	}
+
+	response, err := v.client.Get(subscribeURL.String())
+	if err != nil {
+		return fmt.Errorf("failed to confirm SNS subscription: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to confirm SNS subscription: status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func (v *SNSVerifier) certificate(certificateURL string) (*x509.Certificate, error) {
+	parsedURL, err := snsURL(certificateURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(parsedURL.Path, ".pem") {
+		return nil, fmt.Errorf("%w: signing certificate URL must point to a .pem file", ErrInvalidWebhookSignature)
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if certificate, ok := v.certificates[parsedURL.String()]; ok {
+		return certificate, nil
+	}
+
+	certificate, err := v.fetch(parsedURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	v.certificates[parsedURL.String()] = certificate
+
+	return certificate, nil
+}
+
+func (v *SNSVerifier) fetchCertificate(certificateURL string) (*x509.Certificate, error) {
+	response, err := v.client.Get(certificateURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch SNS signing certificate: status code %d", response.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, snsCertificateMaxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("SNS signing certificate is not PEM encoded")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// signedString is what SNS signs, the fields in alphabetical order, each name and value on its own line
+func (m SNSMessage) signedString() string {
+	fields := [][2]string{{"Message", m.Message}, {"MessageId", m.MessageId}}
+
+	if m.Type == "Notification" {
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+
+		fields = append(fields, [2]string{"Timestamp", m.Timestamp}, [2]string{"TopicArn", m.TopicArn})
+	} else {
+		fields = append(fields,
+			[2]string{"SubscribeURL", m.SubscribeURL},
+			[2]string{"Timestamp", m.Timestamp},
+			[2]string{"Token", m.Token},
+			[2]string{"TopicArn", m.TopicArn},
+		)
+	}
+
+	fields = append(fields, [2]string{"Type", m.Type})
+
+	var signed strings.Builder
+	for _, field := range fields {
+		signed.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+
+	return signed.String()
+}
+
+func snsURL(rawURL string) (*url.URL, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Scheme != "https" || !snsHostPattern.MatchString(parsedURL.Hostname()) {
+		return nil, fmt.Errorf("%w: %q is not an SNS URL", ErrInvalidWebhookSignature, rawURL)
+	}
+
+	return parsedURL, nil
+}
+
+// ParseSESFeedback reads the bounces and complaints of an SES notification, delivered as the message of
+// an SNS notification. Both notifications and event publishing are supported
+func ParseSESFeedback(message string) ([]Feedback, error) {
+	var notification struct {
+		NotificationType string `json:"notificationType"`
+		EventType        string `json:"eventType"`
+		Bounce           struct {
+			BounceType        string `json:"bounceType"`
+			BounceSubType     string `json:"bounceSubType"`
+			BouncedRecipients []struct {
+				EmailAddress   string `json:"emailAddress"`
+				DiagnosticCode string `json:"diagnosticCode"`
+			} `json:"bouncedRecipients"`
+		} `json:"bounce"`
+		Complaint struct {
+			ComplaintFeedbackType string `json:"complaintFeedbackType"`
+			ComplainedRecipients  []struct {
+				EmailAddress string `json:"emailAddress"`
+			} `json:"complainedRecipients"`
+		} `json:"complaint"`
+		Mail struct {
+			MessageId string `json:"messageId"`
+		} `json:"mail"`
+	}
+
+	if err := json.Unmarshal([]byte(message), &notification); err != nil {
+		return nil, fmt.Errorf("invalid SES notification: %v", err)
+	}
+
+	notificationType := notification.NotificationType
+	if notificationType == "" {
+		notificationType = notification.EventType
+	}
+
+	var feedback []Feedback
+	switch notificationType {
+	case "Bounce":
+		// Transient bounces are retried by SES itself
+		if notification.Bounce.BounceType != "Permanent" {
+			return nil, nil
+		}
+
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			details := notification.Bounce.BounceType + "/" + notification.Bounce.BounceSubType
+			if recipient.DiagnosticCode != "" {
+				details += ": " + recipient.DiagnosticCode
+			}
+
+			feedback = append(feedback, Feedback{
+				Type:              constants.EmailSuppressionReasonBounce,
+				Address:           recipient.EmailAddress,
+				ProviderMessageID: notification.Mail.MessageId,
+				Details:           details,
+			})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			feedback = append(feedback, Feedback{
+				Type:              constants.EmailSuppressionReasonComplaint,
+				Address:           recipient.EmailAddress,
+				ProviderMessageID: notification.Mail.MessageId,
+				Details:           notification.Complaint.ComplaintFeedbackType,
+			})
+		}
+	}
+
+	return feedback, nil
+}
@@ -0,0 +1,290 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/setting"
+	"fmt"
+	"github.com/samber/do"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig describes a mail relay, Encryption and Auth take the constants.SMTPEncryption* and constants.SMTPAuth* values
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption string
+	Auth       string
+	From       string
+	Timeout    time.Duration
+	TLSConfig  *tls.Config // defaults to verifying the certificate against Host
+}
+
+type SMTPServiceImpl struct {
+	config SMTPConfig
+}
+
+func NewSMTPProvider(injector *do.Injector) (Provider, error) {
+	settingService, err := setting.NewSettingService(injector)
+	if err != nil {
+		return nil, err
+	}
+
+	port := constants.SMTPDefaultPort
+	if value := settingService.GetValue("smtpPort"); value != "" {
+		port, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("smtpPort must be a number: %v", err)
+		}
+	}
+
+	return newSMTPProvider(SMTPConfig{
+		Host:       settingService.GetValue("smtpHost"),
+		Port:       port,
+		Username:   settingService.GetValue("smtpUsername"),
+		Password:   settingService.GetValue("smtpPassword"),
+		Encryption: strings.ToLower(settingService.GetValue("smtpEncryption")),
+		Auth:       strings.ToLower(settingService.GetValue("smtpAuth")),
+		From:       settingService.GetValue("smtpEmailSource"),
+	})
+}
+
+func newSMTPProvider(config SMTPConfig) (Provider, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("smtpHost is required")
+	}
+
+	if config.Port < 1 || config.Port > 65535 {
+		return nil, fmt.Errorf("smtpPort must be between 1 and 65535")
+	}
+
+	if config.Encryption == "" {
+		config.Encryption = constants.SMTPEncryptionStartTLS
+	}
+
+	switch config.Encryption {
+	case constants.SMTPEncryptionStartTLS, constants.SMTPEncryptionTLS, constants.SMTPEncryptionNone:
+	default:
+		return nil, fmt.Errorf("unsupported smtpEncryption: %s", config.Encryption)
+	}
+
+	// Relays accepting mail from the local network often don't authenticate at all
+	if config.Auth == "" {
+		config.Auth = constants.SMTPAuthPlain
+	}
+
+	if config.Username == "" {
+		config.Auth = constants.SMTPAuthNone
+	}
+
+	switch config.Auth {
+	case constants.SMTPAuthPlain, constants.SMTPAuthLogin, constants.SMTPAuthCRAMMD5, constants.SMTPAuthNone:
+	default:
+		return nil, fmt.Errorf("unsupported smtpAuth: %s", config.Auth)
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = constants.SMTPTimeout
+	}
+
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{ServerName: config.Host, MinVersion: tls.VersionTLS12}
+	}
+
+	return &SMTPServiceImpl{config: config}, nil
+}
+
+// Send returns the Message-ID of the message, relays don't hand out IDs of their own
+func (s *SMTPServiceImpl) Send(message Message) (string, error) {
+	from := message.From
+	if from == "" {
+		from = s.config.From
+	}
+
+	if from == "" {
+		return "", fmt.Errorf("smtpEmailSource is required")
+	}
+
+	if err := message.Validate(from); err != nil {
+		return "", err
+	}
+
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", err
+	}
+
+	if message.MessageID == "" {
+		message.MessageID, err = newMessageID(sender.Address)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	content, err := message.Render(from)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := s.connect()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	defer client.Close()
+
+	if err = s.deliver(client, sender.Address, message.Recipients(), content); err != nil {
+		return "", fmt.Errorf("failed to send email: %v", err)
+	}
+
+	return message.MessageID, nil
+}
+
+// connect opens a session with the relay, encrypted and authenticated as configured. The whole
+// conversation shares one deadline so a stalled relay can't hold the caller forever
+func (s *SMTPServiceImpl) connect() (*smtp.Client, error) {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.Encryption == constants.SMTPEncryptionTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, s.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	if err = s.secure(client); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// secure upgrades the session with STARTTLS when asked and authenticates. A relay not offering STARTTLS
+// is an error rather than a reason to send credentials and mail in the clear
+func (s *SMTPServiceImpl) secure(client *smtp.Client) error {
+	if s.config.Encryption == constants.SMTPEncryptionStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("server doesn't support STARTTLS")
+		}
+
+		if err := client.StartTLS(s.config.TLSConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.config.Auth == constants.SMTPAuthNone {
+		return nil
+	}
+
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return errors.New("server doesn't support authentication")
+	}
+
+	return client.Auth(s.auth())
+}
+
+func (s *SMTPServiceImpl) auth() smtp.Auth {
+	switch s.config.Auth {
+	case constants.SMTPAuthLogin:
+		return &loginAuth{username: s.config.Username, password: s.config.Password, host: s.config.Host}
+	case constants.SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(s.config.Username, s.config.Password)
+	default:
+		return smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+}
+
+func (s *SMTPServiceImpl) deliver(client *smtp.Client, from string, recipients []string, message []byte) error {
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(message); err != nil {
+		writer.Close()
+
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// loginAuth implements the LOGIN mechanism, which net/smtp leaves out but many relays still expect.
+// Like smtp.PlainAuth, credentials are only sent over TLS or to localhost
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge: %s", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,371 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a minimal SMTP server keeping every message it accepts
+type smtpSink struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	offerTLS    bool
+	username    string
+	password    string
+	messages    chan sinkMessage
+}
+
+type sinkMessage struct {
+	from          string
+	to            []string
+	data          []byte
+	tls           bool
+	authenticated bool
+}
+
+func newSMTPSink(t *testing.T, implicitTLS, offerTLS bool) (*smtpSink, *x509.CertPool) {
+	t.Helper()
+
+	// httptest generates a certificate valid for 127.0.0.1, which is all a sink needs
+	tlsServer := httptest.NewUnstartedServer(nil)
+	tlsServer.StartTLS()
+	certificate := tlsServer.TLS.Certificates[0]
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsServer.Certificate())
+	tlsServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	sink := &smtpSink{
+		listener:    listener,
+		tlsConfig:   &tls.Config{Certificates: []tls.Certificate{certificate}},
+		implicitTLS: implicitTLS,
+		offerTLS:    offerTLS,
+		username:    "relay-user",
+		password:    "relay-pass",
+		messages:    make(chan sinkMessage, 1),
+	}
+
+	go sink.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return sink, roots
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	encrypted := false
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+		encrypted = true
+	}
+
+	text := textproto.NewConn(conn)
+	message := sinkMessage{}
+	text.PrintfLine("220 sink ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			if s.offerTLS && !encrypted {
+				text.PrintfLine("250-sink")
+				text.PrintfLine("250-STARTTLS")
+			} else {
+				text.PrintfLine("250-sink")
+			}
+			text.PrintfLine("250 AUTH PLAIN LOGIN CRAM-MD5")
+		case "STARTTLS":
+			text.PrintfLine("220 ready")
+			conn = tls.Server(conn, s.tlsConfig)
+			text = textproto.NewConn(conn)
+			encrypted = true
+		case "AUTH":
+			message.authenticated = s.authenticate(text, argument)
+			if !message.authenticated {
+				text.PrintfLine("535 authentication failed")
+				continue
+			}
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			message.from = strings.Trim(strings.TrimPrefix(argument, "FROM:"), "<>")
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			message.to = append(message.to, strings.Trim(strings.TrimPrefix(argument, "TO:"), "<>"))
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			message.data, _ = text.ReadDotBytes()
+			message.tls = encrypted
+			s.messages <- message
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *smtpSink) authenticate(text *textproto.Conn, argument string) bool {
+	mechanism, initial, _ := strings.Cut(argument, " ")
+
+	readResponse := func(challenge string) string {
+		text.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(challenge)))
+		line, _ := text.ReadLine()
+		decoded, _ := base64.StdEncoding.DecodeString(line)
+
+		return string(decoded)
+	}
+
+	switch mechanism {
+	case "PLAIN":
+		decoded, _ := base64.StdEncoding.DecodeString(initial)
+
+		return string(decoded) == "\x00"+s.username+"\x00"+s.password
+	case "LOGIN":
+		username := readResponse("Username:")
+		password := readResponse("Password:")
+
+		return username == s.username && password == s.password
+	case "CRAM-MD5":
+		challenge := "<1896.697170952@sink>"
+		username, digest, _ := strings.Cut(readResponse(challenge), " ")
+
+		mac := hmac.New(md5.New, []byte(s.password))
+		mac.Write([]byte(challenge))
+
+		return username == s.username && digest == hex.EncodeToString(mac.Sum(nil))
+	default:
+		return false
+	}
+}
+
+func (s *smtpSink) received(t *testing.T) sinkMessage {
+	t.Helper()
+
+	select {
+	case message := <-s.messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+
+		return sinkMessage{}
+	}
+}
+
+func newTestSMTPProvider(t *testing.T, sink *smtpSink, roots *x509.CertPool, encryption, auth, password string) Provider {
+	t.Helper()
+
+	provider, err := newSMTPProvider(SMTPConfig{
+		Host:       "127.0.0.1",
+		Port:       sink.port(),
+		Username:   "relay-user",
+		Password:   password,
+		Encryption: encryption,
+		Auth:       auth,
+		From:       "Fluxend <noreply@fluxend.app>",
+		Timeout:    5 * time.Second,
+		TLSConfig:  &tls.Config{RootCAs: roots, ServerName: "127.0.0.1"},
+	})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestSMTPProvider_Send_Suite(t *testing.T) {
+	t.Run("Send: STARTTLS with PLAIN auth", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, true)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionStartTLS, constants.SMTPAuthPlain, "relay-pass")
+
+		messageID, err := provider.Send(NewTextMessage("jane@example.com", "Welcome to Fluxend — café", "Hello Jane,\n\nYour account at the café is ready.\n.\nBye"))
+		require.NoError(t, err)
+
+		received := sink.received(t)
+		assert.True(t, received.tls)
+		assert.True(t, received.authenticated)
+		assert.Equal(t, "noreply@fluxend.app", received.from)
+		assert.Equal(t, []string{"jane@example.com"}, received.to)
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(received.data)))
+		require.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Welcome to Fluxend — café", subject)
+		assert.Equal(t, `"Fluxend" <noreply@fluxend.app>`, parsed.Header.Get("From"))
+		assert.Equal(t, "quoted-printable", parsed.Header.Get("Content-Transfer-Encoding"))
+		assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-ID"), "@fluxend.app>"))
+		assert.Equal(t, messageID, parsed.Header.Get("Message-ID"))
+
+		// The sink hands lines over with bare line feeds and the end of DATA adds a last one
+		body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello Jane,\n\nYour account at the café is ready.\n.\nBye\n", string(body))
+	})
+
+	t.Run("Send: every recipient is in the envelope, Bcc only there", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, true)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionStartTLS, constants.SMTPAuthPlain, "relay-pass")
+
+		_, err := provider.Send(Message{
+			From:    "Support <support@fluxend.app>",
+			To:      []string{"Jane <jane@example.com>"},
+			Cc:      []string{"john@example.com"},
+			Bcc:     []string{"audit@example.com"},
+			Subject: "Hi",
+			Text:    "Hello",
+			HTML:    "<p>Hello</p>",
+		})
+		require.NoError(t, err)
+
+		received := sink.received(t)
+		assert.Equal(t, "support@fluxend.app", received.from)
+		assert.Equal(t, []string{"jane@example.com", "john@example.com", "audit@example.com"}, received.to)
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(received.data)))
+		require.NoError(t, err)
+		assert.Equal(t, `"Support" <support@fluxend.app>`, parsed.Header.Get("From"))
+		assert.Equal(t, "<john@example.com>", parsed.Header.Get("Cc"))
+		assert.Empty(t, parsed.Header.Get("Bcc"))
+		assert.NotContains(t, string(received.data), "audit@example.com")
+	})
+
+	t.Run("Send: implicit TLS with LOGIN auth", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, true, false)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionTLS, constants.SMTPAuthLogin, "relay-pass")
+
+		_, err := provider.Send(NewTextMessage("jane@example.com", "Hi", "Hello"))
+		require.NoError(t, err)
+
+		received := sink.received(t)
+		assert.True(t, received.tls)
+		assert.True(t, received.authenticated)
+	})
+
+	t.Run("Send: CRAM-MD5 auth without encryption", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, false)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionNone, constants.SMTPAuthCRAMMD5, "relay-pass")
+
+		_, err := provider.Send(NewTextMessage("jane@example.com", "Hi", "Hello"))
+		require.NoError(t, err)
+
+		received := sink.received(t)
+		assert.False(t, received.tls)
+		assert.True(t, received.authenticated)
+	})
+
+	t.Run("Send: relay without authentication", func(t *testing.T) {
+		sink, _ := newSMTPSink(t, false, false)
+		provider, err := newSMTPProvider(SMTPConfig{
+			Host:       "127.0.0.1",
+			Port:       sink.port(),
+			Encryption: constants.SMTPEncryptionNone,
+			From:       "noreply@fluxend.app",
+		})
+		require.NoError(t, err)
+
+		_, err = provider.Send(NewTextMessage("jane@example.com", "Hi", "Hello"))
+		require.NoError(t, err)
+		assert.False(t, sink.received(t).authenticated)
+	})
+
+	t.Run("Send: wrong password", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, true)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionStartTLS, constants.SMTPAuthPlain, "wrong")
+
+		_, err := provider.Send(NewTextMessage("jane@example.com", "Hi", "Hello"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "535")
+	})
+
+	t.Run("Send: STARTTLS not offered", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, false)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionStartTLS, constants.SMTPAuthPlain, "relay-pass")
+
+		_, err := provider.Send(NewTextMessage("jane@example.com", "Hi", "Hello"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "STARTTLS")
+	})
+
+	t.Run("Send: invalid recipient and subject", func(t *testing.T) {
+		sink, roots := newSMTPSink(t, false, true)
+		provider := newTestSMTPProvider(t, sink, roots, constants.SMTPEncryptionStartTLS, constants.SMTPAuthPlain, "relay-pass")
+
+		_, err := provider.Send(NewTextMessage("not an address", "Hi", "Hello"))
+		assert.Error(t, err)
+
+		_, err = provider.Send(NewTextMessage("jane@example.com", "Hi\r\nBcc: everyone@example.com", "Hello"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewSMTPProvider_Config(t *testing.T) {
+	cases := []struct {
+		config SMTPConfig
+		error  string
+	}{
+		{SMTPConfig{Port: 587}, "smtpHost is required"},
+		{SMTPConfig{Host: "mail.example.com", Port: 0}, "smtpPort"},
+		{SMTPConfig{Host: "mail.example.com", Port: 587, Encryption: "ssl"}, "smtpEncryption"},
+		{SMTPConfig{Host: "mail.example.com", Port: 587, Username: "user", Auth: "xoauth2"}, "smtpAuth"},
+	}
+
+	for _, c := range cases {
+		_, err := newSMTPProvider(c.config)
+
+		require.Error(t, err, fmt.Sprintf("%+v", c.config))
+		assert.Contains(t, err.Error(), c.error)
+	}
+
+	provider, err := newSMTPProvider(SMTPConfig{Host: "mail.example.com", Port: 587})
+	require.NoError(t, err)
+
+	config := provider.(*SMTPServiceImpl).config
+	assert.Equal(t, constants.SMTPEncryptionStartTLS, config.Encryption)
+	assert.Equal(t, constants.SMTPAuthNone, config.Auth)
+	assert.Equal(t, "mail.example.com", config.TLSConfig.ServerName)
+}
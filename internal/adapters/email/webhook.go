@@ -0,0 +1,35 @@
+package email
+
+import (
+	"errors"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Feedback is a bounce or complaint a provider reported for a recipient, Type is a suppression reason
+type Feedback struct {
+	Type              string
+	Address           string
+	ProviderMessageID string
+	Details           string
+}
+
+// ErrInvalidWebhookSignature is returned for webhook payloads not signed by the provider
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// checkWebhookTimestamp rejects payloads signed too long ago, so captured ones can't be replayed later
+func checkWebhookTimestamp(timestamp string, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidWebhookSignature)
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > constants.EmailWebhookMaxAge || age < -constants.EmailWebhookMaxAge {
+		return fmt.Errorf("%w: timestamp too old", ErrInvalidWebhookSignature)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package email
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCertificateURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+
+// newTestSNSVerifier trusts a generated certificate for testCertificateURL and returns its key
+func newTestSNSVerifier(t *testing.T) (*SNSVerifier, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	verifier := NewSNSVerifier()
+	verifier.fetch = func(certificateURL string) (*x509.Certificate, error) {
+		if certificateURL != testCertificateURL {
+			return nil, fmt.Errorf("unexpected certificate URL %s", certificateURL)
+		}
+
+		return certificate, nil
+	}
+
+	return verifier, key
+}
+
+func signSNSMessage(t *testing.T, key *rsa.PrivateKey, message *SNSMessage) {
+	hash := sha256.Sum256([]byte(message.signedString()))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	require.NoError(t, err)
+
+	message.SignatureVersion = "2"
+	message.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestSNSVerifier_Verify_Suite(t *testing.T) {
+	verifier, key := newTestSNSVerifier(t)
+
+	newMessage := func() SNSMessage {
+		message := SNSMessage{
+			Type:           "Notification",
+			MessageId:      "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+			TopicArn:       "arn:aws:sns:us-east-1:123456789012:ses-feedback",
+			Message:        `{"notificationType":"Bounce"}`,
+			Timestamp:      "2025-04-19T09:00:00.000Z",
+			SigningCertURL: testCertificateURL,
+		}
+		signSNSMessage(t, key, &message)
+
+		return message
+	}
+
+	t.Run("Verify: signed notification", func(t *testing.T) {
+		assert.NoError(t, verifier.Verify(newMessage()))
+	})
+
+	t.Run("Verify: tampered message", func(t *testing.T) {
+		message := newMessage()
+		message.Message = `{"notificationType":"Complaint"}`
+
+		assert.ErrorIs(t, verifier.Verify(message), ErrInvalidWebhookSignature)
+	})
+
+	t.Run("Verify: certificate outside SNS", func(t *testing.T) {
+		for _, certificateURL := range []string{
+			"https://attacker.example.com/cert.pem",
+			"http://sns.us-east-1.amazonaws.com/cert.pem",
+			"https://sns.us-east-1.amazonaws.com.example.com/cert.pem",
+			"https://sns.us-east-1.amazonaws.com/cert.txt",
+		} {
+			message := newMessage()
+			message.SigningCertURL = certificateURL
+
+			assert.ErrorIs(t, verifier.Verify(message), ErrInvalidWebhookSignature, certificateURL)
+		}
+	})
+
+	t.Run("Verify: unsupported signature version", func(t *testing.T) {
+		message := newMessage()
+		message.SignatureVersion = "3"
+
+		assert.ErrorIs(t, verifier.Verify(message), ErrInvalidWebhookSignature)
+	})
+}
+
+func TestParseSESFeedback_Suite(t *testing.T) {
+	t.Run("ParseSESFeedback: permanent bounce", func(t *testing.T) {
+		feedback, err := ParseSESFeedback(`{
+			"notificationType": "Bounce",
+			"bounce": {
+				"bounceType": "Permanent",
+				"bounceSubType": "General",
+				"bouncedRecipients": [{"emailAddress": "jane@example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"}]
+			},
+			"mail": {"messageId": "0100018e-ses-id"}
+		}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Feedback{{
+			Type:              constants.EmailSuppressionReasonBounce,
+			Address:           "jane@example.com",
+			ProviderMessageID: "0100018e-ses-id",
+			Details:           "Permanent/General: smtp; 550 5.1.1 user unknown",
+		}}, feedback)
+	})
+
+	t.Run("ParseSESFeedback: transient bounce is ignored", func(t *testing.T) {
+		feedback, err := ParseSESFeedback(`{"eventType": "Bounce", "bounce": {"bounceType": "Transient", "bouncedRecipients": [{"emailAddress": "jane@example.com"}]}}`)
+		require.NoError(t, err)
+		assert.Empty(t, feedback)
+	})
+
+	t.Run("ParseSESFeedback: complaint from event publishing", func(t *testing.T) {
+		feedback, err := ParseSESFeedback(`{"eventType": "Complaint", "complaint": {"complaintFeedbackType": "abuse", "complainedRecipients": [{"emailAddress": "john@example.com"}]}}`)
+		require.NoError(t, err)
+
+		require.Len(t, feedback, 1)
+		assert.Equal(t, constants.EmailSuppressionReasonComplaint, feedback[0].Type)
+		assert.Equal(t, "john@example.com", feedback[0].Address)
+		assert.Equal(t, "abuse", feedback[0].Details)
+	})
+}
+
+func TestVerifySendGridSignature_Suite(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := base64.StdEncoding.EncodeToString(der)
+
+	payload := []byte(`[{"email":"jane@example.com","event":"bounce","type":"bounce","reason":"550 user unknown","sg_message_id":"abc123.filter0001.16.0"}]`)
+
+	sign := func(timestamp string) string {
+		hash := sha256.Sum256(append([]byte(timestamp), payload...))
+
+		signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+		require.NoError(t, err)
+
+		return base64.StdEncoding.EncodeToString(signature)
+	}
+
+	t.Run("VerifySendGridSignature: signed events", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		require.NoError(t, VerifySendGridSignature(publicKey, payload, sign(timestamp), timestamp))
+
+		feedback, err := ParseSendGridFeedback(payload)
+		require.NoError(t, err)
+		assert.Equal(t, []Feedback{{
+			Type:              constants.EmailSuppressionReasonBounce,
+			Address:           "jane@example.com",
+			ProviderMessageID: "abc123",
+			Details:           "550 user unknown",
+		}}, feedback)
+	})
+
+	t.Run("VerifySendGridSignature: signature of another timestamp", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		otherTimestamp := strconv.FormatInt(time.Now().Unix()-1, 10)
+
+		assert.ErrorIs(t, VerifySendGridSignature(publicKey, payload, sign(otherTimestamp), timestamp), ErrInvalidWebhookSignature)
+	})
+
+	t.Run("VerifySendGridSignature: replayed events", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+		assert.ErrorIs(t, VerifySendGridSignature(publicKey, payload, sign(timestamp), timestamp), ErrInvalidWebhookSignature)
+	})
+}
+
+func TestParseSendGridFeedback_SkipsBlockedAndDeliveries(t *testing.T) {
+	feedback, err := ParseSendGridFeedback([]byte(`[
+		{"email": "jane@example.com", "event": "bounce", "type": "blocked"},
+		{"email": "john@example.com", "event": "delivered"},
+		{"email": "mary@example.com", "event": "spamreport", "sg_message_id": "def456"}
+	]`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Feedback{{
+		Type:              constants.EmailSuppressionReasonComplaint,
+		Address:           "mary@example.com",
+		ProviderMessageID: "def456",
+	}}, feedback)
+}
+
+func TestParseMailgunFeedback_Suite(t *testing.T) {
+	signingKey := "key-test"
+
+	newPayload := func(timestamp, eventData string) []byte {
+		token := "c3d9f5d6e1a24b0e9f0c"
+
+		mac := hmac.New(sha256.New, []byte(signingKey))
+		mac.Write([]byte(timestamp + token))
+
+		return []byte(fmt.Sprintf(
+			`{"signature": {"timestamp": %q, "token": %q, "signature": %q}, "event-data": %s}`,
+			timestamp,
+			token,
+			hex.EncodeToString(mac.Sum(nil)),
+			eventData,
+		))
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	t.Run("ParseMailgunFeedback: permanent failure", func(t *testing.T) {
+		feedback, err := ParseMailgunFeedback(signingKey, newPayload(now, `{
+			"event": "failed",
+			"severity": "permanent",
+			"recipient": "jane@example.com",
+			"message": {"headers": {"message-id": "20250419.1@mg.fluxend.app"}},
+			"delivery-status": {"description": "No such user"}
+		}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []Feedback{{
+			Type:              constants.EmailSuppressionReasonBounce,
+			Address:           "jane@example.com",
+			ProviderMessageID: "<20250419.1@mg.fluxend.app>",
+			Details:           "No such user",
+		}}, feedback)
+	})
+
+	t.Run("ParseMailgunFeedback: temporary failure is ignored", func(t *testing.T) {
+		feedback, err := ParseMailgunFeedback(signingKey, newPayload(now, `{"event": "failed", "severity": "temporary", "recipient": "jane@example.com"}`))
+		require.NoError(t, err)
+		assert.Empty(t, feedback)
+	})
+
+	t.Run("ParseMailgunFeedback: signed with another key", func(t *testing.T) {
+		_, err := ParseMailgunFeedback("another-key", newPayload(now, `{"event": "complained", "recipient": "jane@example.com"}`))
+		assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+	})
+
+	t.Run("ParseMailgunFeedback: replayed event", func(t *testing.T) {
+		old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+		_, err := ParseMailgunFeedback(signingKey, newPayload(old, `{"event": "complained", "recipient": "jane@example.com"}`))
+		assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+	})
+}
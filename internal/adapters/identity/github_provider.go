@@ -0,0 +1,102 @@
+package identity
+
+import (
+	"context"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	githubAuthorizeURL = "https://github.com/login/oauth/authorize"
+	githubTokenURL     = "https://github.com/login/oauth/access_token"
+	githubAPIURL       = "https://api.github.com"
+)
+
+// GitHubProvider signs in with GitHub OAuth apps. GitHub isn't an OpenID Connect provider, the profile
+// comes from its API and the email from the verified primary address
+type GitHubProvider struct {
+	config       Config
+	client       *http.Client
+	authorizeURL string
+	tokenURL     string
+	apiURL       string
+}
+
+func newGitHubProvider(config Config, client *http.Client) *GitHubProvider {
+	config.Scopes = []string{"read:user", "user:email"}
+
+	return &GitHubProvider{
+		config:       config,
+		client:       client,
+		authorizeURL: githubAuthorizeURL,
+		tokenURL:     githubTokenURL,
+		apiURL:       githubAPIURL,
+	}
+}
+
+func (p *GitHubProvider) Name() string {
+	return constants.IdentityProviderGitHub
+}
+
+func (p *GitHubProvider) Label() string {
+	return "GitHub"
+}
+
+func (p *GitHubProvider) AuthCodeURL(_ context.Context, request AuthRequest) (string, error) {
+	request.Nonce = ""
+
+	return authCodeURL(p.authorizeURL, p.config, request, nil), nil
+}
+
+func (p *GitHubProvider) Exchange(ctx context.Context, request ExchangeRequest) (Profile, error) {
+	token, err := exchangeCode(ctx, p.client, p.tokenURL, p.config, request, false)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+
+	if err = getJSON(ctx, p.client, p.apiURL+"/user", token.AccessToken, &user); err != nil {
+		return Profile{}, err
+	}
+
+	if user.ID == 0 {
+		return Profile{}, fmt.Errorf("GitHub user has no ID")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+
+	if err = getJSON(ctx, p.client, p.apiURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return Profile{}, err
+	}
+
+	profile := Profile{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Username: user.Login,
+	}
+
+	for _, address := range emails {
+		if address.Primary {
+			profile.Email = strings.ToLower(address.Email)
+			profile.EmailVerified = address.Verified
+		}
+	}
+
+	if profile.EmailVerified {
+		profile.Domain = emailDomain(profile.Email)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,20 @@
+package identity
+
+import (
+	"fluxend/internal/config/constants"
+	"net/http"
+	"net/url"
+)
+
+const googleIssuer = "https://accounts.google.com"
+
+// newGoogleProvider signs in with Google accounts through OpenID Connect. The organization domain is only
+// taken from the hd claim, which Google sets for Workspace accounts: a personal account may have a verified
+// company address without belonging to the company
+func newGoogleProvider(config Config, client *http.Client, issuer *oidcIssuer) *OIDCProvider {
+	provider := newOIDCProvider(constants.IdentityProviderGoogle, "Google", config, client, issuer)
+	provider.authParams = url.Values{"prompt": {"select_account"}}
+	provider.trustEmailDomain = false
+
+	return provider
+}
@@ -0,0 +1,142 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const maxResponseSize = 1 << 20
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// authCodeURL adds the parameters of an authorization code request with PKCE to an authorization endpoint
+func authCodeURL(endpoint string, config Config, request AuthRequest, extra url.Values) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {config.ClientID},
+		"redirect_uri":          {request.RedirectURL},
+		"scope":                 {strings.Join(config.Scopes, " ")},
+		"state":                 {request.State},
+		"code_challenge":        {request.CodeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	if request.Nonce != "" {
+		query.Set("nonce", request.Nonce)
+	}
+
+	for name, values := range extra {
+		query[name] = values
+	}
+
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	return endpoint + separator + query.Encode()
+}
+
+// exchangeCode redeems an authorization code. The client authenticates with HTTP basic auth, as every
+// OpenID Connect provider supports, or with its secret in the form when basicAuth is off
+func exchangeCode(ctx context.Context, client *http.Client, tokenURL string, config Config, request ExchangeRequest, basicAuth bool) (tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {request.Code},
+		"redirect_uri":  {request.RedirectURL},
+		"code_verifier": {request.CodeVerifier},
+	}
+
+	if !basicAuth {
+		form.Set("client_id", config.ClientID)
+		form.Set("client_secret", config.ClientSecret)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpRequest.Header.Set("Accept", "application/json")
+
+	if basicAuth {
+		httpRequest.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+	}
+
+	var token tokenResponse
+	status, err := doJSON(client, httpRequest, &token)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("token request failed: %w", err)
+	}
+
+	// GitHub answers errors with a 200
+	if token.Error != "" {
+		return tokenResponse{}, fmt.Errorf("token request failed: %s %s", token.Error, token.ErrorDescription)
+	}
+
+	if status != http.StatusOK {
+		return tokenResponse{}, fmt.Errorf("token request failed with status %d", status)
+	}
+
+	if token.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("token response has no access token")
+	}
+
+	return token, nil
+}
+
+// getJSON fetches a resource, with an access token if one is given
+func getJSON(ctx context.Context, client *http.Client, resourceURL, accessToken string, target interface{}) error {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return err
+	}
+
+	httpRequest.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	status, err := doJSON(client, httpRequest, target)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", resourceURL, err)
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", resourceURL, status)
+	}
+
+	return nil
+}
+
+func doJSON(client *http.Client, request *http.Request, target interface{}) (int, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+
+	// Error statuses may come without a JSON body, the status is reported then
+	if err = json.Unmarshal(body, target); err != nil && response.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	return response.StatusCode, nil
+}
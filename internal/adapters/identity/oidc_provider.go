@@ -0,0 +1,381 @@
+package identity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// OIDCProvider signs users in with an OpenID Connect provider, whose endpoints and signing keys are found
+// through the discovery document of its issuer
+type OIDCProvider struct {
+	name       string
+	label      string
+	config     Config
+	client     *http.Client
+	issuer     *oidcIssuer
+	authParams url.Values // extra authorization parameters
+
+	// trustEmailDomain takes the domain of a verified email as the organization domain when the
+	// provider doesn't tell it with the hd claim
+	trustEmailDomain bool
+}
+
+func newOIDCProvider(name, label string, config Config, client *http.Client, issuer *oidcIssuer) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultOIDCScopes
+	}
+
+	if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+
+	if label == "" {
+		label = "SSO"
+	}
+
+	return &OIDCProvider{
+		name:             name,
+		label:            label,
+		config:           config,
+		client:           client,
+		issuer:           issuer,
+		trustEmailDomain: true,
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) Label() string {
+	return p.label
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, request AuthRequest) (string, error) {
+	document, err := p.issuer.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return authCodeURL(document.AuthorizationEndpoint, p.config, request, p.authParams), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, request ExchangeRequest) (Profile, error) {
+	document, err := p.issuer.discover(ctx)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	basicAuth := len(document.TokenEndpointAuthMethods) == 0 || slices.Contains(document.TokenEndpointAuthMethods, "client_secret_basic")
+
+	token, err := exchangeCode(ctx, p.client, document.TokenEndpoint, p.config, request, basicAuth)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if token.IDToken == "" {
+		return Profile{}, fmt.Errorf("token response has no ID token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, document, token.IDToken, request.Nonce)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	// Some providers leave the email out of the ID token, the user info endpoint has it then
+	if claims.Email == "" && document.UserinfoEndpoint != "" {
+		var userInfo idTokenClaims
+		if err = getJSON(ctx, p.client, document.UserinfoEndpoint, token.AccessToken, &userInfo); err != nil {
+			return Profile{}, err
+		}
+
+		if userInfo.Subject != claims.Subject {
+			return Profile{}, fmt.Errorf("user info is about another subject")
+		}
+
+		claims.Email, claims.EmailVerified = userInfo.Email, userInfo.EmailVerified
+	}
+
+	profile := Profile{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		Username:      claims.PreferredUsername,
+		Domain:        strings.ToLower(claims.HostedDomain),
+	}
+
+	if profile.Domain == "" && p.trustEmailDomain && profile.EmailVerified {
+		profile.Domain = emailDomain(profile.Email)
+	}
+
+	return profile, nil
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string       `json:"nonce"`
+	AuthorizedParty   string       `json:"azp"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	Name              string       `json:"name"`
+	PreferredUsername string       `json:"preferred_username"`
+	HostedDomain      string       `json:"hd"`
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, document discoveryDocument, rawToken, nonce string) (idTokenClaims, error) {
+	var claims idTokenClaims
+
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		return p.issuer.key(ctx, keyID)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(document.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(constants.IdentityClockSkew),
+	)
+	if err != nil {
+		return idTokenClaims{}, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return idTokenClaims{}, fmt.Errorf("invalid ID token: no subject")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return idTokenClaims{}, fmt.Errorf("invalid ID token: nonce mismatch")
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return idTokenClaims{}, fmt.Errorf("invalid ID token: issued to %q", claims.AuthorizedParty)
+	}
+
+	return claims, nil
+}
+
+// flexibleBool also reads the "true" strings some providers send for booleans
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch typed := value.(type) {
+	case bool:
+		*b = flexibleBool(typed)
+	case string:
+		*b = flexibleBool(strings.EqualFold(typed, "true"))
+	}
+
+	return nil
+}
+
+type discoveryDocument struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	UserinfoEndpoint         string   `json:"userinfo_endpoint"`
+	JWKSURI                  string   `json:"jwks_uri"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// oidcIssuer caches the discovery document and signing keys of an issuer
+type oidcIssuer struct {
+	url           string
+	client        *http.Client
+	mutex         sync.Mutex
+	document      discoveryDocument
+	discoveredAt  time.Time
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+func newOIDCIssuer(issuerURL string, client *http.Client) *oidcIssuer {
+	return &oidcIssuer{url: issuerURL, client: client}
+}
+
+func (i *oidcIssuer) discover(ctx context.Context) (discoveryDocument, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if !i.discoveredAt.IsZero() && time.Since(i.discoveredAt) < constants.IdentityDiscoveryTTL {
+		return i.document, nil
+	}
+
+	var document discoveryDocument
+	if err := getJSON(ctx, i.client, i.url+"/.well-known/openid-configuration", "", &document); err != nil {
+		return discoveryDocument{}, fmt.Errorf("discovery failed: %w", err)
+	}
+
+	// The issuer signs the ID tokens, a document naming another one isn't trusted
+	if strings.TrimRight(document.Issuer, "/") != i.url {
+		return discoveryDocument{}, fmt.Errorf("discovery failed: issuer %q doesn't match %q", document.Issuer, i.url)
+	}
+
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return discoveryDocument{}, fmt.Errorf("discovery failed: endpoints are missing")
+	}
+
+	keys, err := fetchKeys(ctx, i.client, document.JWKSURI)
+	if err != nil {
+		return discoveryDocument{}, err
+	}
+
+	i.document, i.discoveredAt = document, time.Now()
+	i.keys, i.keysFetchedAt = keys, time.Now()
+
+	return document, nil
+}
+
+// key finds a signing key by its ID. Keys rotate, an unknown ID fetches them again unless they were
+// just fetched, so made up IDs can't hammer the provider
+func (i *oidcIssuer) key(ctx context.Context, keyID string) (interface{}, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if key := i.lookup(keyID); key != nil {
+		return key, nil
+	}
+
+	if time.Since(i.keysFetchedAt) < constants.IdentityKeysRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	keys, err := fetchKeys(ctx, i.client, i.document.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	i.keys, i.keysFetchedAt = keys, time.Now()
+
+	if key := i.lookup(keyID); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+// lookup takes the only key when the token doesn't name one
+func (i *oidcIssuer) lookup(keyID string) interface{} {
+	if keyID == "" && len(i.keys) == 1 {
+		for _, key := range i.keys {
+			return key
+		}
+	}
+
+	return i.keys[keyID]
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func fetchKeys(ctx context.Context, client *http.Client, jwksURL string) (map[string]interface{}, error) {
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := getJSON(ctx, client, jwksURL, "", &keySet); err != nil {
+		return nil, fmt.Errorf("unable to fetch signing keys: %w", err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, webKey := range keySet.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		// Key types we can't verify with are skipped, a token signed with one fails as an unknown key
+		if key, err := webKey.publicKey(); err == nil {
+			keys[webKey.KeyID] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		modulus, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(exponent) == 0 || len(exponent) > 4 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func emailDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+
+	return strings.ToLower(address[at+1:])
+}
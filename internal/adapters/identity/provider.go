@@ -0,0 +1,126 @@
+package identity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/setting"
+	"fmt"
+	"github.com/samber/do"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrProviderNotConfigured is returned for a provider without a client ID, which keeps it off the login page
+var ErrProviderNotConfigured = errors.New("identity provider is not configured")
+
+// Providers lists the supported providers in the order the console shows them
+var Providers = []string{constants.IdentityProviderGoogle, constants.IdentityProviderGitHub, constants.IdentityProviderOIDC}
+
+// Provider signs users in with OAuth 2.0 authorization codes, protected by PKCE
+type Provider interface {
+	Name() string
+	Label() string
+	AuthCodeURL(ctx context.Context, request AuthRequest) (string, error)
+	Exchange(ctx context.Context, request ExchangeRequest) (Profile, error)
+}
+
+// AuthRequest starts a login. Nonce is only sent by OpenID Connect providers, which echo it in the ID token
+type AuthRequest struct {
+	RedirectURL   string
+	State         string
+	Nonce         string
+	CodeChallenge string
+}
+
+type ExchangeRequest struct {
+	RedirectURL  string
+	Code         string
+	CodeVerifier string
+	Nonce        string
+}
+
+// Profile is who the provider says the user is. Subject never changes for a user of a provider, unlike
+// the email. Domain is the organization domain the provider vouches for, if any
+type Profile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	Domain        string
+}
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+type Factory struct {
+	settingService setting.Service
+	client         *http.Client
+	mutex          sync.Mutex
+	issuers        map[string]*oidcIssuer // kept between logins, keyed by issuer URL
+}
+
+func NewFactory(injector *do.Injector) (*Factory, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+
+	return &Factory{
+		settingService: settingService,
+		client:         &http.Client{Timeout: constants.IdentityHTTPTimeout},
+		issuers:        map[string]*oidcIssuer{},
+	}, nil
+}
+
+// CreateProvider reads the settings of a provider, named after it: githubClientId, oidcIssuer and so on
+func (f *Factory) CreateProvider(name string) (Provider, error) {
+	config := Config{
+		ClientID:     f.settingService.GetValue(name + "ClientId"),
+		ClientSecret: f.settingService.GetValue(name + "ClientSecret"),
+	}
+
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, name)
+	}
+
+	switch name {
+	case constants.IdentityProviderGitHub:
+		return newGitHubProvider(config, f.client), nil
+	case constants.IdentityProviderGoogle:
+		return newGoogleProvider(config, f.client, f.issuer(googleIssuer)), nil
+	case constants.IdentityProviderOIDC:
+		issuerURL := strings.TrimRight(f.settingService.GetValue("oidcIssuer"), "/")
+		if issuerURL == "" {
+			return nil, fmt.Errorf("%w: oidcIssuer is required", ErrProviderNotConfigured)
+		}
+
+		config.Scopes = strings.Fields(strings.ReplaceAll(f.settingService.GetValue("oidcScopes"), ",", " "))
+
+		return newOIDCProvider(name, f.settingService.GetValue("oidcLabel"), config, f.client, f.issuer(issuerURL)), nil
+	default:
+		return nil, fmt.Errorf("unsupported identity provider: %s", name)
+	}
+}
+
+func (f *Factory) issuer(issuerURL string) *oidcIssuer {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.issuers[issuerURL]; !ok {
+		f.issuers[issuerURL] = newOIDCIssuer(issuerURL, f.client)
+	}
+
+	return f.issuers[issuerURL]
+}
+
+// CodeChallenge derives the S256 PKCE challenge sent with the authorization request from its verifier
+func CodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
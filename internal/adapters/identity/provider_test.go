@@ -0,0 +1,287 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fluxend/internal/config/constants"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOIDC is a local OpenID Connect provider issuing codes for whatever the test puts in claims
+type mockOIDC struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	issuer        string
+	claims        jwt.MapClaims
+	codeChallenge string
+	userInfo      map[string]interface{}
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock := &mockOIDC{key: key}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                 mock.issuer,
+			"authorization_endpoint": mock.server.URL + "/authorize",
+			"token_endpoint":         mock.server.URL + "/token",
+			"userinfo_endpoint":      mock.server.URL + "/userinfo",
+			"jwks_uri":               mock.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "fluxend" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		if r.PostFormValue("code") != "code-1" || CodeChallenge(r.PostFormValue("code_verifier")) != mock.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, mock.claims)
+		token.Header["kid"] = "key-1"
+
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		writeJSON(w, map[string]string{"access_token": "access-1", "token_type": "Bearer", "id_token": idToken})
+	})
+
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		writeJSON(w, mock.userInfo)
+	})
+
+	mock.server = httptest.NewServer(mux)
+	mock.issuer = mock.server.URL
+	t.Cleanup(mock.server.Close)
+
+	return mock
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func (m *mockOIDC) provider() *OIDCProvider {
+	return newOIDCProvider(constants.IdentityProviderOIDC, "", Config{ClientID: "fluxend", ClientSecret: "secret"}, m.server.Client(), newOIDCIssuer(m.server.URL, m.server.Client()))
+}
+
+// authorize starts a login the way the account service does and returns what the callback gets
+func (m *mockOIDC) authorize(t *testing.T, provider Provider) ExchangeRequest {
+	request := AuthRequest{
+		RedirectURL:   "https://api.fluxend.app/users/oauth/oidc/callback",
+		State:         "state-1",
+		Nonce:         "nonce-1",
+		CodeChallenge: CodeChallenge("verifier-1"),
+	}
+
+	authURL, err := provider.AuthCodeURL(context.Background(), request)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	query := parsed.Query()
+	assert.Equal(t, m.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "fluxend", query.Get("client_id"))
+	assert.Equal(t, request.RedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	m.codeChallenge = query.Get("code_challenge")
+
+	return ExchangeRequest{
+		RedirectURL:  request.RedirectURL,
+		Code:         "code-1",
+		CodeVerifier: "verifier-1",
+		Nonce:        query.Get("nonce"),
+	}
+}
+
+func (m *mockOIDC) idTokenClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            m.issuer,
+		"sub":            "248289761001",
+		"aud":            "fluxend",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce-1",
+		"email":          "Jane@Example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+}
+
+func TestOIDCProvider_Suite(t *testing.T) {
+	t.Run("OIDC: code exchanged for a verified profile", func(t *testing.T) {
+		mock := newMockOIDC(t)
+		mock.claims = mock.idTokenClaims()
+		provider := mock.provider()
+
+		request := mock.authorize(t, provider)
+		assert.Equal(t, "nonce-1", request.Nonce)
+
+		profile, err := provider.Exchange(context.Background(), request)
+		require.NoError(t, err)
+
+		assert.Equal(t, Profile{
+			Subject:       "248289761001",
+			Email:         "jane@example.com",
+			EmailVerified: true,
+			Name:          "Jane Doe",
+			Domain:        "example.com",
+		}, profile)
+	})
+
+	t.Run("OIDC: email read from the user info endpoint", func(t *testing.T) {
+		mock := newMockOIDC(t)
+		mock.claims = mock.idTokenClaims()
+		delete(mock.claims, "email")
+		delete(mock.claims, "email_verified")
+		mock.userInfo = map[string]interface{}{"sub": "248289761001", "email": "jane@example.com", "email_verified": "true"}
+		provider := mock.provider()
+
+		profile, err := provider.Exchange(context.Background(), mock.authorize(t, provider))
+		require.NoError(t, err)
+
+		assert.Equal(t, "jane@example.com", profile.Email)
+		assert.True(t, profile.EmailVerified)
+	})
+
+	t.Run("OIDC: ID tokens that don't check out are refused", func(t *testing.T) {
+		for name, change := range map[string]func(claims jwt.MapClaims){
+			"nonce":    func(claims jwt.MapClaims) { claims["nonce"] = "nonce-2" },
+			"audience": func(claims jwt.MapClaims) { claims["aud"] = "someone-else" },
+			"issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+			"expired":  func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			"azp": func(claims jwt.MapClaims) {
+				claims["aud"] = []string{"fluxend", "someone-else"}
+				claims["azp"] = "someone-else"
+			},
+		} {
+			mock := newMockOIDC(t)
+			mock.claims = mock.idTokenClaims()
+			change(mock.claims)
+			provider := mock.provider()
+
+			_, err := provider.Exchange(context.Background(), mock.authorize(t, provider))
+			assert.ErrorContains(t, err, "invalid ID token", name)
+		}
+	})
+
+	t.Run("OIDC: code verifier must match the challenge", func(t *testing.T) {
+		mock := newMockOIDC(t)
+		mock.claims = mock.idTokenClaims()
+		provider := mock.provider()
+
+		request := mock.authorize(t, provider)
+		request.CodeVerifier = "verifier-2"
+
+		_, err := provider.Exchange(context.Background(), request)
+		assert.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("OIDC: discovery naming another issuer is refused", func(t *testing.T) {
+		mock := newMockOIDC(t)
+		mock.issuer = "https://evil.example.com"
+
+		_, err := mock.provider().AuthCodeURL(context.Background(), AuthRequest{})
+		assert.ErrorContains(t, err, "doesn't match")
+	})
+
+	t.Run("Google: domain only from the hd claim", func(t *testing.T) {
+		mock := newMockOIDC(t)
+		mock.claims = mock.idTokenClaims()
+		provider := newGoogleProvider(Config{ClientID: "fluxend", ClientSecret: "secret"}, mock.server.Client(), newOIDCIssuer(mock.server.URL, mock.server.Client()))
+
+		profile, err := provider.Exchange(context.Background(), mock.authorize(t, provider))
+		require.NoError(t, err)
+		assert.Empty(t, profile.Domain)
+
+		mock.claims["hd"] = "Example.com"
+		profile, err = provider.Exchange(context.Background(), mock.authorize(t, provider))
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", profile.Domain)
+	})
+}
+
+func TestGitHubProvider_Exchange(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_secret") != "secret" || r.PostFormValue("code_verifier") != "verifier-1" {
+			writeJSON(w, map[string]string{"error": "bad_verification_code"})
+			return
+		}
+
+		writeJSON(w, map[string]string{"access_token": "access-1", "token_type": "bearer"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"id": 583231, "login": "octocat", "name": "The Octocat"})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]interface{}{
+			{"email": "octocat@users.noreply.github.com", "primary": false, "verified": true},
+			{"email": "OctoCat@GitHub.com", "primary": true, "verified": true},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := newGitHubProvider(Config{ClientID: "fluxend", ClientSecret: "secret"}, server.Client())
+	provider.tokenURL = server.URL + "/login/oauth/access_token"
+	provider.apiURL = server.URL
+
+	profile, err := provider.Exchange(context.Background(), ExchangeRequest{Code: "code-1", CodeVerifier: "verifier-1"})
+	require.NoError(t, err)
+	assert.Equal(t, Profile{
+		Subject:       "583231",
+		Email:         "octocat@github.com",
+		EmailVerified: true,
+		Name:          "The Octocat",
+		Username:      "octocat",
+		Domain:        "github.com",
+	}, profile)
+
+	_, err = provider.Exchange(context.Background(), ExchangeRequest{Code: "code-1", CodeVerifier: "verifier-2"})
+	assert.ErrorContains(t, err, "bad_verification_code")
+}
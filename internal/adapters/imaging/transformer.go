@@ -0,0 +1,187 @@
+package imaging
+
+import (
+	"bytes"
+	"fluxend/internal/config/constants"
+	"fluxend/pkg/errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "image/gif"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWEBP = "webp"
+
+	FitCover   = "cover"   // scale to fill the box and crop the overflow
+	FitContain = "contain" // scale to fit inside the box, keeping the aspect ratio
+	FitFill    = "fill"    // stretch to the exact box
+
+	DefaultQuality = 80
+
+	// Guards against decompression bombs, a small file can declare a huge canvas
+	maxSourcePixels = 50_000_000
+)
+
+// SupportedMimeTypes maps the image types that can be decoded to their default output format
+var SupportedMimeTypes = map[string]string{
+	"image/jpeg": FormatJPEG,
+	"image/png":  FormatPNG,
+	"image/gif":  FormatPNG,
+	"image/webp": FormatWEBP,
+}
+
+type TransformOptions struct {
+	Width   int
+	Height  int
+	Fit     string
+	Crop    *image.Rectangle // applied to the source before resizing
+	Format  string
+	Quality int // only used by jpeg, webp is always encoded lossless
+}
+
+// Key identifies the variant produced by the options, equal options give equal keys
+func (o TransformOptions) Key() string {
+	crop := ""
+	if o.Crop != nil {
+		crop = fmt.Sprintf("%d,%d,%d,%d", o.Crop.Min.X, o.Crop.Min.Y, o.Crop.Dx(), o.Crop.Dy())
+	}
+
+	return fmt.Sprintf("w=%d,h=%d,fit=%s,crop=%s,format=%s,q=%d", o.Width, o.Height, o.Fit, crop, o.Format, o.Quality)
+}
+
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+func Extension(format string) string {
+	if format == FormatJPEG {
+		return "jpg"
+	}
+
+	return format
+}
+
+// Transform decodes the source image, applies crop and resize and encodes it in the requested format
+func Transform(source io.Reader, options TransformOptions) ([]byte, error) {
+	sourceBytes, err := io.ReadAll(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image: %w", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(sourceBytes))
+	if err != nil {
+		return nil, errors.NewUnprocessableError("image.error.unsupported")
+	}
+
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, errors.NewUnprocessableError("image.error.tooLarge")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(sourceBytes))
+	if err != nil {
+		return nil, errors.NewUnprocessableError("image.error.unsupported")
+	}
+
+	if options.Crop != nil {
+		cropped := options.Crop.Add(img.Bounds().Min).Intersect(img.Bounds())
+		if cropped.Empty() {
+			return nil, errors.NewBadRequestError("image.error.cropOutOfBounds")
+		}
+
+		img = subImage(img, cropped)
+	}
+
+	img = resize(img, options)
+
+	var output bytes.Buffer
+	switch options.Format {
+	case FormatJPEG:
+		err = jpeg.Encode(&output, img, &jpeg.Options{Quality: options.Quality})
+	case FormatPNG:
+		err = png.Encode(&output, img)
+	case FormatWEBP:
+		err = nativewebp.Encode(&output, img, nil)
+	default:
+		return nil, errors.NewBadRequestError("image.error.unsupportedFormat")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode image: %w", err)
+	}
+
+	return output.Bytes(), nil
+}
+
+func subImage(img image.Image, rectangle image.Rectangle) image.Image {
+	if croppable, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return croppable.SubImage(rectangle)
+	}
+
+	cropped := image.NewRGBA(image.Rect(0, 0, rectangle.Dx(), rectangle.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, rectangle.Min, draw.Src)
+
+	return cropped
+}
+
+func resize(img image.Image, options TransformOptions) image.Image {
+	sourceWidth, sourceHeight := img.Bounds().Dx(), img.Bounds().Dy()
+	if options.Width == 0 && options.Height == 0 {
+		return img
+	}
+
+	// A missing dimension follows the aspect ratio of the source
+	width, height := options.Width, options.Height
+	if width == 0 {
+		width = max(1, sourceWidth*height/sourceHeight)
+	}
+	if height == 0 {
+		height = max(1, sourceHeight*width/sourceWidth)
+	}
+
+	sourceRectangle := img.Bounds()
+	switch options.Fit {
+	case FitContain:
+		scale := min(float64(width)/float64(sourceWidth), float64(height)/float64(sourceHeight))
+		width = max(1, int(float64(sourceWidth)*scale))
+		height = max(1, int(float64(sourceHeight)*scale))
+	case FitCover:
+		// Keep the centre of the source with the aspect ratio of the box
+		scale := max(float64(width)/float64(sourceWidth), float64(height)/float64(sourceHeight))
+		croppedWidth := min(sourceWidth, int(float64(width)/scale))
+		croppedHeight := min(sourceHeight, int(float64(height)/scale))
+		offset := image.Pt((sourceWidth-croppedWidth)/2, (sourceHeight-croppedHeight)/2)
+		sourceRectangle = image.Rectangle{Min: sourceRectangle.Min.Add(offset), Max: sourceRectangle.Min.Add(offset).Add(image.Pt(croppedWidth, croppedHeight))}
+	}
+
+	// A derived dimension of a very narrow or tall source can exceed the limit, scale both down together
+	width, height = clampDimensions(width, height, constants.MaxImageTransformDimension)
+
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(resized, resized.Bounds(), img, sourceRectangle, xdraw.Src, nil)
+
+	return resized
+}
+
+func clampDimensions(width, height, limit int) (int, int) {
+	if width <= limit && height <= limit {
+		return width, height
+	}
+
+	if width >= height {
+		return limit, max(1, height*limit/width)
+	}
+
+	return max(1, width*limit/height), limit
+}
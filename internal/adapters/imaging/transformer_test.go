@@ -0,0 +1,95 @@
+package imaging
+
+import (
+	"bytes"
+	"fluxend/internal/config/constants"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, img))
+
+	return buffer.Bytes()
+}
+
+func decodedSize(t *testing.T, encoded []byte) (string, int, int) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(encoded))
+	require.NoError(t, err)
+
+	return format, config.Width, config.Height
+}
+
+func TestTransform(t *testing.T) {
+	source := testImage(t, 200, 100)
+
+	tests := []struct {
+		name           string
+		options        TransformOptions
+		expectedFormat string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"format only", TransformOptions{Format: FormatJPEG, Quality: DefaultQuality}, "jpeg", 200, 100},
+		{"width keeps aspect ratio", TransformOptions{Width: 50, Fit: FitContain, Format: FormatPNG}, "png", 50, 25},
+		{"contain fits inside box", TransformOptions{Width: 50, Height: 50, Fit: FitContain, Format: FormatPNG}, "png", 50, 25},
+		{"cover fills box", TransformOptions{Width: 50, Height: 50, Fit: FitCover, Format: FormatPNG}, "png", 50, 50},
+		{"fill stretches", TransformOptions{Width: 30, Height: 60, Fit: FitFill, Format: FormatPNG}, "png", 30, 60},
+		{"crop before resize", TransformOptions{Crop: &image.Rectangle{Min: image.Pt(10, 10), Max: image.Pt(50, 30)}, Format: FormatPNG}, "png", 40, 20},
+		{"webp output", TransformOptions{Width: 20, Fit: FitContain, Format: FormatWEBP}, "webp", 20, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Transform(bytes.NewReader(source), tt.options)
+			require.NoError(t, err)
+
+			format, width, height := decodedSize(t, output)
+			assert.Equal(t, tt.expectedFormat, format)
+			assert.Equal(t, tt.expectedWidth, width)
+			assert.Equal(t, tt.expectedHeight, height)
+		})
+	}
+
+	t.Run("crop outside the image", func(t *testing.T) {
+		crop := image.Rect(300, 300, 310, 310)
+		_, err := Transform(bytes.NewReader(source), TransformOptions{Crop: &crop, Format: FormatPNG})
+		assert.EqualError(t, err, "image.error.cropOutOfBounds")
+	})
+
+	t.Run("derived dimension is clamped", func(t *testing.T) {
+		tall := testImage(t, 4, 1000)
+		output, err := Transform(bytes.NewReader(tall), TransformOptions{Width: constants.MaxImageTransformDimension, Fit: FitContain, Format: FormatPNG})
+		require.NoError(t, err)
+
+		_, width, height := decodedSize(t, output)
+		assert.Equal(t, 16, width)
+		assert.Equal(t, constants.MaxImageTransformDimension, height)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := Transform(bytes.NewReader([]byte("plain text")), TransformOptions{Format: FormatPNG})
+		assert.EqualError(t, err, "image.error.unsupported")
+	})
+}
+
+func TestTransformOptions_Key(t *testing.T) {
+	crop := image.Rect(1, 2, 11, 22)
+	options := TransformOptions{Width: 10, Height: 20, Fit: FitCover, Crop: &crop, Format: FormatWEBP, Quality: 75}
+
+	assert.Equal(t, "w=10,h=20,fit=cover,crop=1,2,10,20,format=webp,q=75", options.Key())
+	assert.NotEqual(t, options.Key(), TransformOptions{Width: 10, Format: FormatWEBP}.Key())
+}
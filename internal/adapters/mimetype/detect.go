@@ -0,0 +1,145 @@
+package mimetype
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffableTypes are the types http.DetectContentType recognises, content declared as one of them but not
+// detected as such isn't what the client claims
+var sniffableTypes = map[string]bool{
+	"application/ogg":               true,
+	"application/pdf":               true,
+	"application/postscript":        true,
+	"application/vnd.ms-fontobject": true,
+	"application/wasm":              true,
+	"application/x-gzip":            true,
+	"application/x-rar-compressed":  true,
+	"application/zip":               true,
+	"audio/aiff":                    true,
+	"audio/basic":                   true,
+	"audio/midi":                    true,
+	"audio/mpeg":                    true,
+	"audio/wave":                    true,
+	"font/collection":               true,
+	"font/otf":                      true,
+	"font/ttf":                      true,
+	"font/woff":                     true,
+	"font/woff2":                    true,
+	"image/bmp":                     true,
+	"image/gif":                     true,
+	"image/jpeg":                    true,
+	"image/png":                     true,
+	"image/webp":                    true,
+	"image/x-icon":                  true,
+	"text/html":                     true,
+	"video/avi":                     true,
+	"video/mp4":                     true,
+	"video/webm":                    true,
+}
+
+// zipBasedPrefixes are formats stored as zip archives, which the sniffer can only tell apart as zip
+var zipBasedPrefixes = []string{
+	"application/vnd.openxmlformats-officedocument.",
+	"application/vnd.oasis.opendocument.",
+	"application/vnd.android.package-archive",
+	"application/java-archive",
+	"application/epub+zip",
+}
+
+// Detect works out the MIME type of content from its first bytes. The declared type is only kept when the
+// sniffer can't tell it apart from what it found, e.g. JSON sniffed as plain text or a spreadsheet sniffed as zip
+func Detect(head []byte, declared string) string {
+	sniffed := mediaType(http.DetectContentType(head))
+	declared = mediaType(declared)
+
+	if declared == "" || declared == sniffed {
+		return sniffed
+	}
+
+	switch sniffed {
+	case "text/plain", "text/xml":
+		if isTextual(declared) {
+			return declared
+		}
+	case "application/zip":
+		if isZipBased(declared) {
+			return declared
+		}
+	case "application/octet-stream":
+		if !sniffableTypes[declared] && !isTextual(declared) {
+			return declared
+		}
+	}
+
+	return sniffed
+}
+
+// Allowed tells whether a MIME type matches one of the allowed entries, which are either extensions like
+// "jpg", MIME types like "image/png" or wildcards like "image/*". An empty list allows everything
+func Allowed(mimeType string, allowed []string) bool {
+	mimeType = mediaType(mimeType)
+
+	hasEntries := false
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		hasEntries = true
+
+		switch {
+		case entry == "*" || entry == "*/*":
+			return true
+		case strings.HasSuffix(entry, "/*"):
+			if strings.HasPrefix(mimeType, strings.TrimSuffix(entry, "*")) {
+				return true
+			}
+		case strings.Contains(entry, "/"):
+			if mimeType == entry {
+				return true
+			}
+		default:
+			if mediaType(mime.TypeByExtension("."+strings.TrimPrefix(entry, "."))) == mimeType {
+				return true
+			}
+		}
+	}
+
+	return !hasEntries
+}
+
+func isTextual(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") || strings.HasSuffix(mimeType, "+xml") || strings.HasSuffix(mimeType, "+json") {
+		return true
+	}
+
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript", "application/yaml", "application/x-yaml":
+		return true
+	}
+
+	return false
+}
+
+func isZipBased(mimeType string) bool {
+	for _, prefix := range zipBasedPrefixes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mediaType strips parameters like the charset and lowercases the type
+func mediaType(mimeType string) string {
+	parsed, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	}
+
+	return parsed
+}
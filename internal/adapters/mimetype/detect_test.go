@@ -0,0 +1,75 @@
+package mimetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	pngHead = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+	pdfHead = []byte("%PDF-1.7\n")
+	zipHead = []byte("PK\x03\x04\x14\x00\x00\x00")
+	exeHead = []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00")
+)
+
+func TestDetect(t *testing.T) {
+	t.Run("content wins over the declared type", func(t *testing.T) {
+		assert.Equal(t, "application/pdf", Detect(pdfHead, "image/png"))
+		assert.Equal(t, "image/png", Detect(pngHead, "application/pdf"))
+	})
+
+	t.Run("missing declared type", func(t *testing.T) {
+		assert.Equal(t, "image/png", Detect(pngHead, ""))
+	})
+
+	t.Run("binary content can't claim a sniffable type", func(t *testing.T) {
+		assert.Equal(t, "application/octet-stream", Detect(exeHead, "image/jpeg"))
+		assert.Equal(t, "application/octet-stream", Detect(exeHead, "text/csv"))
+	})
+
+	t.Run("binary content keeps a type the sniffer doesn't know", func(t *testing.T) {
+		assert.Equal(t, "image/heic", Detect(exeHead, "image/heic"))
+	})
+
+	t.Run("text keeps a textual declared type", func(t *testing.T) {
+		assert.Equal(t, "application/json", Detect([]byte(`{"key": "value"}`), "application/json; charset=utf-8"))
+		assert.Equal(t, "text/csv", Detect([]byte("a,b,c\n1,2,3\n"), "text/csv"))
+		assert.Equal(t, "text/plain", Detect([]byte("plain text"), "image/png"))
+	})
+
+	t.Run("zip keeps a zip based declared type", func(t *testing.T) {
+		docx := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+		assert.Equal(t, docx, Detect(zipHead, docx))
+		assert.Equal(t, "application/zip", Detect(zipHead, "application/pdf"))
+	})
+}
+
+func TestAllowed(t *testing.T) {
+	t.Run("empty list allows everything", func(t *testing.T) {
+		assert.True(t, Allowed("application/x-msdownload", nil))
+		assert.True(t, Allowed("application/x-msdownload", []string{"", " "}))
+	})
+
+	t.Run("extensions", func(t *testing.T) {
+		allowed := []string{"jpg", "png", "pdf"}
+
+		assert.True(t, Allowed("image/jpeg", allowed))
+		assert.True(t, Allowed("application/pdf", allowed))
+		assert.False(t, Allowed("image/gif", allowed))
+		assert.False(t, Allowed("application/octet-stream", allowed))
+	})
+
+	t.Run("mime types and wildcards", func(t *testing.T) {
+		allowed := []string{"application/pdf", "image/*"}
+
+		assert.True(t, Allowed("image/webp", allowed))
+		assert.True(t, Allowed("application/pdf", allowed))
+		assert.False(t, Allowed("text/plain", allowed))
+	})
+
+	t.Run("everything", func(t *testing.T) {
+		assert.True(t, Allowed("application/x-msdownload", []string{"*"}))
+	})
+}
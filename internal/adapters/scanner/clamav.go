@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fluxend/internal/config/constants"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// ClamAVScanner streams content to clamd with the INSTREAM command, see clamd(8). CLAMAV_SOCKET is the path
+// of its local socket, or tcp://host:port when clamd runs in another container
+type ClamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+func NewClamAVScanner() *ClamAVScanner {
+	address := os.Getenv("CLAMAV_SOCKET")
+	if address == "" {
+		address = constants.ClamAVDefaultSocket
+	}
+
+	if strings.HasPrefix(address, "tcp://") {
+		return &ClamAVScanner{network: "tcp", address: strings.TrimPrefix(address, "tcp://"), timeout: constants.ClamAVTimeout}
+	}
+
+	return &ClamAVScanner{network: "unix", address: address, timeout: constants.ClamAVTimeout}
+}
+
+func (s *ClamAVScanner) Name() string {
+	return constants.ScannerClamAV
+}
+
+func (s *ClamAVScanner) Scan(content io.Reader) (Result, error) {
+	conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return Result{}, err
+	}
+
+	// The z prefix makes clamd expect and answer with null terminated lines
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("failed to start clamd stream: %w", err)
+	}
+
+	if err = s.stream(conn, content); err != nil {
+		return Result{}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Result{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// stream sends content in chunks prefixed by their length, a zero length chunk ends it
+func (s *ClamAVScanner) stream(conn net.Conn, content io.Reader) error {
+	chunk := make([]byte, constants.ClamAVChunkSize)
+	size := make([]byte, 4)
+
+	for {
+		n, readErr := content.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf("failed to stream to clamd: %w", err)
+			}
+
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				return fmt.Errorf("failed to stream to clamd: %w", err)
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return fmt.Errorf("failed to end clamd stream: %w", err)
+	}
+
+	return nil
+}
+
+// parseClamAVReply reads "stream: OK", "stream: <signature> FOUND" or "<reason> ERROR"
+func parseClamAVReply(reply string) (Result, error) {
+	switch {
+	case strings.HasSuffix(reply, " OK"):
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		signature = strings.TrimSpace(strings.TrimPrefix(signature, "stream:"))
+
+		return Result{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return Result{}, fmt.Errorf("clamd failed to scan: %s", strings.TrimSuffix(reply, " ERROR"))
+	default:
+		return Result{}, fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+}
@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeClamd answers INSTREAM commands, flagging any stream containing the word EICAR
+func startFakeClamd(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "clamd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "clamd.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveFakeClamd(conn)
+		}
+	}()
+
+	return socketPath
+}
+
+func serveFakeClamd(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	command, err := reader.ReadString('\x00')
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND ERROR\x00"))
+		return
+	}
+
+	var content bytes.Buffer
+	for {
+		var size uint32
+		if err = binary.Read(reader, binary.BigEndian, &size); err != nil {
+			return
+		}
+
+		if size == 0 {
+			break
+		}
+
+		if _, err = io.CopyN(&content, reader, int64(size)); err != nil {
+			return
+		}
+	}
+
+	if strings.Contains(content.String(), "EICAR") {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamAVScanner(t *testing.T) {
+	t.Setenv("CLAMAV_SOCKET", startFakeClamd(t))
+	clamAVScanner := NewClamAVScanner()
+
+	t.Run("clean content", func(t *testing.T) {
+		result, err := clamAVScanner.Scan(strings.NewReader("quarterly summary"))
+		assert.NoError(t, err)
+		assert.False(t, result.Infected)
+	})
+
+	t.Run("infected content spread over chunks", func(t *testing.T) {
+		content := strings.Repeat("x", 200*1024) + "EICAR"
+
+		result, err := clamAVScanner.Scan(strings.NewReader(content))
+		assert.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+	})
+
+	t.Run("unreachable clamd", func(t *testing.T) {
+		t.Setenv("CLAMAV_SOCKET", filepath.Join(os.TempDir(), "missing-clamd.sock"))
+
+		_, err := NewClamAVScanner().Scan(strings.NewReader("content"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPipeline(t *testing.T) {
+	t.Setenv("CLAMAV_SOCKET", startFakeClamd(t))
+
+	t.Run("empty pipeline scans nothing", func(t *testing.T) {
+		pipeline, err := NewPipeline(" ")
+		require.NoError(t, err)
+		assert.False(t, pipeline.Enabled())
+	})
+
+	t.Run("unknown scanner", func(t *testing.T) {
+		_, err := NewPipeline("clamav,virustotal")
+		assert.EqualError(t, err, `unknown scanner "virustotal"`)
+	})
+
+	t.Run("rewinds content and names the flagging scanner", func(t *testing.T) {
+		pipeline, err := NewPipeline("clamav, ClamAV")
+		require.NoError(t, err)
+
+		content := strings.NewReader("EICAR")
+		content.Seek(0, io.SeekEnd)
+
+		result, err := pipeline.Scan(content)
+		assert.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "clamav", result.Scanner)
+	})
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	_, err := parseClamAVReply("INSTREAM size limit exceeded. ERROR")
+	assert.EqualError(t, err, "clamd failed to scan: INSTREAM size limit exceeded.")
+
+	_, err = parseClamAVReply("garbage")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"fluxend/internal/config/constants"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Result is the verdict on some content, Signature names what an infected file was flagged for
+type Result struct {
+	Infected  bool
+	Signature string
+	Scanner   string
+}
+
+type Scanner interface {
+	Name() string
+	Scan(content io.Reader) (Result, error)
+}
+
+// Pipeline runs its scanners one after the other and stops at the first one flagging the content
+type Pipeline struct {
+	scanners []Scanner
+}
+
+// NewPipeline builds a pipeline from a comma separated list of scanner names, an empty list scans nothing
+func NewPipeline(names string) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		currentScanner, err := New(name)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline.scanners = append(pipeline.scanners, currentScanner)
+	}
+
+	return pipeline, nil
+}
+
+func New(name string) (Scanner, error) {
+	switch name {
+	case constants.ScannerClamAV:
+		return NewClamAVScanner(), nil
+	default:
+		return nil, fmt.Errorf("unknown scanner %q", name)
+	}
+}
+
+func (p *Pipeline) Enabled() bool {
+	return len(p.scanners) > 0
+}
+
+// Scan rewinds the content for every scanner, any scanner failing fails the scan so nothing goes through unchecked
+func (p *Pipeline) Scan(content io.ReadSeeker) (Result, error) {
+	for _, currentScanner := range p.scanners {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return Result{}, err
+		}
+
+		result, err := currentScanner.Scan(content)
+		if err != nil {
+			return Result{}, fmt.Errorf("%s: %w", currentScanner.Name(), err)
+		}
+
+		if result.Infected {
+			result.Scanner = currentScanner.Name()
+
+			return result, nil
+		}
+	}
+
+	return Result{}, nil
+}
@@ -1,21 +1,32 @@
 package storage
 
 import (
+	"bytes"
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fluxend/pkg/errors"
 	"fmt"
 	"github.com/samber/do"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/guregu/null/v6"
 )
 
+const (
+	// B2 requires parts of at least 5MB and allows up to 10,000 parts per large file
+	backblazeMinimumPartSize = 16 * 1024 * 1024
+	backblazeMaximumParts    = 10000
+)
+
 func NewBackblazeProvider(injector *do.Injector) (Provider, error) {
 	applicationKeyID := os.Getenv("BACKBLAZE_KEY_ID")
 	applicationKey := os.Getenv("BACKBLAZE_APPLICATION_KEY")
@@ -24,11 +35,19 @@ func NewBackblazeProvider(injector *do.Injector) (Provider, error) {
 		return nil, fmt.Errorf("backblaze credentials not found in environment variables")
 	}
 
+	return NewBackblazeService(Credentials{KeyID: applicationKeyID, ApplicationKey: applicationKey})
+}
+
+func NewBackblazeService(storageCredentials Credentials) (Provider, error) {
+	applicationKeyID := storageCredentials.KeyID
+	applicationKey := storageCredentials.ApplicationKey
+
 	service := &BackblazeServiceImpl{
 		apiBase: "https://api.backblazeb2.com/b2api/v2",
 		httpClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		transferClient: &http.Client{},
 	}
 
 	// Authorize the account first
@@ -271,26 +290,37 @@ func (b *BackblazeServiceImpl) UploadFile(input UploadFileInput) error {
 
 	bucketID := containerMetadata.Identifier
 
+	partSize := partSizeFor(input.ContentLength, backblazeMinimumPartSize, backblazeMaximumParts)
+	if input.ContentLength > partSize {
+		return b.uploadLargeFile(bucketID, input, partSize)
+	}
+
 	// Get an upload URL
 	uploadURLResponse, err := b.getUploadURL(bucketID)
 	if err != nil {
 		return fmt.Errorf("unable to get upload URL: %w", err)
 	}
 
+	// The SHA1 is computed while streaming and appended after the file contents
+	hasher := sha1.New()
+	body := io.MultiReader(io.TeeReader(input.Body, hasher), &sha1SuffixReader{hash: hasher})
+
 	// Create a request to upload the file
-	req, err := http.NewRequest("POST", uploadURLResponse.UploadURL, strings.NewReader(string(input.FileBytes)))
+	req, err := http.NewRequest("POST", uploadURLResponse.UploadURL, body)
 	if err != nil {
 		return fmt.Errorf("error creating upload request: %w", err)
 	}
 
+	req.ContentLength = input.ContentLength + sha1.Size*2
+
 	// Add required headers
 	req.Header.Add("Authorization", uploadURLResponse.AuthorizationToken)
 	req.Header.Add("X-Bz-File-Name", url.QueryEscape(input.FileName))
-	req.Header.Add("Content-Type", "application/octet-stream")
-	req.Header.Add("X-Bz-Content-Sha1", "do_not_verify") // In production, calculate and verify SHA1
+	req.Header.Add("Content-Type", b.contentType(input.ContentType))
+	req.Header.Add("X-Bz-Content-Sha1", "hex_digits_at_end")
 
 	// Make the request
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.transferClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("error uploading file: %w", err)
 	}
@@ -304,31 +334,108 @@ func (b *BackblazeServiceImpl) UploadFile(input UploadFileInput) error {
 	return nil
 }
 
+func (b *BackblazeServiceImpl) uploadLargeFile(bucketID string, input UploadFileInput, partSize int64) error {
+	var startResponse B2FileMetadata
+	err := b.makeJSONRequest("/b2_start_large_file", B2StartLargeFileRequest{
+		BucketID:    bucketID,
+		FileName:    input.FileName,
+		ContentType: b.contentType(input.ContentType),
+	}, &startResponse)
+	if err != nil {
+		return fmt.Errorf("unable to start large file upload: %w", err)
+	}
+
+	partHashes, err := b.uploadParts(startResponse.FileID, input, partSize)
+	if err != nil {
+		// Cancel so B2 doesn't keep (and bill for) the parts uploaded so far
+		_ = b.makeJSONRequest("/b2_cancel_large_file", B2LargeFileRequest{FileID: startResponse.FileID}, nil)
+
+		return err
+	}
+
+	err = b.makeJSONRequest("/b2_finish_large_file", B2FinishLargeFileRequest{
+		FileID:        startResponse.FileID,
+		PartSha1Array: partHashes,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("unable to finish large file upload: %w", err)
+	}
+
+	return nil
+}
+
+func (b *BackblazeServiceImpl) uploadParts(fileID string, input UploadFileInput, partSize int64) ([]string, error) {
+	var uploadPartURL B2GetUploadPartURLResponse
+	if err := b.makeJSONRequest("/b2_get_upload_part_url", B2LargeFileRequest{FileID: fileID}, &uploadPartURL); err != nil {
+		return nil, fmt.Errorf("unable to get upload part URL: %w", err)
+	}
+
+	var partHashes []string
+	for partNumber, offset := 1, int64(0); offset < input.ContentLength; partNumber++ {
+		currentPartSize := min(partSize, input.ContentLength-offset)
+
+		part, err := readPart(input.Body, currentPartSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read part %d: %w", partNumber, err)
+		}
+
+		partHash := sha1.Sum(part)
+		partHashes = append(partHashes, hex.EncodeToString(partHash[:]))
+
+		req, err := http.NewRequest("POST", uploadPartURL.UploadURL, bytes.NewReader(part))
+		if err != nil {
+			return nil, fmt.Errorf("error creating upload part request: %w", err)
+		}
+
+		req.Header.Add("Authorization", uploadPartURL.AuthorizationToken)
+		req.Header.Add("X-Bz-Part-Number", strconv.Itoa(partNumber))
+		req.Header.Add("X-Bz-Content-Sha1", partHashes[len(partHashes)-1])
+
+		resp, err := b.transferClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading part %d: %w", partNumber, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("upload of part %d failed with status %d: %s", partNumber, resp.StatusCode, string(bodyBytes))
+		}
+		resp.Body.Close()
+
+		offset += currentPartSize
+	}
+
+	return partHashes, nil
+}
+
 func (b *BackblazeServiceImpl) RenameFile(input RenameFileInput) error {
 	// In B2, renaming a file requires copying it to a new name and then deleting the original
-	// First, download the file
-	fileInput := FileInput{
-		ContainerName: input.ContainerName,
-		FileName:      input.FileName,
+	containerMetadata, err := b.ShowContainer(input.ContainerName)
+	if err != nil {
+		return err
 	}
 
-	fileData, err := b.DownloadFile(fileInput)
+	fileID, err := b.getFileID(containerMetadata.Identifier, input.FileName)
 	if err != nil {
-		return fmt.Errorf("unable to download file for rename: %w", err)
+		return fmt.Errorf("unable to get file ID: %w", err)
 	}
 
-	// Upload with the new name
-	uploadInput := UploadFileInput{
-		ContainerName: input.ContainerName,
-		FileName:      input.NewFileName,
-		FileBytes:     fileData,
+	// The copy happens server side, so the file never passes through the API
+	err = b.makeJSONRequest("/b2_copy_file", B2CopyFileRequest{
+		SourceFileID: fileID,
+		FileName:     input.NewFileName,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("unable to copy file with new name: %w", b.transformError(err))
 	}
 
-	if err := b.UploadFile(uploadInput); err != nil {
-		return fmt.Errorf("unable to upload file with new name: %w", err)
+	// Delete the original file
+	fileInput := FileInput{
+		ContainerName: input.ContainerName,
+		FileName:      input.FileName,
 	}
 
-	// Delete the original file
 	if err := b.DeleteFile(fileInput); err != nil {
 		return fmt.Errorf("unable to delete original file after rename: %w", err)
 	}
@@ -341,21 +448,7 @@ func (d *BackblazeServiceImpl) CreatePresignedURL(input FileInput, expiration ti
 	return "", nil
 }
 
-func (b *BackblazeServiceImpl) DownloadFile(input FileInput) ([]byte, error) {
-	// Get the file info
-	containerMetadata, err := b.ShowContainer(input.ContainerName)
-	if err != nil {
-		return nil, err
-	}
-
-	bucketID := containerMetadata.Identifier
-
-	// First, get the file ID
-	_, err = b.getFileID(bucketID, input.FileName)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get file ID: %w", err)
-	}
-
+func (b *BackblazeServiceImpl) DownloadFile(input DownloadFileInput) (*FileObject, error) {
 	// Construct the download URL
 	downloadURL := fmt.Sprintf("%s/file/%s/%s", b.downloadURL, input.ContainerName, url.QueryEscape(input.FileName))
 
@@ -367,26 +460,46 @@ func (b *BackblazeServiceImpl) DownloadFile(input FileInput) ([]byte, error) {
 
 	// Add authorization
 	req.Header.Add("Authorization", b.authorizationToken)
+	if input.Range != nil {
+		req.Header.Add("Range", input.Range.HeaderValue())
+	}
 
 	// Make the request
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.transferClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error downloading file: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return nil, ErrInvalidRange
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NewNotFoundError("backblaze.error.fileNotFound")
+	default:
+		defer resp.Body.Close()
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("download failed with status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	// Read the file content
-	fileBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("unable to read file content: %w", err)
+	fileObject := &FileObject{
+		Body:          resp.Body,
+		ContentLength: resp.ContentLength,
+		TotalSize:     resp.ContentLength,
+		ContentType:   resp.Header.Get("Content-Type"),
 	}
 
-	return fileBytes, nil
+	if resp.StatusCode == http.StatusPartialContent {
+		fileObject.Range, fileObject.TotalSize, err = parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	return fileObject, nil
 }
 
 func (b *BackblazeServiceImpl) getFileID(bucketID, fileName string) (string, error) {
@@ -454,6 +567,39 @@ func (b *BackblazeServiceImpl) DeleteFile(input FileInput) error {
 	return nil
 }
 
+// makeJSONRequest posts a JSON body to the B2 API and decodes the response into target, when given
+func (b *BackblazeServiceImpl) makeJSONRequest(endpoint string, requestBody, target interface{}) error {
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s request: %w", endpoint, err)
+	}
+
+	resp, err := b.makeAuthorizedRequest("POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if target == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error parsing %s response: %w", endpoint, err)
+	}
+
+	return nil
+}
+
+func (b *BackblazeServiceImpl) contentType(contentType string) string {
+	if contentType == "" {
+		// Lets B2 pick the content type from the file extension
+		return "b2/x-auto"
+	}
+
+	return contentType
+}
+
 func (b *BackblazeServiceImpl) transformError(err error) error {
 	if err == nil {
 		return nil
@@ -475,3 +621,25 @@ func (b *BackblazeServiceImpl) transformError(err error) error {
 
 	return err
 }
+
+// sha1SuffixReader yields the hex digest of the hash once the file contents have been read,
+// as expected by B2 when uploading with "X-Bz-Content-Sha1: hex_digits_at_end"
+type sha1SuffixReader struct {
+	hash   hash.Hash
+	digest []byte
+}
+
+func (r *sha1SuffixReader) Read(p []byte) (int, error) {
+	if r.digest == nil {
+		r.digest = []byte(hex.EncodeToString(r.hash.Sum(nil)))
+	}
+
+	if len(r.digest) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.digest)
+	r.digest = r.digest[n:]
+
+	return n, nil
+}
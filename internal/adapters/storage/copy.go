@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"io"
+	"os"
+)
+
+// CopyFile streams an object to another container, the containers may belong to different providers
+func CopyFile(source Provider, from FileInput, target Provider, to FileInput) error {
+	fileObject, err := source.DownloadFile(DownloadFileInput{ContainerName: from.ContainerName, FileName: from.FileName})
+	if err != nil {
+		return err
+	}
+	defer fileObject.Body.Close()
+
+	body, contentLength, cleanup, err := SizedBody(fileObject)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return target.UploadFile(UploadFileInput{
+		ContainerName: to.ContainerName,
+		FileName:      to.FileName,
+		Body:          body,
+		ContentLength: contentLength,
+		ContentType:   fileObject.ContentType,
+	})
+}
+
+// SizedBody returns the body of a downloaded object with its length, uploads need it upfront and some
+// providers send downloads without a Content-Length, those are spooled to a temporary file first
+func SizedBody(fileObject *FileObject) (io.Reader, int64, func(), error) {
+	if fileObject.ContentLength >= 0 {
+		return fileObject.Body, fileObject.ContentLength, func() {}, nil
+	}
+
+	spool, err := os.CreateTemp("", "fluxend-object-*")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	size, err := io.Copy(spool, fileObject.Body)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		cleanup()
+
+		return nil, 0, nil, err
+	}
+
+	return spool, size, cleanup, nil
+}
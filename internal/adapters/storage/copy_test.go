@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizedBody(t *testing.T) {
+	t.Run("known length is passed through", func(t *testing.T) {
+		body, size, cleanup, err := SizedBody(&FileObject{Body: io.NopCloser(strings.NewReader("abc")), ContentLength: 3})
+		require.NoError(t, err)
+		defer cleanup()
+
+		content, _ := io.ReadAll(body)
+		assert.Equal(t, int64(3), size)
+		assert.Equal(t, "abc", string(content))
+	})
+
+	t.Run("unknown length is spooled", func(t *testing.T) {
+		body, size, cleanup, err := SizedBody(&FileObject{Body: io.NopCloser(strings.NewReader("spooled")), ContentLength: -1})
+		require.NoError(t, err)
+		defer cleanup()
+
+		content, _ := io.ReadAll(body)
+		assert.Equal(t, int64(len("spooled")), size)
+		assert.Equal(t, "spooled", string(content))
+	})
+}
+
+func TestCopyFile(t *testing.T) {
+	service := newTestFilesystemService(t)
+
+	for _, name := range []string{"source", "target"} {
+		_, err := service.CreateContainer(name)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, service.UploadFile(UploadFileInput{ContainerName: "source", FileName: "a/b.txt", Body: strings.NewReader("copied"), ContentLength: 6}))
+
+	err := CopyFile(service, FileInput{ContainerName: "source", FileName: "a/b.txt"}, service, FileInput{ContainerName: "target", FileName: "c.txt"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "copied", readTestFile(t, service, DownloadFileInput{ContainerName: "target", FileName: "c.txt"}))
+	assert.Equal(t, "copied", readTestFile(t, service, DownloadFileInput{ContainerName: "source", FileName: "a/b.txt"}))
+}
@@ -6,6 +6,8 @@ import (
 	"fluxend/pkg/errors"
 	"fmt"
 	"github.com/samber/do"
+	"io"
+	"net/http"
 	"os"
 	"resty.dev/v3"
 	"strings"
@@ -21,6 +23,9 @@ const (
 	dropboxActionRenameFile   = "RENAME_FILE"
 	dropboxActionDownloadFile = "DOWNLOAD_FILE"
 	dropboxActionDeleteFile   = "DELETE_FILE"
+
+	// Files larger than a chunk are uploaded through an upload session, one chunk per request
+	dropboxUploadChunkSize = 16 * 1024 * 1024
 )
 
 type DropboxServiceImpl struct {
@@ -65,6 +70,12 @@ func NewDropboxProvider(injector *do.Injector) (Provider, error) {
 		return nil, fmt.Errorf("DROPBOX_ACCESS_TOKEN is not set")
 	}
 
+	return NewDropboxService(Credentials{AccessToken: accessToken})
+}
+
+func NewDropboxService(storageCredentials Credentials) (Provider, error) {
+	accessToken := storageCredentials.AccessToken
+
 	client := resty.New().
 		SetAuthToken(accessToken).
 		SetRetryCount(3).
@@ -209,14 +220,23 @@ func (d *DropboxServiceImpl) DeleteContainer(path string) error {
 func (d *DropboxServiceImpl) UploadFile(input UploadFileInput) error {
 	path := normalizePath(fmt.Sprintf("%s/%s", input.ContainerName, input.FileName))
 
-	apiArg := map[string]interface{}{
+	commitInfo := map[string]interface{}{
 		"path":       path,
 		"mode":       "overwrite",
 		"autorename": false,
 		"mute":       false,
 	}
 
-	resp, err := d.executeContentRequest("POST", "/files/upload", apiArg, input.FileBytes)
+	if input.ContentLength > dropboxUploadChunkSize {
+		return d.uploadSession(input, commitInfo)
+	}
+
+	fileBytes, err := readPart(input.Body, input.ContentLength)
+	if err != nil {
+		return fmt.Errorf("unable to read file %q: %v", input.FileName, err)
+	}
+
+	resp, err := d.executeContentRequest("POST", "/files/upload", commitInfo, fileBytes)
 	if err != nil {
 		return err
 	}
@@ -224,6 +244,58 @@ func (d *DropboxServiceImpl) UploadFile(input UploadFileInput) error {
 	return d.handleAPIError(resp, dropboxActionUploadFile)
 }
 
+// uploadSession streams a large file in chunks, /files/upload only accepts up to 150MB
+func (d *DropboxServiceImpl) uploadSession(input UploadFileInput, commitInfo map[string]interface{}) error {
+	var sessionID string
+
+	for offset := int64(0); offset < input.ContentLength; {
+		chunkSize := min(int64(dropboxUploadChunkSize), input.ContentLength-offset)
+
+		chunk, err := readPart(input.Body, chunkSize)
+		if err != nil {
+			return fmt.Errorf("unable to read file %q: %v", input.FileName, err)
+		}
+
+		cursor := map[string]interface{}{
+			"session_id": sessionID,
+			"offset":     offset,
+		}
+
+		var resp *resty.Response
+		switch {
+		case offset == 0:
+			resp, err = d.executeContentRequest("POST", "/files/upload_session/start", map[string]interface{}{"close": false}, chunk)
+		case offset+chunkSize < input.ContentLength:
+			resp, err = d.executeContentRequest("POST", "/files/upload_session/append_v2", map[string]interface{}{"cursor": cursor, "close": false}, chunk)
+		default:
+			resp, err = d.executeContentRequest("POST", "/files/upload_session/finish", map[string]interface{}{"cursor": cursor, "commit": commitInfo}, chunk)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := d.handleAPIError(resp, dropboxActionUploadFile); err != nil {
+			return err
+		}
+
+		if offset == 0 {
+			var session struct {
+				SessionID string `json:"session_id"`
+			}
+
+			if err := json.Unmarshal(resp.Bytes(), &session); err != nil {
+				return fmt.Errorf("unable to decode response: %v", err)
+			}
+
+			sessionID = session.SessionID
+		}
+
+		offset += chunkSize
+	}
+
+	return nil
+}
+
 func (d *DropboxServiceImpl) RenameFile(input RenameFileInput) error {
 	payload := map[string]interface{}{
 		"from_path":                normalizePath(fmt.Sprintf("%s/%s", input.ContainerName, input.FileName)),
@@ -246,21 +318,56 @@ func (d *DropboxServiceImpl) CreatePresignedURL(input FileInput, expiration time
 	return "", nil
 }
 
-func (d *DropboxServiceImpl) DownloadFile(input FileInput) ([]byte, error) {
-	apiArg := map[string]string{
+func (d *DropboxServiceImpl) DownloadFile(input DownloadFileInput) (*FileObject, error) {
+	apiArg, err := json.Marshal(map[string]string{
 		"path": normalizePath(input.FileName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+	}
+
+	// The body is handed to the caller unread, so the request must not time out while it streams
+	req := d.client.R().
+		SetTimeout(0).
+		SetDoNotParseResponse(true).
+		SetHeader("Dropbox-API-Arg", string(apiArg))
+
+	if input.Range != nil {
+		req.SetHeader("Range", input.Range.HeaderValue())
 	}
 
-	resp, err := d.executeContentRequest("POST", "/files/download", apiArg, nil)
+	resp, err := req.Post(d.contentBase + "/files/download")
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("request failed: %v", err)
 	}
 
-	if err := d.handleAPIError(resp, dropboxActionDownloadFile); err != nil {
-		return nil, err
+	if resp.StatusCode() == http.StatusRequestedRangeNotSatisfiable {
+		resp.Body.Close()
+		return nil, ErrInvalidRange
+	}
+
+	if !resp.IsSuccess() {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, d.transformError(fmt.Errorf("%s failed: %s, %s", dropboxActionDownloadFile, resp.Status(), string(bodyBytes)))
+	}
+
+	fileObject := &FileObject{
+		Body:          resp.Body,
+		ContentLength: resp.RawResponse.ContentLength,
+		TotalSize:     resp.RawResponse.ContentLength,
+		ContentType:   resp.Header().Get("Content-Type"),
+	}
+
+	if resp.StatusCode() == http.StatusPartialContent {
+		fileObject.Range, fileObject.TotalSize, err = parseContentRange(resp.Header().Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
 	}
 
-	return resp.Bytes(), nil
+	return fileObject, nil
 }
 
 func (d *DropboxServiceImpl) DeleteFile(input FileInput) error {
@@ -316,7 +423,9 @@ func (d *DropboxServiceImpl) executeContentRequest(method, endpoint string, apiA
 		return nil, fmt.Errorf("unable to marshal JSON: %v", err)
 	}
 
+	// Content requests transfer file chunks and would not fit in the client's default timeout
 	req := d.client.R().
+		SetTimeout(0).
 		SetHeader("Content-Type", "application/octet-stream").
 		SetHeader("Dropbox-API-Arg", string(apiArgJson))
 
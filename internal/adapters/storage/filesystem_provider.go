@@ -0,0 +1,417 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/guregu/null/v6"
+	"github.com/samber/do"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	filesystemDefaultBasePath = "/var/lib/fluxend/storage"
+	filesystemServePrefix     = "/storage"
+	filesystemTempFilePrefix  = ".upload-"
+)
+
+type FilesystemServiceImpl struct {
+	basePath   string
+	baseURL    string
+	signingKey []byte
+}
+
+func NewFilesystemProvider(injector *do.Injector) (Provider, error) {
+	service, err := NewFilesystemService()
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// NewFilesystemService is exported separately from NewFilesystemProvider so the
+// handler serving presigned URLs can verify signatures without a type assertion
+func NewFilesystemService() (*FilesystemServiceImpl, error) {
+	basePath := os.Getenv("FILESYSTEM_STORAGE_PATH")
+	if basePath == "" {
+		basePath = filesystemDefaultBasePath
+	}
+
+	signingKey := os.Getenv("FILESYSTEM_STORAGE_SIGNING_KEY")
+	if signingKey == "" {
+		signingKey = os.Getenv("JWT_SECRET")
+	}
+
+	if signingKey == "" {
+		return nil, fmt.Errorf("filesystem storage signing key not found in environment variables")
+	}
+
+	absolutePath, err := filepath.Abs(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve storage path %q: %w", basePath, err)
+	}
+
+	if err := os.MkdirAll(absolutePath, 0o750); err != nil {
+		return nil, fmt.Errorf("unable to create storage path %q: %w", absolutePath, err)
+	}
+
+	return &FilesystemServiceImpl{
+		basePath:   absolutePath,
+		baseURL:    strings.TrimRight(os.Getenv("API_URL"), "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (f *FilesystemServiceImpl) CreateContainer(name string) (string, error) {
+	containerPath, err := f.containerPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	if f.ContainerExists(name) {
+		return "", errors.NewBadRequestError("filesystem.error.containerAlreadyExists")
+	}
+
+	if err := os.Mkdir(containerPath, 0o750); err != nil {
+		return "", fmt.Errorf("unable to create container %q: %w", name, err)
+	}
+
+	return fmt.Sprintf("%s%s/%s", f.baseURL, filesystemServePrefix, url.PathEscape(name)), nil
+}
+
+func (f *FilesystemServiceImpl) ContainerExists(name string) bool {
+	containerPath, err := f.containerPath(name)
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(containerPath)
+
+	return err == nil && info.IsDir()
+}
+
+func (f *FilesystemServiceImpl) ListContainers(input ListContainersInput) ([]string, string, error) {
+	entries, err := os.ReadDir(f.basePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	var containerNames []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() > input.Token {
+			containerNames = append(containerNames, entry.Name())
+		}
+	}
+
+	sort.Strings(containerNames)
+
+	// The token is the last container name returned, directory listings have no native cursor
+	var nextToken string
+	if input.Limit > 0 && len(containerNames) > input.Limit {
+		containerNames = containerNames[:input.Limit]
+		nextToken = containerNames[input.Limit-1]
+	}
+
+	return containerNames, nextToken, nil
+}
+
+func (f *FilesystemServiceImpl) ShowContainer(name string) (*ContainerMetadata, error) {
+	containerPath, err := f.containerPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(containerPath)
+	if err != nil || !info.IsDir() {
+		return nil, errors.NewNotFoundError("filesystem.error.containerNotFound")
+	}
+
+	return &ContainerMetadata{
+		Identifier: name,
+		Name:       info.Name(),
+		Path:       containerPath,
+		Region:     null.StringFrom(""),
+	}, nil
+}
+
+func (f *FilesystemServiceImpl) DeleteContainer(name string) error {
+	containerPath, err := f.containerPath(name)
+	if err != nil {
+		return err
+	}
+
+	if !f.ContainerExists(name) {
+		return errors.NewNotFoundError("filesystem.error.containerNotFound")
+	}
+
+	// Like cloud buckets, a container must be emptied before it can be removed
+	if err := os.Remove(containerPath); err != nil {
+		return fmt.Errorf("unable to delete container %q: %w", name, err)
+	}
+
+	return nil
+}
+
+func (f *FilesystemServiceImpl) UploadFile(input UploadFileInput) error {
+	filePath, err := f.filePath(input.ContainerName, input.FileName)
+	if err != nil {
+		return err
+	}
+
+	if !f.ContainerExists(input.ContainerName) {
+		return errors.NewNotFoundError("filesystem.error.containerNotFound")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o750); err != nil {
+		return fmt.Errorf("unable to create directory for file %q: %w", input.FileName, err)
+	}
+
+	// Write to a temporary file in the same directory and rename it into place,
+	// so readers never observe a partially written file
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), filesystemTempFilePrefix+"*")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file for %q: %w", input.FileName, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	written, err := io.Copy(tempFile, input.Body)
+	if err != nil {
+		tempFile.Close()
+		return fmt.Errorf("unable to write file %q: %w", input.FileName, err)
+	}
+
+	if written != input.ContentLength {
+		tempFile.Close()
+		return fmt.Errorf("unable to write file %q: expected %d bytes, got %d", input.FileName, input.ContentLength, written)
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("unable to sync file %q: %w", input.FileName, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("unable to close file %q: %w", input.FileName, err)
+	}
+
+	if err := os.Rename(tempFile.Name(), filePath); err != nil {
+		return fmt.Errorf("unable to upload file %q: %w", input.FileName, err)
+	}
+
+	return nil
+}
+
+func (f *FilesystemServiceImpl) RenameFile(input RenameFileInput) error {
+	sourcePath, err := f.filePath(input.ContainerName, input.FileName)
+	if err != nil {
+		return err
+	}
+
+	targetPath, err := f.filePath(input.ContainerName, input.NewFileName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(sourcePath); err != nil {
+		return errors.NewNotFoundError("filesystem.error.fileNotFound")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o750); err != nil {
+		return fmt.Errorf("unable to create directory for file %q: %w", input.NewFileName, err)
+	}
+
+	if err := os.Rename(sourcePath, targetPath); err != nil {
+		return fmt.Errorf("unable to rename file %q to %q: %w", input.FileName, input.NewFileName, err)
+	}
+
+	f.pruneEmptyDirectories(input.ContainerName, filepath.Dir(sourcePath))
+
+	return nil
+}
+
+func (f *FilesystemServiceImpl) DownloadFile(input DownloadFileInput) (*FileObject, error) {
+	filePath, err := f.filePath(input.ContainerName, input.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NewNotFoundError("filesystem.error.fileNotFound")
+		}
+
+		return nil, fmt.Errorf("unable to download file %q: %w", input.FileName, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, errors.NewNotFoundError("filesystem.error.fileNotFound")
+	}
+
+	fileObject := &FileObject{
+		Body:          file,
+		ContentLength: info.Size(),
+		TotalSize:     info.Size(),
+		ContentType:   mime.TypeByExtension(filepath.Ext(filePath)),
+	}
+
+	if input.Range == nil {
+		return fileObject, nil
+	}
+
+	byteRange, err := input.Range.Resolve(info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if _, err := file.Seek(byteRange.Start, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to seek file %q: %w", input.FileName, err)
+	}
+
+	fileObject.ContentLength = byteRange.End - byteRange.Start + 1
+	fileObject.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, fileObject.ContentLength), file}
+	fileObject.Range = &byteRange
+
+	return fileObject, nil
+}
+
+// CreatePresignedURL returns a URL served by Fluxend itself, see ResolvePresignedPath
+func (f *FilesystemServiceImpl) CreatePresignedURL(input FileInput, expiration time.Duration) (string, error) {
+	if _, err := f.filePath(input.ContainerName, input.FileName); err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(expiration).Unix()
+	signature := f.sign(input.ContainerName, input.FileName, expiresAt)
+
+	escapedFileName := (&url.URL{Path: input.FileName}).EscapedPath()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", signature)
+
+	return fmt.Sprintf(
+		"%s%s/%s/%s?%s",
+		f.baseURL,
+		filesystemServePrefix,
+		url.PathEscape(input.ContainerName),
+		escapedFileName,
+		query.Encode(),
+	), nil
+}
+
+// ResolvePresignedPath verifies a URL generated by CreatePresignedURL and returns the path of the file on disk
+func (f *FilesystemServiceImpl) ResolvePresignedPath(input FileInput, expires, signature string) (string, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", errors.NewUnauthorizedError("filesystem.error.signatureInvalid")
+	}
+
+	expectedSignature := f.sign(input.ContainerName, input.FileName, expiresAt)
+	if !hmac.Equal([]byte(expectedSignature), []byte(signature)) {
+		return "", errors.NewUnauthorizedError("filesystem.error.signatureInvalid")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return "", errors.NewUnauthorizedError("filesystem.error.signatureExpired")
+	}
+
+	filePath, err := f.filePath(input.ContainerName, input.FileName)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		return "", errors.NewNotFoundError("filesystem.error.fileNotFound")
+	}
+
+	return filePath, nil
+}
+
+func (f *FilesystemServiceImpl) DeleteFile(input FileInput) error {
+	filePath, err := f.filePath(input.ContainerName, input.FileName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.NewNotFoundError("filesystem.error.fileNotFound")
+		}
+
+		return fmt.Errorf("unable to delete file %q: %w", input.FileName, err)
+	}
+
+	f.pruneEmptyDirectories(input.ContainerName, filepath.Dir(filePath))
+
+	return nil
+}
+
+func (f *FilesystemServiceImpl) sign(containerName, fileName string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, f.signingKey)
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%d", containerName, fileName, expiresAt)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (f *FilesystemServiceImpl) containerPath(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", errors.NewBadRequestError("filesystem.error.invalidContainerName")
+	}
+
+	return filepath.Join(f.basePath, name), nil
+}
+
+// filePath resolves a file inside its container and rejects names escaping the container directory
+func (f *FilesystemServiceImpl) filePath(containerName, fileName string) (string, error) {
+	containerPath, err := f.containerPath(containerName)
+	if err != nil {
+		return "", err
+	}
+
+	cleanName := filepath.Clean("/" + fileName)
+	if fileName == "" || cleanName == "/" {
+		return "", errors.NewBadRequestError("filesystem.error.invalidFileName")
+	}
+
+	filePath := filepath.Join(containerPath, cleanName)
+	if !strings.HasPrefix(filePath, containerPath+string(filepath.Separator)) {
+		return "", errors.NewBadRequestError("filesystem.error.invalidFileName")
+	}
+
+	return filePath, nil
+}
+
+// pruneEmptyDirectories removes directories left empty by nested file names, up to the container root
+func (f *FilesystemServiceImpl) pruneEmptyDirectories(containerName, directory string) {
+	containerPath, err := f.containerPath(containerName)
+	if err != nil {
+		return
+	}
+
+	for strings.HasPrefix(directory, containerPath+string(filepath.Separator)) {
+		if err := os.Remove(directory); err != nil {
+			return
+		}
+
+		directory = filepath.Dir(directory)
+	}
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFilesystemService(t *testing.T) *FilesystemServiceImpl {
+	t.Setenv("FILESYSTEM_STORAGE_PATH", t.TempDir())
+	t.Setenv("FILESYSTEM_STORAGE_SIGNING_KEY", "test-signing-key")
+	t.Setenv("API_URL", "http://api.localhost")
+
+	service, err := NewFilesystemService()
+	require.NoError(t, err)
+
+	return service
+}
+
+func readTestFile(t *testing.T, service *FilesystemServiceImpl, input DownloadFileInput) string {
+	fileObject, err := service.DownloadFile(input)
+	require.NoError(t, err)
+	defer fileObject.Body.Close()
+
+	fileBytes, err := io.ReadAll(fileObject.Body)
+	require.NoError(t, err)
+	assert.Equal(t, fileObject.ContentLength, int64(len(fileBytes)))
+
+	return string(fileBytes)
+}
+
+func TestFilesystemProvider_Containers(t *testing.T) {
+	service := newTestFilesystemService(t)
+
+	t.Run("create, show and delete container", func(t *testing.T) {
+		containerURL, err := service.CreateContainer("avatars")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://api.localhost/storage/avatars", containerURL)
+		assert.True(t, service.ContainerExists("avatars"))
+
+		metadata, err := service.ShowContainer("avatars")
+		assert.NoError(t, err)
+		assert.Equal(t, "avatars", metadata.Identifier)
+
+		_, err = service.CreateContainer("avatars")
+		assert.EqualError(t, err, "filesystem.error.containerAlreadyExists")
+
+		assert.NoError(t, service.DeleteContainer("avatars"))
+		assert.False(t, service.ContainerExists("avatars"))
+	})
+
+	t.Run("list containers with pagination", func(t *testing.T) {
+		for _, name := range []string{"c-three", "c-one", "c-two"} {
+			_, err := service.CreateContainer(name)
+			require.NoError(t, err)
+		}
+
+		names, nextToken, err := service.ListContainers(ListContainersInput{Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c-one", "c-three"}, names)
+		assert.Equal(t, "c-three", nextToken)
+
+		names, nextToken, err = service.ListContainers(ListContainersInput{Limit: 2, Token: nextToken})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c-two"}, names)
+		assert.Empty(t, nextToken)
+	})
+
+	t.Run("reject invalid container names", func(t *testing.T) {
+		for _, name := range []string{"", ".", "..", "a/b", `a\b`} {
+			_, err := service.CreateContainer(name)
+			assert.EqualError(t, err, "filesystem.error.invalidContainerName", name)
+		}
+	})
+}
+
+func TestFilesystemProvider_Files(t *testing.T) {
+	service := newTestFilesystemService(t)
+
+	_, err := service.CreateContainer("documents")
+	require.NoError(t, err)
+
+	t.Run("upload, rename, download and delete nested file", func(t *testing.T) {
+		err := service.UploadFile(UploadFileInput{
+			ContainerName: "documents",
+			FileName:      "reports/2025/summary.txt",
+			Body:          strings.NewReader("quarterly summary"), ContentLength: int64(len("quarterly summary")),
+		})
+		assert.NoError(t, err)
+
+		err = service.RenameFile(RenameFileInput{
+			ContainerName: "documents",
+			FileName:      "reports/2025/summary.txt",
+			NewFileName:   "archive/summary.txt",
+		})
+		assert.NoError(t, err)
+
+		content := readTestFile(t, service, DownloadFileInput{ContainerName: "documents", FileName: "archive/summary.txt"})
+		assert.Equal(t, "quarterly summary", content)
+
+		_, err = os.Stat(filepath.Join(service.basePath, "documents", "reports"))
+		assert.True(t, os.IsNotExist(err), "empty directories should be pruned after rename")
+
+		assert.NoError(t, service.DeleteFile(FileInput{ContainerName: "documents", FileName: "archive/summary.txt"}))
+
+		_, err = service.DownloadFile(DownloadFileInput{ContainerName: "documents", FileName: "archive/summary.txt"})
+		assert.EqualError(t, err, "filesystem.error.fileNotFound")
+	})
+
+	t.Run("upload overwrites without leaving temporary files", func(t *testing.T) {
+		for _, content := range []string{"first", "second"} {
+			err := service.UploadFile(UploadFileInput{ContainerName: "documents", FileName: "note.txt", Body: strings.NewReader(content), ContentLength: int64(len(content))})
+			require.NoError(t, err)
+		}
+
+		content := readTestFile(t, service, DownloadFileInput{ContainerName: "documents", FileName: "note.txt"})
+		assert.Equal(t, "second", content)
+
+		entries, err := os.ReadDir(filepath.Join(service.basePath, "documents"))
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			assert.False(t, strings.HasPrefix(entry.Name(), filesystemTempFilePrefix))
+		}
+	})
+
+	t.Run("reject file names escaping the container", func(t *testing.T) {
+		err := service.UploadFile(UploadFileInput{ContainerName: "documents", FileName: "../../escape.txt", Body: strings.NewReader("x"), ContentLength: int64(len("x"))})
+		assert.NoError(t, err, "traversal segments are resolved inside the container")
+
+		_, err = os.Stat(filepath.Join(service.basePath, "documents", "escape.txt"))
+		assert.NoError(t, err)
+
+		err = service.UploadFile(UploadFileInput{ContainerName: "documents", FileName: "/", Body: strings.NewReader("x"), ContentLength: int64(len("x"))})
+		assert.EqualError(t, err, "filesystem.error.invalidFileName")
+	})
+
+	t.Run("reject body shorter than content length", func(t *testing.T) {
+		err := service.UploadFile(UploadFileInput{ContainerName: "documents", FileName: "short.txt", Body: strings.NewReader("abc"), ContentLength: 10})
+		assert.Error(t, err)
+
+		_, err = os.Stat(filepath.Join(service.basePath, "documents", "short.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("download byte ranges", func(t *testing.T) {
+		content := "0123456789"
+		require.NoError(t, service.UploadFile(UploadFileInput{ContainerName: "documents", FileName: "digits.txt", Body: strings.NewReader(content), ContentLength: int64(len(content))}))
+
+		input := DownloadFileInput{ContainerName: "documents", FileName: "digits.txt"}
+
+		input.Range = &ByteRange{Start: 2, End: 4}
+		assert.Equal(t, "234", readTestFile(t, service, input))
+
+		input.Range = &ByteRange{Start: 7, End: -1}
+		assert.Equal(t, "789", readTestFile(t, service, input))
+
+		input.Range = &ByteRange{Start: -2, End: -1}
+		assert.Equal(t, "89", readTestFile(t, service, input))
+
+		input.Range = &ByteRange{Start: 5, End: 100}
+		assert.Equal(t, "56789", readTestFile(t, service, input))
+
+		input.Range = &ByteRange{Start: 10, End: -1}
+		_, err := service.DownloadFile(input)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+
+	t.Run("upload to missing container", func(t *testing.T) {
+		err := service.UploadFile(UploadFileInput{ContainerName: "missing", FileName: "file.txt", Body: strings.NewReader("x"), ContentLength: int64(len("x"))})
+		assert.EqualError(t, err, "filesystem.error.containerNotFound")
+	})
+}
+
+func TestFilesystemProvider_PresignedURL(t *testing.T) {
+	service := newTestFilesystemService(t)
+
+	_, err := service.CreateContainer("public")
+	require.NoError(t, err)
+	require.NoError(t, service.UploadFile(UploadFileInput{ContainerName: "public", FileName: "my photos/cat.png", Body: strings.NewReader("png"), ContentLength: int64(len("png"))}))
+
+	fileInput := FileInput{ContainerName: "public", FileName: "my photos/cat.png"}
+
+	t.Run("valid signature resolves to file", func(t *testing.T) {
+		presignedURL, err := service.CreatePresignedURL(fileInput, time.Hour)
+		require.NoError(t, err)
+
+		parsedURL, err := url.Parse(presignedURL)
+		require.NoError(t, err)
+		assert.Equal(t, "/storage/public/my%20photos/cat.png", parsedURL.EscapedPath())
+
+		filePath, err := service.ResolvePresignedPath(fileInput, parsedURL.Query().Get("expires"), parsedURL.Query().Get("signature"))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(service.basePath, "public", "my photos", "cat.png"), filePath)
+	})
+
+	t.Run("tampered file name is rejected", func(t *testing.T) {
+		presignedURL, err := service.CreatePresignedURL(fileInput, time.Hour)
+		require.NoError(t, err)
+
+		parsedURL, _ := url.Parse(presignedURL)
+		otherFile := FileInput{ContainerName: "public", FileName: "other.png"}
+
+		_, err = service.ResolvePresignedPath(otherFile, parsedURL.Query().Get("expires"), parsedURL.Query().Get("signature"))
+		assert.EqualError(t, err, "filesystem.error.signatureInvalid")
+	})
+
+	t.Run("expired signature is rejected", func(t *testing.T) {
+		presignedURL, err := service.CreatePresignedURL(fileInput, -time.Minute)
+		require.NoError(t, err)
+
+		parsedURL, _ := url.Parse(presignedURL)
+
+		_, err = service.ResolvePresignedPath(fileInput, parsedURL.Query().Get("expires"), parsedURL.Query().Get("signature"))
+		assert.EqualError(t, err, "filesystem.error.signatureExpired")
+	})
+
+	t.Run("malformed expiry is rejected", func(t *testing.T) {
+		_, err := service.ResolvePresignedPath(fileInput, "tomorrow", "deadbeef")
+		assert.EqualError(t, err, "filesystem.error.signatureInvalid")
+	})
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// Shared address space used for carrier-grade NAT, not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// newPublicHTTPClient only connects to public addresses, endpoints chosen by users
+// must not reach the loopback, private or link-local networks of the deployment
+func newPublicHTTPClient() *awshttp.BuildableClient {
+	return awshttp.NewBuildableClient().
+		WithDialerOptions(func(dialer *net.Dialer) {
+			dialer.Control = publicAddressOnly
+		}).
+		WithTransportOptions(func(transport *http.Transport) {
+			// A proxy would make the request on our behalf and skip the check
+			transport.Proxy = nil
+		})
+}
+
+// publicAddressOnly runs after DNS resolution, so a host name resolving to an internal address is refused too
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("connecting to non-public address %s is not allowed", address)
+	}
+
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -15,7 +15,7 @@ type Provider interface {
 	DeleteContainer(name string) error
 	UploadFile(input UploadFileInput) error
 	RenameFile(input RenameFileInput) error
-	DownloadFile(input FileInput) ([]byte, error)
+	DownloadFile(input DownloadFileInput) (*FileObject, error)
 	CreatePresignedURL(input FileInput, expiration time.Duration) (string, error)
 	DeleteFile(input FileInput) error
 }
@@ -30,6 +30,8 @@ func NewFactory(injector *do.Injector) (*Factory, error) {
 
 func (f *Factory) CreateProvider(providerType string) (Provider, error) {
 	switch providerType {
+	case constants.StorageDriverFilesystem:
+		return NewFilesystemProvider(f.injector)
 	case constants.StorageDriverDropbox:
 		return NewDropboxProvider(f.injector)
 	case constants.StorageDriverS3:
@@ -40,3 +42,21 @@ func (f *Factory) CreateProvider(providerType string) (Provider, error) {
 		return nil, fmt.Errorf("unsupported storage provider: %s", providerType)
 	}
 }
+
+// CreateProviderWithCredentials builds a provider from a stored credential set instead of the environment
+func (f *Factory) CreateProviderWithCredentials(providerType string, credentials Credentials) (Provider, error) {
+	if err := credentials.Validate(providerType); err != nil {
+		return nil, err
+	}
+
+	switch providerType {
+	case constants.StorageDriverDropbox:
+		return NewDropboxService(credentials)
+	case constants.StorageDriverS3:
+		return NewExternalS3Service(credentials)
+	case constants.StorageDriverBackBlaze:
+		return NewBackblazeService(credentials)
+	default:
+		return nil, fmt.Errorf("storage provider %s doesn't take credentials", providerType)
+	}
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fluxend/pkg"
 	"fluxend/pkg/errors"
@@ -12,29 +13,62 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/guregu/null/v6"
 	"github.com/samber/do"
-	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	// S3 rejects parts smaller than 5MiB (except the last one) and uploads with more than 10,000 parts
+	s3MinimumPartSize = 16 * 1024 * 1024
+	s3MaximumParts    = 10000
+
+	s3AutoRegion = "auto"
+)
+
 type S3ServiceImpl struct {
 	client *s3.Client
+	region string
 }
 
 func NewS3Provider(injector *do.Injector) (Provider, error) {
-	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	region := os.Getenv("AWS_REGION")
+	// Unset or malformed flags keep the AWS defaults
+	usePathStyle, _ := strconv.ParseBool(os.Getenv("AWS_S3_USE_PATH_STYLE"))
+	disableChecksum, _ := strconv.ParseBool(os.Getenv("AWS_S3_DISABLE_CHECKSUM"))
+
+	return NewS3Service(Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		Region:          os.Getenv("AWS_REGION"),
+		Endpoint:        os.Getenv("AWS_ENDPOINT_URL"),
+		UsePathStyle:    usePathStyle,
+		DisableChecksum: disableChecksum,
+	})
+}
+
+func NewS3Service(storageCredentials Credentials) (Provider, error) {
+	return newS3Service(storageCredentials, nil)
+}
+
+// NewExternalS3Service builds a provider for credentials saved by organization owners,
+// their endpoint can point anywhere so only public addresses are reachable
+func NewExternalS3Service(storageCredentials Credentials) (Provider, error) {
+	return newS3Service(storageCredentials, newPublicHTTPClient())
+}
+
+func newS3Service(storageCredentials Credentials, httpClient aws.HTTPClient) (Provider, error) {
+	region := storageCredentials.Region
 
 	// Default to us-east-1 if region is empty
 	if region == "" {
 		region = "us-east-1"
 	}
 
+	staticCredentials := credentials.NewStaticCredentialsProvider(storageCredentials.AccessKeyID, storageCredentials.SecretAccessKey, "")
 	configOptions := []func(*config.LoadOptions) error{
 		config.WithRegion(region),
-		config.WithCredentialsProvider(aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""))),
+		config.WithCredentialsProvider(aws.NewCredentialsCache(staticCredentials)),
 	}
 
 	cfg, err := config.LoadDefaultConfig(context.TODO(), configOptions...)
@@ -42,10 +76,27 @@ func NewS3Provider(injector *do.Injector) (Provider, error) {
 		return nil, fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
-	client := s3.NewFromConfig(cfg)
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		if storageCredentials.Endpoint != "" {
+			options.BaseEndpoint = aws.String(storageCredentials.Endpoint)
+		}
+
+		if httpClient != nil {
+			options.HTTPClient = httpClient
+		}
+
+		options.UsePathStyle = storageCredentials.UsePathStyle
+
+		// Most S3 compatible services reject the CRC checksums the SDK sends by default
+		if storageCredentials.DisableChecksum {
+			options.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+			options.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+		}
+	})
 
 	return &S3ServiceImpl{
 		client: client,
+		region: region,
 	}, nil
 }
 
@@ -54,10 +105,10 @@ func (s *S3ServiceImpl) CreateContainer(bucketName string) (string, error) {
 		Bucket: aws.String(bucketName),
 	}
 
-	region := os.Getenv("AWS_REGION")
-	if region != "" && region != "us-east-1" {
+	// R2 only knows the "auto" region, which isn't a valid location constraint
+	if s.region != "us-east-1" && s.region != s3AutoRegion {
 		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
-			LocationConstraint: types.BucketLocationConstraint(region),
+			LocationConstraint: types.BucketLocationConstraint(s.region),
 		}
 	}
 
@@ -138,10 +189,27 @@ func (s *S3ServiceImpl) DeleteContainer(bucketName string) error {
 }
 
 func (s *S3ServiceImpl) UploadFile(input UploadFileInput) error {
-	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
-		Bucket: aws.String(input.ContainerName),
-		Key:    aws.String(input.FileName),
-		Body:   strings.NewReader(string(input.FileBytes)),
+	partSize := partSizeFor(input.ContentLength, s3MinimumPartSize, s3MaximumParts)
+	if input.ContentLength <= partSize {
+		return s.putObject(input)
+	}
+
+	return s.uploadMultipart(input, partSize)
+}
+
+func (s *S3ServiceImpl) putObject(input UploadFileInput) error {
+	// Buffer the body so the SDK can sign it and retry failed requests
+	body, err := readPart(input.Body, input.ContentLength)
+	if err != nil {
+		return fmt.Errorf("unable to read file %q, %v", input.FileName, err)
+	}
+
+	_, err = s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:        aws.String(input.ContainerName),
+		Key:           aws.String(input.FileName),
+		Body:          bytes.NewReader(body),
+		ContentLength: aws.Int64(input.ContentLength),
+		ContentType:   s.contentType(input.ContentType),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to upload file %q, %v", input.FileName, err)
@@ -150,6 +218,76 @@ func (s *S3ServiceImpl) UploadFile(input UploadFileInput) error {
 	return nil
 }
 
+func (s *S3ServiceImpl) uploadMultipart(input UploadFileInput, partSize int64) error {
+	ctx := context.Background()
+
+	upload, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(input.ContainerName),
+		Key:         aws.String(input.FileName),
+		ContentType: s.contentType(input.ContentType),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to start upload of file %q, %v", input.FileName, err)
+	}
+
+	completedParts, err := s.uploadParts(input, upload.UploadId, partSize)
+	if err != nil {
+		// Abort so S3 doesn't keep (and bill for) the parts uploaded so far
+		_, _ = s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(input.ContainerName),
+			Key:      aws.String(input.FileName),
+			UploadId: upload.UploadId,
+		})
+
+		return err
+	}
+
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(input.ContainerName),
+		Key:             aws.String(input.FileName),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to complete upload of file %q, %v", input.FileName, err)
+	}
+
+	return nil
+}
+
+func (s *S3ServiceImpl) uploadParts(input UploadFileInput, uploadID *string, partSize int64) ([]types.CompletedPart, error) {
+	var completedParts []types.CompletedPart
+
+	for partNumber, offset := int32(1), int64(0); offset < input.ContentLength; partNumber++ {
+		currentPartSize := min(partSize, input.ContentLength-offset)
+
+		part, err := readPart(input.Body, currentPartSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read part %d of file %q, %v", partNumber, input.FileName, err)
+		}
+
+		resp, err := s.client.UploadPart(context.Background(), &s3.UploadPartInput{
+			Bucket:        aws.String(input.ContainerName),
+			Key:           aws.String(input.FileName),
+			UploadId:      uploadID,
+			PartNumber:    aws.Int32(partNumber),
+			Body:          bytes.NewReader(part),
+			ContentLength: aws.Int64(currentPartSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to upload part %d of file %q, %v", partNumber, input.FileName, err)
+		}
+
+		completedParts = append(completedParts, types.CompletedPart{
+			ETag:       resp.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+		offset += currentPartSize
+	}
+
+	return completedParts, nil
+}
+
 func (s *S3ServiceImpl) RenameFile(input RenameFileInput) error {
 	_, err := s.client.CopyObject(context.Background(), &s3.CopyObjectInput{
 		Bucket:     aws.String(input.ContainerName),
@@ -171,27 +309,42 @@ func (s *S3ServiceImpl) RenameFile(input RenameFileInput) error {
 	return nil
 }
 
-func (s *S3ServiceImpl) DownloadFile(input FileInput) ([]byte, error) {
-	resp, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+func (s *S3ServiceImpl) DownloadFile(input DownloadFileInput) (*FileObject, error) {
+	objectInput := &s3.GetObjectInput{
 		Bucket: aws.String(input.ContainerName),
 		Key:    aws.String(input.FileName),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("unable to download file %q, %v", input.FileName, err)
 	}
 
-	fileBytes, err := io.ReadAll(resp.Body)
+	if input.Range != nil {
+		objectInput.Range = aws.String(input.Range.HeaderValue())
+	}
+
+	resp, err := s.client.GetObject(context.Background(), objectInput)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read file %q, %v", input.FileName, err)
+		return nil, s.transformError(fmt.Errorf("unable to download file %q, %v", input.FileName, err))
+	}
+
+	fileObject := &FileObject{
+		Body:          resp.Body,
+		ContentLength: aws.ToInt64(resp.ContentLength),
+		TotalSize:     aws.ToInt64(resp.ContentLength),
+		ContentType:   aws.ToString(resp.ContentType),
 	}
 
-	return fileBytes, nil
+	if resp.ContentRange != nil {
+		fileObject.Range, fileObject.TotalSize, err = parseContentRange(*resp.ContentRange)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	return fileObject, nil
 }
 
 func (s *S3ServiceImpl) CreatePresignedURL(input FileInput, expiration time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
-	pkg.DumpJSON(input)
 	request, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
 		Bucket: aws.String(input.ContainerName),
 		Key:    aws.String(input.FileName),
@@ -236,5 +389,21 @@ func (s *S3ServiceImpl) transformError(err error) error {
 		return errors.NewNotFoundError("s3.error.bucketNotFound")
 	}
 
+	if strings.Contains(errorString, "NoSuchKey") {
+		return errors.NewNotFoundError("file.error.notFound")
+	}
+
+	if strings.Contains(errorString, "InvalidRange") {
+		return ErrInvalidRange
+	}
+
 	return err
 }
+
+func (s *S3ServiceImpl) contentType(contentType string) *string {
+	if contentType == "" {
+		return nil
+	}
+
+	return aws.String(contentType)
+}
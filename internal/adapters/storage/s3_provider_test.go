@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedS3Request struct {
+	Host    string
+	Path    string
+	Headers http.Header
+}
+
+// newTestS3Server answers every request with 200 and records what the SDK sent
+func newTestS3Server(t *testing.T) (*httptest.Server, func() []recordedS3Request) {
+	var (
+		mutex    sync.Mutex
+		requests []recordedS3Request
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, recordedS3Request{Host: r.Host, Path: r.URL.Path, Headers: r.Header.Clone()})
+		mutex.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []recordedS3Request {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]recordedS3Request(nil), requests...)
+	}
+}
+
+func TestS3ServiceCustomEndpoint(t *testing.T) {
+	server, recorded := newTestS3Server(t)
+
+	service, err := NewS3Service(Credentials{
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		Region:          s3AutoRegion,
+		Endpoint:        server.URL,
+		UsePathStyle:    true,
+		DisableChecksum: true,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, service.ContainerExists("uploads"))
+
+	err = service.UploadFile(UploadFileInput{
+		ContainerName: "uploads",
+		FileName:      "avatar.png",
+		Body:          strings.NewReader("content"),
+		ContentLength: int64(len("content")),
+		ContentType:   "image/png",
+	})
+	require.NoError(t, err)
+
+	requests := recorded()
+	require.Len(t, requests, 2)
+
+	serverHost := strings.TrimPrefix(server.URL, "http://")
+	for _, request := range requests {
+		// Path style keeps the bucket out of the host name
+		assert.Equal(t, serverHost, request.Host)
+
+		for name := range request.Headers {
+			assert.False(t, strings.HasPrefix(strings.ToLower(name), "x-amz-checksum-"), name)
+		}
+	}
+
+	assert.Equal(t, "/uploads", requests[0].Path)
+	assert.Equal(t, "/uploads/avatar.png", requests[1].Path)
+}
+
+func TestExternalS3ServiceRefusesInternalEndpoints(t *testing.T) {
+	server, recorded := newTestS3Server(t)
+
+	service, err := NewExternalS3Service(Credentials{
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		Endpoint:        server.URL,
+		UsePathStyle:    true,
+	})
+	require.NoError(t, err)
+
+	err = service.UploadFile(UploadFileInput{
+		ContainerName: "uploads",
+		FileName:      "avatar.png",
+		Body:          strings.NewReader("content"),
+		ContentLength: int64(len("content")),
+		ContentType:   "image/png",
+	})
+	assert.Error(t, err)
+	assert.Empty(t, recorded())
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isPublicIP(net.ParseIP(tt.address)))
+		})
+	}
+}
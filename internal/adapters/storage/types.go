@@ -1,8 +1,14 @@
 package storage
 
 import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg/errors"
+	"fmt"
 	"github.com/guregu/null/v6"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type ListContainersInput struct {
@@ -23,10 +29,50 @@ type FileInput struct {
 	FileName      string
 }
 
+// ErrInvalidRange is returned by DownloadFile when the requested range lies outside the file
+var ErrInvalidRange = errors.NewBadRequestError("file.error.invalidRange")
+
+// Credentials holds the secrets of a single storage account, only the fields of its driver are set
+type Credentials struct {
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`        // S3 compatible services like MinIO, R2 or Wasabi
+	UsePathStyle    bool   `json:"usePathStyle,omitempty"`    // bucket in the path instead of the host name
+	DisableChecksum bool   `json:"disableChecksum,omitempty"` // only send checksums the operation requires
+	KeyID           string `json:"keyId,omitempty"`
+	ApplicationKey  string `json:"applicationKey,omitempty"`
+	AccessToken     string `json:"accessToken,omitempty"`
+}
+
 type UploadFileInput struct {
 	ContainerName string
 	FileName      string
-	FileBytes     []byte
+	Body          io.Reader
+	ContentLength int64 // in bytes, must match what Body yields
+	ContentType   string
+}
+
+type DownloadFileInput struct {
+	ContainerName string
+	FileName      string
+	Range         *ByteRange // nil downloads the whole file
+}
+
+// ByteRange is an inclusive range of bytes. A negative End reads until the end of
+// the file, a negative Start selects the last -Start bytes of the file.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// FileObject is a streamed file. The caller must close Body.
+type FileObject struct {
+	Body          io.ReadCloser
+	ContentLength int64 // bytes in Body
+	TotalSize     int64 // bytes in the whole file
+	ContentType   string
+	Range         *ByteRange // resolved range, nil if Body holds the whole file
 }
 
 type RenameFileInput struct {
@@ -128,6 +174,32 @@ type B2DeleteFileVersionRequest struct {
 	FileName string `json:"fileName"`
 }
 
+type B2StartLargeFileRequest struct {
+	BucketID    string `json:"bucketId"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+}
+
+type B2LargeFileRequest struct {
+	FileID string `json:"fileId"`
+}
+
+type B2FinishLargeFileRequest struct {
+	FileID        string   `json:"fileId"`
+	PartSha1Array []string `json:"partSha1Array"`
+}
+
+type B2GetUploadPartURLResponse struct {
+	FileID             string `json:"fileId"`
+	UploadURL          string `json:"uploadUrl"`
+	AuthorizationToken string `json:"authorizationToken"`
+}
+
+type B2CopyFileRequest struct {
+	SourceFileID string `json:"sourceFileId"`
+	FileName     string `json:"fileName"`
+}
+
 type BackblazeServiceImpl struct {
 	apiBase            string
 	accountID          string
@@ -135,4 +207,109 @@ type BackblazeServiceImpl struct {
 	apiURL             string
 	downloadURL        string
 	httpClient         *http.Client
+	transferClient     *http.Client // no overall timeout, file transfers can take longer than API calls
+}
+
+// Validate checks that the keys the given driver needs are set, other drivers are refused
+func (c Credentials) Validate(providerType string) error {
+	var complete bool
+
+	switch providerType {
+	case constants.StorageDriverS3:
+		complete = c.AccessKeyID != "" && c.SecretAccessKey != ""
+	case constants.StorageDriverBackBlaze:
+		complete = c.KeyID != "" && c.ApplicationKey != ""
+	case constants.StorageDriverDropbox:
+		complete = c.AccessToken != ""
+	default:
+		return errors.NewBadRequestError("credential.error.unsupportedDriver")
+	}
+
+	if !complete {
+		return errors.NewBadRequestError("credential.error.incomplete")
+	}
+
+	return nil
+}
+
+// HeaderValue formats the range as an HTTP Range header value
+func (r ByteRange) HeaderValue() string {
+	if r.Start < 0 {
+		return fmt.Sprintf("bytes=%d", r.Start)
+	}
+
+	if r.End < 0 {
+		return fmt.Sprintf("bytes=%d-", r.Start)
+	}
+
+	return fmt.Sprintf("bytes=%d-%d", r.Start, r.End)
+}
+
+// Resolve turns the range into absolute offsets for a file of the given size
+func (r ByteRange) Resolve(totalSize int64) (ByteRange, error) {
+	resolved := r
+
+	if r.Start < 0 {
+		resolved.Start = max(totalSize+r.Start, 0)
+		resolved.End = totalSize - 1
+	} else if r.End < 0 || r.End >= totalSize {
+		resolved.End = totalSize - 1
+	}
+
+	if resolved.Start >= totalSize || resolved.Start > resolved.End {
+		return ByteRange{}, ErrInvalidRange
+	}
+
+	return resolved, nil
+}
+
+// parseContentRange reads a "bytes start-end/total" response header
+func parseContentRange(contentRange string) (*ByteRange, int64, error) {
+	spec, found := strings.CutPrefix(contentRange, "bytes ")
+	if !found {
+		return nil, 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+
+	rangePart, totalPart, found := strings.Cut(spec, "/")
+	if !found {
+		return nil, 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+
+	total, err := strconv.ParseInt(totalPart, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+
+	startPart, endPart, found := strings.Cut(rangePart, "-")
+	if !found {
+		return nil, 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+
+	start, startErr := strconv.ParseInt(startPart, 10, 64)
+	end, endErr := strconv.ParseInt(endPart, 10, 64)
+	if startErr != nil || endErr != nil {
+		return nil, 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+
+	return &ByteRange{Start: start, End: end}, total, nil
+}
+
+// partSizeFor picks a part size for multipart uploads that keeps the number of parts within maxParts
+func partSizeFor(contentLength, minimumPartSize int64, maxParts int64) int64 {
+	partSize := minimumPartSize
+	if contentLength/partSize >= maxParts {
+		partSize = contentLength/maxParts + 1
+	}
+
+	return partSize
+}
+
+// readPart reads exactly size bytes of a streamed upload so the part can be retried or hashed
+func readPart(reader io.Reader, size int64) ([]byte, error) {
+	part := make([]byte, size)
+	if _, err := io.ReadFull(reader, part); err != nil {
+		return nil, err
+	}
+
+	return part, nil
 }
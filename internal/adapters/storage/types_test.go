@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"fluxend/internal/config/constants"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContentRange(t *testing.T) {
+	byteRange, total, err := parseContentRange("bytes 100-199/1000")
+	assert.NoError(t, err)
+	assert.Equal(t, &ByteRange{Start: 100, End: 199}, byteRange)
+	assert.Equal(t, int64(1000), total)
+
+	for _, header := range []string{"", "bytes */1000", "bytes 1-2", "items 1-2/3", "bytes a-b/3"} {
+		_, _, err := parseContentRange(header)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestPartSizeFor(t *testing.T) {
+	const mebibyte = 1024 * 1024
+
+	assert.Equal(t, int64(16*mebibyte), partSizeFor(100*mebibyte, 16*mebibyte, 10000))
+
+	// A 1TiB upload would need more than 10,000 parts of 16MiB, so parts grow instead
+	partSize := partSizeFor(1024*1024*mebibyte, 16*mebibyte, 10000)
+	assert.Greater(t, partSize, int64(16*mebibyte))
+	assert.LessOrEqual(t, int64(1024*1024*mebibyte)/partSize, int64(10000))
+}
+
+func TestCredentialsValidate(t *testing.T) {
+	assert.NoError(t, Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}.Validate(constants.StorageDriverS3))
+	assert.NoError(t, Credentials{KeyID: "key", ApplicationKey: "secret"}.Validate(constants.StorageDriverBackBlaze))
+	assert.NoError(t, Credentials{AccessToken: "token"}.Validate(constants.StorageDriverDropbox))
+
+	assert.EqualError(t, Credentials{AccessKeyID: "key"}.Validate(constants.StorageDriverS3), "credential.error.incomplete")
+	assert.EqualError(t, Credentials{AccessToken: "token"}.Validate(constants.StorageDriverBackBlaze), "credential.error.incomplete")
+	assert.EqualError(t, Credentials{}.Validate(constants.StorageDriverFilesystem), "credential.error.unsupportedDriver")
+}
@@ -0,0 +1,57 @@
+package email
+
+import (
+	"fluxend/internal/domain/email"
+)
+
+func ToCreateTemplateInput(request *CreateTemplateRequest) *email.CreateTemplateInput {
+	parameters := make(email.Parameters, len(request.Parameters))
+	for i, parameter := range request.Parameters {
+		parameters[i] = email.Parameter{
+			Name:        parameter.Name,
+			Description: parameter.Description,
+			Example:     parameter.Example,
+			Required:    parameter.Required,
+		}
+	}
+
+	return &email.CreateTemplateInput{
+		Name:        request.Name,
+		Type:        request.Type,
+		Parameters:  parameters,
+		Subject:     request.Subject,
+		Message:     request.Message,
+		HtmlMessage: request.HtmlMessage,
+		Layout:      request.Layout,
+	}
+}
+
+func ToCustomizeTemplateInput(request *CustomizeTemplateRequest) *email.CustomizeTemplateInput {
+	return &email.CustomizeTemplateInput{
+		Subject:     request.Subject,
+		Message:     request.Message,
+		HtmlMessage: request.HtmlMessage,
+	}
+}
+
+func ToListOutboxInput(request *ListOutboxRequest) *email.ListOutboxInput {
+	return &email.ListOutboxInput{
+		Status:    request.Status,
+		Recipient: request.Recipient,
+		Template:  request.Template,
+	}
+}
+
+func ToCreateSuppressionInput(request *CreateSuppressionRequest) *email.CreateSuppressionInput {
+	return &email.CreateSuppressionInput{
+		Email:   request.Email,
+		Details: request.Details,
+	}
+}
+
+func ToListSuppressionsInput(request *ListSuppressionsRequest) *email.ListSuppressionsInput {
+	return &email.ListSuppressionsInput{
+		Email:  request.Email,
+		Reason: request.Reason,
+	}
+}
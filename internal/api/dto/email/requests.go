@@ -0,0 +1,273 @@
+package email
+
+import (
+	"errors"
+	"fluxend/internal/api/dto"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/guregu/null/v6"
+	"github.com/labstack/echo/v4"
+	"regexp"
+)
+
+var parameterNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+type CreateTemplateRequest struct {
+	dto.DefaultRequest
+	Name string `json:"name"`
+
+	// message or layout, layouts get the rendered message as {{ .Content }}
+	Type       string      `json:"type"`
+	Parameters []Parameter `json:"parameters"`
+
+	// Go templates, the HTML one is escaped as it's rendered
+	Subject     string `json:"subject"`
+	Message     string `json:"message"`
+	HtmlMessage string `json:"html_message"`
+
+	// Name of the layout wrapping a message, none when null
+	Layout null.String `json:"layout" swaggertype:"string"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+	Required    bool   `json:"required"`
+}
+
+// CustomizeTemplateRequest overrides parts of a template for an organization, empty parts keep the template ones
+type CustomizeTemplateRequest struct {
+	dto.DefaultRequest
+	Subject     string `json:"subject"`
+	Message     string `json:"message"`
+	HtmlMessage string `json:"html_message"`
+}
+
+type PreviewRequest struct {
+	dto.DefaultRequest
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+type ListOutboxRequest struct {
+	dto.BaseRequest
+	Status    null.String `query:"status"`
+	Recipient null.String `query:"recipient"` // part of an address
+	Template  null.String `query:"template"`
+
+	Limit int    `query:"limit"`
+	Page  int    `query:"page"`
+	Sort  string `query:"sort"`
+	Order string `query:"order"`
+}
+
+type CreateSuppressionRequest struct {
+	dto.DefaultRequest
+	Email   string `json:"email"`
+	Details string `json:"details"` // why it's suppressed
+}
+
+type ListSuppressionsRequest struct {
+	dto.BaseRequest
+	Email  null.String `query:"email"` // part of an address
+	Reason null.String `query:"reason"`
+
+	Limit int    `query:"limit"`
+	Page  int    `query:"page"`
+	Sort  string `query:"sort"`
+	Order string `query:"order"`
+}
+
+func (r *CreateTemplateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(
+				constants.MinEmailTemplateNameLength, constants.MaxEmailTemplateNameLength,
+			).Error(
+				fmt.Sprintf(
+					"Template name must be between %d and %d characters",
+					constants.MinEmailTemplateNameLength,
+					constants.MaxEmailTemplateNameLength,
+				),
+			),
+			validation.Match(
+				regexp.MustCompile(constants.AlphanumericWithUnderscoreAndDashPattern),
+			).Error("Template name must be alphanumeric with underscores and dashes")),
+		validation.Field(
+			&r.Type,
+			validation.Required.Error("Type is required"),
+			validation.In(
+				constants.EmailTemplateTypeMessage,
+				constants.EmailTemplateTypeLayout,
+			).Error("Type must be message or layout"),
+		),
+		validation.Field(
+			&r.Parameters,
+			validation.Length(0, constants.MaxEmailTemplateParameters).Error(
+				fmt.Sprintf("At most %d parameters are allowed", constants.MaxEmailTemplateParameters),
+			),
+			validation.By(validateParameters),
+		),
+		validation.Field(&r.Subject, templateRules("Subject", constants.MaxEmailTemplateSubjectLength, false)...),
+		validation.Field(&r.Message, templateRules("Message", constants.MaxEmailTemplateMessageLength, false)...),
+		validation.Field(&r.HtmlMessage, templateRules("HTML message", constants.MaxEmailTemplateMessageLength, true)...),
+	)
+
+	if errs := r.ExtractValidationErrors(err); errs != nil {
+		return errs
+	}
+
+	if r.Layout.Valid && r.Layout.String == "" {
+		return []string{"Layout must name a layout template or be null"}
+	}
+
+	if r.Message == "" && r.HtmlMessage == "" {
+		return []string{"Either a message or an HTML message is required"}
+	}
+
+	if r.Type == constants.EmailTemplateTypeMessage && r.Subject == "" {
+		return []string{"Subject is required for messages"}
+	}
+
+	return nil
+}
+
+func (r *CustomizeTemplateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Subject, templateRules("Subject", constants.MaxEmailTemplateSubjectLength, false)...),
+		validation.Field(&r.Message, templateRules("Message", constants.MaxEmailTemplateMessageLength, false)...),
+		validation.Field(&r.HtmlMessage, templateRules("HTML message", constants.MaxEmailTemplateMessageLength, true)...),
+	)
+
+	if errs := r.ExtractValidationErrors(err); errs != nil {
+		return errs
+	}
+
+	if r.Subject == "" && r.Message == "" && r.HtmlMessage == "" {
+		return []string{"At least one of subject, message or HTML message is required"}
+	}
+
+	return nil
+}
+
+func (r *PreviewRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	return nil
+}
+
+func (r *ListOutboxRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Status,
+			validation.In(
+				constants.EmailOutboxStatusPending,
+				constants.EmailOutboxStatusSending,
+				constants.EmailOutboxStatusSent,
+				constants.EmailOutboxStatusFailed,
+				constants.EmailOutboxStatusSuppressed,
+			).Error("Status must be pending, sending, sent, failed or suppressed"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *CreateSuppressionRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Email,
+			validation.Required.Error("Email is required"),
+			validation.Length(0, 255).Error("Email must be at most 255 characters"),
+			is.EmailFormat.Error("Email must be a valid email address"),
+		),
+		validation.Field(
+			&r.Details,
+			validation.Length(0, 1000).Error("Details must be at most 1000 characters"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *ListSuppressionsRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Reason,
+			validation.In(
+				constants.EmailSuppressionReasonBounce,
+				constants.EmailSuppressionReasonComplaint,
+				constants.EmailSuppressionReasonManual,
+			).Error("Reason must be bounce, complaint or manual"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+// templateRules limit the length of a template part and check its syntax
+func templateRules(field string, maxLength int, html bool) []validation.Rule {
+	return []validation.Rule{
+		validation.Length(0, maxLength).Error(fmt.Sprintf("%s must be at most %d characters", field, maxLength)),
+		validation.By(func(value interface{}) error {
+			content, _ := value.(string)
+			if err := email.Parse(content, html); err != nil {
+				return fmt.Errorf("%s is not a valid template: %v", field, err)
+			}
+
+			return nil
+		}),
+	}
+}
+
+// validateParameters requires parameter names usable as {{ .name }}, each declared once
+func validateParameters(value interface{}) error {
+	parameters, _ := value.([]Parameter)
+
+	seen := map[string]bool{}
+	for _, parameter := range parameters {
+		if !parameterNamePattern.MatchString(parameter.Name) {
+			return errors.New("Parameter names must start with a letter and contain letters, digits and underscores only")
+		}
+
+		// Layouts get the rendered message under this name
+		if parameter.Name == "Content" {
+			return errors.New("Parameter name Content is reserved for layouts")
+		}
+
+		if seen[parameter.Name] {
+			return fmt.Errorf("Parameter %s is declared more than once", parameter.Name)
+		}
+
+		seen[parameter.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package email
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateTemplateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateTemplateRequest: valid message", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name": "password_reset",
+			"type": constants.EmailTemplateTypeMessage,
+			"parameters": []map[string]interface{}{
+				{"name": "resetUrl", "required": true, "example": "https://example.com/reset"},
+			},
+			"subject":      "Reset your password",
+			"message":      "Open {{ .resetUrl }}",
+			"html_message": `<a href="{{ .resetUrl }}">Reset</a>`,
+			"layout":       constants.EmailTemplateDefaultLayout,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "resetUrl", r.Parameters[0].Name)
+		assert.True(t, r.Parameters[0].Required)
+		assert.Equal(t, constants.EmailTemplateDefaultLayout, r.Layout.String)
+	})
+
+	t.Run("CreateTemplateRequest: valid layout without subject", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":         "branded",
+			"type":         constants.EmailTemplateTypeLayout,
+			"html_message": "<html>{{ .Content }}</html>",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.False(t, r.Layout.Valid)
+	})
+
+	t.Run("CreateTemplateRequest: invalid fields", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":       "a b",
+			"type":       "partial",
+			"parameters": []map[string]interface{}{{"name": "1st"}},
+			"subject":    "{{ .name",
+			"message":    "Hello",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 4)
+	})
+
+	t.Run("CreateTemplateRequest: reserved and duplicated parameters", func(t *testing.T) {
+		for _, parameters := range [][]map[string]interface{}{
+			{{"name": "Content"}},
+			{{"name": "name"}, {"name": "name"}},
+		} {
+			payload := map[string]interface{}{
+				"name":       "welcome",
+				"type":       constants.EmailTemplateTypeMessage,
+				"parameters": parameters,
+				"subject":    "Welcome",
+				"message":    "Hello",
+			}
+
+			ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+			var r CreateTemplateRequest
+			errs := r.BindAndValidate(ctx)
+
+			assert.Len(t, errs, 1)
+		}
+	})
+
+	t.Run("CreateTemplateRequest: message needs a subject and a body", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":    "welcome",
+			"type":    constants.EmailTemplateTypeMessage,
+			"message": "Hello",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Equal(t, []string{"Subject is required for messages"}, errs)
+
+		payload["subject"] = "Welcome"
+		delete(payload, "message")
+		ctx = pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		r = CreateTemplateRequest{}
+		errs = r.BindAndValidate(ctx)
+
+		assert.Equal(t, []string{"Either a message or an HTML message is required"}, errs)
+	})
+}
+
+func TestCustomizeTemplateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CustomizeTemplateRequest: subject only", func(t *testing.T) {
+		payload := map[string]interface{}{"subject": "Your Acme password"}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+
+		var r CustomizeTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Empty(t, r.Message)
+	})
+
+	t.Run("CustomizeTemplateRequest: nothing to customize", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, map[string]interface{}{})
+
+		var r CustomizeTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("CustomizeTemplateRequest: invalid HTML template", func(t *testing.T) {
+		payload := map[string]interface{}{"html_message": "<p>{{ if .name }}</p>"}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+
+		var r CustomizeTemplateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 1)
+	})
+}
+
+func TestListOutboxRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(query string) echo.Context {
+		return e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), httptest.NewRecorder())
+	}
+
+	t.Run("ListOutboxRequest: valid filters", func(t *testing.T) {
+		var r ListOutboxRequest
+		errs := r.BindAndValidate(newContext("status=failed&recipient=example.com&template=password_reset"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, constants.EmailOutboxStatusFailed, r.Status.String)
+		assert.Equal(t, "example.com", r.Recipient.String)
+		assert.Equal(t, "password_reset", r.Template.String)
+	})
+
+	t.Run("ListOutboxRequest: no filters", func(t *testing.T) {
+		var r ListOutboxRequest
+		errs := r.BindAndValidate(newContext(""))
+
+		assert.Len(t, errs, 0)
+		assert.False(t, r.Status.Valid)
+	})
+
+	t.Run("ListOutboxRequest: unknown status", func(t *testing.T) {
+		var r ListOutboxRequest
+		errs := r.BindAndValidate(newContext("status=bounced"))
+
+		pkg.AssertErrorContains(t, errs, "Status must be pending, sending, sent, failed or suppressed")
+	})
+}
+
+func TestCreateSuppressionRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateSuppressionRequest: valid address", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"email":   "jane@example.com",
+			"details": "Asked to stop receiving emails",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateSuppressionRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("CreateSuppressionRequest: missing address", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r CreateSuppressionRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email is required")
+	})
+
+	t.Run("CreateSuppressionRequest: invalid address", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"email": "jane"})
+
+		var r CreateSuppressionRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email must be a valid email address")
+	})
+}
+
+func TestListSuppressionsRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(query string) echo.Context {
+		return e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), httptest.NewRecorder())
+	}
+
+	t.Run("ListSuppressionsRequest: valid filters", func(t *testing.T) {
+		var r ListSuppressionsRequest
+		errs := r.BindAndValidate(newContext("email=example.com&reason=bounce"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "example.com", r.Email.String)
+		assert.Equal(t, constants.EmailSuppressionReasonBounce, r.Reason.String)
+	})
+
+	t.Run("ListSuppressionsRequest: unknown reason", func(t *testing.T) {
+		var r ListSuppressionsRequest
+		errs := r.BindAndValidate(newContext("reason=unsubscribe"))
+
+		pkg.AssertErrorContains(t, errs, "Reason must be bounce, complaint or manual")
+	})
+}
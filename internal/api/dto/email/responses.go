@@ -0,0 +1,113 @@
+package email
+
+import (
+	"github.com/google/uuid"
+)
+
+type TemplateResponse struct {
+	Uuid        uuid.UUID           `json:"uuid"`
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Parameters  []ParameterResponse `json:"parameters"`
+	Subject     string              `json:"subject"`
+	Message     string              `json:"message"`
+	HtmlMessage string              `json:"htmlMessage"`
+	Layout      *string             `json:"layout"`
+	CreatedAt   string              `json:"createdAt"`
+	UpdatedAt   string              `json:"updatedAt"`
+}
+
+type ParameterResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+	Required    bool   `json:"required"`
+}
+
+type PreviewResponse struct {
+	Subject     string `json:"subject"`
+	Message     string `json:"message"`
+	HtmlMessage string `json:"htmlMessage"`
+}
+
+type OutboxMessageResponse struct {
+	Uuid              uuid.UUID  `json:"uuid"`
+	Subject           string     `json:"subject"`
+	Recipients        string     `json:"recipients"`
+	Template          *string    `json:"template"`
+	OrganizationUuid  *uuid.UUID `json:"organizationUuid"`
+	IsSensitive       bool       `json:"isSensitive"`
+	Status            string     `json:"status"`
+	Attempts          int        `json:"attempts"`
+	MaxAttempts       int        `json:"maxAttempts"`
+	NextAttemptAt     string     `json:"nextAttemptAt"`
+	Driver            string     `json:"driver"`
+	ProviderMessageId string     `json:"providerMessageId"`
+	LastError         string     `json:"lastError"`
+	SentAt            *string    `json:"sentAt"`
+	CreatedAt         string     `json:"createdAt"`
+	UpdatedAt         string     `json:"updatedAt"`
+}
+
+type OutboxDetailsResponse struct {
+	OutboxMessageResponse
+	From        string               `json:"from"`
+	To          []string             `json:"to"`
+	Cc          []string             `json:"cc"`
+	Bcc         []string             `json:"bcc"`
+	ReplyTo     []string             `json:"replyTo"`
+	Message     string               `json:"message"`
+	HtmlMessage string               `json:"htmlMessage"`
+	Attachments []AttachmentResponse `json:"attachments"`
+	Deliveries  []DeliveryResponse   `json:"deliveries"`
+}
+
+// AttachmentResponse describes an attachment, its content is left out
+type AttachmentResponse struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+type DeliveryResponse struct {
+	Uuid              uuid.UUID `json:"uuid"`
+	Attempt           int       `json:"attempt"`
+	Driver            string    `json:"driver"`
+	Status            string    `json:"status"`
+	ProviderMessageId string    `json:"providerMessageId"`
+	Error             string    `json:"error"`
+	CreatedAt         string    `json:"createdAt"`
+}
+
+type CapturedMessageResponse struct {
+	Id        string   `json:"id"`
+	From      string   `json:"from"`
+	To        []string `json:"to"`
+	Cc        []string `json:"cc"`
+	Bcc       []string `json:"bcc"`
+	ReplyTo   []string `json:"replyTo"`
+	Subject   string   `json:"subject"`
+	Size      int64    `json:"size"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+type CapturedMessageDetailsResponse struct {
+	CapturedMessageResponse
+	MessageId   string               `json:"messageId"`
+	Headers     map[string]string    `json:"headers"`
+	Message     string               `json:"message"`
+	HtmlMessage string               `json:"htmlMessage"`
+	Attachments []AttachmentResponse `json:"attachments"`
+}
+
+type SuppressionResponse struct {
+	Uuid              uuid.UUID  `json:"uuid"`
+	Email             string     `json:"email"`
+	Reason            string     `json:"reason"`
+	Driver            string     `json:"driver"`
+	ProviderMessageId string     `json:"providerMessageId"`
+	Details           string     `json:"details"`
+	CreatedBy         *uuid.UUID `json:"createdBy"`
+	CreatedAt         string     `json:"createdAt"`
+	UpdatedAt         string     `json:"updatedAt"`
+}
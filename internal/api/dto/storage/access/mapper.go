@@ -0,0 +1,25 @@
+package access
+
+import (
+	"fluxend/internal/domain/storage/access"
+	"fluxend/internal/domain/storage/file"
+)
+
+func ToCreatePolicyInput(request *CreateRequest) *access.CreatePolicyInput {
+	return &access.CreatePolicyInput{
+		Name:        request.Name,
+		Operations:  request.Operations,
+		PathPattern: request.PathPattern,
+		Roles:       request.Roles,
+		IsEnabled:   request.IsEnabled,
+	}
+}
+
+func ToStoreFileInput(request *UploadRequest) *file.StoreFileInput {
+	return &file.StoreFileInput{
+		FullFileName: request.FullFileName,
+		MimeType:     request.MimeType,
+		Size:         request.Size,
+		Body:         request.Body,
+	}
+}
@@ -0,0 +1,189 @@
+package access
+
+import (
+	"errors"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/api/dto"
+	fileDto "fluxend/internal/api/dto/storage/file"
+	"fluxend/internal/config/constants"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var claimNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+type CreateRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Name       string   `json:"name"`
+	Operations []string `json:"operations"`
+
+	// Full file names the policy applies to, * matches any characters and {claim} the claim of the token,
+	// like users/{sub}/*
+	PathPattern string `json:"path_pattern"`
+
+	// Roles of the token the policy applies to, every role when empty
+	Roles []string `json:"roles"`
+
+	// Policies are enabled unless asked otherwise
+	IsEnabled bool `json:"is_enabled"`
+}
+
+// ObjectRequest addresses a file of a container by its full file name, taken from the rest of the path
+type ObjectRequest struct {
+	dto.DefaultRequest
+	FullFileName string             `json:"-"`
+	ByteRange    *storage.ByteRange `json:"-"`
+}
+
+// UploadRequest carries the content of a file as its raw body
+type UploadRequest struct {
+	ObjectRequest
+	MimeType string    `json:"-"`
+	Size     int64     `json:"-"`
+	Body     io.Reader `json:"-"`
+}
+
+func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
+	r.IsEnabled = true
+
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(
+				constants.MinPolicyNameLength, constants.MaxPolicyNameLength,
+			).Error(
+				fmt.Sprintf(
+					"Policy name must be between %d and %d characters",
+					constants.MinPolicyNameLength,
+					constants.MaxPolicyNameLength,
+				),
+			),
+			validation.Match(
+				regexp.MustCompile(constants.AlphanumericWithUnderscoreAndDashPattern),
+			).Error("Policy name must be alphanumeric with underscores and dashes")),
+		validation.Field(
+			&r.Operations,
+			validation.Required.Error("At least one operation is required"),
+			validation.Each(validation.In(
+				constants.PolicyOperationRead,
+				constants.PolicyOperationUpload,
+				constants.PolicyOperationDelete,
+			).Error("Operations must be read, upload or delete")),
+		),
+		validation.Field(
+			&r.PathPattern,
+			validation.Required.Error("path_pattern is required"),
+			validation.Length(0, constants.MaxFileNameLength).Error(
+				fmt.Sprintf("path_pattern must be less than %d characters", constants.MaxFileNameLength),
+			),
+			validation.By(validatePathPattern),
+		),
+		validation.Field(
+			&r.Roles,
+			validation.Length(0, constants.MaxPolicyRoles).Error(
+				fmt.Sprintf("At most %d roles are allowed", constants.MaxPolicyRoles),
+			),
+			validation.Each(
+				validation.Required.Error("Roles must not be empty"),
+				validation.Length(0, constants.MaxPolicyRoleLength).Error(
+					fmt.Sprintf("Roles must be at most %d characters", constants.MaxPolicyRoleLength),
+				),
+			),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *ObjectRequest) BindAndValidate(c echo.Context) []string {
+	// Everything comes from the path and headers, an upload body is the file itself so nothing is bound
+	fullFileName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return []string{"File name is invalid"}
+	}
+
+	r.FullFileName = fullFileName
+	r.ByteRange = fileDto.ParseRangeHeader(c.Request().Header.Get("Range"))
+
+	err = validation.ValidateStruct(r,
+		validation.Field(
+			&r.FullFileName,
+			validation.Required.Error("File name is required"),
+			validation.Length(
+				constants.MinContainerNameLength, constants.MaxContainerNameLength,
+			).Error(
+				fmt.Sprintf(
+					"File name must be between %d and %d characters",
+					constants.MinContainerNameLength,
+					constants.MaxContainerNameLength,
+				),
+			),
+			validation.By(fileDto.NotReservedName),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *UploadRequest) BindAndValidate(c echo.Context) []string {
+	if err := r.ObjectRequest.BindAndValidate(c); err != nil {
+		return err
+	}
+
+	size, err := strconv.ParseInt(c.Request().Header.Get(echo.HeaderContentLength), 10, 64)
+	if err != nil || size < 1 {
+		return []string{"Content-Length must be a positive number"}
+	}
+
+	r.Size = size
+	r.MimeType = c.Request().Header.Get(echo.HeaderContentType)
+	if r.MimeType == "" {
+		r.MimeType = echo.MIMEOctetStream
+	}
+
+	r.Body = io.LimitReader(c.Request().Body, size)
+
+	return nil
+}
+
+// validatePathPattern requires every {claim} placeholder to be closed and named after a claim
+func validatePathPattern(value interface{}) error {
+	pattern, _ := value.(string)
+
+	for {
+		start := strings.IndexAny(pattern, "{}")
+		if start == -1 {
+			return nil
+		}
+
+		if pattern[start] == '}' {
+			return errors.New("path_pattern has an unopened }")
+		}
+
+		end := strings.IndexByte(pattern[start:], '}')
+		if end == -1 {
+			return errors.New("path_pattern has an unclosed {")
+		}
+
+		if !claimNamePattern.MatchString(pattern[start+1 : start+end]) {
+			return errors.New("path_pattern placeholders must name a claim, like {sub}")
+		}
+
+		pattern = pattern[start+end+1:]
+	}
+}
@@ -0,0 +1,176 @@
+package access
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateRequest: valid policy", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":         "own_avatars",
+			"operations":   []string{constants.PolicyOperationRead, constants.PolicyOperationUpload},
+			"path_pattern": "users/{sub}/*",
+			"roles":        []string{"authenticated"},
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "users/{sub}/*", r.PathPattern)
+		assert.Equal(t, []string{"authenticated"}, r.Roles)
+		assert.True(t, r.IsEnabled)
+	})
+
+	t.Run("CreateRequest: disabled policy for every role", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":         "public_read",
+			"operations":   []string{constants.PolicyOperationRead},
+			"path_pattern": "public/*",
+			"is_enabled":   false,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Empty(t, r.Roles)
+		assert.False(t, r.IsEnabled)
+	})
+
+	t.Run("CreateRequest: invalid fields", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":         "a",
+			"operations":   []string{"write"},
+			"path_pattern": strings.Repeat("p", constants.MaxFileNameLength+1),
+			"roles":        []string{""},
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 4)
+	})
+
+	t.Run("CreateRequest: invalid placeholders", func(t *testing.T) {
+		for _, pattern := range []string{"users/{sub/*", "users/sub}/*", "users/{}/*", "users/{user id}/*"} {
+			payload := map[string]interface{}{
+				"name":         "own_files",
+				"operations":   []string{constants.PolicyOperationRead},
+				"path_pattern": pattern,
+			}
+
+			ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+			ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+			var r CreateRequest
+			errs := r.BindAndValidate(ctx)
+
+			assert.Len(t, errs, 1, pattern)
+			pkg.AssertErrorContains(t, errs, "path_pattern")
+		}
+	})
+
+	t.Run("CreateRequest: missing fields", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 3)
+	})
+
+	t.Run("CreateRequest: missing project header", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":         "own_files",
+			"operations":   []string{constants.PolicyOperationRead},
+			"path_pattern": "users/{sub}/*",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.NotEmpty(t, errs)
+	})
+}
+
+func TestUploadRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(fileName, body string) echo.Context {
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+
+		ctx := e.NewContext(request, httptest.NewRecorder())
+		ctx.SetParamNames("containerUUID", "*")
+		ctx.SetParamValues(dummyProjectUUID, fileName)
+
+		return ctx
+	}
+
+	t.Run("UploadRequest: valid upload", func(t *testing.T) {
+		ctx := newContext("users/42/avatar%20large.png", "png bytes")
+		ctx.Request().Header.Set(echo.HeaderContentType, "image/png")
+
+		var r UploadRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "users/42/avatar large.png", r.FullFileName)
+		assert.Equal(t, "image/png", r.MimeType)
+		assert.Equal(t, int64(9), r.Size)
+	})
+
+	t.Run("UploadRequest: content type defaults to octet-stream", func(t *testing.T) {
+		var r UploadRequest
+		errs := r.BindAndValidate(newContext("users/42/notes", "notes"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, echo.MIMEOctetStream, r.MimeType)
+	})
+
+	t.Run("UploadRequest: empty body", func(t *testing.T) {
+		var r UploadRequest
+		errs := r.BindAndValidate(newContext("users/42/avatar.png", ""))
+
+		assert.Equal(t, []string{"Content-Length must be a positive number"}, errs)
+	})
+
+	t.Run("UploadRequest: missing file name", func(t *testing.T) {
+		var r UploadRequest
+		errs := r.BindAndValidate(newContext("", "png bytes"))
+
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("UploadRequest: reserved prefix", func(t *testing.T) {
+		var r UploadRequest
+		errs := r.BindAndValidate(newContext(".variants%2Fabc", "png bytes"))
+
+		pkg.AssertErrorContains(t, errs, "reserved prefix")
+	})
+}
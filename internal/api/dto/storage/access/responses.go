@@ -0,0 +1,19 @@
+package access
+
+import (
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	Uuid          uuid.UUID `json:"uuid"`
+	ContainerUuid uuid.UUID `json:"containerUuid"`
+	Name          string    `json:"name"`
+	Operations    []string  `json:"operations"`
+	PathPattern   string    `json:"pathPattern"`
+	Roles         []string  `json:"roles"`
+	IsEnabled     bool      `json:"isEnabled"`
+	CreatedBy     uuid.UUID `json:"createdBy"`
+	UpdatedBy     uuid.UUID `json:"updatedBy"`
+	CreatedAt     string    `json:"createdAt"`
+	UpdatedAt     string    `json:"updatedAt"`
+}
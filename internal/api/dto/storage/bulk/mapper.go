@@ -0,0 +1,12 @@
+package bulk
+
+import (
+	"fluxend/internal/domain/storage/bulk"
+)
+
+func ToSelectFilesInput(request *SelectRequest) *bulk.SelectFilesInput {
+	return &bulk.SelectFilesInput{
+		Prefix:    request.Prefix,
+		FileUUIDs: request.FileUUIDs,
+	}
+}
@@ -0,0 +1,44 @@
+package bulk
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/config/constants"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// SelectRequest picks files either by name prefix or by UUID
+type SelectRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Prefix    string      `json:"prefix"`
+	FileUUIDs []uuid.UUID `json:"file_uuids"`
+}
+
+func (r *SelectRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	if r.Prefix == "" && len(r.FileUUIDs) == 0 {
+		return []string{"Either prefix or file_uuids is required"}
+	}
+
+	if r.Prefix != "" && len(r.FileUUIDs) > 0 {
+		return []string{"Only one of prefix and file_uuids can be given"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.FileUUIDs,
+			validation.Length(0, constants.MaxBulkFileUUIDs).Error(fmt.Sprintf("file_uuids must not have more than %d entries", constants.MaxBulkFileUUIDs)),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
@@ -0,0 +1,78 @@
+package bulk
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestSelectRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(payload map[string]interface{}) echo.Context {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		return ctx
+	}
+
+	t.Run("SelectRequest: valid prefix", func(t *testing.T) {
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{"prefix": "reports/2025/"}))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "reports/2025/", ToSelectFilesInput(&r).Prefix)
+	})
+
+	t.Run("SelectRequest: valid file UUIDs", func(t *testing.T) {
+		fileUUID := uuid.New()
+
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{"file_uuids": []string{fileUUID.String()}}))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, []uuid.UUID{fileUUID}, ToSelectFilesInput(&r).FileUUIDs)
+	})
+
+	t.Run("SelectRequest: empty selection", func(t *testing.T) {
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{}))
+
+		assert.Equal(t, []string{"Either prefix or file_uuids is required"}, errs)
+	})
+
+	t.Run("SelectRequest: prefix and file UUIDs", func(t *testing.T) {
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{
+			"prefix":     "reports/",
+			"file_uuids": []string{uuid.NewString()},
+		}))
+
+		assert.Equal(t, []string{"Only one of prefix and file_uuids can be given"}, errs)
+	})
+
+	t.Run("SelectRequest: too many file UUIDs", func(t *testing.T) {
+		fileUUIDs := make([]string, constants.MaxBulkFileUUIDs+1)
+		for i := range fileUUIDs {
+			fileUUIDs[i] = uuid.NewString()
+		}
+
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{"file_uuids": fileUUIDs}))
+
+		pkg.AssertErrorContains(t, errs, "file_uuids must not have more than")
+	})
+
+	t.Run("SelectRequest: malformed file UUID", func(t *testing.T) {
+		var r SelectRequest
+		errs := r.BindAndValidate(newContext(map[string]interface{}{"file_uuids": []string{"not-a-uuid"}}))
+
+		assert.Equal(t, []string{"Invalid request payload"}, errs)
+	})
+}
@@ -0,0 +1,27 @@
+package bulk
+
+import (
+	"github.com/google/uuid"
+)
+
+type JobResponse struct {
+	Uuid          uuid.UUID        `json:"uuid"`
+	ContainerUuid uuid.UUID        `json:"containerUuid"`
+	Status        string           `json:"status"`
+	Error         string           `json:"error"`
+	TotalFiles    int              `json:"totalFiles"`
+	DeletedFiles  int              `json:"deletedFiles"`
+	FailedFiles   int              `json:"failedFiles"`
+	CreatedBy     uuid.NullUUID    `json:"createdBy" swaggertype:"string"`
+	StartedAt     string           `json:"startedAt"`
+	CompletedAt   *string          `json:"completedAt"`
+	Results       []ResultResponse `json:"results"`
+}
+
+type ResultResponse struct {
+	FileUuid     uuid.UUID `json:"fileUuid"`
+	FullFileName string    `json:"fullFileName"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error"`
+	ProcessedAt  string    `json:"processedAt"`
+}
@@ -6,10 +6,16 @@ import (
 
 func ToCreateContainerInput(request *CreateRequest) *container.CreateContainerInput {
 	return &container.CreateContainerInput{
-		ProjectUUID: request.ProjectUUID,
-		Name:        request.Name,
-		Description: request.Description,
-		IsPublic:    request.IsPublic,
-		MaxFileSize: request.MaxFileSize,
+		ProjectUUID:             request.ProjectUUID,
+		Name:                    request.Name,
+		Description:             request.Description,
+		IsPublic:                request.IsPublic,
+		MaxFileSize:             request.MaxFileSize,
+		Versioning:              request.Versioning,
+		TrashRetentionDays:      request.TrashRetentionDays,
+		Deduplication:           request.Deduplication,
+		Driver:                  request.Driver,
+		CredentialUUID:          request.CredentialUUID,
+		QuarantineContainerUUID: request.QuarantineContainerUUID,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"fluxend/internal/config/constants"
 	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"regexp"
 )
@@ -15,6 +16,19 @@ type CreateRequest struct {
 	Description string `json:"description"`
 	IsPublic    bool   `json:"is_public"`
 	MaxFileSize int    `json:"max_file_size"`
+
+	Versioning         bool `json:"versioning"`
+	TrashRetentionDays int  `json:"trash_retention_days"`
+
+	// Files with the same content are stored once, can't be combined with versioning
+	Deduplication bool `json:"deduplication"`
+
+	// Uploads flagged by a scanner go to this container instead of being rejected
+	QuarantineContainerUUID uuid.NullUUID `json:"quarantine_container_uuid"`
+
+	// Only used when creating a container, the driver defaults to the credential's or the instance wide one
+	Driver         string        `json:"driver"`
+	CredentialUUID uuid.NullUUID `json:"credential_uuid"`
 }
 
 func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
@@ -55,6 +69,22 @@ func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
 				),
 			),
 		),
+		validation.Field(
+			&r.TrashRetentionDays,
+			validation.Min(0).Error("trash_retention_days can't be negative"),
+			validation.Max(constants.MaxTrashRetentionDays).Error(
+				fmt.Sprintf("trash_retention_days must be at most %d", constants.MaxTrashRetentionDays),
+			),
+		),
+		validation.Field(
+			&r.Driver,
+			validation.In(
+				constants.StorageDriverFilesystem,
+				constants.StorageDriverS3,
+				constants.StorageDriverBackBlaze,
+				constants.StorageDriverDropbox,
+			).Error("Driver must be one of FILESYSTEM, S3, BACKBLAZE or DROPBOX"),
+		),
 	)
 
 	return r.ExtractValidationErrors(err)
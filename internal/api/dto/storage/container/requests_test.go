@@ -99,6 +99,101 @@ func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
 		assert.Equal(t, 512, r.MaxFileSize)
 	})
 
+	t.Run("CreateRequest: valid with driver and credential", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":            "archives",
+			"max_file_size":   1024,
+			"driver":          constants.StorageDriverBackBlaze,
+			"credential_uuid": dummyProjectUUID,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, constants.StorageDriverBackBlaze, r.Driver)
+		assert.True(t, r.CredentialUUID.Valid)
+		assert.Equal(t, dummyProjectUUID, r.CredentialUUID.UUID.String())
+	})
+
+	t.Run("CreateRequest: unknown driver", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":          "archives",
+			"max_file_size": 1024,
+			"driver":        "FTP",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Driver must be one of")
+	})
+
+	t.Run("CreateRequest: valid with versioning and trash", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":                 "documents",
+			"max_file_size":        1024,
+			"versioning":           true,
+			"trash_retention_days": 30,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+
+		input := ToCreateContainerInput(&r)
+		assert.True(t, input.Versioning)
+		assert.Equal(t, 30, input.TrashRetentionDays)
+	})
+
+	t.Run("CreateRequest: valid with deduplication", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":          "attachments",
+			"max_file_size": 1024,
+			"deduplication": true,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+
+		input := ToCreateContainerInput(&r)
+		assert.True(t, input.Deduplication)
+		assert.False(t, input.Versioning)
+	})
+
+	t.Run("CreateRequest: trash retention out of range", func(t *testing.T) {
+		for _, retentionDays := range []int{-1, constants.MaxTrashRetentionDays + 1} {
+			payload := map[string]interface{}{
+				"name":                 "documents",
+				"max_file_size":        1024,
+				"trash_retention_days": retentionDays,
+			}
+
+			ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+			ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+			var r CreateRequest
+			errs := r.BindAndValidate(ctx)
+
+			pkg.AssertErrorContains(t, errs, "trash_retention_days")
+		}
+	})
+
 	t.Run("CreateRequest: invalid", func(t *testing.T) {
 		tests := []struct {
 			name     string
@@ -5,16 +5,23 @@ import (
 )
 
 type Response struct {
-	Uuid        uuid.UUID `json:"uuid"`
-	ProjectUuid uuid.UUID `json:"projectUuid"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	IsPublic    bool      `json:"isPublic"`
-	Url         string    `json:"url"`
-	TotalFiles  int       `json:"totalFiles"`
-	MaxFileSize int       `json:"maxFileSize"`
-	CreatedBy   uuid.UUID `json:"createdBy"`
-	UpdatedBy   uuid.UUID `json:"updatedBy"`
-	CreatedAt   string    `json:"createdAt"`
-	UpdatedAt   string    `json:"updatedAt"`
+	Uuid               uuid.UUID     `json:"uuid"`
+	ProjectUuid        uuid.UUID     `json:"projectUuid"`
+	Name               string        `json:"name"`
+	Provider           string        `json:"provider"`
+	CredentialUuid     uuid.NullUUID `json:"credentialUuid"`
+	Description        string        `json:"description"`
+	IsPublic           bool          `json:"isPublic"`
+	Url                string        `json:"url"`
+	TotalFiles         int           `json:"totalFiles"`
+	MaxFileSize        int           `json:"maxFileSize"`
+	Versioning         bool          `json:"versioning"`
+	TrashRetentionDays int           `json:"trashRetentionDays"`
+	Deduplication      bool          `json:"deduplication"`
+	CreatedBy          uuid.UUID     `json:"createdBy"`
+	UpdatedBy          uuid.UUID     `json:"updatedBy"`
+	CreatedAt          string        `json:"createdAt"`
+	UpdatedAt          string        `json:"updatedAt"`
+
+	QuarantineContainerUuid uuid.NullUUID `json:"quarantineContainerUuid" swaggertype:"string"`
 }
@@ -0,0 +1,36 @@
+package credential
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/credential"
+)
+
+func ToCreateCredentialInput(request *CreateRequest) *credential.CreateCredentialInput {
+	return &credential.CreateCredentialInput{
+		ProjectUUID: request.ProjectUUID,
+		Name:        request.Name,
+		Driver:      request.Driver,
+		Credentials: toCredentials(request.SecretsRequest),
+	}
+}
+
+func ToUpdateCredentialInput(request *UpdateRequest) *credential.UpdateCredentialInput {
+	return &credential.UpdateCredentialInput{
+		Name:        request.Name,
+		Credentials: toCredentials(request.SecretsRequest),
+	}
+}
+
+func toCredentials(request SecretsRequest) storage.Credentials {
+	return storage.Credentials{
+		AccessKeyID:     request.AccessKeyID,
+		SecretAccessKey: request.SecretAccessKey,
+		Region:          request.Region,
+		Endpoint:        request.Endpoint,
+		UsePathStyle:    request.UsePathStyle,
+		DisableChecksum: request.DisableChecksum,
+		KeyID:           request.KeyID,
+		ApplicationKey:  request.ApplicationKey,
+		AccessToken:     request.AccessToken,
+	}
+}
@@ -0,0 +1,119 @@
+package credential
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/config/constants"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"net/url"
+	"regexp"
+)
+
+type CreateRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Name   string `json:"name"`
+	Driver string `json:"driver"`
+	SecretsRequest
+}
+
+type UpdateRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Name string `json:"name"`
+	SecretsRequest
+}
+
+// SecretsRequest holds the secrets of every driver, which ones are required depends on the driver
+type SecretsRequest struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	Region          string `json:"region"`
+	Endpoint        string `json:"endpoint"`
+	UsePathStyle    bool   `json:"use_path_style"`
+	DisableChecksum bool   `json:"disable_checksum"`
+	KeyID           string `json:"key_id"`
+	ApplicationKey  string `json:"application_key"`
+	AccessToken     string `json:"access_token"`
+}
+
+func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		nameRule(&r.Name),
+		validation.Field(
+			&r.Driver,
+			validation.Required.Error("Driver is required"),
+			validation.In(
+				constants.StorageDriverS3,
+				constants.StorageDriverBackBlaze,
+				constants.StorageDriverDropbox,
+			).Error("Driver must be one of S3, BACKBLAZE or DROPBOX"),
+		),
+		endpointRule(&r.Endpoint),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *UpdateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r, nameRule(&r.Name), endpointRule(&r.Endpoint))
+
+	return r.ExtractValidationErrors(err)
+}
+
+func nameRule(name *string) *validation.FieldRules {
+	return validation.Field(
+		name,
+		validation.Required.Error("Name is required"),
+		validation.Length(
+			constants.MinCredentialNameLength, constants.MaxCredentialNameLength,
+		).Error(
+			fmt.Sprintf(
+				"Credential name must be between %d and %d characters",
+				constants.MinCredentialNameLength,
+				constants.MaxCredentialNameLength,
+			),
+		),
+		validation.Match(
+			regexp.MustCompile(constants.AlphanumericWithSpaceUnderScoreAndDashPattern),
+		).Error("Credential name must be alphanumeric with spaces, underscores and dashes"),
+	)
+}
+
+// endpointRule accepts an empty endpoint, which keeps the AWS default
+func endpointRule(endpoint *string) *validation.FieldRules {
+	return validation.Field(
+		endpoint,
+		validation.Length(0, constants.MaxCredentialEndpointLength).Error(
+			fmt.Sprintf("Endpoint must be at most %d characters", constants.MaxCredentialEndpointLength),
+		),
+		validation.By(func(value interface{}) error {
+			endpoint, _ := value.(string)
+			if endpoint == "" {
+				return nil
+			}
+
+			parsed, err := url.Parse(endpoint)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("Endpoint must be an http or https URL")
+			}
+
+			return nil
+		}),
+	)
+}
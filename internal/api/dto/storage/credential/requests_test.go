@@ -0,0 +1,181 @@
+package credential
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":              "Customer uploads",
+			"driver":            constants.StorageDriverS3,
+			"access_key_id":     "AKIAEXAMPLE",
+			"secret_access_key": "secret",
+			"region":            "eu-central-1",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "Customer uploads", r.Name)
+
+		input := ToCreateCredentialInput(&r)
+		assert.Equal(t, constants.StorageDriverS3, input.Driver)
+		assert.Equal(t, "AKIAEXAMPLE", input.Credentials.AccessKeyID)
+		assert.Equal(t, "secret", input.Credentials.SecretAccessKey)
+		assert.Equal(t, "eu-central-1", input.Credentials.Region)
+	})
+
+	t.Run("CreateRequest: S3 compatible endpoint", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":              "MinIO",
+			"driver":            constants.StorageDriverS3,
+			"access_key_id":     "minioadmin",
+			"secret_access_key": "minioadmin",
+			"endpoint":          "http://minio:9000",
+			"use_path_style":    true,
+			"disable_checksum":  true,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+
+		input := ToCreateCredentialInput(&r)
+		assert.Equal(t, "http://minio:9000", input.Credentials.Endpoint)
+		assert.True(t, input.Credentials.UsePathStyle)
+		assert.True(t, input.Credentials.DisableChecksum)
+	})
+
+	t.Run("CreateRequest: invalid endpoint", func(t *testing.T) {
+		for _, endpoint := range []string{"minio:9000", "ftp://minio:9000", "https://"} {
+			payload := map[string]interface{}{
+				"name":              "MinIO",
+				"driver":            constants.StorageDriverS3,
+				"access_key_id":     "minioadmin",
+				"secret_access_key": "minioadmin",
+				"endpoint":          endpoint,
+			}
+
+			ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+			ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+			var r CreateRequest
+			errs := r.BindAndValidate(ctx)
+
+			pkg.AssertErrorContains(t, errs, "Endpoint must be an http or https URL")
+		}
+	})
+
+	t.Run("CreateRequest: missing name and driver", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Name is required")
+		pkg.AssertErrorContains(t, errs, "Driver is required")
+	})
+
+	t.Run("CreateRequest: filesystem driver takes no credentials", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":   "Local",
+			"driver": constants.StorageDriverFilesystem,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Driver must be one of S3, BACKBLAZE or DROPBOX")
+	})
+
+	t.Run("CreateRequest: name too long", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":   strings.Repeat("a", constants.MaxCredentialNameLength+1),
+			"driver": constants.StorageDriverDropbox,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Credential name must be between")
+	})
+
+	t.Run("CreateRequest: missing project header", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":   "Archive",
+			"driver": constants.StorageDriverBackBlaze,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.NotEmpty(t, errs)
+	})
+}
+
+func TestUpdateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("UpdateRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":            "Archive",
+			"key_id":          "key",
+			"application_key": "secret",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r UpdateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+
+		input := ToUpdateCredentialInput(&r)
+		assert.Equal(t, "Archive", input.Name)
+		assert.Equal(t, "key", input.Credentials.KeyID)
+		assert.Equal(t, "secret", input.Credentials.ApplicationKey)
+	})
+
+	t.Run("UpdateRequest: invalid name characters", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name": "archive/2025",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r UpdateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Credential name must be alphanumeric")
+	})
+}
@@ -0,0 +1,17 @@
+package credential
+
+import (
+	"github.com/google/uuid"
+)
+
+// Response never includes the secrets, they can only be replaced
+type Response struct {
+	Uuid        uuid.UUID `json:"uuid"`
+	ProjectUuid uuid.UUID `json:"projectUuid"`
+	Name        string    `json:"name"`
+	Driver      string    `json:"driver"`
+	CreatedBy   uuid.UUID `json:"createdBy"`
+	UpdatedBy   uuid.UUID `json:"updatedBy"`
+	CreatedAt   string    `json:"createdAt"`
+	UpdatedAt   string    `json:"updatedAt"`
+}
@@ -9,6 +9,29 @@ func ToCreateFileInput(request *CreateRequest) *file.CreateFileInput {
 		ProjectUUID:  request.ProjectUUID,
 		FullFileName: request.FullFileName,
 		File:         request.File,
+		Metadata:     request.parsedMetadata,
+		Tags:         request.parsedTags,
+	}
+}
+
+func ToListFilesInput(request *ListRequest) file.ListFilesInput {
+	return file.ListFilesInput{
+		Prefix:        request.Prefix,
+		Delimiter:     request.Delimiter,
+		MimeType:      request.MimeType,
+		MinSize:       request.MinSize,
+		MaxSize:       request.MaxSize,
+		CreatedAfter:  request.createdAfter,
+		CreatedBefore: request.createdBefore,
+		Tag:           request.Tag,
+		Metadata:      request.metadata,
+	}
+}
+
+func ToUpdateMetadataInput(request *UpdateMetadataRequest) *file.UpdateMetadataInput {
+	return &file.UpdateMetadataInput{
+		Metadata: request.Metadata,
+		Tags:     request.Tags,
 	}
 }
 
@@ -18,3 +41,9 @@ func ToRenameFileInput(request *RenameRequest) *file.RenameFileInput {
 		FullFileName: request.FullFileName,
 	}
 }
+
+func ToRestoreFileInput(request *RestoreRequest) *file.RestoreFileInput {
+	return &file.RestoreFileInput{
+		FullFileName: request.FullFileName,
+	}
+}
@@ -1,18 +1,62 @@
 package file
 
 import (
+	"encoding/json"
+	"errors"
+	"fluxend/internal/adapters/imaging"
+	"fluxend/internal/adapters/storage"
 	"fluxend/internal/api/dto"
 	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/file"
 	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v4"
+	"image"
 	"mime/multipart"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type CreateRequest struct {
 	dto.DefaultRequestWithProjectHeader
 	FullFileName string                `json:"-" form:"full_file_name"`
 	File         *multipart.FileHeader `json:"-" form:"file"`
+	Metadata     string                `json:"-" form:"metadata"` // JSON object of strings
+	Tags         string                `json:"-" form:"tags"`     // comma separated
+
+	parsedMetadata map[string]string
+	parsedTags     []string
+}
+
+// ListRequest filters the files of a container, dates are either a day like 2025-04-07 or RFC 3339.
+// Metadata is matched with metadata[key]=value pairs, which the binder can't read into a map
+type ListRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Prefix        string `query:"prefix"`
+	Delimiter     string `query:"delimiter"`
+	MimeType      string `query:"mime_type"`
+	MinSize       int    `query:"min_size"` // in KB
+	MaxSize       int    `query:"max_size"` // in KB
+	CreatedAfter  string `query:"created_after"`
+	CreatedBefore string `query:"created_before"`
+	Tag           string `query:"tag"`
+
+	createdAfter  *time.Time
+	createdBefore *time.Time
+	metadata      map[string]string
+}
+
+// BrowseRequest lists one level of the folder hierarchy, the delimiter defaults to a slash
+type BrowseRequest struct {
+	ListRequest
+}
+
+type UpdateMetadataRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Metadata map[string]string `json:"metadata"`
+	Tags     []string          `json:"tags"`
 }
 
 type RenameRequest struct {
@@ -20,6 +64,35 @@ type RenameRequest struct {
 	FullFileName string `json:"full_file_name"`
 }
 
+// RestoreRequest restores a trashed file, under another name when FullFileName is set
+type RestoreRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	FullFileName string `json:"full_file_name"`
+}
+
+type DownloadRequest struct {
+	dto.DefaultRequest
+	ByteRange *storage.ByteRange `json:"-"`
+}
+
+type TransformRequest struct {
+	dto.DefaultRequest
+	Width   int    `query:"width"`
+	Height  int    `query:"height"`
+	Fit     string `query:"fit"`
+	Crop    string `query:"crop"`
+	Format  string `query:"format"`
+	Quality int    `query:"quality"`
+
+	cropRectangle *image.Rectangle
+}
+
+type SignedTransformRequest struct {
+	TransformRequest
+	Expires   string
+	Signature string
+}
+
 func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
 	if err := c.Bind(r); err != nil {
 		return []string{"Invalid request payload"}
@@ -41,8 +114,68 @@ func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
 					constants.MinContainerNameLength,
 					constants.MaxContainerNameLength,
 				),
-			)),
+			),
+			validation.By(NotReservedName),
+		),
 		validation.Field(&r.File, validation.By(fileRequired)),
+		validation.Field(&r.Metadata, validation.By(r.parseMetadata)),
+		validation.Field(&r.Tags, validation.By(r.parseTags)),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *ListRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	r.metadata = parseMetadataFilter(c.QueryParams())
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.MinSize, validation.Min(0).Error("min_size must not be negative")),
+		validation.Field(&r.MaxSize, validation.Min(0).Error("max_size must not be negative")),
+		validation.Field(&r.MimeType, validation.By(validateMimeTypeFilter)),
+		validation.Field(&r.CreatedAfter, validation.By(func(value interface{}) error {
+			return parseDateFilter(value, &r.createdAfter)
+		})),
+		validation.Field(&r.CreatedBefore, validation.By(func(value interface{}) error {
+			return parseDateFilter(value, &r.createdBefore)
+		})),
+		validation.Field(&r.metadata, validation.By(validateMetadata)),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *BrowseRequest) BindAndValidate(c echo.Context) []string {
+	if err := r.ListRequest.BindAndValidate(c); err != nil {
+		return err
+	}
+
+	if r.Delimiter == "" {
+		r.Delimiter = "/"
+	}
+
+	return nil
+}
+
+func (r *UpdateMetadataRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Metadata, validation.By(validateMetadata)),
+		validation.Field(&r.Tags, validation.By(validateTags)),
 	)
 
 	return r.ExtractValidationErrors(err)
@@ -69,12 +202,313 @@ func (r *RenameRequest) BindAndValidate(c echo.Context) []string {
 					constants.MinContainerNameLength,
 					constants.MaxContainerNameLength,
 				),
-			)),
+			),
+			validation.By(NotReservedName),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *RestoreRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.FullFileName,
+			validation.Length(
+				constants.MinContainerNameLength, constants.MaxContainerNameLength,
+			).Error(
+				fmt.Sprintf(
+					"File name must be between %d and %d characters",
+					constants.MinContainerNameLength,
+					constants.MaxContainerNameLength,
+				),
+			),
+			validation.By(NotReservedName),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *DownloadRequest) BindAndValidate(c echo.Context) []string {
+	if err := r.DefaultRequest.BindAndValidate(c); err != nil {
+		return err
+	}
+
+	// Malformed or multi-range headers are ignored and the whole file is sent, as RFC 9110 allows
+	r.ByteRange = ParseRangeHeader(c.Request().Header.Get("Range"))
+
+	return nil
+}
+
+func (r *TransformRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Width,
+			validation.Min(0).Error("width must not be negative"),
+			validation.Max(constants.MaxImageTransformDimension).Error(
+				fmt.Sprintf("width must not exceed %d", constants.MaxImageTransformDimension),
+			),
+		),
+		validation.Field(
+			&r.Height,
+			validation.Min(0).Error("height must not be negative"),
+			validation.Max(constants.MaxImageTransformDimension).Error(
+				fmt.Sprintf("height must not exceed %d", constants.MaxImageTransformDimension),
+			),
+		),
+		validation.Field(
+			&r.Fit,
+			validation.In(imaging.FitCover, imaging.FitContain, imaging.FitFill).Error("fit must be one of cover, contain or fill"),
+		),
+		validation.Field(
+			&r.Format,
+			validation.In(imaging.FormatJPEG, imaging.FormatPNG, imaging.FormatWEBP).Error("format must be one of jpeg, png or webp"),
+		),
+		validation.Field(
+			&r.Quality,
+			validation.Min(0).Error("quality must be between 1 and 100"),
+			validation.Max(100).Error("quality must be between 1 and 100"),
+			validation.When(
+				r.Format == imaging.FormatPNG || r.Format == imaging.FormatWEBP,
+				validation.Empty.Error("quality can only be set for jpeg output"),
+			),
+		),
+		validation.Field(&r.Crop, validation.By(r.validateCrop)),
 	)
 
 	return r.ExtractValidationErrors(err)
 }
 
+func (r *SignedTransformRequest) BindAndValidate(c echo.Context) []string {
+	if err := r.TransformRequest.BindAndValidate(c); err != nil {
+		return err
+	}
+
+	r.Expires = c.QueryParam("expires")
+	r.Signature = c.QueryParam("signature")
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Expires, validation.Required.Error("expires is required")),
+		validation.Field(&r.Signature, validation.Required.Error("signature is required")),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+// Options returns the requested transformation, defaults are left to the file service
+func (r *TransformRequest) Options() imaging.TransformOptions {
+	return imaging.TransformOptions{
+		Width:   r.Width,
+		Height:  r.Height,
+		Fit:     r.Fit,
+		Crop:    r.cropRectangle,
+		Format:  r.Format,
+		Quality: r.Quality,
+	}
+}
+
+// validateCrop parses the "x,y,width,height" crop parameter
+func (r *TransformRequest) validateCrop(value interface{}) error {
+	crop, _ := value.(string)
+	if crop == "" {
+		return nil
+	}
+
+	parts := strings.Split(crop, ",")
+	if len(parts) != 4 {
+		return fmt.Errorf("crop must be in the format x,y,width,height")
+	}
+
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		parsed, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("crop must be in the format x,y,width,height")
+		}
+
+		values[i] = parsed
+	}
+
+	if values[2] == 0 || values[3] == 0 {
+		return fmt.Errorf("crop width and height must be greater than zero")
+	}
+
+	rectangle := image.Rect(values[0], values[1], values[0]+values[2], values[1]+values[3])
+	r.cropRectangle = &rectangle
+
+	return nil
+}
+
+// ParseRangeHeader parses a single "bytes=start-end", "bytes=start-" or "bytes=-suffix" range
+func ParseRangeHeader(header string) *storage.ByteRange {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return nil
+	}
+
+	startPart, endPart, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return nil
+	}
+
+	if startPart == "" {
+		suffixLength, err := strconv.ParseInt(endPart, 10, 64)
+		if err != nil || suffixLength <= 0 {
+			return nil
+		}
+
+		return &storage.ByteRange{Start: -suffixLength, End: -1}
+	}
+
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil || start < 0 {
+		return nil
+	}
+
+	if endPart == "" {
+		return &storage.ByteRange{Start: start, End: -1}
+	}
+
+	end, err := strconv.ParseInt(endPart, 10, 64)
+	if err != nil || end < start {
+		return nil
+	}
+
+	return &storage.ByteRange{Start: start, End: end}
+}
+
+// parseMetadata decodes the metadata form field, which has to be sent as JSON within a multipart form
+func (r *CreateRequest) parseMetadata(value interface{}) error {
+	metadata, _ := value.(string)
+	if strings.TrimSpace(metadata) == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(metadata), &r.parsedMetadata); err != nil {
+		return errors.New("metadata must be a JSON object of strings")
+	}
+
+	return validateMetadata(r.parsedMetadata)
+}
+
+func (r *CreateRequest) parseTags(value interface{}) error {
+	tags, _ := value.(string)
+	if strings.TrimSpace(tags) == "" {
+		return nil
+	}
+
+	r.parsedTags = strings.Split(tags, ",")
+
+	return validateTags(r.parsedTags)
+}
+
+func validateMetadata(value interface{}) error {
+	metadata, _ := value.(map[string]string)
+
+	if len(metadata) > constants.MaxFileMetadataKeys {
+		return fmt.Errorf("metadata must not have more than %d keys", constants.MaxFileMetadataKeys)
+	}
+
+	for key, val := range metadata {
+		if key == "" || len(key) > constants.MaxFileMetadataKeyLength {
+			return fmt.Errorf("metadata keys must be between 1 and %d characters", constants.MaxFileMetadataKeyLength)
+		}
+
+		if len(val) > constants.MaxFileMetadataValueLength {
+			return fmt.Errorf("metadata values must not exceed %d characters", constants.MaxFileMetadataValueLength)
+		}
+	}
+
+	return nil
+}
+
+// parseMetadataFilter collects metadata[key]=value query parameters, a key given twice keeps its first value
+func parseMetadataFilter(query url.Values) map[string]string {
+	metadata := map[string]string{}
+	for param, values := range query {
+		key, found := strings.CutPrefix(param, "metadata[")
+		if !found || !strings.HasSuffix(key, "]") || len(values) == 0 {
+			continue
+		}
+
+		metadata[strings.TrimSuffix(key, "]")] = values[0]
+	}
+
+	return metadata
+}
+
+func validateTags(value interface{}) error {
+	tags, _ := value.([]string)
+
+	if len(tags) > constants.MaxFileTags {
+		return fmt.Errorf("a file must not have more than %d tags", constants.MaxFileTags)
+	}
+
+	for _, tag := range tags {
+		if len(strings.TrimSpace(tag)) > constants.MaxFileTagLength {
+			return fmt.Errorf("tags must not exceed %d characters", constants.MaxFileTagLength)
+		}
+	}
+
+	return nil
+}
+
+// validateMimeTypeFilter accepts a full type like image/png or a wildcard like image/*
+func validateMimeTypeFilter(value interface{}) error {
+	mimeType, _ := value.(string)
+	if mimeType == "" {
+		return nil
+	}
+
+	mediaType, subType, found := strings.Cut(mimeType, "/")
+	if !found || mediaType == "" || subType == "" || mediaType == "*" {
+		return errors.New("mime_type must be a type like image/png or image/*")
+	}
+
+	return nil
+}
+
+func parseDateFilter(value interface{}, target **time.Time) error {
+	date, _ := value.(string)
+	if date == "" {
+		return nil
+	}
+
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		parsed, err := time.Parse(layout, date)
+		if err == nil {
+			*target = &parsed
+
+			return nil
+		}
+	}
+
+	return errors.New("dates must be formatted as 2006-01-02 or RFC 3339")
+}
+
+// NotReservedName refuses names stored among the internal objects of a container, like its trash or versions
+func NotReservedName(value interface{}) error {
+	fullFileName, _ := value.(string)
+	if file.IsReservedName(fullFileName) {
+		return errors.New("File name must not start with a reserved prefix like .trash or .versions")
+	}
+
+	return nil
+}
+
 func fileRequired(value interface{}) error {
 	file, ok := value.(*multipart.FileHeader)
 	if !ok || file == nil {
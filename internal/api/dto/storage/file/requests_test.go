@@ -1,14 +1,21 @@
 package file
 
 import (
+	"fluxend/internal/adapters/imaging"
+	"fluxend/internal/adapters/storage"
 	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/file"
 	"fluxend/pkg"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"image"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
@@ -176,6 +183,20 @@ func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
 				file:     nil,
 				expected: []string{"file is required"},
 			},
+			{
+				name: "Reserved prefix",
+				payload: map[string]interface{}{
+					"full_file_name": ".trash/0b8f6a8e",
+				},
+				headers: map[string]string{
+					constants.ProjectHeaderKey: dummyProjectUUID,
+				},
+				file: &multipart.FileHeader{
+					Filename: "test.txt",
+					Size:     1024,
+				},
+				expected: []string{"reserved prefix"},
+			},
 			{
 				name: "Multiple validation errors",
 				payload: map[string]interface{}{
@@ -334,6 +355,16 @@ func TestRenameRequest_BindAndValidate_Suite(t *testing.T) {
 				},
 				expected: []string{"File name must be between 3 and 63 characters"},
 			},
+			{
+				name: "Reserved prefix",
+				payload: map[string]interface{}{
+					"full_file_name": "docs/../.versions/a/b",
+				},
+				headers: map[string]string{
+					constants.ProjectHeaderKey: dummyProjectUUID,
+				},
+				expected: []string{"reserved prefix"},
+			},
 			{
 				name: "Invalid JSON payload",
 				payload: map[string]interface{}{
@@ -365,6 +396,78 @@ func TestRenameRequest_BindAndValidate_Suite(t *testing.T) {
 	})
 }
 
+func TestRestoreRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("RestoreRequest: valid without name", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r RestoreRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Empty(t, ToRestoreFileInput(&r).FullFileName)
+	})
+
+	t.Run("RestoreRequest: valid with new name", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"full_file_name": "restored_file.txt",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r RestoreRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "restored_file.txt", ToRestoreFileInput(&r).FullFileName)
+	})
+
+	t.Run("RestoreRequest: name too long", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"full_file_name": strings.Repeat("a", constants.MaxContainerNameLength+1),
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r RestoreRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "File name must be between")
+	})
+
+	t.Run("RestoreRequest: reserved prefix", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"full_file_name": ".objects/e3b0c442",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r RestoreRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "reserved prefix")
+	})
+}
+
+func TestNotReservedName(t *testing.T) {
+	t.Run("NotReservedName: reserved", func(t *testing.T) {
+		for _, name := range []string{".trash", ".trash/abc", "/.versions/abc", "./.variants/abc", "a/../.objects/abc", ".uploads/abc"} {
+			assert.Error(t, NotReservedName(name), name)
+		}
+	})
+
+	t.Run("NotReservedName: allowed", func(t *testing.T) {
+		for _, name := range []string{"docs/.trash/abc", ".trashed/abc", ".trash.txt", "versions/abc", ".env"} {
+			assert.NoError(t, NotReservedName(name), name)
+		}
+	})
+}
+
 func TestFileRequired(t *testing.T) {
 	t.Run("fileRequired: valid file", func(t *testing.T) {
 		fileHeader := &multipart.FileHeader{
@@ -401,3 +504,291 @@ func TestFileRequired(t *testing.T) {
 		assert.Equal(t, "file is required", err.Error())
 	})
 }
+
+func TestDownloadRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	tests := []struct {
+		name     string
+		header   string
+		expected *storage.ByteRange
+	}{
+		{"DownloadRequest: no range", "", nil},
+		{"DownloadRequest: closed range", "bytes=0-499", &storage.ByteRange{Start: 0, End: 499}},
+		{"DownloadRequest: open range", "bytes=500-", &storage.ByteRange{Start: 500, End: -1}},
+		{"DownloadRequest: suffix range", "bytes=-200", &storage.ByteRange{Start: -200, End: -1}},
+		{"DownloadRequest: multiple ranges are ignored", "bytes=0-1,5-6", nil},
+		{"DownloadRequest: end before start is ignored", "bytes=10-5", nil},
+		{"DownloadRequest: unknown unit is ignored", "items=0-5", nil},
+		{"DownloadRequest: empty suffix is ignored", "bytes=-0", nil},
+		{"DownloadRequest: malformed range is ignored", "bytes=abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := pkg.CreateFakeRequestContext(t, e, http.MethodGet, map[string]interface{}{})
+			if tt.header != "" {
+				ctx.Request().Header.Set("Range", tt.header)
+			}
+
+			var r DownloadRequest
+			errs := r.BindAndValidate(ctx)
+
+			assert.Len(t, errs, 0)
+			assert.Equal(t, tt.expected, r.ByteRange)
+		})
+	}
+}
+
+func TestTransformRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(query string) echo.Context {
+		return e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), httptest.NewRecorder())
+	}
+
+	t.Run("TransformRequest: valid", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("width=320&height=240&fit=cover&crop=10,20,300,200&format=jpeg&quality=75"))
+
+		assert.Len(t, errs, 0)
+
+		cropRectangle := image.Rect(10, 20, 310, 220)
+		assert.Equal(t, imaging.TransformOptions{
+			Width:   320,
+			Height:  240,
+			Fit:     imaging.FitCover,
+			Crop:    &cropRectangle,
+			Format:  imaging.FormatJPEG,
+			Quality: 75,
+		}, r.Options())
+	})
+
+	t.Run("TransformRequest: empty query leaves defaults to the service", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext(""))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, imaging.TransformOptions{}, r.Options())
+	})
+
+	t.Run("TransformRequest: dimensions out of bounds", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext(fmt.Sprintf("width=%d&height=-1", constants.MaxImageTransformDimension+1)))
+
+		pkg.AssertErrorContains(t, errs, "width must not exceed")
+		pkg.AssertErrorContains(t, errs, "height must not be negative")
+	})
+
+	t.Run("TransformRequest: invalid fit and format", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("fit=stretch&format=gif"))
+
+		pkg.AssertErrorContains(t, errs, "fit must be one of")
+		pkg.AssertErrorContains(t, errs, "format must be one of")
+	})
+
+	t.Run("TransformRequest: quality out of bounds", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("quality=101"))
+
+		pkg.AssertErrorContains(t, errs, "quality must be between 1 and 100")
+	})
+
+	t.Run("TransformRequest: quality for lossless formats", func(t *testing.T) {
+		for _, format := range []string{"png", "webp"} {
+			var r TransformRequest
+			errs := r.BindAndValidate(newContext("format=" + format + "&quality=60"))
+
+			pkg.AssertErrorContains(t, errs, "quality can only be set for jpeg output")
+		}
+
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("format=jpeg&quality=60"))
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("TransformRequest: malformed crop", func(t *testing.T) {
+		for _, crop := range []string{"10,20,300", "a,b,c,d", "-1,0,10,10"} {
+			var r TransformRequest
+			errs := r.BindAndValidate(newContext("crop=" + crop))
+
+			pkg.AssertErrorContains(t, errs, "crop must be in the format x,y,width,height")
+		}
+
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("crop=0,0,0,10"))
+
+		pkg.AssertErrorContains(t, errs, "crop width and height must be greater than zero")
+	})
+
+	t.Run("TransformRequest: non numeric width", func(t *testing.T) {
+		var r TransformRequest
+		errs := r.BindAndValidate(newContext("width=wide"))
+
+		assert.Equal(t, []string{"Invalid request payload"}, errs)
+	})
+
+	t.Run("SignedTransformRequest: requires signature", func(t *testing.T) {
+		var r SignedTransformRequest
+		errs := r.BindAndValidate(newContext("width=100"))
+
+		pkg.AssertErrorContains(t, errs, "expires is required")
+		pkg.AssertErrorContains(t, errs, "signature is required")
+
+		r = SignedTransformRequest{}
+		errs = r.BindAndValidate(newContext("width=100&expires=1700000000&signature=abc"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, 100, r.Options().Width)
+	})
+}
+
+func TestListRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(query string) echo.Context {
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+query, nil), httptest.NewRecorder())
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		return ctx
+	}
+
+	t.Run("ListRequest: valid filters", func(t *testing.T) {
+		var r ListRequest
+		errs := r.BindAndValidate(newContext("prefix=photos/&mime_type=image/*&min_size=10&max_size=500&created_after=2025-04-01&created_before=2025-04-07T12:00:00Z&tag=summer"))
+
+		assert.Len(t, errs, 0)
+
+		filter := ToListFilesInput(&r)
+		assert.Equal(t, "photos/", filter.Prefix)
+		assert.Equal(t, "image/*", filter.MimeType)
+		assert.Equal(t, 10, filter.MinSize)
+		assert.Equal(t, 500, filter.MaxSize)
+		assert.Equal(t, "summer", filter.Tag)
+		assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), *filter.CreatedAfter)
+		assert.Equal(t, time.Date(2025, 4, 7, 12, 0, 0, 0, time.UTC), *filter.CreatedBefore)
+	})
+
+	t.Run("ListRequest: no filters", func(t *testing.T) {
+		var r ListRequest
+		errs := r.BindAndValidate(newContext(""))
+
+		assert.Len(t, errs, 0)
+		assert.Nil(t, ToListFilesInput(&r).CreatedAfter)
+	})
+
+	t.Run("ListRequest: invalid filters", func(t *testing.T) {
+		var r ListRequest
+		errs := r.BindAndValidate(newContext("mime_type=image&min_size=-1&created_after=yesterday"))
+
+		pkg.AssertErrorContains(t, errs, "mime_type must be a type like")
+		pkg.AssertErrorContains(t, errs, "min_size must not be negative")
+		pkg.AssertErrorContains(t, errs, "dates must be formatted as")
+	})
+
+	t.Run("ListRequest: metadata filter", func(t *testing.T) {
+		var r ListRequest
+		errs := r.BindAndValidate(newContext("metadata[camera]=x100&metadata%5Balbum%5D=summer%20trip&metadata=ignored"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, file.Metadata{"camera": "x100", "album": "summer trip"}, ToListFilesInput(&r).Metadata)
+	})
+
+	t.Run("ListRequest: invalid metadata filter", func(t *testing.T) {
+		var r ListRequest
+		errs := r.BindAndValidate(newContext("metadata[]=x100"))
+
+		pkg.AssertErrorContains(t, errs, "metadata keys must be between 1 and")
+
+		r = ListRequest{}
+		errs = r.BindAndValidate(newContext("metadata[camera]=" + strings.Repeat("a", constants.MaxFileMetadataValueLength+1)))
+
+		pkg.AssertErrorContains(t, errs, "metadata values must not exceed")
+	})
+
+	t.Run("BrowseRequest: delimiter defaults to a slash", func(t *testing.T) {
+		var r BrowseRequest
+		errs := r.BindAndValidate(newContext("prefix=photos/"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "/", r.Delimiter)
+	})
+}
+
+func TestUpdateMetadataRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("UpdateMetadataRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"metadata": map[string]string{"camera": "x100", "album": "summer"},
+			"tags":     []string{"holiday", "beach"},
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r UpdateMetadataRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "x100", r.Metadata["camera"])
+		assert.Equal(t, []string{"holiday", "beach"}, r.Tags)
+	})
+
+	t.Run("UpdateMetadataRequest: too many tags and oversized values", func(t *testing.T) {
+		tags := make([]string, constants.MaxFileTags+1)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag-%d", i)
+		}
+
+		payload := map[string]interface{}{
+			"metadata": map[string]string{"notes": strings.Repeat("a", constants.MaxFileMetadataValueLength+1)},
+			"tags":     tags,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r UpdateMetadataRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "metadata values must not exceed")
+		pkg.AssertErrorContains(t, errs, "must not have more than")
+	})
+}
+
+func TestCreateRequest_Metadata_Suite(t *testing.T) {
+	e := echo.New()
+
+	newRequest := func(metadata, tags string) (*CreateRequest, []string) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		r := &CreateRequest{
+			FullFileName: "photo.jpg",
+			File:         &multipart.FileHeader{Filename: "photo.jpg", Size: 1024},
+			Metadata:     metadata,
+			Tags:         tags,
+		}
+
+		return r, r.BindAndValidate(ctx)
+	}
+
+	t.Run("CreateRequest: metadata and tags are parsed", func(t *testing.T) {
+		r, errs := newRequest(`{"camera": "x100"}`, "holiday, beach")
+
+		assert.Len(t, errs, 0)
+
+		input := ToCreateFileInput(r)
+		assert.Equal(t, "x100", input.Metadata["camera"])
+		assert.Equal(t, []string{"holiday", " beach"}, input.Tags)
+	})
+
+	t.Run("CreateRequest: metadata must be an object of strings", func(t *testing.T) {
+		_, errs := newRequest(`{"count": 3}`, "")
+
+		pkg.AssertErrorContains(t, errs, "metadata must be a JSON object of strings")
+	})
+}
@@ -2,6 +2,7 @@ package file
 
 import (
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
 )
 
 type Response struct {
@@ -14,6 +15,35 @@ type Response struct {
 	UpdatedBy     uuid.UUID `json:"updatedBy"`
 	CreatedAt     string    `json:"createdAt"`
 	UpdatedAt     string    `json:"updatedAt"`
+
+	Metadata map[string]string `json:"metadata"`
+	Tags     []string          `json:"tags"`
+
+	// SHA-256 of the content, hex encoded. Null for files stored before checksums were recorded
+	Checksum null.String `json:"checksum" swaggertype:"string"`
+}
+
+// ListingResponse is one level of the folder hierarchy, folders end with the delimiter
+type ListingResponse struct {
+	Prefix  string     `json:"prefix"`
+	Folders []string   `json:"folders"`
+	Files   []Response `json:"files"`
+}
+
+type TrashedResponse struct {
+	Response
+	DeletedAt string        `json:"deletedAt"`
+	DeletedBy uuid.NullUUID `json:"deletedBy" swaggertype:"string"`
+}
+
+type VersionResponse struct {
+	Uuid      uuid.UUID   `json:"uuid"`
+	FileUuid  uuid.UUID   `json:"fileUuid"`
+	Size      int         `json:"size"` // in KB
+	MimeType  string      `json:"mimeType"`
+	CreatedBy uuid.UUID   `json:"createdBy"`
+	CreatedAt string      `json:"createdAt"`
+	Checksum  null.String `json:"checksum" swaggertype:"string"`
 }
 
 type DownloadResponse struct {
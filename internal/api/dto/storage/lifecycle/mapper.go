@@ -0,0 +1,16 @@
+package lifecycle
+
+import (
+	"fluxend/internal/domain/storage/lifecycle"
+)
+
+func ToCreateRuleInput(request *CreateRequest) *lifecycle.CreateRuleInput {
+	return &lifecycle.CreateRuleInput{
+		Name:                request.Name,
+		Prefix:              request.Prefix,
+		Action:              request.Action,
+		AfterDays:           request.AfterDays,
+		TargetContainerUUID: request.TargetContainerUUID,
+		IsEnabled:           request.IsEnabled,
+	}
+}
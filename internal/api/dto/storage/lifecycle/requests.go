@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/config/constants"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"regexp"
+)
+
+type CreateRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	Name      string `json:"name"`
+	Prefix    string `json:"prefix"`
+	Action    string `json:"action"`
+	AfterDays int    `json:"after_days"`
+
+	// Only used by move rules, the target has to be another container of the same project
+	TargetContainerUUID uuid.NullUUID `json:"target_container_uuid"`
+
+	// Rules are disabled unless asked otherwise, so their dry-run report can be checked first
+	IsEnabled bool `json:"is_enabled"`
+}
+
+func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(
+			&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(
+				constants.MinLifecycleRuleNameLength, constants.MaxLifecycleRuleNameLength,
+			).Error(
+				fmt.Sprintf(
+					"Rule name must be between %d and %d characters",
+					constants.MinLifecycleRuleNameLength,
+					constants.MaxLifecycleRuleNameLength,
+				),
+			),
+			validation.Match(
+				regexp.MustCompile(constants.AlphanumericWithUnderscoreAndDashPattern),
+			).Error("Rule name must be alphanumeric with underscores and dashes")),
+		validation.Field(
+			&r.Prefix,
+			validation.Length(0, constants.MaxFileNameLength).Error(
+				fmt.Sprintf("Prefix must be less than %d characters", constants.MaxFileNameLength),
+			),
+		),
+		validation.Field(
+			&r.Action,
+			validation.Required.Error("Action is required"),
+			validation.In(
+				constants.LifecycleActionDelete,
+				constants.LifecycleActionMove,
+			).Error("Action must be one of delete or move"),
+		),
+		validation.Field(
+			&r.AfterDays,
+			validation.Required.Error("after_days is required"),
+			validation.Min(1).Error("after_days must be a positive number"),
+			validation.Max(constants.MaxLifecycleRuleAfterDays).Error(
+				fmt.Sprintf("after_days must be at most %d", constants.MaxLifecycleRuleAfterDays),
+			),
+		),
+	)
+
+	if err == nil && r.Action == constants.LifecycleActionMove && !r.TargetContainerUUID.Valid {
+		return []string{"target_container_uuid is required for move rules"}
+	}
+
+	return r.ExtractValidationErrors(err)
+}
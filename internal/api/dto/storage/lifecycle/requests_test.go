@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateRequest: valid delete rule", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":       "expire_tmp",
+			"prefix":     "tmp/",
+			"action":     constants.LifecycleActionDelete,
+			"after_days": 7,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "tmp/", r.Prefix)
+		assert.Equal(t, 7, r.AfterDays)
+		assert.False(t, r.IsEnabled)
+		assert.False(t, r.TargetContainerUUID.Valid)
+	})
+
+	t.Run("CreateRequest: valid move rule", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":                  "archive",
+			"action":                constants.LifecycleActionMove,
+			"after_days":            30,
+			"target_container_uuid": "7d4c2b1e-5f3a-4c8e-9b2d-1a6f0e3c5d7b",
+			"is_enabled":            true,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.True(t, r.TargetContainerUUID.Valid)
+		assert.True(t, r.IsEnabled)
+	})
+
+	t.Run("CreateRequest: move rule without target", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":       "archive",
+			"action":     constants.LifecycleActionMove,
+			"after_days": 30,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Equal(t, []string{"target_container_uuid is required for move rules"}, errs)
+	})
+
+	t.Run("CreateRequest: invalid fields", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":       "a",
+			"prefix":     strings.Repeat("p", constants.MaxFileNameLength+1),
+			"action":     "archive",
+			"after_days": constants.MaxLifecycleRuleAfterDays + 1,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 4)
+	})
+
+	t.Run("CreateRequest: missing fields", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 3)
+	})
+
+	t.Run("CreateRequest: missing project header", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":       "expire_tmp",
+			"action":     constants.LifecycleActionDelete,
+			"after_days": 7,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.NotEmpty(t, errs)
+	})
+}
@@ -0,0 +1,39 @@
+package lifecycle
+
+import (
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	Uuid                uuid.UUID     `json:"uuid"`
+	ContainerUuid       uuid.UUID     `json:"containerUuid"`
+	Name                string        `json:"name"`
+	Prefix              string        `json:"prefix"`
+	Action              string        `json:"action"`
+	AfterDays           int           `json:"afterDays"`
+	TargetContainerUuid uuid.NullUUID `json:"targetContainerUuid" swaggertype:"string"`
+	IsEnabled           bool          `json:"isEnabled"`
+	LastRunAt           *string       `json:"lastRunAt"`
+	CreatedBy           uuid.UUID     `json:"createdBy"`
+	UpdatedBy           uuid.UUID     `json:"updatedBy"`
+	CreatedAt           string        `json:"createdAt"`
+	UpdatedAt           string        `json:"updatedAt"`
+}
+
+type ReportResponse struct {
+	RuleUuid uuid.UUID            `json:"ruleUuid"`
+	RuleName string               `json:"ruleName"`
+	Action   string               `json:"action"`
+	DryRun   bool                 `json:"dryRun"`
+	Applied  int                  `json:"applied"`
+	Failed   int                  `json:"failed"`
+	Items    []ReportItemResponse `json:"items"`
+}
+
+type ReportItemResponse struct {
+	FileUuid     uuid.UUID `json:"fileUuid"`
+	FullFileName string    `json:"fullFileName"`
+	Size         int       `json:"size"` // in KB
+	UpdatedAt    string    `json:"updatedAt"`
+	Error        string    `json:"error,omitempty"`
+}
@@ -0,0 +1,12 @@
+package migration
+
+import (
+	"fluxend/internal/domain/storage/migration"
+)
+
+func ToCreateMigrationInput(request *CreateRequest) *migration.CreateMigrationInput {
+	return &migration.CreateMigrationInput{
+		SourceDriver: request.SourceDriver,
+		TargetDriver: request.TargetDriver,
+	}
+}
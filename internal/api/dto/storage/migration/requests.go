@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/config/constants"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+type CreateRequest struct {
+	dto.BaseRequest
+	SourceDriver string `json:"source_driver"`
+	TargetDriver string `json:"target_driver"`
+}
+
+func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		driverRule(&r.SourceDriver, "Source driver"),
+		driverRule(&r.TargetDriver, "Target driver"),
+	)
+
+	if err == nil && r.SourceDriver == r.TargetDriver {
+		return []string{"Source and target driver must be different"}
+	}
+
+	return r.ExtractValidationErrors(err)
+}
+
+func driverRule(driver *string, label string) *validation.FieldRules {
+	return validation.Field(
+		driver,
+		validation.Required.Error(label+" is required"),
+		validation.In(
+			constants.StorageDriverFilesystem,
+			constants.StorageDriverS3,
+			constants.StorageDriverBackBlaze,
+			constants.StorageDriverDropbox,
+		).Error(label+" must be one of FILESYSTEM, S3, BACKBLAZE or DROPBOX"),
+	)
+}
@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("CreateRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"source_driver": constants.StorageDriverFilesystem,
+			"target_driver": constants.StorageDriverS3,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+
+		input := ToCreateMigrationInput(&r)
+		assert.Equal(t, constants.StorageDriverFilesystem, input.SourceDriver)
+		assert.Equal(t, constants.StorageDriverS3, input.TargetDriver)
+	})
+
+	t.Run("CreateRequest: missing drivers", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Contains(t, errs, "Source driver is required")
+		assert.Contains(t, errs, "Target driver is required")
+	})
+
+	t.Run("CreateRequest: unknown driver", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"source_driver": "FTP",
+			"target_driver": constants.StorageDriverS3,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Contains(t, errs, "Source driver must be one of FILESYSTEM, S3, BACKBLAZE or DROPBOX")
+	})
+
+	t.Run("CreateRequest: same driver", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"source_driver": constants.StorageDriverS3,
+			"target_driver": constants.StorageDriverS3,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Equal(t, []string{"Source and target driver must be different"}, errs)
+	})
+}
@@ -0,0 +1,18 @@
+package migration
+
+import (
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	Uuid          uuid.UUID     `json:"uuid"`
+	SourceDriver  string        `json:"sourceDriver"`
+	TargetDriver  string        `json:"targetDriver"`
+	Status        string        `json:"status"`
+	Error         string        `json:"error"`
+	TotalObjects  int           `json:"totalObjects"`
+	CopiedObjects int           `json:"copiedObjects"`
+	CreatedBy     uuid.NullUUID `json:"createdBy" swaggertype:"string"`
+	StartedAt     string        `json:"startedAt"`
+	CompletedAt   *string       `json:"completedAt"`
+}
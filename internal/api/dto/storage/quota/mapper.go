@@ -0,0 +1,12 @@
+package quota
+
+import (
+	"fluxend/internal/domain/storage/quota"
+)
+
+func ToUpdateQuotaInput(request *UpdateRequest) *quota.UpdateQuotaInput {
+	return &quota.UpdateQuotaInput{
+		MaxSize:  request.MaxSize,
+		MaxFiles: request.MaxFiles,
+	}
+}
@@ -0,0 +1,27 @@
+package quota
+
+import (
+	"fluxend/internal/api/dto"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// UpdateRequest sets the limits of a project or organization, 0 falls back to the instance wide setting
+type UpdateRequest struct {
+	dto.BaseRequest
+	MaxSize  int `json:"max_size"` // in KB
+	MaxFiles int `json:"max_files"`
+}
+
+func (r *UpdateRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload"}
+	}
+
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.MaxSize, validation.Min(0).Error("max_size can't be negative")),
+		validation.Field(&r.MaxFiles, validation.Min(0).Error("max_files can't be negative")),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
@@ -0,0 +1,56 @@
+package quota
+
+import (
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestUpdateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("UpdateRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"max_size":  1048576,
+			"max_files": 1000,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+
+		var r UpdateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, 1048576, r.MaxSize)
+		assert.Equal(t, 1000, r.MaxFiles)
+	})
+
+	t.Run("UpdateRequest: zero falls back to defaults", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, map[string]interface{}{})
+
+		var r UpdateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+		assert.Zero(t, r.MaxSize)
+		assert.Zero(t, r.MaxFiles)
+	})
+
+	t.Run("UpdateRequest: negative limits", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"max_size":  -1,
+			"max_files": -5,
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPut, payload)
+
+		var r UpdateRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 2)
+		assert.Contains(t, errs, "max_size can't be negative")
+		assert.Contains(t, errs, "max_files can't be negative")
+	})
+}
@@ -0,0 +1,16 @@
+package quota
+
+import (
+	"github.com/google/uuid"
+)
+
+// UsageResponse includes the limits that apply, a limit of 0 means unlimited
+type UsageResponse struct {
+	Scope     string    `json:"scope"`
+	OwnerUuid uuid.UUID `json:"ownerUuid"`
+	UsedSize  int       `json:"usedSize"` // in KB
+	UsedBytes int64     `json:"usedBytes"`
+	UsedFiles int       `json:"usedFiles"`
+	MaxSize   int       `json:"maxSize"` // in KB
+	MaxFiles  int       `json:"maxFiles"`
+}
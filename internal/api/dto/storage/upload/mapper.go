@@ -0,0 +1,21 @@
+package upload
+
+import (
+	"fluxend/internal/domain/storage/upload"
+)
+
+func ToCreateUploadInput(request *CreateRequest) *upload.CreateUploadInput {
+	return &upload.CreateUploadInput{
+		ProjectUUID:  request.ProjectUUID,
+		FullFileName: request.FullFileName,
+		MimeType:     request.MimeType,
+		UploadLength: request.UploadLength,
+	}
+}
+
+func ToAppendChunkInput(request *AppendRequest) *upload.AppendChunkInput {
+	return &upload.AppendChunkInput{
+		UploadOffset: request.UploadOffset,
+		Body:         request.Body,
+	}
+}
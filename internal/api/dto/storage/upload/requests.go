@@ -0,0 +1,124 @@
+package upload
+
+import (
+	"encoding/base64"
+	"errors"
+	"fluxend/internal/api/dto"
+	fileDto "fluxend/internal/api/dto/storage/file"
+	"fluxend/internal/config/constants"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CreateRequest is read from tus headers, the request body is empty
+type CreateRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	UploadLength int64  `json:"-"`
+	FullFileName string `json:"-"`
+	MimeType     string `json:"-"`
+}
+
+// AppendRequest carries the next chunk of an upload as its raw body
+type AppendRequest struct {
+	dto.DefaultRequestWithProjectHeader
+	UploadOffset int64     `json:"-"`
+	Body         io.Reader `json:"-"`
+}
+
+func (r *CreateRequest) BindAndValidate(c echo.Context) []string {
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	uploadLength, err := strconv.ParseInt(c.Request().Header.Get(constants.UploadLengthHeader), 10, 64)
+	if err != nil || uploadLength < 1 {
+		return []string{"Upload-Length must be a positive number"}
+	}
+
+	metadata, err := parseMetadata(c.Request().Header.Get(constants.UploadMetaHeader))
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	r.UploadLength = uploadLength
+	r.FullFileName = firstNonEmpty(metadata["full_file_name"], metadata["filename"], metadata["name"])
+	r.MimeType = firstNonEmpty(metadata["filetype"], metadata["type"], echo.MIMEOctetStream)
+
+	err = validation.ValidateStruct(r,
+		validation.Field(
+			&r.FullFileName,
+			validation.Required.Error("filename metadata is required"),
+			validation.Length(
+				constants.MinContainerNameLength, constants.MaxContainerNameLength,
+			).Error(
+				fmt.Sprintf(
+					"File name must be between %d and %d characters",
+					constants.MinContainerNameLength,
+					constants.MaxContainerNameLength,
+				),
+			),
+			validation.By(fileDto.NotReservedName),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *AppendRequest) BindAndValidate(c echo.Context) []string {
+	// The body is the chunk itself, so it is never bound
+	if err := r.WithProjectHeader(c); err != nil {
+		return []string{err.Error()}
+	}
+
+	if c.Request().Header.Get(echo.HeaderContentType) != constants.TusContentType {
+		return []string{fmt.Sprintf("Content-Type must be %s", constants.TusContentType)}
+	}
+
+	uploadOffset, err := strconv.ParseInt(c.Request().Header.Get(constants.UploadOffsetHeader), 10, 64)
+	if err != nil || uploadOffset < 0 {
+		return []string{"Upload-Offset must be a non-negative number"}
+	}
+
+	r.UploadOffset = uploadOffset
+	r.Body = c.Request().Body
+
+	return nil
+}
+
+// parseMetadata decodes an Upload-Metadata header: comma separated pairs of a key and a base64 encoded value
+func parseMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encodedValue, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("Upload-Metadata contains an empty key")
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encodedValue)
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata value of %q is not valid base64", key)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
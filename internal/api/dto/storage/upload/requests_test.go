@@ -0,0 +1,133 @@
+package upload
+
+import (
+	"encoding/base64"
+	"fluxend/internal/config/constants"
+	"fluxend/pkg"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var dummyProjectUUID = "123e4567-e89b-12d3-a456-426614174000"
+
+func encodeMetadata(key, value string) string {
+	return key + " " + base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func TestCreateRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(uploadLength, metadata string) echo.Context {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+		ctx.Request().Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+		ctx.Request().Header.Set(constants.UploadLengthHeader, uploadLength)
+		ctx.Request().Header.Set(constants.UploadMetaHeader, metadata)
+
+		return ctx
+	}
+
+	t.Run("CreateRequest: valid", func(t *testing.T) {
+		metadata := encodeMetadata("filename", "videos/intro.mp4") + "," + encodeMetadata("filetype", "video/mp4")
+
+		var r CreateRequest
+		errs := r.BindAndValidate(newContext("2048", metadata))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, int64(2048), r.UploadLength)
+		assert.Equal(t, "videos/intro.mp4", r.FullFileName)
+		assert.Equal(t, "video/mp4", r.MimeType)
+	})
+
+	t.Run("CreateRequest: defaults mime type and accepts empty values", func(t *testing.T) {
+		metadata := encodeMetadata("name", "notes.txt") + ",is_confidential"
+
+		var r CreateRequest
+		errs := r.BindAndValidate(newContext("10", metadata))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "notes.txt", r.FullFileName)
+		assert.Equal(t, echo.MIMEOctetStream, r.MimeType)
+	})
+
+	t.Run("CreateRequest: missing project header", func(t *testing.T) {
+		ctx := newContext("10", encodeMetadata("filename", "notes.txt"))
+		ctx.Request().Header.Del(constants.ProjectHeaderKey)
+
+		var r CreateRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "invalid project UUID")
+	})
+
+	t.Run("CreateRequest: invalid upload length", func(t *testing.T) {
+		for _, uploadLength := range []string{"", "0", "-5", "abc"} {
+			var r CreateRequest
+			errs := r.BindAndValidate(newContext(uploadLength, encodeMetadata("filename", "notes.txt")))
+
+			pkg.AssertErrorContains(t, errs, "Upload-Length must be a positive number")
+		}
+	})
+
+	t.Run("CreateRequest: missing file name", func(t *testing.T) {
+		var r CreateRequest
+		errs := r.BindAndValidate(newContext("10", encodeMetadata("filetype", "text/plain")))
+
+		pkg.AssertErrorContains(t, errs, "filename metadata is required")
+	})
+
+	t.Run("CreateRequest: reserved prefix", func(t *testing.T) {
+		var r CreateRequest
+		errs := r.BindAndValidate(newContext("10", encodeMetadata("filename", ".uploads/abc/0")))
+
+		pkg.AssertErrorContains(t, errs, "reserved prefix")
+	})
+
+	t.Run("CreateRequest: invalid metadata encoding", func(t *testing.T) {
+		var r CreateRequest
+		errs := r.BindAndValidate(newContext("10", "filename not-base64!"))
+
+		pkg.AssertErrorContains(t, errs, "is not valid base64")
+	})
+}
+
+func TestAppendRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	newContext := func(contentType, uploadOffset string) echo.Context {
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader("chunk"))
+		req.Header.Set(constants.ProjectHeaderKey, dummyProjectUUID)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(constants.UploadOffsetHeader, uploadOffset)
+
+		return e.NewContext(req, httptest.NewRecorder())
+	}
+
+	t.Run("AppendRequest: valid", func(t *testing.T) {
+		var r AppendRequest
+		errs := r.BindAndValidate(newContext(constants.TusContentType, "1024"))
+
+		assert.Len(t, errs, 0)
+		assert.Equal(t, int64(1024), r.UploadOffset)
+		assert.NotNil(t, r.Body)
+	})
+
+	t.Run("AppendRequest: wrong content type", func(t *testing.T) {
+		var r AppendRequest
+		errs := r.BindAndValidate(newContext(echo.MIMEOctetStream, "0"))
+
+		pkg.AssertErrorContains(t, errs, "Content-Type must be application/offset+octet-stream")
+	})
+
+	t.Run("AppendRequest: invalid offset", func(t *testing.T) {
+		for _, uploadOffset := range []string{"", "-1", "abc"} {
+			var r AppendRequest
+			errs := r.BindAndValidate(newContext(constants.TusContentType, uploadOffset))
+
+			pkg.AssertErrorContains(t, errs, "Upload-Offset must be a non-negative number")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"fluxend/internal/domain/account"
 	"fluxend/internal/domain/user"
 )
 
@@ -25,3 +26,46 @@ func ToUpdateUserInput(request *UpdateRequest) *user.UpdateUserInput {
 		Bio: request.Bio,
 	}
 }
+
+func ToForgotPasswordInput(request *ForgotPasswordRequest) *account.ForgotPasswordInput {
+	return &account.ForgotPasswordInput{
+		Email: request.Email,
+	}
+}
+
+func ToResetPasswordInput(request *ResetPasswordRequest) *account.ResetPasswordInput {
+	return &account.ResetPasswordInput{
+		Token:    request.Token,
+		Password: request.Password,
+	}
+}
+
+func ToResendVerificationInput(request *ResendVerificationRequest) *account.ResendVerificationInput {
+	return &account.ResendVerificationInput{
+		Email: request.Email,
+	}
+}
+
+func ToVerifyEmailInput(request *VerifyEmailRequest) *account.VerifyEmailInput {
+	return &account.VerifyEmailInput{
+		Token: request.Token,
+	}
+}
+
+func ToRequestMagicLinkInput(request *RequestMagicLinkRequest) *account.RequestMagicLinkInput {
+	return &account.RequestMagicLinkInput{
+		Email: request.Email,
+	}
+}
+
+func ToMagicLinkLoginInput(request *MagicLinkLoginRequest) *account.MagicLinkLoginInput {
+	return &account.MagicLinkLoginInput{
+		Token: request.Token,
+	}
+}
+
+func ToSocialLoginInput(request *SocialLoginRequest) *account.SocialLoginInput {
+	return &account.SocialLoginInput{
+		Token: request.Token,
+	}
+}
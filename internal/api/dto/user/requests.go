@@ -22,6 +22,42 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type ForgotPasswordRequest struct {
+	dto.BaseRequest
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	dto.BaseRequest
+	Token    string `json:"token"` // from the emailed link
+	Password string `json:"password"`
+}
+
+type ResendVerificationRequest struct {
+	dto.BaseRequest
+	Email string `json:"email"`
+}
+
+type VerifyEmailRequest struct {
+	dto.BaseRequest
+	Token string `json:"token"` // from the emailed link
+}
+
+type RequestMagicLinkRequest struct {
+	dto.BaseRequest
+	Email string `json:"email"`
+}
+
+type MagicLinkLoginRequest struct {
+	dto.BaseRequest
+	Token string `json:"token"` // from the emailed link
+}
+
+type SocialLoginRequest struct {
+	dto.BaseRequest
+	Token string `json:"token"` // from the console page a social login ends on
+}
+
 type UpdateRequest struct {
 	dto.BaseRequest
 	Bio string `json:"bio"`
@@ -95,3 +131,120 @@ func (r *UpdateRequest) BindAndValidate(c echo.Context) []string {
 
 	return r.ExtractValidationErrors(err)
 }
+
+func (r *ForgotPasswordRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Email: required, valid format
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *ResetPasswordRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Token: required, as emailed
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(0, 100).Error("Token must be at most 100 characters"),
+		),
+		// Password: required, at least 5 characters
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+			validation.Length(5, 0).Error("Password must be at least 5 characters"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *ResendVerificationRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Email: required, valid format
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *VerifyEmailRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Token: required, as emailed
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(0, 100).Error("Token must be at most 100 characters"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *RequestMagicLinkRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Email: required, valid format
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *MagicLinkLoginRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Token: required, as emailed
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(0, 100).Error("Token must be at most 100 characters"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
+
+func (r *SocialLoginRequest) BindAndValidate(c echo.Context) []string {
+	if err := c.Bind(r); err != nil {
+		return []string{"Invalid request payload: " + err.Error()}
+	}
+
+	err := validation.ValidateStruct(r,
+		// Token: required, as redirected with
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(0, 100).Error("Token must be at most 100 characters"),
+		),
+	)
+
+	return r.ExtractValidationErrors(err)
+}
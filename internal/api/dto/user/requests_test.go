@@ -429,3 +429,141 @@ func TestUpdateRequest_BindAndValidate_Suite(t *testing.T) {
 		}
 	})
 }
+
+func TestForgotPasswordRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("ForgotPasswordRequest: missing email", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r ForgotPasswordRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email is required")
+	})
+
+	t.Run("ForgotPasswordRequest: invalid email", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"email": "jane"})
+
+		var r ForgotPasswordRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email must be a valid email address")
+	})
+}
+
+func TestResetPasswordRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("ResetPasswordRequest: valid", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"token":    "oG3vXn2Qb5WcJz8yR1tKpL0aHdFsE7uM4iN6wV9xY2c",
+			"password": "new-password",
+		}
+
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, payload)
+
+		var r ResetPasswordRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("ResetPasswordRequest: missing token and short password", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"password": "abc"})
+
+		var r ResetPasswordRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Token is required")
+		pkg.AssertErrorContains(t, errs, "Password must be at least 5 characters")
+	})
+}
+
+func TestVerifyEmailRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("VerifyEmailRequest: valid", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"token": "oG3vXn2Qb5WcJz8yR1tKpL0aHdFsE7uM4iN6wV9xY2c"})
+
+		var r VerifyEmailRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("VerifyEmailRequest: token too long", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"token": strings.Repeat("a", 101)})
+
+		var r VerifyEmailRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Token must be at most 100 characters")
+	})
+}
+
+func TestRequestMagicLinkRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("RequestMagicLinkRequest: missing email", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r RequestMagicLinkRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email is required")
+	})
+
+	t.Run("RequestMagicLinkRequest: invalid email", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"email": "jane"})
+
+		var r RequestMagicLinkRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Email must be a valid email address")
+	})
+}
+
+func TestMagicLinkLoginRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("MagicLinkLoginRequest: valid", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"token": "oG3vXn2Qb5WcJz8yR1tKpL0aHdFsE7uM4iN6wV9xY2c"})
+
+		var r MagicLinkLoginRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("MagicLinkLoginRequest: missing token", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r MagicLinkLoginRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Token is required")
+	})
+}
+
+func TestSocialLoginRequest_BindAndValidate_Suite(t *testing.T) {
+	e := echo.New()
+
+	t.Run("SocialLoginRequest: valid", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{"token": "oG3vXn2Qb5WcJz8yR1tKpL0aHdFsE7uM4iN6wV9xY2c"})
+
+		var r SocialLoginRequest
+		errs := r.BindAndValidate(ctx)
+
+		assert.Len(t, errs, 0)
+	})
+
+	t.Run("SocialLoginRequest: missing token", func(t *testing.T) {
+		ctx := pkg.CreateFakeRequestContext(t, e, http.MethodPost, map[string]interface{}{})
+
+		var r SocialLoginRequest
+		errs := r.BindAndValidate(ctx)
+
+		pkg.AssertErrorContains(t, errs, "Token is required")
+	})
+}
@@ -12,6 +12,12 @@ type Response struct {
 	Status           string     `json:"status"`
 	RoleID           int        `json:"roleId"`
 	Bio              string     `json:"bio"`
+	EmailVerifiedAt  *string    `json:"emailVerifiedAt"`
 	CreatedAt        string     `json:"createdAt"`
 	UpdatedAt        string     `json:"updatedAt"`
 }
+
+type SocialProviderResponse struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
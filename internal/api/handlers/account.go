@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	userDto "fluxend/internal/api/dto/user"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/account"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type AccountHandler struct {
+	accountService account.Service
+}
+
+func NewAccountHandler(injector *do.Injector) (*AccountHandler, error) {
+	accountService := do.MustInvoke[account.Service](injector)
+
+	return &AccountHandler{accountService: accountService}, nil
+}
+
+// ForgotPassword emails a password reset link.
+//
+// @Summary Forgot password
+// @Description Email a single-use link to reset the password. The response is the same whether an account uses the address or not.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.ForgotPasswordRequest true "Account email"
+//
+// @Success 200 {object} response.Response{} "Reset link sent if the account exists"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/forgot-password [post]
+func (ah *AccountHandler) ForgotPassword(c echo.Context) error {
+	var request userDto.ForgotPasswordRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	if err := ah.accountService.ForgotPassword(userDto.ToForgotPasswordInput(&request)); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// ResetPassword sets a new password with an emailed token.
+//
+// @Summary Reset password
+// @Description Set a new password with the token of a reset link. The token works once, and existing sessions are signed out.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.ResetPasswordRequest true "Reset token and new password"
+//
+// @Success 200 {object} response.Response{} "Password reset"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Invalid or expired token response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/reset-password [post]
+func (ah *AccountHandler) ResetPassword(c echo.Context) error {
+	var request userDto.ResetPasswordRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	if err := ah.accountService.ResetPassword(userDto.ToResetPasswordInput(&request)); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// VerifyEmail confirms an email address with an emailed token.
+//
+// @Summary Verify email
+// @Description Confirm the email address of a new user with the token of the emailed link, which activates the account.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.VerifyEmailRequest true "Verification token"
+//
+// @Success 200 {object} response.Response{} "Email verified"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Invalid or expired token response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/verify-email [post]
+func (ah *AccountHandler) VerifyEmail(c echo.Context) error {
+	var request userDto.VerifyEmailRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	if err := ah.accountService.VerifyEmail(userDto.ToVerifyEmailInput(&request)); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// ResendVerification emails a new verification link.
+//
+// @Summary Resend verification email
+// @Description Email a new link to confirm the address of an account pending verification, at most once a minute. The response is the same for every address.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.ResendVerificationRequest true "Account email"
+//
+// @Success 200 {object} response.Response{} "Link sent if the account is pending verification"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/verify-email/resend [post]
+func (ah *AccountHandler) ResendVerification(c echo.Context) error {
+	var request userDto.ResendVerificationRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	if err := ah.accountService.ResendVerification(userDto.ToResendVerificationInput(&request)); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// RequestMagicLink emails a sign-in link.
+//
+// @Summary Request magic link
+// @Description Email a single-use link signing in without the password, when magic links are allowed. Requests are rate limited per IP address, and the response is the same for every address.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.RequestMagicLinkRequest true "Account email"
+//
+// @Success 200 {object} response.Response{} "Link sent if the account exists"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Magic links disabled response"
+// @Failure 429 {object} response.TooManyRequestsErrorResponse "Too many requests response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/magic-link [post]
+func (ah *AccountHandler) RequestMagicLink(c echo.Context) error {
+	var request userDto.RequestMagicLinkRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	if err := ah.accountService.RequestMagicLink(userDto.ToRequestMagicLinkInput(&request)); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// MagicLinkLogin authenticates a user with an emailed token and returns a JWT token.
+//
+// @Summary Login with magic link
+// @Description Exchange the token of a magic link for a JWT token, the same as a password login returns. The token works once.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.MagicLinkLoginRequest true "Magic link token"
+//
+// @Success 200 {object} response.Response{content=user.Response} "User details"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Invalid or expired token response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/magic-link/login [post]
+func (ah *AccountHandler) MagicLinkLogin(c echo.Context) error {
+	var request userDto.MagicLinkLoginRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	loggedInUser, token, err := ah.accountService.LoginWithMagicLink(userDto.ToMagicLinkLoginInput(&request))
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, map[string]interface{}{
+		"user":  mapper.ToUserResource(&loggedInUser),
+		"token": token,
+	})
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	accessDto "fluxend/internal/api/dto/storage/access"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/access"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type ContainerPolicyHandler struct {
+	accessService access.Service
+}
+
+func NewContainerPolicyHandler(injector *do.Injector) (*ContainerPolicyHandler, error) {
+	accessService := do.MustInvoke[access.Service](injector)
+
+	return &ContainerPolicyHandler{accessService: accessService}, nil
+}
+
+// List retrieves the policies of a container
+//
+// @Summary List container policies
+// @Description Retrieve the policies granting project end users access to a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Success 200 {object} response.Response{content=[]access.Response} "List of container policies"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/policies [get]
+func (ph *ContainerPolicyHandler) List(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	policies, err := ph.accessService.List(containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToContainerPolicyResourceCollection(policies))
+}
+
+// Show retrieves a container policy
+//
+// @Summary Show container policy
+// @Description Retrieve a policy of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param policyUUID path string true "Policy UUID"
+//
+// @Success 200 {object} response.Response{content=access.Response} "Container policy details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/policies/{policyUUID} [get]
+func (ph *ContainerPolicyHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	policyUUID, err := request.GetUUIDPathParam(c, "policyUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedPolicy, err := ph.accessService.GetByUUID(policyUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToContainerPolicyResource(&fetchedPolicy))
+}
+
+// Store creates a container policy
+//
+// @Summary Create container policy
+// @Description Add a policy letting the end users of the project read, upload or delete files of a container. The path pattern matches full file names, * stands for any characters and {claim} for a claim of the end user JWT, like users/{sub}/*.
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param policy body access.CreateRequest true "Container policy details"
+//
+// @Success 201 {object} response.Response{content=access.Response} "Container policy created"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/policies [post]
+func (ph *ContainerPolicyHandler) Store(c echo.Context) error {
+	var request accessDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	createdPolicy, err := ph.accessService.Create(containerUUID, accessDto.ToCreatePolicyInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToContainerPolicyResource(&createdPolicy))
+}
+
+// Update updates a container policy
+//
+// @Summary Update container policy
+// @Description Update a policy of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param policyUUID path string true "Policy UUID"
+// @Param policy body access.CreateRequest true "Container policy details"
+//
+// @Success 200 {object} response.Response{content=access.Response} "Container policy updated"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/policies/{policyUUID} [put]
+func (ph *ContainerPolicyHandler) Update(c echo.Context) error {
+	var request accessDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	policyUUID, err := request.GetUUIDPathParam(c, "policyUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedPolicy, err := ph.accessService.Update(policyUUID, containerUUID, accessDto.ToCreatePolicyInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToContainerPolicyResource(updatedPolicy))
+}
+
+// Delete removes a container policy
+//
+// @Summary Delete container policy
+// @Description Remove a policy of a container, end users lose the access it granted
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param policyUUID path string true "Policy UUID"
+//
+// @Success 204 "Container policy deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/policies/{policyUUID} [delete]
+func (ph *ContainerPolicyHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	policyUUID, err := request.GetUUIDPathParam(c, "policyUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := ph.accessService.Delete(policyUUID, containerUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
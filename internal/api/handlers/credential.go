@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	credentialDto "fluxend/internal/api/dto/storage/credential"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type CredentialHandler struct {
+	credentialService credential.Service
+}
+
+func NewCredentialHandler(injector *do.Injector) (*CredentialHandler, error) {
+	credentialService := do.MustInvoke[credential.Service](injector)
+
+	return &CredentialHandler{credentialService: credentialService}, nil
+}
+
+// List retrieves all storage credentials of a project
+//
+// @Summary Lists storage credentials
+// @Description Retrieve the storage credentials of a project. Secrets are never returned.
+// @Tags Credentials
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Success 200 {object} response.Response{content=[]credential.Response} "List of credentials"
+// @Failure 400 {object} response.BadRequestErrorResponse "Invalid input response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /credentials [get]
+func (ch *CredentialHandler) List(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	credentials, err := ch.credentialService.List(request.ProjectUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToCredentialResourceCollection(credentials))
+}
+
+// Show retrieves details of a storage credential
+//
+// @Summary Retrieves storage credential
+// @Description Get details of a specific storage credential. Secrets are never returned.
+// @Tags Credentials
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param credentialUUID path string true "Credential UUID"
+//
+// @Success 200 {object} response.Response{content=credential.Response} "Credential details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /credentials/{credentialUUID} [get]
+func (ch *CredentialHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	credentialUUID, err := request.GetUUIDPathParam(c, "credentialUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedCredential, err := ch.credentialService.GetByUUID(credentialUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToCredentialResource(&fetchedCredential))
+}
+
+// Store creates a storage credential
+//
+// @Summary Creates storage credential
+// @Description Store the secrets of a storage account, encrypted with a key of the project. Containers created with the credential use it instead of the instance wide credentials.
+// @Tags Credentials
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+// @Param credential body credential.CreateRequest true "Credential details"
+//
+// @Success 201 {object} response.Response{content=credential.Response} "Credential created"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Invalid input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /credentials [post]
+func (ch *CredentialHandler) Store(c echo.Context) error {
+	var request credentialDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	createdCredential, err := ch.credentialService.Create(credentialDto.ToCreateCredentialInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToCredentialResource(&createdCredential))
+}
+
+// Update replaces the name and secrets of a storage credential
+//
+// @Summary Updates storage credential
+// @Description Rename a storage credential and replace its secrets. The driver can't be changed.
+// @Tags Credentials
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param credentialUUID path string true "Credential UUID"
+// @Param credential body credential.UpdateRequest true "Credential details"
+//
+// @Success 200 {object} response.Response{content=credential.Response} "Credential updated"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /credentials/{credentialUUID} [put]
+func (ch *CredentialHandler) Update(c echo.Context) error {
+	var request credentialDto.UpdateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	credentialUUID, err := request.GetUUIDPathParam(c, "credentialUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedCredential, err := ch.credentialService.Update(credentialUUID, authUser, credentialDto.ToUpdateCredentialInput(&request))
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToCredentialResource(updatedCredential))
+}
+
+// Delete removes a storage credential
+//
+// @Summary Deletes storage credential
+// @Description Remove a storage credential. Credentials still used by a container can't be deleted.
+// @Tags Credentials
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param credentialUUID path string true "Credential UUID"
+//
+// @Success 204 "Credential deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /credentials/{credentialUUID} [delete]
+func (ch *CredentialHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	credentialUUID, err := request.GetUUIDPathParam(c, "credentialUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := ch.credentialService.Delete(credentialUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
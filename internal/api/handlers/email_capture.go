@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/email"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"mime"
+	"net/http"
+)
+
+type EmailCaptureHandler struct {
+	captureService email.CaptureService
+}
+
+func NewEmailCaptureHandler(injector *do.Injector) (*EmailCaptureHandler, error) {
+	captureService := do.MustInvoke[email.CaptureService](injector)
+
+	return &EmailCaptureHandler{captureService: captureService}, nil
+}
+
+// List retrieves captured emails
+//
+// @Summary List captured emails
+// @Description Retrieve the emails captured by the FILE mail driver instead of being sent, newest first
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Param page query string false "Page number for pagination"
+// @Param limit query string false "Number of items per page"
+//
+// @Success 200 {object} response.Response{content=[]email.CapturedMessageResponse} "List of captured emails"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/captured [get]
+func (ech *EmailCaptureHandler) List(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	messages, paginationDetails, err := ech.captureService.List(request.ExtractPaginationParams(c), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponseWithPagination(c, mapper.ToCapturedEmailResourceCollection(messages), paginationDetails)
+}
+
+// Show retrieves a captured email
+//
+// @Summary Show captured email
+// @Description Retrieve a captured email with its headers and bodies, attachments are only described
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param messageID path string true "Captured email ID"
+//
+// @Success 200 {object} response.Response{content=email.CapturedMessageDetailsResponse} "Captured email details"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/captured/{messageID} [get]
+func (ech *EmailCaptureHandler) Show(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	captured, err := ech.captureService.GetByID(c.Param("messageID"), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToCapturedEmailDetailsResource(&captured))
+}
+
+// Raw downloads a captured email
+//
+// @Summary Download captured email
+// @Description Download a captured email as an .eml file, to open it in a mail client
+// @Tags Admin
+//
+// @Produce message/rfc822
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param messageID path string true "Captured email ID"
+//
+// @Success 200 {file} file "Captured email"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/captured/{messageID}/raw [get]
+func (ech *EmailCaptureHandler) Raw(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	messageID := c.Param("messageID")
+	raw, err := ech.captureService.Raw(messageID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+		"filename": messageID + ".eml",
+	}))
+
+	return c.Blob(http.StatusOK, "message/rfc822", raw)
+}
+
+// Delete removes a captured email
+//
+// @Summary Delete captured email
+// @Description Remove a captured email
+// @Tags Admin
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param messageID path string true "Captured email ID"
+//
+// @Success 204 "Captured email deleted"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/captured/{messageID} [delete]
+func (ech *EmailCaptureHandler) Delete(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	if _, err := ech.captureService.Delete(c.Param("messageID"), authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
+
+// Clear removes every captured email
+//
+// @Summary Clear captured emails
+// @Description Remove every captured email, e.g. between test runs
+// @Tags Admin
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Success 204 "Captured emails deleted"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/captured [delete]
+func (ech *EmailCaptureHandler) Clear(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	if _, err := ech.captureService.Clear(authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
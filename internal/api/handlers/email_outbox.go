@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	emailDto "fluxend/internal/api/dto/email"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/email"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type EmailOutboxHandler struct {
+	outboxService email.OutboxService
+}
+
+func NewEmailOutboxHandler(injector *do.Injector) (*EmailOutboxHandler, error) {
+	outboxService := do.MustInvoke[email.OutboxService](injector)
+
+	return &EmailOutboxHandler{outboxService: outboxService}, nil
+}
+
+// List retrieves outbox messages
+//
+// @Summary List outbox messages
+// @Description Retrieve the queued, sent and failed emails, newest first
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Param status query string false "Filter by status: pending, sending, sent, failed or suppressed"
+// @Param recipient query string false "Filter by part of a recipient address"
+// @Param template query string false "Filter by template name"
+// @Param page query string false "Page number for pagination"
+// @Param limit query string false "Number of items per page"
+// @Param sort query string false "Field to sort by"
+//
+// @Success 200 {object} response.Response{content=[]email.OutboxMessageResponse} "List of outbox messages"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/outbox [get]
+func (eoh *EmailOutboxHandler) List(c echo.Context) error {
+	var request emailDto.ListOutboxRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	paginationParams := request.ExtractPaginationParams(c)
+
+	messages, paginationDetails, err := eoh.outboxService.List(emailDto.ToListOutboxInput(&request), paginationParams, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponseWithPagination(c, mapper.ToEmailOutboxResourceCollection(messages), paginationDetails)
+}
+
+// Show retrieves an outbox message
+//
+// @Summary Show outbox message
+// @Description Retrieve an outbox message with its content and every delivery attempt, attachments are only described. The content of sensitive messages, like password reset links, is left out
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param messageUUID path string true "Message UUID"
+//
+// @Success 200 {object} response.Response{content=email.OutboxDetailsResponse} "Outbox message details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/outbox/{messageUUID} [get]
+func (eoh *EmailOutboxHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	messageUUID, err := request.GetUUIDPathParam(c, "messageUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	details, err := eoh.outboxService.GetByUUID(messageUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailOutboxDetailsResource(&details))
+}
+
+// Resend queues an outbox message again
+//
+// @Summary Resend outbox message
+// @Description Queue a sent, failed or suppressed message again with a fresh set of attempts, sensitive messages can't be resent
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param messageUUID path string true "Message UUID"
+//
+// @Success 200 {object} response.Response{content=email.OutboxMessageResponse} "Outbox message queued"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/outbox/{messageUUID}/resend [post]
+func (eoh *EmailOutboxHandler) Resend(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	messageUUID, err := request.GetUUIDPathParam(c, "messageUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	requeuedMessage, err := eoh.outboxService.Resend(messageUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailOutboxResource(&requeuedMessage))
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	emailDto "fluxend/internal/api/dto/email"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/email"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type EmailSuppressionHandler struct {
+	suppressionService email.SuppressionService
+}
+
+func NewEmailSuppressionHandler(injector *do.Injector) (*EmailSuppressionHandler, error) {
+	suppressionService := do.MustInvoke[email.SuppressionService](injector)
+
+	return &EmailSuppressionHandler{suppressionService: suppressionService}, nil
+}
+
+// List retrieves suppressed email addresses
+//
+// @Summary List email suppressions
+// @Description Retrieve the addresses no email is sent to anymore, after a bounce, a complaint or by hand
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Param email query string false "Filter by part of the address"
+// @Param reason query string false "Filter by reason: bounce, complaint or manual"
+// @Param page query string false "Page number for pagination"
+// @Param limit query string false "Number of items per page"
+// @Param sort query string false "Field to sort by"
+//
+// @Success 200 {object} response.Response{content=[]email.SuppressionResponse} "List of suppressions"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/suppressions [get]
+func (esh *EmailSuppressionHandler) List(c echo.Context) error {
+	var request emailDto.ListSuppressionsRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	paginationParams := request.ExtractPaginationParams(c)
+
+	suppressions, paginationDetails, err := esh.suppressionService.List(emailDto.ToListSuppressionsInput(&request), paginationParams, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponseWithPagination(c, mapper.ToEmailSuppressionResourceCollection(suppressions), paginationDetails)
+}
+
+// Store suppresses an email address
+//
+// @Summary Create email suppression
+// @Description Stop sending emails to an address, queued messages leave it out from their next attempt
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param suppression body email.CreateSuppressionRequest true "Suppressed address"
+//
+// @Success 201 {object} response.Response{content=email.SuppressionResponse} "Suppression created"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/suppressions [post]
+func (esh *EmailSuppressionHandler) Store(c echo.Context) error {
+	var request emailDto.CreateSuppressionRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	createdSuppression, err := esh.suppressionService.Create(emailDto.ToCreateSuppressionInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToEmailSuppressionResource(&createdSuppression))
+}
+
+// Delete lifts an email suppression
+//
+// @Summary Delete email suppression
+// @Description Send emails to a suppressed address again
+// @Tags Admin
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param suppressionUUID path string true "Suppression UUID"
+//
+// @Success 204 "Suppression deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/suppressions/{suppressionUUID} [delete]
+func (esh *EmailSuppressionHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	suppressionUUID, err := request.GetUUIDPathParam(c, "suppressionUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := esh.suppressionService.Delete(suppressionUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
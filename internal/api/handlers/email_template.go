@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	emailDto "fluxend/internal/api/dto/email"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/email"
+	"fluxend/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type EmailTemplateHandler struct {
+	templateService email.Service
+}
+
+func NewEmailTemplateHandler(injector *do.Injector) (*EmailTemplateHandler, error) {
+	templateService := do.MustInvoke[email.Service](injector)
+
+	return &EmailTemplateHandler{templateService: templateService}, nil
+}
+
+// List retrieves all email templates
+//
+// @Summary List email templates
+// @Description Retrieve the email templates and layouts the platform sends emails with
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Success 200 {object} response.Response{content=[]email.TemplateResponse} "List of email templates"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates [get]
+func (eth *EmailTemplateHandler) List(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	templates, err := eth.templateService.List(authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailTemplateResourceCollection(templates))
+}
+
+// Show retrieves an email template
+//
+// @Summary Show email template
+// @Description Retrieve an email template or layout
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param templateUUID path string true "Template UUID"
+//
+// @Success 200 {object} response.Response{content=email.TemplateResponse} "Email template details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates/{templateUUID} [get]
+func (eth *EmailTemplateHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedTemplate, err := eth.templateService.GetByUUID(templateUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailTemplateResource(&fetchedTemplate))
+}
+
+// Store creates an email template
+//
+// @Summary Create email template
+// @Description Add an email template or layout. Subjects and bodies are Go templates using the declared parameters, like {{ .resetUrl }}. Layouts wrap messages, which they get as {{ .Content }}.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param template body email.CreateTemplateRequest true "Email template details"
+//
+// @Success 201 {object} response.Response{content=email.TemplateResponse} "Email template created"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates [post]
+func (eth *EmailTemplateHandler) Store(c echo.Context) error {
+	var request emailDto.CreateTemplateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	createdTemplate, err := eth.templateService.Create(emailDto.ToCreateTemplateInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToEmailTemplateResource(&createdTemplate))
+}
+
+// Update updates an email template
+//
+// @Summary Update email template
+// @Description Update the parameters, content and layout of an email template. Its name and type can't change.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param templateUUID path string true "Template UUID"
+// @Param template body email.CreateTemplateRequest true "Email template details"
+//
+// @Success 200 {object} response.Response{content=email.TemplateResponse} "Email template updated"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates/{templateUUID} [put]
+func (eth *EmailTemplateHandler) Update(c echo.Context) error {
+	var request emailDto.CreateTemplateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedTemplate, err := eth.templateService.Update(templateUUID, emailDto.ToCreateTemplateInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailTemplateResource(updatedTemplate))
+}
+
+// Delete removes an email template
+//
+// @Summary Delete email template
+// @Description Remove an email template along with the customizations of organizations. Layouts still wrapping messages can't be removed.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param templateUUID path string true "Template UUID"
+//
+// @Success 204 "Email template deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates/{templateUUID} [delete]
+func (eth *EmailTemplateHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := eth.templateService.Delete(templateUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
+
+// Preview renders an email template
+//
+// @Summary Preview email template
+// @Description Render a message template with its layout. Parameters left out take their example value.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param templateUUID path string true "Template UUID"
+// @Param preview body email.PreviewRequest true "Parameters to render with"
+//
+// @Success 200 {object} response.Response{content=email.PreviewResponse} "Rendered email"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/email/templates/{templateUUID}/preview [post]
+func (eth *EmailTemplateHandler) Preview(c echo.Context) error {
+	return eth.preview(c, uuid.NullUUID{})
+}
+
+// ListForOrganization retrieves the email templates of an organization
+//
+// @Summary List organization email templates
+// @Description Retrieve the email templates as the organization sends them, with its customizations applied
+// @Tags Organizations
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+//
+// @Success 200 {object} response.Response{content=[]email.TemplateResponse} "List of email templates"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /organizations/{organizationUUID}/email/templates [get]
+func (eth *EmailTemplateHandler) ListForOrganization(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	templates, err := eth.templateService.ListForOrganization(organizationUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailTemplateResourceCollection(templates))
+}
+
+// Customize overrides an email template for an organization
+//
+// @Summary Customize email template
+// @Description Replace the subject, message or HTML message of a template in the emails of an organization. Parts left empty keep the template content, and the previous customization is replaced.
+// @Tags Organizations
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+// @Param templateUUID path string true "Template UUID"
+// @Param template body email.CustomizeTemplateRequest true "Customized parts"
+//
+// @Success 200 {object} response.Response{content=email.TemplateResponse} "Customized email template"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /organizations/{organizationUUID}/email/templates/{templateUUID} [put]
+func (eth *EmailTemplateHandler) Customize(c echo.Context) error {
+	var request emailDto.CustomizeTemplateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	customizedTemplate, err := eth.templateService.Customize(templateUUID, organizationUUID, emailDto.ToCustomizeTemplateInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailTemplateResource(&customizedTemplate))
+}
+
+// Reset removes the customization of an email template for an organization
+//
+// @Summary Reset email template
+// @Description Remove the customization of a template, the organization sends it as is again
+// @Tags Organizations
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+// @Param templateUUID path string true "Template UUID"
+//
+// @Success 204 "Customization removed"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /organizations/{organizationUUID}/email/templates/{templateUUID} [delete]
+func (eth *EmailTemplateHandler) Reset(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := eth.templateService.ResetCustomization(templateUUID, organizationUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
+
+// PreviewForOrganization renders an email template as an organization sends it
+//
+// @Summary Preview organization email template
+// @Description Render a message template with its layout and the customization of the organization. Parameters left out take their example value.
+// @Tags Organizations
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+// @Param templateUUID path string true "Template UUID"
+// @Param preview body email.PreviewRequest true "Parameters to render with"
+//
+// @Success 200 {object} response.Response{content=email.PreviewResponse} "Rendered email"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /organizations/{organizationUUID}/email/templates/{templateUUID}/preview [post]
+func (eth *EmailTemplateHandler) PreviewForOrganization(c echo.Context) error {
+	var request dto.DefaultRequest
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	return eth.preview(c, uuid.NullUUID{UUID: organizationUUID, Valid: true})
+}
+
+func (eth *EmailTemplateHandler) preview(c echo.Context, organizationUUID uuid.NullUUID) error {
+	var request emailDto.PreviewRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	templateUUID, err := request.GetUUIDPathParam(c, "templateUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	rendered, err := eth.templateService.Preview(templateUUID, organizationUUID, request.Parameters, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToEmailPreviewResource(&rendered))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fluxend/internal/api/response"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"io"
+)
+
+type EmailWebhookHandler struct {
+	webhookService email.WebhookService
+}
+
+func NewEmailWebhookHandler(injector *do.Injector) (*EmailWebhookHandler, error) {
+	webhookService := do.MustInvoke[email.WebhookService](injector)
+
+	return &EmailWebhookHandler{webhookService: webhookService}, nil
+}
+
+// SES receives Amazon SES bounces and complaints
+//
+// @Summary SES webhook
+// @Description Endpoint for the SNS topics SES reports bounces and complaints to, subscriptions are confirmed automatically. Only the topics in SES_WEBHOOK_TOPIC_ARNS are accepted.
+// @Tags Email
+//
+// @Accept json
+// @Produce json
+//
+// @Success 200 {object} response.Response{} "Notification handled"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Invalid signature response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Webhook not configured response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /email/webhooks/ses [post]
+func (ewh *EmailWebhookHandler) SES(c echo.Context) error {
+	payload, err := readWebhookPayload(c)
+	if err != nil {
+		return response.BadRequestResponse(c, "emailWebhook.error.invalidPayload")
+	}
+
+	if err = ewh.webhookService.HandleSES(payload); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// SendGrid receives SendGrid bounces and spam reports
+//
+// @Summary SendGrid webhook
+// @Description Endpoint for the signed SendGrid Event Webhook, bounces and spam reports suppress their address
+// @Tags Email
+//
+// @Accept json
+// @Produce json
+//
+// @Param X-Twilio-Email-Event-Webhook-Signature header string true "Event Webhook signature"
+// @Param X-Twilio-Email-Event-Webhook-Timestamp header string true "Event Webhook timestamp"
+//
+// @Success 200 {object} response.Response{} "Events handled"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Invalid signature response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Webhook not configured response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /email/webhooks/sendgrid [post]
+func (ewh *EmailWebhookHandler) SendGrid(c echo.Context) error {
+	payload, err := readWebhookPayload(c)
+	if err != nil {
+		return response.BadRequestResponse(c, "emailWebhook.error.invalidPayload")
+	}
+
+	err = ewh.webhookService.HandleSendGrid(
+		payload,
+		c.Request().Header.Get("X-Twilio-Email-Event-Webhook-Signature"),
+		c.Request().Header.Get("X-Twilio-Email-Event-Webhook-Timestamp"),
+	)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// Mailgun receives Mailgun permanent failures and complaints
+//
+// @Summary Mailgun webhook
+// @Description Endpoint for the Mailgun "failed" and "complained" webhooks, permanent failures and complaints suppress their address
+// @Tags Email
+//
+// @Accept json
+// @Produce json
+//
+// @Success 200 {object} response.Response{} "Event handled"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Invalid signature response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Webhook not configured response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /email/webhooks/mailgun [post]
+func (ewh *EmailWebhookHandler) Mailgun(c echo.Context) error {
+	payload, err := readWebhookPayload(c)
+	if err != nil {
+		return response.BadRequestResponse(c, "emailWebhook.error.invalidPayload")
+	}
+
+	if err = ewh.webhookService.HandleMailgun(payload); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, nil)
+}
+
+// readWebhookPayload reads the raw body, signatures are computed over it as sent
+func readWebhookPayload(c echo.Context) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(c.Request().Body, constants.EmailWebhookMaxBodySize))
+}
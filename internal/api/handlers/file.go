@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"errors"
+	"fluxend/internal/adapters/storage"
 	"fluxend/internal/api/dto"
 	fileDto "fluxend/internal/api/dto/storage/file"
 	"fluxend/internal/api/mapper"
 	"fluxend/internal/api/response"
 	"fluxend/internal/domain/storage/file"
 	"fluxend/pkg/auth"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/do"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
 )
 
 type FileHandler struct {
@@ -39,6 +47,15 @@ func NewFileHandler(injector *do.Injector) (*FileHandler, error) {
 // @Param limit query string false "Number of items per page"
 // @Param sort query string false "Field to sort by"
 // @Param order query string false "Sort order (asc or desc)"
+// @Param prefix query string false "Only files whose name starts with this prefix"
+// @Param delimiter query string false "Only files without this delimiter after the prefix"
+// @Param mime_type query string false "MIME type, e.g. image/png or image/*"
+// @Param min_size query int false "Minimum size in KB"
+// @Param max_size query int false "Maximum size in KB"
+// @Param created_after query string false "Created on or after this date"
+// @Param created_before query string false "Created before this date"
+// @Param tag query string false "Only files with this tag"
+// @Param metadata[key] query string false "Only files whose metadata has this value for key, repeatable"
 //
 // @Success 200 {array} response.Response{content=[]file.Response} "List of files"
 // @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
@@ -47,7 +64,7 @@ func NewFileHandler(injector *do.Injector) (*FileHandler, error) {
 //
 // @Router /containers/{containerUUID}/files [get]
 func (fh *FileHandler) List(c echo.Context) error {
-	var request dto.DefaultRequestWithProjectHeader
+	var request fileDto.ListRequest
 	if err := request.BindAndValidate(c); err != nil {
 		return response.UnprocessableResponse(c, err)
 	}
@@ -60,7 +77,7 @@ func (fh *FileHandler) List(c echo.Context) error {
 	}
 
 	paginationParams := request.ExtractPaginationParams(c)
-	files, err := fh.fileService.List(paginationParams, containerUUID, authUser)
+	files, err := fh.fileService.List(paginationParams, containerUUID, fileDto.ToListFilesInput(&request), authUser)
 	if err != nil {
 		return response.ErrorResponse(c, err)
 	}
@@ -68,6 +85,60 @@ func (fh *FileHandler) List(c echo.Context) error {
 	return response.SuccessResponse(c, mapper.ToFileResourceCollection(files))
 }
 
+// Browse lists one level of the folder hierarchy of a container
+//
+// @Summary Browse files
+// @Description List the folders and files directly under a prefix, folders being the file names up to the next delimiter
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Param page query string false "Page number for pagination of files"
+// @Param limit query string false "Number of files per page"
+// @Param prefix query string false "Folder to list, ending with the delimiter"
+// @Param delimiter query string false "Folder delimiter, defaults to /"
+// @Param mime_type query string false "MIME type, e.g. image/png or image/*"
+// @Param min_size query int false "Minimum size in KB"
+// @Param max_size query int false "Maximum size in KB"
+// @Param created_after query string false "Created on or after this date"
+// @Param created_before query string false "Created before this date"
+// @Param tag query string false "Only files with this tag"
+// @Param metadata[key] query string false "Only files whose metadata has this value for key, repeatable"
+//
+// @Success 200 {object} response.Response{content=file.ListingResponse} "Folders and files"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/browse [get]
+func (fh *FileHandler) Browse(c echo.Context) error {
+	var request fileDto.BrowseRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, "Invalid container UUID")
+	}
+
+	paginationParams := request.ExtractPaginationParams(c)
+	listing, err := fh.fileService.Browse(paginationParams, containerUUID, fileDto.ToListFilesInput(&request.ListRequest), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToFileListingResource(&listing))
+}
+
 // Show retrieves details of a specific file.
 //
 // @Summary Retrieve file
@@ -205,10 +276,10 @@ func (fh *FileHandler) Rename(c echo.Context) error {
 	return response.SuccessResponse(c, mapper.ToFileResource(updatedFile))
 }
 
-// Download Retrieves a presigned URL for downloading a file
+// UpdateMetadata replaces the metadata and tags of a file
 //
-// @Summary Download file
-// @Description Get a presigned URL to download a specific file
+// @Summary Update file metadata
+// @Description Replace the user-defined key/value metadata and the tags of a specific file
 // @Tags Files
 //
 // @Accept json
@@ -219,14 +290,135 @@ func (fh *FileHandler) Rename(c echo.Context) error {
 //
 // @Param containerUUID path string true "Container UUID"
 // @Param fileUUID path string true "File UUID"
+// @Param metadata body file.UpdateMetadataRequest true "Metadata and tags"
 //
-// @Success 200 {object} response.Response{content=file.DownloadResponse} "File details"
+// @Success 200 {object} response.Response{content=file.Response} "File details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/metadata [put]
+func (fh *FileHandler) UpdateMetadata(c echo.Context) error {
+	var request fileDto.UpdateMetadataRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedFile, err := fh.fileService.UpdateMetadata(fileUUID, containerUUID, authUser, fileDto.ToUpdateMetadataInput(&request))
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToFileResource(updatedFile))
+}
+
+// Download streams the contents of a file
+//
+// @Summary Download file
+// @Description Stream the contents of a specific file. A single byte range can be requested with the Range header.
+// @Tags Files
+//
+// @Produce octet-stream
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+//
+// @Success 200 "File contents"
+// @Success 206 "Requested range of the file contents"
 // @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
 // @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 416 "Range not satisfiable"
 // @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
 //
 // @Router /containers/{containerUUID}/files/{fileUUID}/download [get]
 func (fh *FileHandler) Download(c echo.Context) error {
+	var request fileDto.DownloadRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedFile, fileObject, err := fh.fileService.Download(fileUUID, containerUUID, authUser, request.ByteRange)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidRange) {
+			return c.NoContent(http.StatusRequestedRangeNotSatisfiable)
+		}
+
+		return response.ErrorResponse(c, err)
+	}
+	defer fileObject.Body.Close()
+
+	contentType := fetchedFile.MimeType
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+		"filename": path.Base(fetchedFile.FullFileName),
+	}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileObject.ContentLength, 10))
+	header.Set("Accept-Ranges", "bytes")
+
+	status := http.StatusOK
+	if fileObject.Range != nil {
+		status = http.StatusPartialContent
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", fileObject.Range.Start, fileObject.Range.End, fileObject.TotalSize))
+	}
+
+	return c.Stream(status, contentType, fileObject.Body)
+}
+
+// PresignedURL Retrieves a presigned URL for downloading a file
+//
+// @Summary Get file URL
+// @Description Get a presigned URL to download a specific file directly from the storage provider
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+//
+// @Success 200 {object} response.Response{content=file.DownloadResponse} "File details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/url [get]
+func (fh *FileHandler) PresignedURL(c echo.Context) error {
 	var request dto.DefaultRequest
 	if err := request.BindAndValidate(c); err != nil {
 		return response.UnprocessableResponse(c, err)
@@ -252,10 +444,121 @@ func (fh *FileHandler) Download(c echo.Context) error {
 	return response.SuccessResponse(c, mapper.ToDownloadResource(url, 3600))
 }
 
+// Transform serves a resized, cropped or converted variant of an image file
+//
+// @Summary Transform image
+// @Description Resize, crop and convert an image file. Variants are cached in the container, so repeated requests are served from storage.
+// @Tags Files
+//
+// @Produce image/jpeg,image/png,image/webp
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+// @Param width query int false "Width in pixels"
+// @Param height query int false "Height in pixels"
+// @Param fit query string false "Fit when both dimensions are set (cover, contain, fill)"
+// @Param crop query string false "Crop applied before resizing as x,y,width,height"
+// @Param format query string false "Output format (jpeg, png, webp), defaults to the source format"
+// @Param quality query int false "JPEG quality between 1 and 100, refused for PNG and WebP which are lossless"
+//
+// @Success 200 "Transformed image"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/transform [get]
+func (fh *FileHandler) Transform(c echo.Context) error {
+	var request fileDto.TransformRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileObject, err := fh.fileService.Transform(fileUUID, containerUUID, authUser, request.Options())
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+	defer fileObject.Body.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileObject.ContentLength, 10))
+	header.Set("Cache-Control", "private, max-age=3600")
+
+	return c.Stream(http.StatusOK, fileObject.ContentType, fileObject.Body)
+}
+
+// TransformURL creates a signed URL serving an image variant of a public container
+//
+// @Summary Get transformed image URL
+// @Description Get a signed URL serving a transformed image without authentication. Only available for files of public containers.
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+// @Param width query int false "Width in pixels"
+// @Param height query int false "Height in pixels"
+// @Param fit query string false "Fit when both dimensions are set (cover, contain, fill)"
+// @Param crop query string false "Crop applied before resizing as x,y,width,height"
+// @Param format query string false "Output format (jpeg, png, webp), defaults to the source format"
+// @Param quality query int false "JPEG quality between 1 and 100, refused for PNG and WebP which are lossless"
+//
+// @Success 200 {object} response.Response{content=file.DownloadResponse} "Signed URL"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/transform/url [get]
+func (fh *FileHandler) TransformURL(c echo.Context) error {
+	var request fileDto.TransformRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	url, err := fh.fileService.CreateTransformURL(fileUUID, containerUUID, authUser, request.Options(), time.Hour)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToDownloadResource(url, 3600))
+}
+
 // Delete removes a file from a container
 //
 // @Summary Delete file
-// @Description Permanently remove a specific file from a container
+// @Description Remove a specific file from a container. Containers with a trash retention keep the file in the trash until it expires, other containers remove it permanently along with its versions.
 // @Tags Files
 //
 // @Accept json
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	bulkDto "fluxend/internal/api/dto/storage/bulk"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/bulk"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"mime"
+	"net/http"
+)
+
+type FileBulkHandler struct {
+	bulkService bulk.Service
+}
+
+func NewFileBulkHandler(injector *do.Injector) (*FileBulkHandler, error) {
+	bulkService := do.MustInvoke[bulk.Service](injector)
+
+	return &FileBulkHandler{bulkService: bulkService}, nil
+}
+
+// Download streams a ZIP archive of several files
+//
+// @Summary Download files as ZIP
+// @Description Stream a ZIP archive of the files of a container matching a prefix or a list of file UUIDs
+// @Tags Files
+//
+// @Accept json
+// @Produce application/zip
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param selection body bulk.SelectRequest true "Prefix or file UUIDs"
+//
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/bulk/download [post]
+func (bh *FileBulkHandler) Download(c echo.Context) error {
+	var request bulkDto.SelectRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	archive, err := bh.bulkService.CreateArchive(containerUUID, bulkDto.ToSelectFilesInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "application/zip")
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+		"filename": archive.Name,
+	}))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// The status is already sent, a failure can only cut the archive short
+	if err = archive.Stream(c.Response()); err != nil {
+		log.Error().
+			Str("action", constants.ActionBulkZip).
+			Str("container_uuid", containerUUID.String()).
+			Str("error", err.Error()).
+			Msg("failed to stream archive")
+	}
+
+	return nil
+}
+
+// Delete starts deleting several files
+//
+// @Summary Delete files in bulk
+// @Description Delete the files of a container matching a prefix or a list of file UUIDs in the background. The job reports the outcome for every file.
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param selection body bulk.SelectRequest true "Prefix or file UUIDs"
+//
+// @Success 201 {object} response.Response{content=bulk.JobResponse} "Bulk delete started"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/bulk/delete [post]
+func (bh *FileBulkHandler) Delete(c echo.Context) error {
+	var request bulkDto.SelectRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	startedJob, err := bh.bulkService.CreateDeleteJob(containerUUID, bulkDto.ToSelectFilesInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToBulkJobResource(&startedJob, nil))
+}
+
+// ShowJob retrieves a bulk delete job
+//
+// @Summary Retrieve bulk job
+// @Description Get the progress of a bulk delete job along with the outcome for every processed file
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param jobUUID path string true "Job UUID"
+//
+// @Success 200 {object} response.Response{content=bulk.JobResponse} "Bulk job details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/bulk/jobs/{jobUUID} [get]
+func (bh *FileBulkHandler) ShowJob(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	jobUUID, err := request.GetUUIDPathParam(c, "jobUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedJob, results, err := bh.bulkService.GetJob(jobUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToBulkJobResource(&fetchedJob, results))
+}
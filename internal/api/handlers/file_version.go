@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type FileVersionHandler struct {
+	fileService file.Service
+}
+
+func NewFileVersionHandler(injector *do.Injector) (*FileVersionHandler, error) {
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &FileVersionHandler{fileService: fileService}, nil
+}
+
+// List retrieves the previous versions of a file
+//
+// @Summary List file versions
+// @Description Retrieve the previous contents of a file, kept when the file name is uploaded again in a container with versioning. Newest versions come first.
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+//
+// @Success 200 {object} response.Response{content=[]file.VersionResponse} "List of file versions"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/versions [get]
+func (fvh *FileVersionHandler) List(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	versions, err := fvh.fileService.ListVersions(fileUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToFileVersionResourceCollection(versions))
+}
+
+// Restore makes a previous version the current content of a file
+//
+// @Summary Restore file version
+// @Description Make a previous version the current content of a file. The content it replaces is kept as a new version.
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+// @Param versionUUID path string true "Version UUID"
+//
+// @Success 200 {object} response.Response{content=file.Response} "File with the restored content"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/versions/{versionUUID}/restore [post]
+func (fvh *FileVersionHandler) Restore(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	versionUUID, err := request.GetUUIDPathParam(c, "versionUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	restoredFile, err := fvh.fileService.RestoreVersion(versionUUID, fileUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToFileResource(&restoredFile))
+}
+
+// Delete permanently removes a previous version of a file
+//
+// @Summary Delete file version
+// @Description Permanently remove a previous version of a file, the current content is not affected
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+// @Param versionUUID path string true "Version UUID"
+//
+// @Success 204 "File version deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/{fileUUID}/versions/{versionUUID} [delete]
+func (fvh *FileVersionHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	versionUUID, err := request.GetUUIDPathParam(c, "versionUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := fvh.fileService.DeleteVersion(versionUUID, fileUUID, containerUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	lifecycleDto "fluxend/internal/api/dto/storage/lifecycle"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/lifecycle"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type LifecycleRuleHandler struct {
+	lifecycleService lifecycle.Service
+}
+
+func NewLifecycleRuleHandler(injector *do.Injector) (*LifecycleRuleHandler, error) {
+	lifecycleService := do.MustInvoke[lifecycle.Service](injector)
+
+	return &LifecycleRuleHandler{lifecycleService: lifecycleService}, nil
+}
+
+// List retrieves the lifecycle rules of a container
+//
+// @Summary List lifecycle rules
+// @Description Retrieve the lifecycle rules of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Success 200 {object} response.Response{content=[]lifecycle.Response} "List of lifecycle rules"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules [get]
+func (lh *LifecycleRuleHandler) List(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	rules, err := lh.lifecycleService.List(containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToLifecycleRuleResourceCollection(rules))
+}
+
+// Show retrieves a lifecycle rule
+//
+// @Summary Show lifecycle rule
+// @Description Retrieve a lifecycle rule of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param ruleUUID path string true "Rule UUID"
+//
+// @Success 200 {object} response.Response{content=lifecycle.Response} "Lifecycle rule details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules/{ruleUUID} [get]
+func (lh *LifecycleRuleHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	ruleUUID, err := request.GetUUIDPathParam(c, "ruleUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedRule, err := lh.lifecycleService.GetByUUID(ruleUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToLifecycleRuleResource(&fetchedRule))
+}
+
+// Store creates a lifecycle rule
+//
+// @Summary Create lifecycle rule
+// @Description Add a rule deleting or moving the files of a container under a prefix once they haven't changed for a number of days. Rules are applied in the background once enabled.
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param rule body lifecycle.CreateRequest true "Lifecycle rule details"
+//
+// @Success 201 {object} response.Response{content=lifecycle.Response} "Lifecycle rule created"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules [post]
+func (lh *LifecycleRuleHandler) Store(c echo.Context) error {
+	var request lifecycleDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	createdRule, err := lh.lifecycleService.Create(containerUUID, lifecycleDto.ToCreateRuleInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToLifecycleRuleResource(&createdRule))
+}
+
+// Update updates a lifecycle rule
+//
+// @Summary Update lifecycle rule
+// @Description Update a lifecycle rule of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param ruleUUID path string true "Rule UUID"
+// @Param rule body lifecycle.CreateRequest true "Lifecycle rule details"
+//
+// @Success 200 {object} response.Response{content=lifecycle.Response} "Lifecycle rule updated"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules/{ruleUUID} [put]
+func (lh *LifecycleRuleHandler) Update(c echo.Context) error {
+	var request lifecycleDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	ruleUUID, err := request.GetUUIDPathParam(c, "ruleUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedRule, err := lh.lifecycleService.Update(ruleUUID, containerUUID, lifecycleDto.ToCreateRuleInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToLifecycleRuleResource(updatedRule))
+}
+
+// Delete removes a lifecycle rule
+//
+// @Summary Delete lifecycle rule
+// @Description Remove a lifecycle rule of a container
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param ruleUUID path string true "Rule UUID"
+//
+// @Success 204 "Lifecycle rule deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules/{ruleUUID} [delete]
+func (lh *LifecycleRuleHandler) Delete(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	ruleUUID, err := request.GetUUIDPathParam(c, "ruleUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := lh.lifecycleService.Delete(ruleUUID, containerUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
+
+// Report previews what the lifecycle rules of a container would do
+//
+// @Summary Lifecycle dry-run report
+// @Description List the files every lifecycle rule of a container would delete or move if it ran now, disabled rules included. Nothing is changed.
+// @Tags Containers
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Success 200 {object} response.Response{content=[]lifecycle.ReportResponse} "Dry-run report per rule"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/rules/report [get]
+func (lh *LifecycleRuleHandler) Report(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	reports, err := lh.lifecycleService.DryRun(containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToLifecycleReportResourceCollection(reports))
+}
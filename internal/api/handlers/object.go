@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"fluxend/internal/adapters/storage"
+	accessDto "fluxend/internal/api/dto/storage/access"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/access"
+	"fluxend/pkg/auth"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// ObjectHandler serves the files of containers to the end users of projects, as allowed by container policies
+type ObjectHandler struct {
+	accessService access.Service
+}
+
+func NewObjectHandler(injector *do.Injector) (*ObjectHandler, error) {
+	accessService := do.MustInvoke[access.Service](injector)
+
+	return &ObjectHandler{accessService: accessService}, nil
+}
+
+// Download streams a file to a project end user
+//
+// @Summary Download file as end user
+// @Description Download a file by its full name on behalf of a project end user, identified by a JWT with a role claim like PostgREST and a project_uuid or aud claim naming the project of the container. Requests without a token are made as the default role. A policy of the container has to allow reading the file.
+// @Tags Files
+//
+// @Produce octet-stream
+//
+// @Param Authorization header string false "Bearer end user token"
+// @Param Range header string false "Single byte range, like bytes=0-1023"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param filePath path string true "Full file name"
+//
+// @Success 200 "File contents"
+// @Success 206 "Requested range of the file"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 416 "Range not satisfiable"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /objects/{containerUUID}/{filePath} [get]
+func (oh *ObjectHandler) Download(c echo.Context) error {
+	var request accessDto.ObjectRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	endUser, _ := auth.NewAuth(c).EndUser()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedFile, fileObject, err := oh.accessService.Download(containerUUID, request.FullFileName, endUser, request.ByteRange)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidRange) {
+			return c.NoContent(http.StatusRequestedRangeNotSatisfiable)
+		}
+
+		return response.ErrorResponse(c, err)
+	}
+	defer fileObject.Body.Close()
+
+	contentType := fetchedFile.MimeType
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{
+		"filename": path.Base(fetchedFile.FullFileName),
+	}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileObject.ContentLength, 10))
+	header.Set("Accept-Ranges", "bytes")
+
+	status := http.StatusOK
+	if fileObject.Range != nil {
+		status = http.StatusPartialContent
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", fileObject.Range.Start, fileObject.Range.End, fileObject.TotalSize))
+	}
+
+	return c.Stream(status, contentType, fileObject.Body)
+}
+
+// Upload stores a file sent by a project end user
+//
+// @Summary Upload file as end user
+// @Description Store the request body as a file on behalf of a project end user, identified by a JWT with a role claim like PostgREST and a project_uuid or aud claim naming the project of the container. A policy of the container has to allow uploading the file, which is recorded as created by the author of that policy.
+// @Tags Files
+//
+// @Accept octet-stream
+// @Produce json
+//
+// @Param Authorization header string false "Bearer end user token"
+// @Param Content-Length header int true "Size of the file in bytes"
+// @Param Content-Type header string false "MIME type of the file, detected from the content anyway"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param filePath path string true "Full file name"
+//
+// @Success 201 {object} response.Response{content=file.Response} "File details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable entity response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /objects/{containerUUID}/{filePath} [put]
+func (oh *ObjectHandler) Upload(c echo.Context) error {
+	var request accessDto.UploadRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	endUser, _ := auth.NewAuth(c).EndUser()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	createdFile, err := oh.accessService.Upload(containerUUID, accessDto.ToStoreFileInput(&request), endUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToFileResource(&createdFile))
+}
+
+// Delete removes a file on behalf of a project end user
+//
+// @Summary Delete file as end user
+// @Description Delete a file by its full name on behalf of a project end user, identified by a JWT with a role claim like PostgREST and a project_uuid or aud claim naming the project of the container. A policy of the container has to allow deleting the file. Containers with a trash retention keep it in the trash.
+// @Tags Files
+//
+// @Param Authorization header string false "Bearer end user token"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param filePath path string true "Full file name"
+//
+// @Success 204 "File deleted"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /objects/{containerUUID}/{filePath} [delete]
+func (oh *ObjectHandler) Delete(c echo.Context) error {
+	var request accessDto.ObjectRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	endUser, _ := auth.NewAuth(c).EndUser()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if err = oh.accessService.Remove(containerUUID, request.FullFileName, endUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
@@ -34,11 +34,14 @@ func NewSettingHandler(injector *do.Injector) (*SettingHandler, error) {
 // @Success 200 {object} response.Response{content=[]setting.Response} "List of indexes"
 // @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
 // @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
 // @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
 //
 // @Router /admin/settings [get]
 func (sh *SettingHandler) List(c echo.Context) error {
-	settings, err := sh.settingService.List()
+	authUser, _ := auth.NewAuth(c).User()
+
+	settings, err := sh.settingService.List(authUser)
 	if err != nil {
 		return response.ErrorResponse(c, err)
 	}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	stdErrors "errors"
+	userDto "fluxend/internal/api/dto/user"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/account"
+	flxErrors "fluxend/pkg/errors"
+	"fluxend/pkg/message"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+type SocialLoginHandler struct {
+	socialService account.SocialService
+}
+
+func NewSocialLoginHandler(injector *do.Injector) (*SocialLoginHandler, error) {
+	socialService := do.MustInvoke[account.SocialService](injector)
+
+	return &SocialLoginHandler{socialService: socialService}, nil
+}
+
+// Providers lists the identity providers users can sign in with.
+//
+// @Summary List identity providers
+// @Description List the configured identity providers, to show a sign in button for each
+// @Tags Users
+//
+// @Produce json
+//
+// @Success 200 {object} response.Response{content=[]user.SocialProviderResponse} "Identity providers"
+//
+// @Router /users/oauth/providers [get]
+func (slh *SocialLoginHandler) Providers(c echo.Context) error {
+	return response.SuccessResponse(c, mapper.ToSocialProviderResourceCollection(slh.socialService.Providers()))
+}
+
+// Authorize starts a social login.
+//
+// @Summary Start social login
+// @Description Redirect the browser to the identity provider. It comes back to the callback, which ends on the console with a code for the login endpoint.
+// @Tags Users
+//
+// @Param provider path string true "Identity provider: google, github or oidc"
+//
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} response.NotFoundErrorResponse "Identity provider not configured"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/oauth/{provider} [get]
+func (slh *SocialLoginHandler) Authorize(c echo.Context) error {
+	provider := c.Param("provider")
+
+	authURL, sealedState, err := slh.socialService.Authorize(c.Request().Context(), provider)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	c.SetCookie(stateCookie(provider, sealedState, int(constants.IdentityStateTTL.Seconds())))
+
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback finishes a social login.
+//
+// @Summary Social login callback
+// @Description Where the identity provider sends the browser back to. It links the identity, creating the user when registrations are allowed, and redirects to the console with a single-use code, or with an error.
+// @Tags Users
+//
+// @Param provider path string true "Identity provider"
+// @Param code query string false "Authorization code"
+// @Param state query string true "State sent to the provider"
+//
+// @Success 302 "Redirect to the console"
+//
+// @Router /users/oauth/{provider}/callback [get]
+func (slh *SocialLoginHandler) Callback(c echo.Context) error {
+	provider := c.Param("provider")
+
+	input := account.SocialCallbackInput{
+		Provider: provider,
+		Code:     c.QueryParam("code"),
+		State:    c.QueryParam("state"),
+		Error:    c.QueryParam("error"),
+	}
+
+	if cookie, err := c.Cookie(constants.AccountSocialStateCookie); err == nil {
+		input.SealedState = cookie.Value
+	}
+
+	// The state is single use, whatever happens next
+	c.SetCookie(stateCookie(provider, "", -1))
+
+	code, err := slh.socialService.Callback(c.Request().Context(), &input)
+	if err != nil {
+		return c.Redirect(http.StatusFound, slh.socialService.ConsoleURL(url.Values{"error": {socialLoginError(provider, err)}}))
+	}
+
+	return c.Redirect(http.StatusFound, slh.socialService.ConsoleURL(url.Values{"token": {code}}))
+}
+
+// Login exchanges the code of a social login for a JWT token.
+//
+// @Summary Login with social login code
+// @Description Exchange the code the social login callback redirected to the console with for a JWT token, the same as a password login returns. The code works once, within a minute.
+// @Tags Users
+//
+// @Accept json
+// @Produce json
+//
+// @Param request body user.SocialLoginRequest true "Social login code"
+//
+// @Success 200 {object} response.Response{content=user.Response} "User details"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Invalid or expired code response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /users/oauth/login [post]
+func (slh *SocialLoginHandler) Login(c echo.Context) error {
+	var request userDto.SocialLoginRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	loggedInUser, token, err := slh.socialService.Login(userDto.ToSocialLoginInput(&request))
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, map[string]interface{}{
+		"user":  mapper.ToUserResource(&loggedInUser),
+		"token": token,
+	})
+}
+
+// stateCookie only goes back to the callback of the provider. Lax lets the browser send it when the
+// provider redirects back
+func stateCookie(provider, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     constants.AccountSocialStateCookie,
+		Value:    value,
+		Path:     "/users/oauth/" + provider,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(os.Getenv("API_URL"), "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// socialLoginError is shown by the console. Errors other than the expected ones stay in the logs
+func socialLoginError(provider string, err error) string {
+	var notFoundErr *flxErrors.NotFoundError
+	var unauthorizedErr *flxErrors.UnauthorizedError
+	var forbiddenErr *flxErrors.ForbiddenError
+	var badRequestErr *flxErrors.BadRequestError
+
+	if stdErrors.As(err, &notFoundErr) || stdErrors.As(err, &unauthorizedErr) || stdErrors.As(err, &forbiddenErr) || stdErrors.As(err, &badRequestErr) {
+		return message.Message(err.Error())
+	}
+
+	log.Error().
+		Str("action", constants.ActionSocialLogin).
+		Str("provider", provider).
+		Str("error", err.Error()).
+		Msg("social login failed")
+
+	return message.Message("account.error.socialLoginFailed")
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fluxend/internal/adapters/storage"
+	fileDto "fluxend/internal/api/dto/storage/file"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/file"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type StorageHandler struct {
+	fileService file.Service
+}
+
+func NewStorageHandler(injector *do.Injector) (*StorageHandler, error) {
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &StorageHandler{fileService: fileService}, nil
+}
+
+// Serve streams a file stored by the filesystem driver using a presigned URL
+//
+// @Summary Serve stored file
+// @Description Serve a file from the filesystem storage driver. The URL is generated by the download endpoint and is only valid until it expires.
+// @Tags Files
+//
+// @Produce octet-stream
+//
+// @Param containerName path string true "Container name key"
+// @Param filePath path string true "Full file name"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param signature query string true "URL signature"
+//
+// @Success 200 "File contents"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+//
+// @Router /storage/{containerName}/{filePath} [get]
+func (sh *StorageHandler) Serve(c echo.Context) error {
+	fileName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return response.BadRequestResponse(c, "filesystem.error.invalidFileName")
+	}
+
+	filesystemService, err := storage.NewFilesystemService()
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	filePath, err := filesystemService.ResolvePresignedPath(
+		storage.FileInput{ContainerName: c.Param("containerName"), FileName: fileName},
+		c.QueryParam("expires"),
+		c.QueryParam("signature"),
+	)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	// c.File relies on http.ServeContent, which takes care of Range and conditional requests
+	return c.File(filePath)
+}
+
+// ServeTransform serves an image variant of a public container using a signed URL
+//
+// @Summary Serve transformed image
+// @Description Serve a transformed image of a public container. The URL is generated by the transform URL endpoint and is only valid until it expires.
+// @Tags Files
+//
+// @Produce image/jpeg,image/png,image/webp
+//
+// @Param fileUUID path string true "File UUID"
+// @Param width query int false "Width in pixels"
+// @Param height query int false "Height in pixels"
+// @Param fit query string false "Fit when both dimensions are set (cover, contain, fill)"
+// @Param crop query string false "Crop applied before resizing as x,y,width,height"
+// @Param format query string false "Output format (jpeg, png, webp)"
+// @Param quality query int false "JPEG quality between 1 and 100, refused for PNG and WebP which are lossless"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param signature query string true "URL signature"
+//
+// @Success 200 "Transformed image"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+//
+// @Router /storage/transform/{fileUUID} [get]
+func (sh *StorageHandler) ServeTransform(c echo.Context) error {
+	var request fileDto.SignedTransformRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileObject, err := sh.fileService.TransformSigned(fileUUID, request.Options(), request.Expires, request.Signature)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+	defer fileObject.Body.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(fileObject.ContentLength, 10))
+	header.Set("Cache-Control", "public, max-age=3600")
+
+	return c.Stream(http.StatusOK, fileObject.ContentType, fileObject.Body)
+}
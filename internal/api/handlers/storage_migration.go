@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	migrationDto "fluxend/internal/api/dto/storage/migration"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/migration"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type StorageMigrationHandler struct {
+	migrationService migration.Service
+}
+
+func NewStorageMigrationHandler(injector *do.Injector) (*StorageMigrationHandler, error) {
+	migrationService := do.MustInvoke[migration.Service](injector)
+
+	return &StorageMigrationHandler{migrationService: migrationService}, nil
+}
+
+// List retrieves all storage migrations
+//
+// @Summary List storage migrations
+// @Description Retrieve all storage migrations with their progress
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+//
+// @Success 200 {object} response.Response{content=[]migration.Response} "List of storage migrations"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/storage/migrations [get]
+func (smh *StorageMigrationHandler) List(c echo.Context) error {
+	authUser, _ := auth.NewAuth(c).User()
+
+	migrations, err := smh.migrationService.List(authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageMigrationResourceCollection(migrations))
+}
+
+// Show retrieves a storage migration
+//
+// @Summary Retrieve storage migration
+// @Description Get the status and progress of a storage migration
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param migrationUUID path string true "Migration UUID"
+//
+// @Success 200 {object} response.Response{content=migration.Response} "Storage migration details"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/storage/migrations/{migrationUUID} [get]
+func (smh *StorageMigrationHandler) Show(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	migrationUUID, err := request.GetUUIDPathParam(c, "migrationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fetchedMigration, err := smh.migrationService.GetByUUID(migrationUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageMigrationResource(&fetchedMigration))
+}
+
+// Store starts a storage migration
+//
+// @Summary Start storage migration
+// @Description Copy all containers, files and backups from one storage driver to another in the background. Every object is verified on the target before containers are switched over. Starting a migration between the same drivers again resumes the unfinished one.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param migration body migration.CreateRequest true "Source and target drivers"
+//
+// @Success 201 {object} response.Response{content=migration.Response} "Storage migration started"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/storage/migrations [post]
+func (smh *StorageMigrationHandler) Store(c echo.Context) error {
+	var request migrationDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	startedMigration, err := smh.migrationService.Create(migrationDto.ToCreateMigrationInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.CreatedResponse(c, mapper.ToStorageMigrationResource(&startedMigration))
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	quotaDto "fluxend/internal/api/dto/storage/quota"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/quota"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type StorageQuotaHandler struct {
+	quotaService quota.Service
+}
+
+func NewStorageQuotaHandler(injector *do.Injector) (*StorageQuotaHandler, error) {
+	quotaService := do.MustInvoke[quota.Service](injector)
+
+	return &StorageQuotaHandler{quotaService: quotaService}, nil
+}
+
+// ShowProject retrieves the storage usage of a project
+//
+// @Summary Retrieve project storage usage
+// @Description Get the space and number of files a project takes up across its containers, trashed files and versions included, along with the limits that apply to it
+// @Tags Projects
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param projectUUID path string true "Project UUID"
+//
+// @Success 200 {object} response.Response{content=quota.UsageResponse} "Project storage usage"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /projects/{projectUUID}/storage/usage [get]
+func (sqh *StorageQuotaHandler) ShowProject(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	projectUUID, err := request.GetUUIDPathParam(c, "projectUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	usage, err := sqh.quotaService.GetProjectUsage(projectUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageUsageResource(&usage))
+}
+
+// ShowOrganization retrieves the storage usage of an organization
+//
+// @Summary Retrieve organization storage usage
+// @Description Get the space and number of files the projects of an organization take up together, along with the limits that apply to it
+// @Tags Organizations
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+//
+// @Success 200 {object} response.Response{content=quota.UsageResponse} "Organization storage usage"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /organizations/{organizationUUID}/storage/usage [get]
+func (sqh *StorageQuotaHandler) ShowOrganization(c echo.Context) error {
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	usage, err := sqh.quotaService.GetOrganizationUsage(organizationUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageUsageResource(&usage))
+}
+
+// UpdateProject sets the storage quota of a project
+//
+// @Summary Update project storage quota
+// @Description Set the maximum space and number of files of a project. A limit of 0 falls back to the instance wide setting.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param projectUUID path string true "Project UUID"
+// @Param quota body quota.UpdateRequest true "Quota limits"
+//
+// @Success 200 {object} response.Response{content=quota.UsageResponse} "Project storage usage"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/storage/quotas/projects/{projectUUID} [put]
+func (sqh *StorageQuotaHandler) UpdateProject(c echo.Context) error {
+	var request quotaDto.UpdateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	projectUUID, err := request.GetUUIDPathParam(c, "projectUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	usage, err := sqh.quotaService.UpdateProjectQuota(projectUUID, quotaDto.ToUpdateQuotaInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageUsageResource(&usage))
+}
+
+// UpdateOrganization sets the storage quota of an organization
+//
+// @Summary Update organization storage quota
+// @Description Set the maximum space and number of files of all projects of an organization together. A limit of 0 falls back to the instance wide setting.
+// @Tags Admin
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param organizationUUID path string true "Organization UUID"
+// @Param quota body quota.UpdateRequest true "Quota limits"
+//
+// @Success 200 {object} response.Response{content=quota.UsageResponse} "Organization storage usage"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 403 {object} response.ForbiddenErrorResponse "Forbidden response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /admin/storage/quotas/organizations/{organizationUUID} [put]
+func (sqh *StorageQuotaHandler) UpdateOrganization(c echo.Context) error {
+	var request quotaDto.UpdateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	organizationUUID, err := request.GetUUIDPathParam(c, "organizationUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	usage, err := sqh.quotaService.UpdateOrganizationQuota(organizationUUID, quotaDto.ToUpdateQuotaInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToStorageUsageResource(&usage))
+}
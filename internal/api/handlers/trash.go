@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"fluxend/internal/api/dto"
+	fileDto "fluxend/internal/api/dto/storage/file"
+	"fluxend/internal/api/mapper"
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type TrashHandler struct {
+	fileService file.Service
+}
+
+func NewTrashHandler(injector *do.Injector) (*TrashHandler, error) {
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &TrashHandler{fileService: fileService}, nil
+}
+
+// List retrieves the trashed files of a container
+//
+// @Summary List trashed files
+// @Description Retrieve the files deleted from a container that are still kept in its trash, most recently deleted first
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Param page query string false "Page number for pagination"
+// @Param limit query string false "Number of items per page"
+//
+// @Success 200 {object} response.Response{content=[]file.TrashedResponse} "List of trashed files"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/trash [get]
+func (th *TrashHandler) List(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	paginationParams := request.ExtractPaginationParams(c)
+	files, err := th.fileService.ListTrash(paginationParams, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToTrashedFileResourceCollection(files))
+}
+
+// Restore moves a file out of the trash
+//
+// @Summary Restore trashed file
+// @Description Move a file out of the trash of its container. When its name was taken since the file was deleted, a new name has to be given.
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+// @Param file body file.RestoreRequest false "Name to restore the file under"
+//
+// @Success 200 {object} response.Response{content=file.Response} "File restored"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable input response"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/trash/{fileUUID}/restore [post]
+func (th *TrashHandler) Restore(c echo.Context) error {
+	var request fileDto.RestoreRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	restoredFile, err := th.fileService.RestoreFromTrash(fileUUID, containerUUID, authUser, fileDto.ToRestoreFileInput(&request))
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, mapper.ToFileResource(&restoredFile))
+}
+
+// Purge permanently removes a trashed file
+//
+// @Summary Purge trashed file
+// @Description Permanently remove a file from the trash of its container, along with its versions
+// @Tags Files
+//
+// @Accept json
+// @Produce json
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param fileUUID path string true "File UUID"
+//
+// @Success 204 "File purged"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/trash/{fileUUID} [delete]
+func (th *TrashHandler) Purge(c echo.Context) error {
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	fileUUID, err := request.GetUUIDPathParam(c, "fileUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := th.fileService.Purge(fileUUID, containerUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return response.DeletedResponse(c, nil)
+}
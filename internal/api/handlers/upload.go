@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"fluxend/internal/api/dto"
+	uploadDto "fluxend/internal/api/dto/storage/upload"
+	"fluxend/internal/api/response"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/upload"
+	"fluxend/pkg/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+	"net/http"
+	"strconv"
+)
+
+// UploadHandler implements the tus protocol for resumable uploads, see https://tus.io/protocols/resumable-upload
+type UploadHandler struct {
+	uploadService upload.Service
+}
+
+func NewUploadHandler(injector *do.Injector) (*UploadHandler, error) {
+	uploadService := do.MustInvoke[upload.Service](injector)
+
+	return &UploadHandler{uploadService: uploadService}, nil
+}
+
+// Options describes the supported tus version and extensions
+//
+// @Summary Resumable upload capabilities
+// @Description Report the tus protocol version and extensions supported by the upload endpoint
+// @Tags Files
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param containerUUID path string true "Container UUID"
+//
+// @Success 204 "Supported tus version and extensions"
+//
+// @Router /containers/{containerUUID}/files/uploads [options]
+func (uh *UploadHandler) Options(c echo.Context) error {
+	header := c.Response().Header()
+	header.Set(constants.TusResumableHeader, constants.TusVersion)
+	header.Set(constants.TusVersionHeader, constants.TusVersion)
+	header.Set(constants.TusExtensionHeader, constants.TusExtensions)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Store starts a resumable upload
+//
+// @Summary Create resumable upload
+// @Description Start a tus upload. The file is created once all of its bytes have been sent with PATCH requests. The declared length counts towards the storage quota until then, and an upload not resumed before its Upload-Expires time is dropped.
+// @Tags Files
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+// @Param Tus-Resumable header string true "Protocol version, 1.0.0"
+// @Param Upload-Length header int true "File size in bytes"
+// @Param Upload-Metadata header string true "Base64 encoded metadata, filename is required and filetype is optional"
+//
+// @Param containerUUID path string true "Container UUID"
+//
+// @Success 201 "Upload created, its URL is in the Location header and its expiry in the Upload-Expires header"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 412 "Unsupported tus version"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable entity response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/uploads [post]
+func (uh *UploadHandler) Store(c echo.Context) error {
+	if !uh.hasSupportedVersion(c) {
+		return c.NoContent(http.StatusPreconditionFailed)
+	}
+
+	var request uploadDto.CreateRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	createdUpload, err := uh.uploadService.Create(containerUUID, uploadDto.ToCreateUploadInput(&request), authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, c.Request().URL.Path+"/"+createdUpload.Uuid.String())
+	c.Response().Header().Set(constants.UploadExpiresHeader, uh.formatExpiry(createdUpload))
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// Show reports how many bytes of an upload have been received
+//
+// @Summary Get resumable upload offset
+// @Description Get the offset to resume a tus upload from
+// @Tags Files
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param Tus-Resumable header string true "Protocol version, 1.0.0"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param uploadUUID path string true "Upload UUID"
+//
+// @Success 200 "Upload-Offset, Upload-Length and Upload-Expires headers"
+// @Failure 404 "Upload not found"
+// @Failure 412 "Unsupported tus version"
+//
+// @Router /containers/{containerUUID}/files/uploads/{uploadUUID} [head]
+func (uh *UploadHandler) Show(c echo.Context) error {
+	if !uh.hasSupportedVersion(c) {
+		return c.NoContent(http.StatusPreconditionFailed)
+	}
+
+	var request dto.DefaultRequest
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	uploadUUID, err := request.GetUUIDPathParam(c, "uploadUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	// net/http drops the body of HEAD responses, only the status code reaches the client
+	fetchedUpload, err := uh.uploadService.GetByUUID(uploadUUID, containerUUID, authUser)
+	if err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	header := c.Response().Header()
+	header.Set(constants.UploadOffsetHeader, strconv.FormatInt(fetchedUpload.UploadOffset, 10))
+	header.Set(constants.UploadLengthHeader, strconv.FormatInt(fetchedUpload.UploadLength, 10))
+	header.Set(constants.UploadExpiresHeader, uh.formatExpiry(fetchedUpload))
+	header.Set(echo.HeaderCacheControl, "no-store")
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Append receives the next chunk of a resumable upload
+//
+// @Summary Upload chunk
+// @Description Append bytes to a tus upload at the given offset. The file is created when the last byte is received and its UUID is returned in the X-File-UUID header.
+// @Tags Files
+//
+// @Accept application/offset+octet-stream
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+// @Param Tus-Resumable header string true "Protocol version, 1.0.0"
+// @Param Upload-Offset header int true "Offset of the chunk in bytes"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param uploadUUID path string true "Upload UUID"
+//
+// @Success 204 "Chunk stored, the new offset is in the Upload-Offset header and the pushed back expiry in the Upload-Expires header"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 409 "Upload-Offset doesn't match the upload"
+// @Failure 412 "Unsupported tus version"
+// @Failure 422 {object} response.UnprocessableErrorResponse "Unprocessable entity response"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/uploads/{uploadUUID} [patch]
+func (uh *UploadHandler) Append(c echo.Context) error {
+	if !uh.hasSupportedVersion(c) {
+		return c.NoContent(http.StatusPreconditionFailed)
+	}
+
+	var request uploadDto.AppendRequest
+	if err := request.BindAndValidate(c); err != nil {
+		return response.UnprocessableResponse(c, err)
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	uploadUUID, err := request.GetUUIDPathParam(c, "uploadUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	updatedUpload, createdFile, err := uh.uploadService.AppendChunk(uploadUUID, containerUUID, uploadDto.ToAppendChunkInput(&request), authUser)
+	if err != nil {
+		if errors.Is(err, upload.ErrOffsetConflict) {
+			return c.NoContent(http.StatusConflict)
+		}
+
+		return response.ErrorResponse(c, err)
+	}
+
+	c.Response().Header().Set(constants.UploadOffsetHeader, strconv.FormatInt(updatedUpload.UploadOffset, 10))
+	if createdFile != nil {
+		c.Response().Header().Set(constants.FileUUIDHeader, createdFile.Uuid.String())
+	} else {
+		c.Response().Header().Set(constants.UploadExpiresHeader, uh.formatExpiry(updatedUpload))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Delete cancels a resumable upload and discards the bytes received so far
+//
+// @Summary Cancel resumable upload
+// @Description Terminate a tus upload and remove its stored chunks
+// @Tags Files
+//
+// @Param Authorization header string true "Bearer Token"
+// @Param X-Project header string true "Project UUID"
+// @Param Tus-Resumable header string true "Protocol version, 1.0.0"
+//
+// @Param containerUUID path string true "Container UUID"
+// @Param uploadUUID path string true "Upload UUID"
+//
+// @Success 204 "Upload cancelled"
+// @Failure 400 {object} response.BadRequestErrorResponse "Bad request response"
+// @Failure 401 {object} response.UnauthorizedErrorResponse "Unauthorized response"
+// @Failure 404 {object} response.NotFoundErrorResponse "Not found response"
+// @Failure 412 "Unsupported tus version"
+// @Failure 500 {object} response.InternalServerErrorResponse "Internal server error response"
+//
+// @Router /containers/{containerUUID}/files/uploads/{uploadUUID} [delete]
+func (uh *UploadHandler) Delete(c echo.Context) error {
+	if !uh.hasSupportedVersion(c) {
+		return c.NoContent(http.StatusPreconditionFailed)
+	}
+
+	var request dto.DefaultRequestWithProjectHeader
+	if err := request.WithProjectHeader(c); err != nil {
+		return response.UnprocessableResponse(c, []string{err.Error()})
+	}
+
+	authUser, _ := auth.NewAuth(c).User()
+
+	containerUUID, err := request.GetUUIDPathParam(c, "containerUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	uploadUUID, err := request.GetUUIDPathParam(c, "uploadUUID", true)
+	if err != nil {
+		return response.BadRequestResponse(c, err.Error())
+	}
+
+	if _, err := uh.uploadService.Delete(uploadUUID, containerUUID, authUser); err != nil {
+		return response.ErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// formatExpiry gives the expiry of an upload as the HTTP date tus expects in Upload-Expires
+func (uh *UploadHandler) formatExpiry(fetchedUpload upload.Upload) string {
+	return fetchedUpload.ExpiresAt.UTC().Format(http.TimeFormat)
+}
+
+// hasSupportedVersion checks the Tus-Resumable request header and sets the response headers every tus reply carries
+func (uh *UploadHandler) hasSupportedVersion(c echo.Context) bool {
+	c.Response().Header().Set(constants.TusResumableHeader, constants.TusVersion)
+
+	if c.Request().Header.Get(constants.TusResumableHeader) != constants.TusVersion {
+		c.Response().Header().Set(constants.TusVersionHeader, constants.TusVersion)
+		return false
+	}
+
+	return true
+}
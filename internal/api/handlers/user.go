@@ -6,26 +6,31 @@ import (
 	"fluxend/internal/api/mapper"
 	"fluxend/internal/api/response"
 	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/account"
 	authDomain "fluxend/internal/domain/auth"
 	"fluxend/internal/domain/organization"
 	"fluxend/internal/domain/user"
 	"fluxend/pkg/auth"
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 	"github.com/samber/do"
 )
 
 type UserHandler struct {
 	userService         user.Service
 	organizationService organization.Service
+	accountService      account.Service
 }
 
 func NewUserHandler(injector *do.Injector) (*UserHandler, error) {
 	userService := do.MustInvoke[user.Service](injector)
 	organizationService := do.MustInvoke[organization.Service](injector)
+	accountService := do.MustInvoke[account.Service](injector)
 
 	return &UserHandler{
 		userService:         userService,
 		organizationService: organizationService,
+		accountService:      accountService,
 	}, nil
 }
 
@@ -134,7 +139,7 @@ func (uh *UserHandler) Login(c echo.Context) error {
 // Store creates a new user.
 //
 // @Summary Create user
-// @Description Add a new user with a name, email, and password
+// @Description Add a new user with a name, email, and password. When email verification is required the user stays inactive, and the returned token is refused, until the emailed link is confirmed.
 // @Tags Users
 //
 // @Accept json
@@ -170,6 +175,15 @@ func (uh *UserHandler) Store(c echo.Context) error {
 		return response.ErrorResponse(c, err)
 	}
 
+	// The account exists either way, a link that couldn't be queued can be resent
+	if err = uh.accountService.SendVerification(storedUser); err != nil {
+		log.Error().
+			Str("action", constants.ActionEmailVerification).
+			Str("user_uuid", storedUser.Uuid.String()).
+			Str("error", err.Error()).
+			Msg("failed to send verification email")
+	}
+
 	return response.CreatedResponse(c, map[string]interface{}{
 		"user":  mapper.ToRegisterUserResource(&storedUser, createdOrganization.Uuid),
 		"token": token,
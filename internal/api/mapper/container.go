@@ -7,18 +7,24 @@ import (
 
 func ToContainerResource(container *containerDomain.Container) containerDto.Response {
 	return containerDto.Response{
-		Uuid:        container.Uuid,
-		ProjectUuid: container.ProjectUuid,
-		Name:        container.Name,
-		Description: container.Description,
-		IsPublic:    container.IsPublic,
-		Url:         container.Url,
-		TotalFiles:  container.TotalFiles,
-		MaxFileSize: container.MaxFileSize,
-		CreatedBy:   container.CreatedBy,
-		UpdatedBy:   container.UpdatedBy,
-		CreatedAt:   container.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   container.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Uuid:                    container.Uuid,
+		ProjectUuid:             container.ProjectUuid,
+		Name:                    container.Name,
+		Provider:                container.Provider,
+		CredentialUuid:          container.CredentialUuid,
+		Description:             container.Description,
+		IsPublic:                container.IsPublic,
+		Url:                     container.Url,
+		TotalFiles:              container.TotalFiles,
+		MaxFileSize:             container.MaxFileSize,
+		Versioning:              container.Versioning,
+		TrashRetentionDays:      container.TrashRetentionDays,
+		Deduplication:           container.Deduplication,
+		QuarantineContainerUuid: container.QuarantineContainerUuid,
+		CreatedBy:               container.CreatedBy,
+		UpdatedBy:               container.UpdatedBy,
+		CreatedAt:               container.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:               container.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
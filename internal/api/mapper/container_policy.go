@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	accessDto "fluxend/internal/api/dto/storage/access"
+	accessDomain "fluxend/internal/domain/storage/access"
+)
+
+func ToContainerPolicyResource(policy *accessDomain.Policy) accessDto.Response {
+	return accessDto.Response{
+		Uuid:          policy.Uuid,
+		ContainerUuid: policy.ContainerUuid,
+		Name:          policy.Name,
+		Operations:    policy.Operations,
+		PathPattern:   policy.PathPattern,
+		Roles:         policy.Roles,
+		IsEnabled:     policy.IsEnabled,
+		CreatedBy:     policy.CreatedBy,
+		UpdatedBy:     policy.UpdatedBy,
+		CreatedAt:     policy.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     policy.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToContainerPolicyResourceCollection(policies []accessDomain.Policy) []accessDto.Response {
+	resourcePolicies := make([]accessDto.Response, len(policies))
+	for i, currentPolicy := range policies {
+		resourcePolicies[i] = ToContainerPolicyResource(&currentPolicy)
+	}
+
+	return resourcePolicies
+}
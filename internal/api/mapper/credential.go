@@ -0,0 +1,28 @@
+package mapper
+
+import (
+	credentialDto "fluxend/internal/api/dto/storage/credential"
+	credentialDomain "fluxend/internal/domain/storage/credential"
+)
+
+func ToCredentialResource(credential *credentialDomain.Credential) credentialDto.Response {
+	return credentialDto.Response{
+		Uuid:        credential.Uuid,
+		ProjectUuid: credential.ProjectUuid,
+		Name:        credential.Name,
+		Driver:      credential.Driver,
+		CreatedBy:   credential.CreatedBy,
+		UpdatedBy:   credential.UpdatedBy,
+		CreatedAt:   credential.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   credential.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToCredentialResourceCollection(credentials []credentialDomain.Credential) []credentialDto.Response {
+	resourceCredentials := make([]credentialDto.Response, len(credentials))
+	for i, currentCredential := range credentials {
+		resourceCredentials[i] = ToCredentialResource(&currentCredential)
+	}
+
+	return resourceCredentials
+}
@@ -0,0 +1,49 @@
+package mapper
+
+import (
+	emailDto "fluxend/internal/api/dto/email"
+	emailDomain "fluxend/internal/domain/email"
+)
+
+func ToCapturedEmailResource(captured *emailDomain.CapturedMessage) emailDto.CapturedMessageResponse {
+	return emailDto.CapturedMessageResponse{
+		Id:        captured.ID,
+		From:      captured.Message.From,
+		To:        captured.Message.To,
+		Cc:        captured.Message.Cc,
+		Bcc:       captured.Message.Bcc,
+		ReplyTo:   captured.Message.ReplyTo,
+		Subject:   captured.Message.Subject,
+		Size:      captured.Size,
+		CreatedAt: captured.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToCapturedEmailResourceCollection(captured []emailDomain.CapturedMessage) []emailDto.CapturedMessageResponse {
+	resourceMessages := make([]emailDto.CapturedMessageResponse, len(captured))
+	for i, currentMessage := range captured {
+		resourceMessages[i] = ToCapturedEmailResource(&currentMessage)
+	}
+
+	return resourceMessages
+}
+
+func ToCapturedEmailDetailsResource(captured *emailDomain.CapturedMessage) emailDto.CapturedMessageDetailsResponse {
+	attachments := make([]emailDto.AttachmentResponse, len(captured.Message.Attachments))
+	for i, attachment := range captured.Message.Attachments {
+		attachments[i] = emailDto.AttachmentResponse{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Size:        len(attachment.Content),
+		}
+	}
+
+	return emailDto.CapturedMessageDetailsResponse{
+		CapturedMessageResponse: ToCapturedEmailResource(captured),
+		MessageId:               captured.Message.MessageID,
+		Headers:                 captured.Message.Headers,
+		Message:                 captured.Message.Text,
+		HtmlMessage:             captured.Message.HTML,
+		Attachments:             attachments,
+	}
+}
@@ -0,0 +1,85 @@
+package mapper
+
+import (
+	emailDto "fluxend/internal/api/dto/email"
+	emailDomain "fluxend/internal/domain/email"
+	"github.com/google/uuid"
+)
+
+func ToEmailOutboxResource(message *emailDomain.OutboxMessage) emailDto.OutboxMessageResponse {
+	var organizationUUID *uuid.UUID
+	if message.OrganizationUuid.Valid {
+		organizationUUID = &message.OrganizationUuid.UUID
+	}
+
+	var sentAt *string
+	if message.SentAt != nil {
+		formatted := message.SentAt.Format("2006-01-02 15:04:05")
+		sentAt = &formatted
+	}
+
+	return emailDto.OutboxMessageResponse{
+		Uuid:              message.Uuid,
+		Subject:           message.Subject,
+		Recipients:        message.Recipients,
+		Template:          message.Template.Ptr(),
+		OrganizationUuid:  organizationUUID,
+		IsSensitive:       message.IsSensitive,
+		Status:            message.Status,
+		Attempts:          message.Attempts,
+		MaxAttempts:       message.MaxAttempts,
+		NextAttemptAt:     message.NextAttemptAt.Format("2006-01-02 15:04:05"),
+		Driver:            message.Driver,
+		ProviderMessageId: message.ProviderMessageId,
+		LastError:         message.LastError,
+		SentAt:            sentAt,
+		CreatedAt:         message.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:         message.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToEmailOutboxResourceCollection(messages []emailDomain.OutboxMessage) []emailDto.OutboxMessageResponse {
+	resourceMessages := make([]emailDto.OutboxMessageResponse, len(messages))
+	for i, currentMessage := range messages {
+		resourceMessages[i] = ToEmailOutboxResource(&currentMessage)
+	}
+
+	return resourceMessages
+}
+
+func ToEmailOutboxDetailsResource(details *emailDomain.OutboxDetails) emailDto.OutboxDetailsResponse {
+	attachments := make([]emailDto.AttachmentResponse, len(details.Payload.Attachments))
+	for i, attachment := range details.Payload.Attachments {
+		attachments[i] = emailDto.AttachmentResponse{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Size:        len(attachment.Content),
+		}
+	}
+
+	deliveries := make([]emailDto.DeliveryResponse, len(details.Deliveries))
+	for i, delivery := range details.Deliveries {
+		deliveries[i] = emailDto.DeliveryResponse{
+			Uuid:              delivery.Uuid,
+			Attempt:           delivery.Attempt,
+			Driver:            delivery.Driver,
+			Status:            delivery.Status,
+			ProviderMessageId: delivery.ProviderMessageId,
+			Error:             delivery.Error,
+			CreatedAt:         delivery.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return emailDto.OutboxDetailsResponse{
+		OutboxMessageResponse: ToEmailOutboxResource(&details.Message),
+		From:                  details.Payload.From,
+		To:                    details.Payload.To,
+		Cc:                    details.Payload.Cc,
+		Bcc:                   details.Payload.Bcc,
+		ReplyTo:               details.Payload.ReplyTo,
+		Message:               details.Payload.Text,
+		HtmlMessage:           details.Payload.HTML,
+		Attachments:           attachments,
+		Deliveries:            deliveries,
+	}
+}
@@ -0,0 +1,35 @@
+package mapper
+
+import (
+	emailDto "fluxend/internal/api/dto/email"
+	emailDomain "fluxend/internal/domain/email"
+	"github.com/google/uuid"
+)
+
+func ToEmailSuppressionResource(suppression *emailDomain.Suppression) emailDto.SuppressionResponse {
+	var createdBy *uuid.UUID
+	if suppression.CreatedBy.Valid {
+		createdBy = &suppression.CreatedBy.UUID
+	}
+
+	return emailDto.SuppressionResponse{
+		Uuid:              suppression.Uuid,
+		Email:             suppression.Email,
+		Reason:            suppression.Reason,
+		Driver:            suppression.Driver,
+		ProviderMessageId: suppression.ProviderMessageId,
+		Details:           suppression.Details,
+		CreatedBy:         createdBy,
+		CreatedAt:         suppression.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:         suppression.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToEmailSuppressionResourceCollection(suppressions []emailDomain.Suppression) []emailDto.SuppressionResponse {
+	resourceSuppressions := make([]emailDto.SuppressionResponse, len(suppressions))
+	for i, currentSuppression := range suppressions {
+		resourceSuppressions[i] = ToEmailSuppressionResource(&currentSuppression)
+	}
+
+	return resourceSuppressions
+}
@@ -0,0 +1,48 @@
+package mapper
+
+import (
+	emailDto "fluxend/internal/api/dto/email"
+	emailDomain "fluxend/internal/domain/email"
+)
+
+func ToEmailTemplateResource(template *emailDomain.Template) emailDto.TemplateResponse {
+	parameters := make([]emailDto.ParameterResponse, len(template.Parameters))
+	for i, parameter := range template.Parameters {
+		parameters[i] = emailDto.ParameterResponse{
+			Name:        parameter.Name,
+			Description: parameter.Description,
+			Example:     parameter.Example,
+			Required:    parameter.Required,
+		}
+	}
+
+	return emailDto.TemplateResponse{
+		Uuid:        template.Uuid,
+		Name:        template.Name,
+		Type:        template.Type,
+		Parameters:  parameters,
+		Subject:     template.Subject,
+		Message:     template.Message,
+		HtmlMessage: template.HtmlMessage,
+		Layout:      template.Layout.Ptr(),
+		CreatedAt:   template.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   template.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToEmailTemplateResourceCollection(templates []emailDomain.Template) []emailDto.TemplateResponse {
+	resourceTemplates := make([]emailDto.TemplateResponse, len(templates))
+	for i, currentTemplate := range templates {
+		resourceTemplates[i] = ToEmailTemplateResource(&currentTemplate)
+	}
+
+	return resourceTemplates
+}
+
+func ToEmailPreviewResource(rendered *emailDomain.Rendered) emailDto.PreviewResponse {
+	return emailDto.PreviewResponse{
+		Subject:     rendered.Subject,
+		Message:     rendered.Text,
+		HtmlMessage: rendered.HTML,
+	}
+}
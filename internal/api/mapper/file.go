@@ -16,6 +16,22 @@ func ToFileResource(file *fileDomain.File) fileDto.Response {
 		UpdatedBy:     file.UpdatedBy,
 		CreatedAt:     file.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:     file.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Metadata:      file.Metadata,
+		Tags:          file.Tags,
+		Checksum:      file.Checksum,
+	}
+}
+
+func ToFileListingResource(listing *fileDomain.Listing) fileDto.ListingResponse {
+	folders := listing.Folders
+	if folders == nil {
+		folders = []string{}
+	}
+
+	return fileDto.ListingResponse{
+		Prefix:  listing.Prefix,
+		Folders: folders,
+		Files:   ToFileResourceCollection(listing.Files),
 	}
 }
 
@@ -34,3 +50,46 @@ func ToFileResourceCollection(files []fileDomain.File) []fileDto.Response {
 
 	return resourceContainers
 }
+
+func ToTrashedFileResource(file *fileDomain.File) fileDto.TrashedResponse {
+	var deletedAt string
+	if file.DeletedAt != nil {
+		deletedAt = file.DeletedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return fileDto.TrashedResponse{
+		Response:  ToFileResource(file),
+		DeletedAt: deletedAt,
+		DeletedBy: file.DeletedBy,
+	}
+}
+
+func ToTrashedFileResourceCollection(files []fileDomain.File) []fileDto.TrashedResponse {
+	resourceFiles := make([]fileDto.TrashedResponse, len(files))
+	for i, currentFile := range files {
+		resourceFiles[i] = ToTrashedFileResource(&currentFile)
+	}
+
+	return resourceFiles
+}
+
+func ToFileVersionResource(version *fileDomain.Version) fileDto.VersionResponse {
+	return fileDto.VersionResponse{
+		Uuid:      version.Uuid,
+		FileUuid:  version.FileUuid,
+		Size:      version.Size,
+		MimeType:  version.MimeType,
+		CreatedBy: version.CreatedBy,
+		CreatedAt: version.CreatedAt.Format("2006-01-02 15:04:05"),
+		Checksum:  version.Checksum,
+	}
+}
+
+func ToFileVersionResourceCollection(versions []fileDomain.Version) []fileDto.VersionResponse {
+	resourceVersions := make([]fileDto.VersionResponse, len(versions))
+	for i, currentVersion := range versions {
+		resourceVersions[i] = ToFileVersionResource(&currentVersion)
+	}
+
+	return resourceVersions
+}
@@ -0,0 +1,71 @@
+package mapper
+
+import (
+	lifecycleDto "fluxend/internal/api/dto/storage/lifecycle"
+	lifecycleDomain "fluxend/internal/domain/storage/lifecycle"
+)
+
+func ToLifecycleRuleResource(rule *lifecycleDomain.Rule) lifecycleDto.Response {
+	var lastRunAt *string
+	if rule.LastRunAt != nil {
+		formatted := rule.LastRunAt.Format("2006-01-02 15:04:05")
+		lastRunAt = &formatted
+	}
+
+	return lifecycleDto.Response{
+		Uuid:                rule.Uuid,
+		ContainerUuid:       rule.ContainerUuid,
+		Name:                rule.Name,
+		Prefix:              rule.Prefix,
+		Action:              rule.Action,
+		AfterDays:           rule.AfterDays,
+		TargetContainerUuid: rule.TargetContainerUuid,
+		IsEnabled:           rule.IsEnabled,
+		LastRunAt:           lastRunAt,
+		CreatedBy:           rule.CreatedBy,
+		UpdatedBy:           rule.UpdatedBy,
+		CreatedAt:           rule.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:           rule.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToLifecycleRuleResourceCollection(rules []lifecycleDomain.Rule) []lifecycleDto.Response {
+	resourceRules := make([]lifecycleDto.Response, len(rules))
+	for i, currentRule := range rules {
+		resourceRules[i] = ToLifecycleRuleResource(&currentRule)
+	}
+
+	return resourceRules
+}
+
+func ToLifecycleReportResource(report *lifecycleDomain.Report) lifecycleDto.ReportResponse {
+	items := make([]lifecycleDto.ReportItemResponse, len(report.Items))
+	for i, item := range report.Items {
+		items[i] = lifecycleDto.ReportItemResponse{
+			FileUuid:     item.FileUuid,
+			FullFileName: item.FullFileName,
+			Size:         item.Size,
+			UpdatedAt:    item.UpdatedAt.Format("2006-01-02 15:04:05"),
+			Error:        item.Error,
+		}
+	}
+
+	return lifecycleDto.ReportResponse{
+		RuleUuid: report.RuleUuid,
+		RuleName: report.RuleName,
+		Action:   report.Action,
+		DryRun:   report.DryRun,
+		Applied:  report.Applied,
+		Failed:   report.Failed,
+		Items:    items,
+	}
+}
+
+func ToLifecycleReportResourceCollection(reports []lifecycleDomain.Report) []lifecycleDto.ReportResponse {
+	resourceReports := make([]lifecycleDto.ReportResponse, len(reports))
+	for i, currentReport := range reports {
+		resourceReports[i] = ToLifecycleReportResource(&currentReport)
+	}
+
+	return resourceReports
+}
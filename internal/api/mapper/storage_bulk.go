@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	bulkDto "fluxend/internal/api/dto/storage/bulk"
+	bulkDomain "fluxend/internal/domain/storage/bulk"
+	"fluxend/pkg/message"
+)
+
+func ToBulkJobResource(job *bulkDomain.Job, results []bulkDomain.Result) bulkDto.JobResponse {
+	var completedAt *string
+	if job.CompletedAt != nil {
+		formatted := job.CompletedAt.Format("2006-01-02 15:04:05")
+		completedAt = &formatted
+	}
+
+	resourceResults := make([]bulkDto.ResultResponse, len(results))
+	for i, result := range results {
+		resourceResults[i] = bulkDto.ResultResponse{
+			FileUuid:     result.FileUuid,
+			FullFileName: result.FullFileName,
+			Status:       result.Status,
+			Error:        message.Message(result.Error),
+			ProcessedAt:  result.ProcessedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return bulkDto.JobResponse{
+		Uuid:          job.Uuid,
+		ContainerUuid: job.ContainerUuid,
+		Status:        job.Status,
+		Error:         job.Error,
+		TotalFiles:    job.TotalFiles,
+		DeletedFiles:  job.DeletedFiles,
+		FailedFiles:   job.FailedFiles,
+		CreatedBy:     job.CreatedBy,
+		StartedAt:     job.StartedAt.Format("2006-01-02 15:04:05"),
+		CompletedAt:   completedAt,
+		Results:       resourceResults,
+	}
+}
@@ -0,0 +1,36 @@
+package mapper
+
+import (
+	migrationDto "fluxend/internal/api/dto/storage/migration"
+	migrationDomain "fluxend/internal/domain/storage/migration"
+)
+
+func ToStorageMigrationResource(migration *migrationDomain.Migration) migrationDto.Response {
+	var completedAt *string
+	if migration.CompletedAt != nil {
+		formatted := migration.CompletedAt.Format("2006-01-02 15:04:05")
+		completedAt = &formatted
+	}
+
+	return migrationDto.Response{
+		Uuid:          migration.Uuid,
+		SourceDriver:  migration.SourceDriver,
+		TargetDriver:  migration.TargetDriver,
+		Status:        migration.Status,
+		Error:         migration.Error,
+		TotalObjects:  migration.TotalObjects,
+		CopiedObjects: migration.CopiedObjects,
+		CreatedBy:     migration.CreatedBy,
+		StartedAt:     migration.StartedAt.Format("2006-01-02 15:04:05"),
+		CompletedAt:   completedAt,
+	}
+}
+
+func ToStorageMigrationResourceCollection(migrations []migrationDomain.Migration) []migrationDto.Response {
+	resourceMigrations := make([]migrationDto.Response, len(migrations))
+	for i, currentMigration := range migrations {
+		resourceMigrations[i] = ToStorageMigrationResource(&currentMigration)
+	}
+
+	return resourceMigrations
+}
@@ -0,0 +1,19 @@
+package mapper
+
+import (
+	quotaDto "fluxend/internal/api/dto/storage/quota"
+	quotaDomain "fluxend/internal/domain/storage/quota"
+	"fluxend/pkg"
+)
+
+func ToStorageUsageResource(usage *quotaDomain.Usage) quotaDto.UsageResponse {
+	return quotaDto.UsageResponse{
+		Scope:     usage.Scope,
+		OwnerUuid: usage.OwnerUuid,
+		UsedSize:  pkg.ConvertBytesToKiloBytes(int(usage.UsedBytes)),
+		UsedBytes: usage.UsedBytes,
+		UsedFiles: usage.UsedFiles,
+		MaxSize:   usage.MaxSize,
+		MaxFiles:  usage.MaxFiles,
+	}
+}
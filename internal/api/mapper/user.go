@@ -2,20 +2,28 @@ package mapper
 
 import (
 	userDto "fluxend/internal/api/dto/user"
+	"fluxend/internal/domain/account"
 	userDomain "fluxend/internal/domain/user"
 	"github.com/google/uuid"
 )
 
 func ToUserResource(user *userDomain.User) userDto.Response {
+	var emailVerifiedAt *string
+	if user.EmailVerifiedAt != nil {
+		formatted := user.EmailVerifiedAt.Format("2006-01-02 15:04:05")
+		emailVerifiedAt = &formatted
+	}
+
 	return userDto.Response{
-		Uuid:      user.Uuid,
-		Username:  user.Username,
-		Email:     user.Email,
-		Status:    user.Status,
-		RoleID:    user.RoleID,
-		Bio:       user.Bio,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Uuid:            user.Uuid,
+		Username:        user.Username,
+		Email:           user.Email,
+		Status:          user.Status,
+		RoleID:          user.RoleID,
+		Bio:             user.Bio,
+		EmailVerifiedAt: emailVerifiedAt,
+		CreatedAt:       user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       user.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
@@ -34,3 +42,15 @@ func ToUserResourceCollection(users []userDomain.User) []userDto.Response {
 
 	return resourceUsers
 }
+
+func ToSocialProviderResourceCollection(providers []account.SocialProvider) []userDto.SocialProviderResponse {
+	resourceProviders := make([]userDto.SocialProviderResponse, len(providers))
+	for i, provider := range providers {
+		resourceProviders[i] = userDto.SocialProviderResponse{
+			Name:  provider.Name,
+			Label: provider.Label,
+		}
+	}
+
+	return resourceProviders
+}
@@ -63,6 +63,16 @@ func Authentication(userRepo user.Repository) echo.MiddlewareFunc {
 				return response.UnauthorizedResponse(c, "auth.error.tokenInvalid")
 			}
 
+			// Registration returns a token before the email address is confirmed
+			pendingVerification, err := userRepo.IsPendingVerification(userUUID)
+			if err != nil {
+				return response.ErrorResponse(c, err)
+			}
+
+			if pendingVerification {
+				return response.UnauthorizedResponse(c, "user.error.emailNotVerified")
+			}
+
 			c.Set("user", auth.User{
 				Uuid:   userUUID,
 				RoleID: int(claims["role_id"].(float64)),
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"fluxend/internal/api/response"
+	"fluxend/internal/domain/auth"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"os"
+	"strings"
+)
+
+// EndUserAuthentication identifies the end users of projects the way PostgREST does, from the role claim of
+// a JWT signed with the shared secret. Requests without a token are made as the default PostgREST role, tokens
+// without a role are refused. The project a token belongs to is checked where the project is known
+func EndUserAuthentication() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				c.Set("endUser", auth.EndUser{
+					Role:      os.Getenv("POSTGREST_DEFAULT_ROLE"),
+					Claims:    jwt.MapClaims{},
+					Anonymous: true,
+				})
+
+				return next(c)
+			}
+
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+			if tokenString == authHeader {
+				return response.UnauthorizedResponse(c, "auth.error.bearerInvalid")
+			}
+
+			claims := jwt.MapClaims{}
+			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				}
+
+				return []byte(os.Getenv("JWT_SECRET")), nil
+			})
+
+			if err != nil || !token.Valid {
+				return response.UnauthorizedResponse(c, "auth.error.tokenInvalid")
+			}
+
+			role, ok := claims["role"].(string)
+			if !ok || role == "" {
+				return response.UnauthorizedResponse(c, "auth.error.roleRequired")
+			}
+
+			c.Set("endUser", auth.EndUser{
+				Role:   role,
+				Claims: claims,
+			})
+
+			return next(c)
+		}
+	}
+}
@@ -42,3 +42,9 @@ type ForbiddenErrorResponse struct {
 	Errors  []string `json:"errors" example:"Forbidden access"`
 	Content *string  `json:"content" example:"null"`
 }
+
+type TooManyRequestsErrorResponse struct {
+	Success bool     `json:"success" example:"false"`
+	Errors  []string `json:"errors" example:"Too many requests"`
+	Content *string  `json:"content" example:"null"`
+}
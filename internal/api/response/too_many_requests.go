@@ -0,0 +1,17 @@
+package response
+
+import (
+	"fluxend/pkg/message"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+func TooManyRequestsResponse(c echo.Context, error string) error {
+	response := TooManyRequestsErrorResponse{
+		Success: false,
+		Errors:  []string{message.Message(error)},
+		Content: nil,
+	}
+
+	return c.JSON(http.StatusTooManyRequests, response)
+}
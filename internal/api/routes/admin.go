@@ -9,6 +9,12 @@ import (
 func RegisterAdminRoutes(e *echo.Echo, container *do.Injector, authMiddleware echo.MiddlewareFunc) {
 	settingHandler := do.MustInvoke[*handlers.SettingHandler](container)
 	healthHandler := do.MustInvoke[*handlers.HealthHandler](container)
+	storageMigrationHandler := do.MustInvoke[*handlers.StorageMigrationHandler](container)
+	storageQuotaHandler := do.MustInvoke[*handlers.StorageQuotaHandler](container)
+	emailTemplateHandler := do.MustInvoke[*handlers.EmailTemplateHandler](container)
+	emailOutboxHandler := do.MustInvoke[*handlers.EmailOutboxHandler](container)
+	emailCaptureHandler := do.MustInvoke[*handlers.EmailCaptureHandler](container)
+	emailSuppressionHandler := do.MustInvoke[*handlers.EmailSuppressionHandler](container)
 
 	adminGroup := e.Group("admin", authMiddleware)
 
@@ -17,6 +23,40 @@ func RegisterAdminRoutes(e *echo.Echo, container *do.Injector, authMiddleware ec
 	adminGroup.PUT("/settings", settingHandler.Update)
 	adminGroup.PUT("/settings/reset", settingHandler.Reset)
 
+	// Storage migrations
+	adminGroup.GET("/storage/migrations", storageMigrationHandler.List)
+	adminGroup.POST("/storage/migrations", storageMigrationHandler.Store)
+	adminGroup.GET("/storage/migrations/:migrationUUID", storageMigrationHandler.Show)
+
+	// Storage quotas
+	adminGroup.PUT("/storage/quotas/projects/:projectUUID", storageQuotaHandler.UpdateProject)
+	adminGroup.PUT("/storage/quotas/organizations/:organizationUUID", storageQuotaHandler.UpdateOrganization)
+
+	// Email templates
+	adminGroup.GET("/email/templates", emailTemplateHandler.List)
+	adminGroup.POST("/email/templates", emailTemplateHandler.Store)
+	adminGroup.GET("/email/templates/:templateUUID", emailTemplateHandler.Show)
+	adminGroup.PUT("/email/templates/:templateUUID", emailTemplateHandler.Update)
+	adminGroup.DELETE("/email/templates/:templateUUID", emailTemplateHandler.Delete)
+	adminGroup.POST("/email/templates/:templateUUID/preview", emailTemplateHandler.Preview)
+
+	// Email outbox
+	adminGroup.GET("/email/outbox", emailOutboxHandler.List)
+	adminGroup.GET("/email/outbox/:messageUUID", emailOutboxHandler.Show)
+	adminGroup.POST("/email/outbox/:messageUUID/resend", emailOutboxHandler.Resend)
+
+	// Emails captured by the FILE mail driver
+	adminGroup.GET("/email/captured", emailCaptureHandler.List)
+	adminGroup.DELETE("/email/captured", emailCaptureHandler.Clear)
+	adminGroup.GET("/email/captured/:messageID", emailCaptureHandler.Show)
+	adminGroup.GET("/email/captured/:messageID/raw", emailCaptureHandler.Raw)
+	adminGroup.DELETE("/email/captured/:messageID", emailCaptureHandler.Delete)
+
+	// Addresses emails aren't sent to anymore
+	adminGroup.GET("/email/suppressions", emailSuppressionHandler.List)
+	adminGroup.POST("/email/suppressions", emailSuppressionHandler.Store)
+	adminGroup.DELETE("/email/suppressions/:suppressionUUID", emailSuppressionHandler.Delete)
+
 	// Health check
 	adminGroup.GET("/health", healthHandler.Pulse)
 }
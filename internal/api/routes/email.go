@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"fluxend/internal/api/handlers"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+// RegisterEmailRoutes adds the provider webhooks, they authenticate with the provider's signature
+func RegisterEmailRoutes(e *echo.Echo, container *do.Injector) {
+	emailWebhookHandler := do.MustInvoke[*handlers.EmailWebhookHandler](container)
+
+	e.POST("email/webhooks/ses", emailWebhookHandler.SES)
+	e.POST("email/webhooks/sendgrid", emailWebhookHandler.SendGrid)
+	e.POST("email/webhooks/mailgun", emailWebhookHandler.Mailgun)
+}
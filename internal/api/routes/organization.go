@@ -9,6 +9,8 @@ import (
 func RegisterOrganizationRoutes(e *echo.Echo, container *do.Injector, authMiddleware echo.MiddlewareFunc) {
 	organizationController := do.MustInvoke[*handlers.OrganizationHandler](container)
 	organizationMemberController := do.MustInvoke[*handlers.OrganizationMemberHandler](container)
+	storageQuotaController := do.MustInvoke[*handlers.StorageQuotaHandler](container)
+	emailTemplateController := do.MustInvoke[*handlers.EmailTemplateHandler](container)
 
 	organizationsGroup := e.Group("organizations", authMiddleware)
 
@@ -22,4 +24,13 @@ func RegisterOrganizationRoutes(e *echo.Echo, container *do.Injector, authMiddle
 	organizationsGroup.POST("/:organizationUUID/members", organizationMemberController.Store)
 	organizationsGroup.GET("/:organizationUUID/members", organizationMemberController.List)
 	organizationsGroup.DELETE("/:organizationUUID/members/:userID", organizationMemberController.Delete)
+
+	// storage usage
+	organizationsGroup.GET("/:organizationUUID/storage/usage", storageQuotaController.ShowOrganization)
+
+	// email templates
+	organizationsGroup.GET("/:organizationUUID/email/templates", emailTemplateController.ListForOrganization)
+	organizationsGroup.PUT("/:organizationUUID/email/templates/:templateUUID", emailTemplateController.Customize)
+	organizationsGroup.DELETE("/:organizationUUID/email/templates/:templateUUID", emailTemplateController.Reset)
+	organizationsGroup.POST("/:organizationUUID/email/templates/:templateUUID/preview", emailTemplateController.PreviewForOrganization)
 }
@@ -9,6 +9,7 @@ import (
 func RegisterProjectRoutes(e *echo.Echo, container *do.Injector, authMiddleware echo.MiddlewareFunc, allowProjectMiddleware echo.MiddlewareFunc) {
 	projectController := do.MustInvoke[*handlers.ProjectHandler](container)
 	statHandler := do.MustInvoke[*handlers.StatHandler](container)
+	storageQuotaHandler := do.MustInvoke[*handlers.StorageQuotaHandler](container)
 
 	projectsGroup := e.Group("projects", authMiddleware, allowProjectMiddleware)
 
@@ -21,6 +22,7 @@ func RegisterProjectRoutes(e *echo.Echo, container *do.Injector, authMiddleware
 	projectsGroup.GET("/:projectUUID/logs", projectController.ListLogs)
 
 	projectsGroup.GET("/:projectUUID/stats", statHandler.Retrieve)
+	projectsGroup.GET("/:projectUUID/storage/usage", storageQuotaHandler.ShowProject)
 
 	// track postgrest requests
 	e.GET("projects/:dbName/logs/capture", projectController.StoreLogs)
@@ -6,9 +6,37 @@ import (
 	"github.com/samber/do"
 )
 
-func RegisterStorageRoutes(e *echo.Echo, container *do.Injector, authMiddleware echo.MiddlewareFunc, allowStorageMiddleware echo.MiddlewareFunc) {
+func RegisterStorageRoutes(e *echo.Echo, container *do.Injector, authMiddleware, endUserAuthMiddleware, allowStorageMiddleware echo.MiddlewareFunc) {
 	containerController := do.MustInvoke[*handlers.ContainerHandler](container)
 	fileController := do.MustInvoke[*handlers.FileHandler](container)
+	storageController := do.MustInvoke[*handlers.StorageHandler](container)
+	uploadController := do.MustInvoke[*handlers.UploadHandler](container)
+	credentialController := do.MustInvoke[*handlers.CredentialHandler](container)
+	fileVersionController := do.MustInvoke[*handlers.FileVersionHandler](container)
+	trashController := do.MustInvoke[*handlers.TrashHandler](container)
+	lifecycleRuleController := do.MustInvoke[*handlers.LifecycleRuleHandler](container)
+	fileBulkController := do.MustInvoke[*handlers.FileBulkHandler](container)
+	containerPolicyController := do.MustInvoke[*handlers.ContainerPolicyHandler](container)
+	objectController := do.MustInvoke[*handlers.ObjectHandler](container)
+
+	// Presigned URLs of the filesystem driver carry their own signature instead of a bearer token
+	e.GET("storage/:containerName/*", storageController.Serve, allowStorageMiddleware)
+	e.GET("storage/transform/:fileUUID", storageController.ServeTransform, allowStorageMiddleware)
+
+	// Files reached by the end users of projects, as far as container policies allow
+	objectsGroup := e.Group("objects/:containerUUID", endUserAuthMiddleware, allowStorageMiddleware)
+
+	objectsGroup.GET("/*", objectController.Download)
+	objectsGroup.PUT("/*", objectController.Upload)
+	objectsGroup.DELETE("/*", objectController.Delete)
+
+	credentialsGroup := e.Group("credentials", authMiddleware, allowStorageMiddleware)
+
+	credentialsGroup.POST("", credentialController.Store)
+	credentialsGroup.GET("", credentialController.List)
+	credentialsGroup.GET("/:credentialUUID", credentialController.Show)
+	credentialsGroup.PUT("/:credentialUUID", credentialController.Update)
+	credentialsGroup.DELETE("/:credentialUUID", credentialController.Delete)
 
 	projectsGroup := e.Group("containers", authMiddleware, allowStorageMiddleware)
 
@@ -22,8 +50,53 @@ func RegisterStorageRoutes(e *echo.Echo, container *do.Injector, authMiddleware
 
 	filesGroup.POST("", fileController.Store)
 	filesGroup.GET("", fileController.List)
+	filesGroup.GET("/browse", fileController.Browse)
 	filesGroup.GET("/:fileUUID", fileController.Show)
 	filesGroup.PUT("/:fileUUID", fileController.Rename)
+	filesGroup.PUT("/:fileUUID/metadata", fileController.UpdateMetadata)
 	filesGroup.GET("/:fileUUID/download", fileController.Download)
+	filesGroup.GET("/:fileUUID/url", fileController.PresignedURL)
+	filesGroup.GET("/:fileUUID/transform", fileController.Transform)
+	filesGroup.GET("/:fileUUID/transform/url", fileController.TransformURL)
 	filesGroup.DELETE("/:fileUUID", fileController.Delete)
+
+	// Several files at once, selected by prefix or UUID
+	filesGroup.POST("/bulk/download", fileBulkController.Download)
+	filesGroup.POST("/bulk/delete", fileBulkController.Delete)
+	filesGroup.GET("/bulk/jobs/:jobUUID", fileBulkController.ShowJob)
+
+	// Versions of files in containers with versioning
+	filesGroup.GET("/:fileUUID/versions", fileVersionController.List)
+	filesGroup.POST("/:fileUUID/versions/:versionUUID/restore", fileVersionController.Restore)
+	filesGroup.DELETE("/:fileUUID/versions/:versionUUID", fileVersionController.Delete)
+
+	// Resumable uploads (tus protocol)
+	filesGroup.OPTIONS("/uploads", uploadController.Options)
+	filesGroup.POST("/uploads", uploadController.Store)
+	filesGroup.HEAD("/uploads/:uploadUUID", uploadController.Show)
+	filesGroup.PATCH("/uploads/:uploadUUID", uploadController.Append)
+	filesGroup.DELETE("/uploads/:uploadUUID", uploadController.Delete)
+
+	trashGroup := projectsGroup.Group("/:containerUUID/trash")
+
+	trashGroup.GET("", trashController.List)
+	trashGroup.POST("/:fileUUID/restore", trashController.Restore)
+	trashGroup.DELETE("/:fileUUID", trashController.Purge)
+
+	rulesGroup := projectsGroup.Group("/:containerUUID/rules")
+
+	rulesGroup.POST("", lifecycleRuleController.Store)
+	rulesGroup.GET("", lifecycleRuleController.List)
+	rulesGroup.GET("/report", lifecycleRuleController.Report)
+	rulesGroup.GET("/:ruleUUID", lifecycleRuleController.Show)
+	rulesGroup.PUT("/:ruleUUID", lifecycleRuleController.Update)
+	rulesGroup.DELETE("/:ruleUUID", lifecycleRuleController.Delete)
+
+	policiesGroup := projectsGroup.Group("/:containerUUID/policies")
+
+	policiesGroup.POST("", containerPolicyController.Store)
+	policiesGroup.GET("", containerPolicyController.List)
+	policiesGroup.GET("/:policyUUID", containerPolicyController.Show)
+	policiesGroup.PUT("/:policyUUID", containerPolicyController.Update)
+	policiesGroup.DELETE("/:policyUUID", containerPolicyController.Delete)
 }
@@ -2,17 +2,48 @@ package routes
 
 import (
 	"fluxend/internal/api/handlers"
+	"fluxend/internal/api/response"
+	"fluxend/internal/config/constants"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/samber/do"
 )
 
 func RegisterUserRoutes(e *echo.Echo, container *do.Injector, authMiddleware echo.MiddlewareFunc) {
 	userController := do.MustInvoke[*handlers.UserHandler](container)
+	accountController := do.MustInvoke[*handlers.AccountHandler](container)
+	socialLoginController := do.MustInvoke[*handlers.SocialLoginHandler](container)
 
 	e.POST("users/register", userController.Store)
 	e.POST("users/login", userController.Login)
+	e.POST("users/forgot-password", accountController.ForgotPassword)
+	e.POST("users/reset-password", accountController.ResetPassword)
+	e.POST("users/verify-email", accountController.VerifyEmail)
+	e.POST("users/verify-email/resend", accountController.ResendVerification)
+	e.POST("users/magic-link", accountController.RequestMagicLink, magicLinkRateLimiter())
+	e.POST("users/magic-link/login", accountController.MagicLinkLogin)
+	e.GET("users/oauth/providers", socialLoginController.Providers)
+	e.GET("users/oauth/:provider", socialLoginController.Authorize)
+	e.GET("users/oauth/:provider/callback", socialLoginController.Callback)
+	e.POST("users/oauth/login", socialLoginController.Login)
 	e.GET("users/:userUUID", authMiddleware(userController.Show))
 	e.GET("users/me", authMiddleware(userController.Me))
 	e.PUT("users/:userUUID", authMiddleware(userController.Update))
 	e.POST("users/logout", authMiddleware(userController.Logout))
 }
+
+// magicLinkRateLimiter limits link requests per IP address, the service caps the links a user receives
+func magicLinkRateLimiter() echo.MiddlewareFunc {
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:  constants.AccountMagicLinkRequestRate,
+			Burst: constants.AccountMagicLinkRequestBurst,
+		}),
+		DenyHandler: func(c echo.Context, _ string, _ error) error {
+			return response.TooManyRequestsResponse(c, "account.error.tooManyRequests")
+		},
+		ErrorHandler: func(c echo.Context, _ error) error {
+			return response.TooManyRequestsResponse(c, "account.error.tooManyRequests")
+		},
+	})
+}
@@ -25,4 +25,8 @@ func init() {
 	RootCmd.AddCommand(udbStats)
 	RootCmd.AddCommand(udbRestart)
 	RootCmd.AddCommand(optimizeCmd)
+	RootCmd.AddCommand(storageMigrate)
+	RootCmd.AddCommand(storagePurgeTrash)
+	RootCmd.AddCommand(storageRecalculateUsage)
+	RootCmd.AddCommand(storageVerify)
 }
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"context"
 	"fluxend/internal/api/middlewares"
 	"fluxend/internal/api/routes"
 	"fluxend/internal/app"
 	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
 	"fluxend/internal/domain/logging"
 	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/storage/bulk"
+	"fluxend/internal/domain/storage/lifecycle"
+	"fluxend/internal/domain/storage/upload"
 	"fluxend/internal/domain/user"
 	"fmt"
 	"github.com/getsentry/sentry-go"
@@ -30,9 +35,36 @@ var serverCmd = &cobra.Command{
 }
 
 func startServer() {
-	e := SetupServer(app.InitializeContainer())
+	container := app.InitializeContainer()
+	e := SetupServer(container)
 	validateEnvVariables()
 
+	// Lifecycle rules and trash retention are applied by the API process itself
+	lifecycleService := do.MustInvoke[lifecycle.Service](container)
+	go lifecycleService.Work(context.Background())
+
+	// Queued emails are sent by the API process too
+	outboxService := do.MustInvoke[email.OutboxService](container)
+	go outboxService.Work(context.Background())
+
+	// Expired resumable uploads are dropped with their chunks
+	uploadService := do.MustInvoke[upload.Service](container)
+	go uploadService.Work(context.Background())
+
+	// Delete jobs run inside the process that created them, the ones a previous process left running are failed
+	bulkService := do.MustInvoke[bulk.Service](container)
+	if staleJobs, err := bulkService.FailStaleJobs(); err != nil {
+		log.Error().
+			Str("action", constants.ActionBulkDelete).
+			Str("error", err.Error()).
+			Msg("failed to mark stale bulk delete jobs as failed")
+	} else if staleJobs > 0 {
+		log.Warn().
+			Str("action", constants.ActionBulkDelete).
+			Int("stale_jobs", staleJobs).
+			Msg("marked stale bulk delete jobs as failed")
+	}
+
 	e.Logger.Fatal(e.Start("0.0.0.0:8080"))
 }
 
@@ -68,7 +100,7 @@ func getCorsConfig() middleware.CORSConfig {
 			return false, nil
 		},
 		AllowMethods: []string{
-			echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS,
+			echo.GET, echo.HEAD, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS,
 		},
 		AllowHeaders: []string{
 			"Origin",
@@ -83,9 +115,20 @@ func getCorsConfig() middleware.CORSConfig {
 			"Range-Unit",
 			"range",
 			"Prefer",
+			constants.TusResumableHeader,
+			constants.UploadLengthHeader,
+			constants.UploadOffsetHeader,
+			constants.UploadMetaHeader,
 		},
 		ExposeHeaders: []string{
-			echo.HeaderContentLength, echo.HeaderContentType,
+			echo.HeaderContentLength, echo.HeaderContentType, echo.HeaderContentDisposition, echo.HeaderLocation,
+			"Accept-Ranges", "Content-Range",
+			constants.TusResumableHeader,
+			constants.TusVersionHeader,
+			constants.TusExtensionHeader,
+			constants.UploadLengthHeader,
+			constants.UploadOffsetHeader,
+			constants.FileUUIDHeader,
 		},
 		AllowCredentials: true,
 	}
@@ -100,6 +143,7 @@ func registerRoutes(e *echo.Echo, container *do.Injector) {
 	allowFormMiddleware := middlewares.AllowForm(settingService)
 	allowStorageMiddleware := middlewares.AllowStorage(settingService)
 	allowBackupMiddleware := middlewares.AllowBackup(settingService)
+	endUserAuthMiddleware := middlewares.EndUserAuthentication()
 
 	requestLogRepo := do.MustInvoke[logging.Repository](container)
 	requestLogMiddleware := middlewares.RequestLogger(requestLogRepo)
@@ -111,9 +155,10 @@ func registerRoutes(e *echo.Echo, container *do.Injector) {
 	routes.RegisterProjectRoutes(e, container, authMiddleware, allowProjectMiddleware)
 	routes.RegisterTableRoutes(e, container, authMiddleware)
 	routes.RegisterFormRoutes(e, container, authMiddleware, allowFormMiddleware)
-	routes.RegisterStorageRoutes(e, container, authMiddleware, allowStorageMiddleware)
+	routes.RegisterStorageRoutes(e, container, authMiddleware, endUserAuthMiddleware, allowStorageMiddleware)
 	routes.RegisterFunctionRoutes(e, container, authMiddleware)
 	routes.RegisterBackup(e, container, authMiddleware, allowBackupMiddleware)
+	routes.RegisterEmailRoutes(e, container)
 
 	e.GET("/", func(c echo.Context) error {
 		response := map[string]string{
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fluxend/internal/app"
+	"fluxend/internal/domain/storage/migration"
+	"fluxend/pkg"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var storageMigrate = &cobra.Command{
+	Use:   "storage.migrate [source_driver] [target_driver]",
+	Short: "Copy all containers, files and backups from one storage driver to another",
+	Long: `Copy all containers, files and backups from one storage driver to another.
+Every object is verified on the target before containers are switched over.
+Running the command again resumes an interrupted migration, objects on the source are kept.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		container := app.InitializeContainer()
+		migrationService := do.MustInvoke[migration.Service](container)
+
+		preparedMigration, err := migrationService.Prepare(&migration.CreateMigrationInput{
+			SourceDriver: strings.ToUpper(args[0]),
+			TargetDriver: strings.ToUpper(args[1]),
+		}, uuid.NullUUID{})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Running storage migration %s\n", preparedMigration.Uuid)
+
+		runErr := migrationService.Run(preparedMigration.Uuid)
+
+		// The migration records its progress and failure, show it either way
+		completedMigration, err := do.MustInvoke[migration.Repository](container).GetByUUID(preparedMigration.Uuid)
+		if err != nil {
+			return err
+		}
+
+		pkg.DumpJSON(completedMigration)
+
+		return runErr
+	},
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fluxend/internal/app"
+	"fluxend/internal/domain/storage/file"
+	"github.com/samber/do"
+	"github.com/spf13/cobra"
+)
+
+var storagePurgeTrash = &cobra.Command{
+	Use:   "storage.purge_trash",
+	Short: "Delete trashed files kept longer than the retention of their container",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		container := app.InitializeContainer()
+		fileService := do.MustInvoke[file.Service](container)
+
+		purged, err := fileService.PurgeExpiredTrash()
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Purged %d trashed files\n", purged)
+
+		return nil
+	},
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fluxend/internal/app"
+	"fluxend/internal/domain/storage/quota"
+	"github.com/samber/do"
+	"github.com/spf13/cobra"
+)
+
+var storageRecalculateUsage = &cobra.Command{
+	Use:   "storage.recalculate_usage",
+	Short: "Recount the storage usage of every project from its files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		container := app.InitializeContainer()
+		quotaService := do.MustInvoke[quota.Service](container)
+
+		recalculated, err := quotaService.RecalculateAll()
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Recalculated storage usage of %d projects\n", recalculated)
+
+		return nil
+	},
+}
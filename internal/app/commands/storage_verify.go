@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fluxend/internal/app"
+	"fluxend/internal/domain/storage/integrity"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/samber/do"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var storageVerify = &cobra.Command{
+	Use:   "storage.verify [driver]",
+	Short: "Download every stored file and report missing or corrupted objects",
+	Long: `Download every file, trashed file and version and compare it with the checksum recorded when it was stored.
+Files stored before checksums were recorded are only checked for existence. Without a driver, containers of all
+drivers are verified. The command fails when any object is missing, corrupted or couldn't be read.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		container := app.InitializeContainer()
+		integrityService := do.MustInvoke[integrity.Service](container)
+
+		driver := ""
+		if len(args) == 1 {
+			driver = strings.ToUpper(args[0])
+		}
+
+		reports, err := integrityService.Verify(driver)
+		if err != nil {
+			return err
+		}
+
+		pkg.DumpJSON(reports)
+
+		problems := 0
+		for _, report := range reports {
+			problems += len(report.Problems)
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("found %d missing, corrupted or unreadable objects", problems)
+		}
+
+		return nil
+	},
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"fluxend/internal/adapters/client"
 	"fluxend/internal/adapters/email"
+	"fluxend/internal/adapters/identity"
 	"fluxend/internal/adapters/postgrest"
 	sqlxAdapter "fluxend/internal/adapters/sqlx"
 	"fluxend/internal/adapters/storage"
@@ -10,8 +11,10 @@ import (
 	"fluxend/internal/database"
 	"fluxend/internal/database/factories"
 	"fluxend/internal/database/repositories"
+	"fluxend/internal/domain/account"
 	"fluxend/internal/domain/backup"
 	databaseDomain "fluxend/internal/domain/database"
+	emailDomain "fluxend/internal/domain/email"
 	"fluxend/internal/domain/form"
 	"fluxend/internal/domain/health"
 	"fluxend/internal/domain/logging"
@@ -21,8 +24,16 @@ import (
 	"fluxend/internal/domain/setting"
 	"fluxend/internal/domain/shared"
 	"fluxend/internal/domain/stats"
+	"fluxend/internal/domain/storage/access"
+	"fluxend/internal/domain/storage/bulk"
 	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
 	"fluxend/internal/domain/storage/file"
+	"fluxend/internal/domain/storage/integrity"
+	"fluxend/internal/domain/storage/lifecycle"
+	"fluxend/internal/domain/storage/migration"
+	"fluxend/internal/domain/storage/quota"
+	"fluxend/internal/domain/storage/upload"
 	"fluxend/internal/domain/user"
 	"github.com/jmoiron/sqlx"
 	"github.com/samber/do"
@@ -52,6 +63,14 @@ func InitializeContainer() *do.Injector {
 	do.Provide(injector, handlers.NewUserHandler)
 	do.Provide(injector, factories.NewUserFactory)
 
+	// --- Account ---
+	do.Provide(injector, repositories.NewAccountTokenRepository)
+	do.Provide(injector, repositories.NewUserIdentityRepository)
+	do.Provide(injector, account.NewAccountService)
+	do.Provide(injector, account.NewSocialService)
+	do.Provide(injector, handlers.NewAccountHandler)
+	do.Provide(injector, handlers.NewSocialLoginHandler)
+
 	// --- Setting ---
 	do.Provide(injector, repositories.NewSettingRepository)
 	do.Provide(injector, setting.NewSettingService)
@@ -86,14 +105,40 @@ func InitializeContainer() *do.Injector {
 	do.Provide(injector, handlers.NewFormResponseHandler)
 
 	// --- Storage ---
+	do.Provide(injector, repositories.NewCredentialRepository)
 	do.Provide(injector, repositories.NewContainerRepository)
 	do.Provide(injector, repositories.NewFileRepository)
-
+	do.Provide(injector, repositories.NewUploadRepository)
+	do.Provide(injector, repositories.NewStorageMigrationRepository)
+	do.Provide(injector, repositories.NewLifecycleRuleRepository)
+	do.Provide(injector, repositories.NewStorageQuotaRepository)
+	do.Provide(injector, repositories.NewStorageBulkJobRepository)
+	do.Provide(injector, repositories.NewContainerPolicyRepository)
+
+	do.Provide(injector, quota.NewStorageQuotaService)
+	do.Provide(injector, credential.NewCredentialService)
 	do.Provide(injector, container.NewContainerService)
 	do.Provide(injector, file.NewFileService)
-
+	do.Provide(injector, upload.NewUploadService)
+	do.Provide(injector, migration.NewStorageMigrationService)
+	do.Provide(injector, lifecycle.NewLifecycleService)
+	do.Provide(injector, bulk.NewBulkService)
+	do.Provide(injector, integrity.NewIntegrityService)
+	do.Provide(injector, access.NewAccessService)
+
+	do.Provide(injector, handlers.NewCredentialHandler)
 	do.Provide(injector, handlers.NewContainerHandler)
 	do.Provide(injector, handlers.NewFileHandler)
+	do.Provide(injector, handlers.NewFileVersionHandler)
+	do.Provide(injector, handlers.NewTrashHandler)
+	do.Provide(injector, handlers.NewStorageHandler)
+	do.Provide(injector, handlers.NewUploadHandler)
+	do.Provide(injector, handlers.NewStorageMigrationHandler)
+	do.Provide(injector, handlers.NewLifecycleRuleHandler)
+	do.Provide(injector, handlers.NewStorageQuotaHandler)
+	do.Provide(injector, handlers.NewFileBulkHandler)
+	do.Provide(injector, handlers.NewContainerPolicyHandler)
+	do.Provide(injector, handlers.NewObjectHandler)
 
 	// --- Backups ---
 	do.Provide(injector, repositories.NewBackupRepository)
@@ -119,12 +164,29 @@ func InitializeContainer() *do.Injector {
 	do.Provide(injector, handlers.NewIndexHandler)
 	do.Provide(injector, handlers.NewFunctionHandler)
 
+	// --- Email ---
+	do.Provide(injector, repositories.NewEmailTemplateRepository)
+	do.Provide(injector, repositories.NewEmailOutboxRepository)
+	do.Provide(injector, repositories.NewEmailSuppressionRepository)
+	do.Provide(injector, emailDomain.NewEmailTemplateService)
+	do.Provide(injector, emailDomain.NewOutboxService)
+	do.Provide(injector, emailDomain.NewCaptureService)
+	do.Provide(injector, emailDomain.NewMailer)
+	do.Provide(injector, emailDomain.NewSuppressionService)
+	do.Provide(injector, emailDomain.NewWebhookService)
+	do.Provide(injector, handlers.NewEmailTemplateHandler)
+	do.Provide(injector, handlers.NewEmailOutboxHandler)
+	do.Provide(injector, handlers.NewEmailCaptureHandler)
+	do.Provide(injector, handlers.NewEmailSuppressionHandler)
+	do.Provide(injector, handlers.NewEmailWebhookHandler)
+
 	// --- Health ---
 	do.Provide(injector, health.NewHealthService)
 	do.Provide(injector, handlers.NewHealthHandler)
 
 	do.Provide(injector, storage.NewFactory)
 	do.Provide(injector, email.NewFactory)
+	do.Provide(injector, identity.NewFactory)
 
 	return injector
 }
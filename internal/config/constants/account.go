@@ -0,0 +1,36 @@
+package constants
+
+import "time"
+
+const (
+	AccountTokenPurposePasswordReset     = "password_reset"
+	AccountTokenPurposeEmailVerification = "email_verification"
+	AccountTokenPurposeMagicLink         = "magic_link"
+	AccountTokenPurposeSocialLogin       = "social_login"
+
+	AccountTokenBytes          = 32             // random bytes of a token, only their SHA-256 is stored
+	AccountTokenResendInterval = time.Minute    // a new token isn't emailed sooner to the same user
+	AccountTokenRetention      = 24 * time.Hour // expired tokens are dropped after it
+
+	AccountPasswordResetTTL      = time.Hour
+	AccountPasswordResetTemplate = "password_reset"
+	AccountPasswordResetPath     = "/reset-password" // console page receiving the token as ?token=
+
+	AccountEmailVerificationTTL      = 24 * time.Hour
+	AccountEmailVerificationTemplate = "email_verification"
+	AccountEmailVerificationPath     = "/verify-email"
+
+	AccountMagicLinkTTL          = 15 * time.Minute
+	AccountMagicLinkTemplate     = "magic_link"
+	AccountMagicLinkPath         = "/magic-link"
+	AccountMagicLinkMaxPerHour   = 5        // links emailed to the same user, later requests are ignored
+	AccountMagicLinkRequestRate  = 1.0 / 12 // link requests per second from an IP address, after the burst
+	AccountMagicLinkRequestBurst = 5
+
+	// A social login ends on this console page with a code, exchanged right away for a JWT. The
+	// JWT itself never goes through a URL
+	AccountSocialLoginTTL         = time.Minute
+	AccountSocialLoginPath        = "/oauth/callback"
+	AccountSocialStateCookie      = "fluxend_oauth_state"
+	AccountSocialUsernameAttempts = 20
+)
@@ -4,6 +4,23 @@ const (
 	ActionAPIRequest = "api_request"
 	ActionPostgrest  = "postgrest"
 	ActionBackup     = "backup"
+	ActionUpload     = "upload"
+	ActionTrashPurge = "trash_purge"
+	ActionLifecycle  = "lifecycle"
+	ActionFileScan   = "file_scan"
+	ActionBulkDelete = "bulk_delete"
+	ActionBulkZip    = "bulk_zip"
+
+	ActionStorageMigration = "storage_migration"
+	ActionStorageVerify    = "storage_verify"
+	ActionStoragePolicy    = "storage_policy"
+
+	ActionEmailOutbox  = "email_outbox"
+	ActionEmailLog     = "email_log"
+	ActionEmailWebhook = "email_webhook"
+
+	ActionEmailVerification = "email_verification"
+	ActionSocialLogin       = "social_login"
 
 	ActionClientDatabaseCreate  = "client_database_create"
 	ActionClientDatabaseConnect = "client_database_connect"
@@ -0,0 +1,21 @@
+package constants
+
+import "time"
+
+const (
+	BulkJobStatusRunning   = "running"
+	BulkJobStatusCompleted = "completed"
+	BulkJobStatusFailed    = "failed"
+
+	BulkResultDeleted = "deleted"
+	BulkResultFailed  = "failed"
+
+	// MaxBulkFiles caps how many files a single archive or delete job can select
+	MaxBulkFiles = 10000
+
+	// MaxBulkFileUUIDs caps how many files can be listed one by one, larger selections go by prefix
+	MaxBulkFileUUIDs = 1000
+)
+
+// A running delete job that recorded no result for this long was interrupted, its process is gone
+const BulkJobStaleAfter = 10 * time.Minute
@@ -0,0 +1,57 @@
+package constants
+
+import "time"
+
+const (
+	SMTPEncryptionStartTLS = "starttls" // upgrade a plain connection, usually on port 587
+	SMTPEncryptionTLS      = "tls"      // implicit TLS from the first byte, usually on port 465
+	SMTPEncryptionNone     = "none"
+
+	SMTPAuthPlain   = "plain"
+	SMTPAuthLogin   = "login"
+	SMTPAuthCRAMMD5 = "cram-md5"
+	SMTPAuthNone    = "none"
+
+	SMTPDefaultPort = 587
+	SMTPTimeout     = 30 * time.Second // for the whole conversation of a message
+)
+
+const (
+	EmailMaxAttachmentsSize = 10 * 1024 * 1024              // in bytes, the smallest limit of the supported drivers after encoding
+	EmailLocalDriverFrom    = "Fluxend <noreply@localhost>" // sender of LOG and FILE driver messages without one
+)
+
+const (
+	EmailTemplateTypeMessage = "message"
+	EmailTemplateTypeLayout  = "layout" // wraps the content of messages, given as {{ .Content }}
+
+	EmailTemplateDefaultLayout = "default"
+)
+
+const (
+	EmailOutboxStatusPending    = "pending"
+	EmailOutboxStatusSending    = "sending" // claimed by a worker
+	EmailOutboxStatusSent       = "sent"
+	EmailOutboxStatusFailed     = "failed"     // gave up after the last attempt
+	EmailOutboxStatusSuppressed = "suppressed" // every recipient is on the suppression list
+
+	// Each attempt tries the mailDriver and then the mailFallbackDriver, failed attempts are retried
+	// after a delay doubling from the base delay up to the max delay
+	EmailOutboxMaxAttempts  = 6
+	EmailOutboxBaseDelay    = time.Minute
+	EmailOutboxMaxDelay     = time.Hour
+	EmailOutboxWorkerTick   = 10 * time.Second
+	EmailOutboxBatchSize    = 50
+	EmailOutboxClaimTimeout = 5 * time.Minute     // messages left sending that long are claimed again
+	EmailOutboxRetention    = 30 * 24 * time.Hour // sent and failed messages are purged after it
+)
+
+const (
+	EmailSuppressionReasonBounce    = "bounce" // permanent bounces only, temporary ones are retried by the provider
+	EmailSuppressionReasonComplaint = "complaint"
+	EmailSuppressionReasonManual    = "manual"
+
+	EmailWebhookMaxBodySize = 1024 * 1024      // in bytes
+	EmailWebhookMaxAge      = 15 * time.Minute // signed timestamps older than this are rejected as replays
+	EmailWebhookTimeout     = 10 * time.Second // for fetching SNS certificates and confirming subscriptions
+)
@@ -13,6 +13,8 @@ const (
 	EmailDriverSMTP         = "SMTP"
 	EmailDriverSES          = "SES"
 	EmailDriverMailgun      = "MAILGUN"
+	EmailDriverLog          = "LOG"  // writes messages to the log, for development
+	EmailDriverFile         = "FILE" // captures messages as .eml files, for development and CI
 
 	AlphanumericWithUnderscorePattern             = "^[A-Za-z0-9_]+$"
 	AlphanumericWithUnderscoreAndDashPattern      = "^[A-Za-z0-9_-]+$"
@@ -0,0 +1,15 @@
+package constants
+
+import "time"
+
+const (
+	IdentityProviderGitHub = "github"
+	IdentityProviderGoogle = "google"
+	IdentityProviderOIDC   = "oidc" // any OpenID Connect provider found through its discovery document
+
+	IdentityHTTPTimeout         = 10 * time.Second
+	IdentityDiscoveryTTL        = time.Hour // discovery documents and signing keys are fetched again after it
+	IdentityClockSkew           = time.Minute
+	IdentityKeysRefreshInterval = time.Minute      // an unknown signing key fetches the keys again once they're older
+	IdentityStateTTL            = 10 * time.Minute // to sign in with the provider and come back
+)
@@ -0,0 +1,13 @@
+package constants
+
+import "time"
+
+const (
+	LifecycleActionDelete = "delete"
+	LifecycleActionMove   = "move"
+
+	// The worker wakes up every tick and runs the enabled rules last run more than an interval ago
+	LifecycleWorkerTick    = 5 * time.Minute
+	LifecycleRuleInterval  = time.Hour
+	LifecycleRuleBatchSize = 500 // files handled by a rule per run
+)
@@ -21,4 +21,26 @@ const (
 	MaxContainerDescriptionLength = 255
 	MinFileNameLength             = 3
 	MaxFileNameLength             = 63
+	MaxImageTransformDimension    = 4096
+	MinCredentialNameLength       = 3
+	MaxCredentialNameLength       = 63
+	MaxCredentialEndpointLength   = 255
+	MaxTrashRetentionDays         = 365
+	MinLifecycleRuleNameLength    = 3
+	MaxLifecycleRuleNameLength    = 63
+	MaxLifecycleRuleAfterDays     = 3650
+	MaxFileMetadataKeys           = 32
+	MaxFileMetadataKeyLength      = 128
+	MaxFileMetadataValueLength    = 1024
+	MaxFileTags                   = 32
+	MaxFileTagLength              = 64
+	MinPolicyNameLength           = 3
+	MaxPolicyNameLength           = 63
+	MaxPolicyRoles                = 16
+	MaxPolicyRoleLength           = 63 // longest role name Postgres accepts
+	MinEmailTemplateNameLength    = 3
+	MaxEmailTemplateNameLength    = 63
+	MaxEmailTemplateParameters    = 32
+	MaxEmailTemplateSubjectLength = 255
+	MaxEmailTemplateMessageLength = 65536
 )
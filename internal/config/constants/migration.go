@@ -0,0 +1,10 @@
+package constants
+
+const (
+	StorageMigrationStatusRunning   = "running"
+	StorageMigrationStatusCompleted = "completed"
+	StorageMigrationStatusFailed    = "failed"
+)
+
+// A container is copied again while objects keep being written to it, up to this many passes
+const StorageMigrationMaxPasses = 5
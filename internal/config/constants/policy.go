@@ -0,0 +1,7 @@
+package constants
+
+const (
+	PolicyOperationRead   = "read"
+	PolicyOperationUpload = "upload"
+	PolicyOperationDelete = "delete"
+)
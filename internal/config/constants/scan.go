@@ -0,0 +1,15 @@
+package constants
+
+import "time"
+
+// Uploads are checked by the scanners listed in the storageScanners setting, in that order
+const (
+	ScannerClamAV = "clamav"
+
+	ClamAVDefaultSocket = "/var/run/clamav/clamd.ctl"
+	ClamAVTimeout       = 2 * time.Minute
+	ClamAVChunkSize     = 64 * 1024
+
+	// MimeSniffLength is how much of the content is read to detect its MIME type
+	MimeSniffLength = 512
+)
@@ -0,0 +1,30 @@
+package constants
+
+import "time"
+
+// Resumable uploads follow the tus protocol, see https://tus.io/protocols/resumable-upload
+const (
+	TusVersion     = "1.0.0"
+	TusExtensions  = "creation,termination,expiration"
+	TusContentType = "application/offset+octet-stream"
+
+	TusResumableHeader = "Tus-Resumable"
+	TusVersionHeader   = "Tus-Version"
+	TusExtensionHeader = "Tus-Extension"
+	TusMaxSizeHeader   = "Tus-Max-Size"
+	UploadLengthHeader = "Upload-Length"
+	UploadOffsetHeader = "Upload-Offset"
+	UploadMetaHeader   = "Upload-Metadata"
+
+	// UploadExpiresHeader tells the client until when an unfinished upload can be resumed
+	UploadExpiresHeader = "Upload-Expires"
+
+	// An upload not resumed within the expiration is dropped along with its chunks, until then
+	// its declared length counts towards the storage quota
+	UploadExpiration     = 24 * time.Hour
+	UploadWorkerTick     = 5 * time.Minute
+	UploadPurgeBatchSize = 100
+
+	// FileUUIDHeader tells the client which file the final chunk of an upload created
+	FileUUIDHeader = "X-File-UUID"
+)
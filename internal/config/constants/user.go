@@ -11,4 +11,5 @@ const (
 
 	UserStatusActive   = "active"
 	UserStatusInactive = "inactive"
+	UserStatusPending  = "pending" // registered while email verification was required, until the address is confirmed
 )
@@ -34,6 +34,7 @@ func (f *UserFactory) Create(opts ...UserOption) (*user.User, error) {
 		Username:  pkg.Faker.Internet().User(),
 		Email:     pkg.Faker.Internet().Email(),
 		Password:  defaultPassword,
+		Status:    constants.UserStatusActive,
 		RoleID:    constants.UserRoleAdmin,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.uploads (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    container_uuid UUID NOT NULL REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    full_file_name TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    upload_length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (upload_offset >= 0 AND upload_offset <= upload_length)
+);
+
+CREATE TABLE storage.upload_chunks (
+    upload_uuid UUID NOT NULL REFERENCES storage.uploads(uuid) ON DELETE CASCADE,
+    chunk_offset BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    object_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (upload_uuid, chunk_offset)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.upload_chunks;
+DROP TABLE storage.uploads;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.file_variants (
+    file_uuid UUID NOT NULL REFERENCES storage.files(uuid) ON DELETE CASCADE,
+    variant_key TEXT NOT NULL,
+    object_key TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    mime_type TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (file_uuid, variant_key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.file_variants;
+-- +goose StatementEnd
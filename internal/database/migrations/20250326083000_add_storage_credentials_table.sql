@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.credentials (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_uuid UUID NOT NULL REFERENCES fluxend.projects(uuid) ON DELETE CASCADE,
+    name varchar NOT NULL,
+    driver varchar NOT NULL,
+    secrets BYTEA NOT NULL,
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    updated_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    UNIQUE (project_uuid, name)
+);
+
+-- Containers without a credential keep using the credentials from the environment
+ALTER TABLE storage.containers
+    ADD COLUMN credential_uuid UUID REFERENCES storage.credentials(uuid) ON DELETE RESTRICT;
+
+-- Backups remember the driver they were written with, so changing STORAGE_DRIVER doesn't orphan them
+ALTER TABLE storage.backups
+    ADD COLUMN storage_driver varchar NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE storage.backups DROP COLUMN storage_driver;
+ALTER TABLE storage.containers DROP COLUMN credential_uuid;
+DROP TABLE storage.credentials;
+-- +goose StatementEnd
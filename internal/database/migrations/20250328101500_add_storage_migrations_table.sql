@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.migrations (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_driver varchar NOT NULL,
+    target_driver varchar NOT NULL,
+    status varchar NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    total_objects INT NOT NULL DEFAULT 0,
+    copied_objects INT NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP
+);
+
+-- Objects copied and verified on the target, an interrupted migration resumes after them
+CREATE TABLE storage.migration_items (
+    migration_uuid UUID NOT NULL REFERENCES storage.migrations(uuid) ON DELETE CASCADE,
+    container_name varchar NOT NULL,
+    object_key TEXT NOT NULL,
+    checksum varchar NOT NULL,
+    copied_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (migration_uuid, container_name, object_key)
+);
+
+CREATE TABLE storage.migration_containers (
+    migration_uuid UUID NOT NULL REFERENCES storage.migrations(uuid) ON DELETE CASCADE,
+    container_name varchar NOT NULL,
+    target_url TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (migration_uuid, container_name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.migration_containers;
+DROP TABLE storage.migration_items;
+DROP TABLE storage.migrations;
+-- +goose StatementEnd
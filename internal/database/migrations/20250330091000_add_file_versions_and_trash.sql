@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE storage.containers
+    ADD COLUMN versioning BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN trash_retention_days INT NOT NULL DEFAULT 0;
+
+ALTER TABLE storage.files
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL;
+
+-- Trashed files keep their name, so it only has to be unique among live files
+ALTER TABLE storage.files DROP CONSTRAINT files_container_uuid_full_file_name_key;
+CREATE UNIQUE INDEX files_container_uuid_full_file_name_key
+    ON storage.files (container_uuid, full_file_name) WHERE deleted_at IS NULL;
+CREATE INDEX files_deleted_at_idx ON storage.files (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE storage.file_versions (
+    uuid UUID PRIMARY KEY,
+    file_uuid UUID NOT NULL REFERENCES storage.files(uuid) ON DELETE CASCADE,
+    object_key TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    mime_type TEXT NOT NULL,
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX file_versions_file_uuid_idx ON storage.file_versions (file_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.file_versions;
+
+DELETE FROM storage.files WHERE deleted_at IS NOT NULL;
+DROP INDEX storage.files_deleted_at_idx;
+DROP INDEX storage.files_container_uuid_full_file_name_key;
+ALTER TABLE storage.files ADD CONSTRAINT files_container_uuid_full_file_name_key UNIQUE (container_uuid, full_file_name);
+
+ALTER TABLE storage.files
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE storage.containers
+    DROP COLUMN trash_retention_days,
+    DROP COLUMN versioning;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.lifecycle_rules (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    container_uuid UUID NOT NULL REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    name varchar NOT NULL,
+    prefix TEXT NOT NULL DEFAULT '',
+    action varchar NOT NULL,
+    after_days INT NOT NULL,
+    target_container_uuid UUID REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    is_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_run_at TIMESTAMP,
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    updated_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (container_uuid, name)
+);
+
+-- Rules are matched against the age of files under a prefix
+CREATE INDEX files_container_uuid_updated_at_idx ON storage.files (container_uuid, updated_at) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX storage.files_container_uuid_updated_at_idx;
+DROP TABLE storage.lifecycle_rules;
+-- +goose StatementEnd
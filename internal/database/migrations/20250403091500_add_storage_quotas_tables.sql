@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Limits of 0 fall back to the instance wide settings, used_* counters are kept current by the file service
+CREATE TABLE storage.project_quotas (
+    project_uuid UUID PRIMARY KEY REFERENCES fluxend.projects(uuid) ON DELETE CASCADE,
+    max_size BIGINT NOT NULL DEFAULT 0,
+    max_files BIGINT NOT NULL DEFAULT 0,
+    used_size BIGINT NOT NULL DEFAULT 0,
+    used_files BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Usage of an organization is the sum of its projects, so only its limits are stored
+CREATE TABLE storage.organization_quotas (
+    organization_uuid UUID PRIMARY KEY REFERENCES fluxend.organizations(uuid) ON DELETE CASCADE,
+    max_size BIGINT NOT NULL DEFAULT 0,
+    max_files BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO storage.project_quotas (project_uuid, used_size, used_files)
+SELECT
+    c.project_uuid,
+    COALESCE(SUM(f.size), 0) + COALESCE(SUM(v.size), 0),
+    COUNT(f.uuid)
+FROM storage.containers c
+JOIN storage.files f ON f.container_uuid = c.uuid
+LEFT JOIN (
+    SELECT file_uuid, SUM(size) AS size FROM storage.file_versions GROUP BY file_uuid
+) v ON v.file_uuid = f.uuid
+GROUP BY c.project_uuid;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.organization_quotas;
+DROP TABLE storage.project_quotas;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Uploads flagged by a scanner are moved to the quarantine container instead of being rejected
+ALTER TABLE storage.containers
+    ADD COLUMN quarantine_container_uuid UUID REFERENCES storage.containers(uuid) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE storage.containers DROP COLUMN quarantine_container_uuid;
+-- +goose StatementEnd
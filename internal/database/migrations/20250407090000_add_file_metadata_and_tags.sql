@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE storage.files
+    ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Prefix listings compare names byte-wise, which the default collation can't serve from an index
+CREATE INDEX files_container_uuid_full_file_name_prefix_idx
+    ON storage.files (container_uuid, full_file_name text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX files_tags_idx ON storage.files USING GIN (tags);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX storage.files_tags_idx;
+DROP INDEX storage.files_container_uuid_full_file_name_prefix_idx;
+
+ALTER TABLE storage.files
+    DROP COLUMN tags,
+    DROP COLUMN metadata;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage.bulk_jobs (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    container_uuid UUID NOT NULL REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    status varchar NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    total_files INT NOT NULL DEFAULT 0,
+    deleted_files INT NOT NULL DEFAULT 0,
+    failed_files INT NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX bulk_jobs_container_uuid_idx ON storage.bulk_jobs (container_uuid);
+
+-- One row per selected file, the file itself is gone once deleted so its name is kept here
+CREATE TABLE storage.bulk_job_results (
+    job_uuid UUID NOT NULL REFERENCES storage.bulk_jobs(uuid) ON DELETE CASCADE,
+    file_uuid UUID NOT NULL,
+    full_file_name TEXT NOT NULL,
+    status varchar NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    processed_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (job_uuid, file_uuid)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.bulk_job_results;
+DROP TABLE storage.bulk_jobs;
+-- +goose StatementEnd
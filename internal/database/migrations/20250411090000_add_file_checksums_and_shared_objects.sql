@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- SHA-256 of the content as hex, files stored before checksums were recorded have none
+ALTER TABLE storage.files
+    ADD COLUMN checksum VARCHAR(64),
+    ADD COLUMN object_key TEXT;
+
+ALTER TABLE storage.file_versions ADD COLUMN checksum VARCHAR(64);
+
+ALTER TABLE storage.containers ADD COLUMN deduplication BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Content stored once for every file of a deduplicated container with the same checksum,
+-- the object is deleted when the last file referencing it is purged
+CREATE TABLE storage.objects (
+    container_uuid UUID NOT NULL REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    checksum VARCHAR(64) NOT NULL,
+    object_key TEXT NOT NULL,
+    size INT NOT NULL,
+    reference_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (container_uuid, checksum)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.objects;
+
+ALTER TABLE storage.containers DROP COLUMN deduplication;
+
+ALTER TABLE storage.file_versions DROP COLUMN checksum;
+
+ALTER TABLE storage.files
+    DROP COLUMN object_key,
+    DROP COLUMN checksum;
+-- +goose StatementEnd
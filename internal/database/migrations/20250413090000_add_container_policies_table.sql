@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Policies grant project end users, identified by the role and claims of their JWT, access to the files
+-- of a container whose name matches path_pattern. An empty roles list applies to every role
+CREATE TABLE storage.container_policies (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    container_uuid UUID NOT NULL REFERENCES storage.containers(uuid) ON DELETE CASCADE,
+    name varchar NOT NULL,
+    operations TEXT[] NOT NULL,
+    path_pattern TEXT NOT NULL,
+    roles TEXT[] NOT NULL DEFAULT '{}',
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    updated_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (container_uuid, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE storage.container_policies;
+-- +goose StatementEnd
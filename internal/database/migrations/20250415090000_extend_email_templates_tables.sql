@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE fluxend.email_templates
+    ADD COLUMN uuid UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN subject TEXT NOT NULL DEFAULT '',
+    ADD COLUMN html_message TEXT NOT NULL DEFAULT '',
+    ADD COLUMN layout VARCHAR(255),
+    /* templates shipped with migrations have no author */
+    ADD COLUMN created_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL,
+    ADD COLUMN updated_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL,
+    ADD CONSTRAINT email_templates_uuid_key UNIQUE (uuid),
+    ADD CONSTRAINT email_templates_name_key UNIQUE (name);
+
+/* overrides belong to organizations rather than single users */
+DROP TABLE fluxend.user_email_templates;
+
+CREATE TABLE fluxend.organization_email_templates (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id INT NOT NULL REFERENCES fluxend.email_templates(id) ON DELETE CASCADE,
+    organization_uuid UUID NOT NULL REFERENCES fluxend.organizations(uuid) ON DELETE CASCADE,
+    /* empty parts keep the content of the template */
+    subject TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    html_message TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    updated_by UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (template_id, organization_uuid)
+);
+
+INSERT INTO fluxend.email_templates (name, type, parameters, subject, message, html_message) VALUES
+/* wraps every message unless a template picks another layout */
+  (
+    'default',
+    'layout',
+    '[{"name":"appName","description":"Name shown in the footer","example":"Fluxend"}]',
+    '',
+    E'{{ .Content }}\n\n--\n{{ .appName }}',
+    E'<!DOCTYPE html>\n<html>\n<body style="font-family: sans-serif; color: #1f2937;">\n{{ .Content }}\n<p style="color: #6b7280; font-size: 12px;">{{ .appName }}</p>\n</body>\n</html>'
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE fluxend.organization_email_templates;
+
+CREATE TABLE fluxend.user_email_templates (
+    id SERIAL PRIMARY KEY,
+    template_id INT NOT NULL REFERENCES fluxend.email_templates(id),
+    user_uuid UUID NOT NULL REFERENCES authentication.users(uuid),
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DELETE FROM fluxend.email_templates WHERE name = 'default' AND type = 'layout';
+
+ALTER TABLE fluxend.email_templates
+    DROP CONSTRAINT email_templates_name_key,
+    DROP CONSTRAINT email_templates_uuid_key,
+    DROP COLUMN updated_by,
+    DROP COLUMN created_by,
+    DROP COLUMN layout,
+    DROP COLUMN html_message,
+    DROP COLUMN subject,
+    DROP COLUMN uuid;
+-- +goose StatementEnd
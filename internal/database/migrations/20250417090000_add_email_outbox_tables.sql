@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE fluxend.email_outbox (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payload JSONB NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    recipients TEXT NOT NULL DEFAULT '',
+    template varchar,
+    organization_uuid UUID REFERENCES fluxend.organizations(uuid) ON DELETE SET NULL,
+    status varchar NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    driver varchar NOT NULL DEFAULT '',
+    provider_message_id TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX email_outbox_due_idx ON fluxend.email_outbox (next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX email_outbox_created_at_idx ON fluxend.email_outbox (created_at);
+
+-- One row per driver tried, a message failing over to the secondary driver gets two rows for the attempt
+CREATE TABLE fluxend.email_deliveries (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    outbox_uuid UUID NOT NULL REFERENCES fluxend.email_outbox(uuid) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    driver varchar NOT NULL,
+    status varchar NOT NULL,
+    provider_message_id TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX email_deliveries_outbox_uuid_idx ON fluxend.email_deliveries (outbox_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE fluxend.email_deliveries;
+DROP TABLE fluxend.email_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Addresses nothing is sent to anymore, the outbox drops them from the recipients of every message
+CREATE TABLE fluxend.email_suppressions (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email varchar(255) NOT NULL UNIQUE, -- bare and lowercased
+    reason varchar NOT NULL,
+    driver varchar NOT NULL DEFAULT '', -- the one reporting the bounce or complaint
+    provider_message_id TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES authentication.users(uuid) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE fluxend.email_suppressions;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Single-use tokens emailed to users, only their SHA-256 is kept so a leaked table can't be replayed
+CREATE TABLE authentication.user_tokens (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    purpose varchar(50) NOT NULL,
+    token_hash varchar(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON authentication.user_tokens (user_uuid, purpose);
+
+INSERT INTO fluxend.email_templates (name, type, parameters, subject, message, html_message, layout) VALUES
+  (
+    'password_reset',
+    'message',
+    '[{"name":"username","example":"jane","required":true},{"name":"resetUrl","example":"https://console.fluxend.app/reset-password?token=abc","required":true},{"name":"expiresInMinutes","example":"60","required":true},{"name":"appName","description":"Name of the application, also used by the layout","example":"Fluxend"}]',
+    'Reset your password',
+    E'Hi {{ .username }},\n\nSomeone asked to reset the password of your account. Open the link below to choose a new one:\n\n{{ .resetUrl }}\n\nThe link expires in {{ .expiresInMinutes }} minutes and works once. If you didn''t ask for it, ignore this email, your password stays the same.',
+    E'<p>Hi {{ .username }},</p>\n<p>Someone asked to reset the password of your account. Use the button below to choose a new one:</p>\n<p><a href="{{ .resetUrl }}" style="background: #2563eb; color: #ffffff; padding: 10px 16px; border-radius: 6px; text-decoration: none;">Reset password</a></p>\n<p>The link expires in {{ .expiresInMinutes }} minutes and works once. If you didn''t ask for it, ignore this email, your password stays the same.</p>',
+    'default'
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM fluxend.email_templates WHERE name = 'password_reset';
+
+DROP TABLE authentication.user_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+/* users registered while requireEmailVerification is on stay inactive until it's set */
+ALTER TABLE authentication.users ADD COLUMN email_verified_at TIMESTAMP;
+
+INSERT INTO fluxend.email_templates (name, type, parameters, subject, message, html_message, layout) VALUES
+  (
+    'email_verification',
+    'message',
+    '[{"name":"username","example":"jane","required":true},{"name":"verifyUrl","example":"https://console.fluxend.app/verify-email?token=abc","required":true},{"name":"expiresInHours","example":"24","required":true},{"name":"appName","description":"Name of the application, also used by the layout","example":"Fluxend"}]',
+    'Confirm your email address',
+    E'Hi {{ .username }},\n\nThanks for signing up to {{ .appName }}. Open the link below to confirm your email address and activate your account:\n\n{{ .verifyUrl }}\n\nThe link expires in {{ .expiresInHours }} hours. If you didn''t sign up, ignore this email.',
+    E'<p>Hi {{ .username }},</p>\n<p>Thanks for signing up to {{ .appName }}. Use the button below to confirm your email address and activate your account:</p>\n<p><a href="{{ .verifyUrl }}" style="background: #2563eb; color: #ffffff; padding: 10px 16px; border-radius: 6px; text-decoration: none;">Confirm email</a></p>\n<p>The link expires in {{ .expiresInHours }} hours. If you didn''t sign up, ignore this email.</p>',
+    'default'
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM fluxend.email_templates WHERE name = 'email_verification';
+
+ALTER TABLE authentication.users DROP COLUMN email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO fluxend.email_templates (name, type, parameters, subject, message, html_message, layout) VALUES
+  (
+    'magic_link',
+    'message',
+    '[{"name":"username","example":"jane","required":true},{"name":"loginUrl","example":"https://console.fluxend.app/magic-link?token=abc","required":true},{"name":"expiresInMinutes","example":"15","required":true},{"name":"appName","description":"Name of the application, also used by the layout","example":"Fluxend"}]',
+    'Your sign-in link',
+    E'Hi {{ .username }},\n\nOpen the link below to sign in to {{ .appName }}:\n\n{{ .loginUrl }}\n\nThe link can be used once and expires in {{ .expiresInMinutes }} minutes. If you didn''t ask for it, ignore this email.',
+    E'<p>Hi {{ .username }},</p>\n<p>Use the button below to sign in to {{ .appName }}:</p>\n<p><a href="{{ .loginUrl }}" style="background: #2563eb; color: #ffffff; padding: 10px 16px; border-radius: 6px; text-decoration: none;">Sign in</a></p>\n<p>The link can be used once and expires in {{ .expiresInMinutes }} minutes. If you didn''t ask for it, ignore this email.</p>',
+    'default'
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM fluxend.email_templates WHERE name = 'magic_link';
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- External identities users sign in with, subject is the ID the provider gave the user and never changes
+CREATE TABLE authentication.user_identities (
+    uuid UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_uuid UUID NOT NULL REFERENCES authentication.users(uuid) ON DELETE CASCADE,
+    provider varchar(50) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255) NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_uuid ON authentication.user_identities (user_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE authentication.user_identities;
+-- +goose StatementEnd
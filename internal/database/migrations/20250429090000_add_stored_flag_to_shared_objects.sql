@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A shared object is only reused once its content was uploaded, objects recorded so far are stored
+ALTER TABLE storage.objects ADD COLUMN is_stored BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE storage.objects SET is_stored = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE storage.objects DROP COLUMN is_stored;
+-- +goose StatementEnd
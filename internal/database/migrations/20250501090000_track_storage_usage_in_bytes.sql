@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Usage is counted in bytes, sizes in KB round small files down to nothing. Content stored
+-- so far is only known to the KB
+ALTER TABLE storage.files ADD COLUMN size_in_bytes BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE storage.file_versions ADD COLUMN size_in_bytes BIGINT NOT NULL DEFAULT 0;
+
+UPDATE storage.files SET size_in_bytes = size::BIGINT * 1024;
+UPDATE storage.file_versions SET size_in_bytes = size::BIGINT * 1024;
+
+ALTER TABLE storage.project_quotas RENAME COLUMN used_size TO used_bytes;
+UPDATE storage.project_quotas SET used_bytes = used_bytes * 1024;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE storage.project_quotas SET used_bytes = used_bytes / 1024;
+ALTER TABLE storage.project_quotas RENAME COLUMN used_bytes TO used_size;
+
+ALTER TABLE storage.file_versions DROP COLUMN size_in_bytes;
+ALTER TABLE storage.files DROP COLUMN size_in_bytes;
+-- +goose StatementEnd
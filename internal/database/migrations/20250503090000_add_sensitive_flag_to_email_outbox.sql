@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Messages carrying single-use links lose their bodies once finished and are never shown in full
+ALTER TABLE fluxend.email_outbox ADD COLUMN is_sensitive BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE fluxend.email_outbox
+SET is_sensitive = TRUE
+WHERE template IN ('password_reset', 'email_verification', 'magic_link');
+
+UPDATE fluxend.email_outbox
+SET payload = payload - 'Text' - 'HTML'
+WHERE is_sensitive AND status IN ('sent', 'failed', 'suppressed');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE fluxend.email_outbox DROP COLUMN is_sensitive;
+-- +goose StatementEnd
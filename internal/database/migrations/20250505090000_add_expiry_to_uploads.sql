@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Unfinished uploads expire when they aren't resumed, until then their declared length counts as used
+ALTER TABLE storage.uploads ADD COLUMN expires_at TIMESTAMP;
+
+UPDATE storage.uploads SET expires_at = updated_at + INTERVAL '24 hours';
+
+ALTER TABLE storage.uploads ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX uploads_expires_at_idx ON storage.uploads (expires_at);
+
+INSERT INTO storage.project_quotas (project_uuid, used_bytes, used_files)
+SELECT c.project_uuid, SUM(u.upload_length), 0
+FROM storage.uploads u
+JOIN storage.containers c ON c.uuid = u.container_uuid
+GROUP BY c.project_uuid
+ON CONFLICT (project_uuid) DO UPDATE SET
+    used_bytes = project_quotas.used_bytes + EXCLUDED.used_bytes,
+    updated_at = NOW();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE storage.project_quotas q
+SET used_bytes = GREATEST(q.used_bytes - reserved.bytes, 0)
+FROM (
+    SELECT c.project_uuid, SUM(u.upload_length) AS bytes
+    FROM storage.uploads u
+    JOIN storage.containers c ON c.uuid = u.container_uuid
+    GROUP BY c.project_uuid
+) reserved
+WHERE q.project_uuid = reserved.project_uuid;
+
+ALTER TABLE storage.uploads DROP COLUMN expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+/* users waiting to confirm their address get their own status, an inactive user stays inactive when verifying */
+ALTER TABLE authentication.users DROP CONSTRAINT users_status_check;
+ALTER TABLE authentication.users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'inactive', 'pending'));
+
+/* only registrations sent a verification link are pending, other inactive users were made so by an admin */
+UPDATE authentication.users SET status = 'pending'
+WHERE status = 'inactive'
+  AND email_verified_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM authentication.user_tokens t
+    WHERE t.user_uuid = users.uuid AND t.purpose = 'email_verification' AND t.used_at IS NULL
+  );
+
+/* the other accounts never had to confirm their address, they count as confirmed when created */
+UPDATE authentication.users SET email_verified_at = created_at
+WHERE email_verified_at IS NULL AND status <> 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE authentication.users SET status = 'inactive' WHERE status = 'pending';
+
+ALTER TABLE authentication.users DROP CONSTRAINT users_status_check;
+ALTER TABLE authentication.users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'inactive'));
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Where the last run of a rule stopped, so files that keep failing don't hold back the ones after them
+ALTER TABLE storage.lifecycle_rules ADD COLUMN cursor_updated_at TIMESTAMP;
+ALTER TABLE storage.lifecycle_rules ADD COLUMN cursor_file_uuid UUID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE storage.lifecycle_rules DROP COLUMN cursor_file_uuid;
+ALTER TABLE storage.lifecycle_rules DROP COLUMN cursor_updated_at;
+-- +goose StatementEnd
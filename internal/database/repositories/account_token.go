@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"database/sql"
+	stdErrors "errors"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/account"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type AccountTokenRepository struct {
+	db shared.DB
+}
+
+func NewAccountTokenRepository(injector *do.Injector) (account.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &AccountTokenRepository{db: db}, nil
+}
+
+// CreateToken stores a token and expires the unused ones of the same purpose, so only the latest link works.
+// Expired tokens are kept for a while to count the recent ones, then dropped
+func (r *AccountTokenRepository) CreateToken(token *account.Token) (*account.Token, error) {
+	return token, r.db.WithTransaction(func(tx shared.Tx) error {
+		_, err := tx.Exec(
+			"UPDATE authentication.user_tokens SET expires_at = NOW() WHERE user_uuid = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()",
+			token.UserUuid,
+			token.Purpose,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			"DELETE FROM authentication.user_tokens WHERE user_uuid = $1 AND expires_at < $2",
+			token.UserUuid,
+			time.Now().Add(-constants.AccountTokenRetention),
+		)
+		if err != nil {
+			return err
+		}
+
+		query := `
+		INSERT INTO authentication.user_tokens (
+			user_uuid, purpose, token_hash, expires_at
+		) VALUES (
+			$1, $2, $3, $4
+		)
+		RETURNING uuid, created_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			token.UserUuid,
+			token.Purpose,
+			token.TokenHash,
+			token.ExpiresAt,
+		).Scan(&token.Uuid, &token.CreatedAt)
+	})
+}
+
+func (r *AccountTokenRepository) CountTokensSince(userUUID uuid.UUID, purpose string, since time.Time) (int, error) {
+	query := "SELECT COUNT(*) FROM authentication.user_tokens WHERE user_uuid = $1 AND purpose = $2 AND created_at > $3"
+
+	var count int
+	return count, r.db.Get(&count, query, userUUID, purpose, since)
+}
+
+// ConsumeToken marks an unused, unexpired token as used and returns its user. Doing both in one statement
+// keeps a token from being used twice by concurrent requests
+func (r *AccountTokenRepository) ConsumeToken(purpose, tokenHash string) (uuid.UUID, error) {
+	query := `
+		UPDATE authentication.user_tokens SET used_at = NOW()
+		WHERE purpose = $1 AND token_hash = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_uuid
+	`
+
+	var userUUID uuid.UUID
+	err := r.db.QueryRow(query, purpose, tokenHash).Scan(&userUUID)
+	if stdErrors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, errors.NewBadRequestError("account.error.invalidToken")
+	}
+
+	return userUUID, err
+}
@@ -43,6 +43,20 @@ func (r *BackupRepository) GetByUUID(backupUUID uuid.UUID) (backup.Backup, error
 	return form, r.db.GetWithNotFound(&form, "backup.error.notFound", query, backupUUID)
 }
 
+// ListForStorageDriver returns the backups stored with a driver, includeUnrecorded adds backups taken before drivers were recorded
+func (r *BackupRepository) ListForStorageDriver(storageDriver string, includeUnrecorded bool) ([]backup.Backup, error) {
+	query := `
+       SELECT %s FROM storage.backups
+       WHERE storage_driver = $1 OR (storage_driver = '' AND $2)
+       ORDER BY started_at
+    `
+
+	query = fmt.Sprintf(query, pkg.GetColumns[backup.Backup]())
+
+	var backups []backup.Backup
+	return backups, r.db.Select(&backups, query, storageDriver, includeUnrecorded)
+}
+
 func (r *BackupRepository) ExistsByUUID(backupUUID uuid.UUID) (bool, error) {
 	return r.db.Exists("storage.backups", "uuid = $1", backupUUID)
 }
@@ -51,16 +65,16 @@ func (r *BackupRepository) Create(backup *backup.Backup) (*backup.Backup, error)
 	return backup, r.db.WithTransaction(func(tx shared.Tx) error {
 		query := `
         INSERT INTO storage.backups (
-            project_uuid, status, error, started_at
+            project_uuid, status, error, storage_driver, started_at
         ) VALUES (
-            $1, $2, $3, $4
+            $1, $2, $3, $4, $5
         )
         RETURNING uuid
         `
 
 		return tx.QueryRowx(
 			query,
-			backup.ProjectUuid, backup.Status, backup.Error, backup.StartedAt,
+			backup.ProjectUuid, backup.Status, backup.Error, backup.StorageDriver, backup.StartedAt,
 		).Scan(&backup.Uuid)
 	})
 }
@@ -70,6 +84,10 @@ func (r *BackupRepository) UpdateStatus(backupUUID uuid.UUID, status, error stri
 	return err
 }
 
+func (r *BackupRepository) UpdateStorageDriver(backupUUID uuid.UUID, storageDriver string) error {
+	return r.db.ExecWithErr("UPDATE storage.backups SET storage_driver = $1 WHERE uuid = $2", storageDriver, backupUUID)
+}
+
 func (r *BackupRepository) Delete(backupUUID uuid.UUID) (bool, error) {
 	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.backups WHERE uuid = $1", backupUUID)
 	if err != nil {
@@ -54,6 +54,57 @@ func (r *ContainerRepository) GetByUUID(containerUUID uuid.UUID) (container.Cont
 	return fetchedContainer, r.db.GetWithNotFound(&fetchedContainer, "container.error.notFound", query, containerUUID)
 }
 
+// ListForProvider returns the containers stored with the instance wide credentials of a provider
+func (r *ContainerRepository) ListForProvider(provider string) ([]container.Container, error) {
+	query := "SELECT %s FROM storage.containers WHERE provider = $1 AND credential_uuid IS NULL ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[container.Container]())
+
+	var containers []container.Container
+	return containers, r.db.Select(&containers, query, provider)
+}
+
+// ListAll returns every container, whichever credentials it's stored with
+func (r *ContainerRepository) ListAll() ([]container.Container, error) {
+	query := "SELECT %s FROM storage.containers ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[container.Container]())
+
+	var containers []container.Container
+	return containers, r.db.Select(&containers, query)
+}
+
+// ListObjectKeys returns the key of every object stored in a container: files, trashed files, shared
+// objects, file versions, image variants and upload chunks
+func (r *ContainerRepository) ListObjectKeys(containerUUID uuid.UUID) ([]string, error) {
+	// Trashed files are moved to .trash/<file uuid>, see file.trashObjectKey. Files referencing a
+	// shared object are left out, the object is listed once instead and only once its content is stored
+	query := `
+		SELECT CASE WHEN deleted_at IS NULL THEN full_file_name ELSE '.trash/' || uuid END
+			FROM storage.files WHERE container_uuid = $1 AND object_key IS NULL
+		UNION ALL
+		SELECT object_key FROM storage.objects WHERE container_uuid = $1 AND is_stored
+		UNION ALL
+		SELECT file_versions.object_key FROM storage.file_versions
+			JOIN storage.files ON files.uuid = file_versions.file_uuid
+			WHERE files.container_uuid = $1
+		UNION ALL
+		SELECT file_variants.object_key FROM storage.file_variants
+			JOIN storage.files ON files.uuid = file_variants.file_uuid
+			WHERE files.container_uuid = $1
+		UNION ALL
+		SELECT upload_chunks.object_key FROM storage.upload_chunks
+			JOIN storage.uploads ON uploads.uuid = upload_chunks.upload_uuid
+			WHERE uploads.container_uuid = $1
+	`
+
+	var objectKeys []string
+	return objectKeys, r.db.Select(&objectKeys, query, containerUUID)
+}
+
+// HasSharedObjects tells whether files of the container still reference deduplicated content
+func (r *ContainerRepository) HasSharedObjects(containerUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("storage.objects", "container_uuid = $1", containerUUID)
+}
+
 func (r *ContainerRepository) ExistsByUUID(containerUUID uuid.UUID) (bool, error) {
 	return r.db.Exists("storage.containers", "uuid = $1", containerUUID)
 }
@@ -66,9 +117,10 @@ func (r *ContainerRepository) Create(container *container.Container) (*container
 	return container, r.db.WithTransaction(func(tx shared.Tx) error {
 		query := `
         INSERT INTO storage.containers (
-            project_uuid, name, name_key, provider, description, is_public, url, max_file_size, created_by, updated_by
+            project_uuid, name, name_key, provider, credential_uuid, description, is_public, url, max_file_size,
+            versioning, trash_retention_days, deduplication, quarantine_container_uuid, created_by, updated_by
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
         )
         RETURNING uuid
         `
@@ -79,10 +131,15 @@ func (r *ContainerRepository) Create(container *container.Container) (*container
 			container.Name,
 			container.NameKey,
 			container.Provider,
+			container.CredentialUuid,
 			container.Description,
 			container.IsPublic,
 			container.Url,
 			container.MaxFileSize,
+			container.Versioning,
+			container.TrashRetentionDays,
+			container.Deduplication,
+			container.QuarantineContainerUuid,
 			container.CreatedBy,
 			container.UpdatedBy,
 		).Scan(&container.Uuid)
@@ -97,11 +154,17 @@ func (r *ContainerRepository) Update(containerInput *container.Container) (*cont
 		    description = :description, 
 		    is_public = :is_public, 
 		    max_file_size = :max_file_size,
+		    versioning = :versioning,
+		    trash_retention_days = :trash_retention_days,
+		    deduplication = :deduplication,
+		    quarantine_container_uuid = :quarantine_container_uuid,
 		    updated_at = :updated_at, 
 		    updated_by = :updated_by
 		WHERE uuid = :uuid`
 
-	return containerInput, r.db.ExecWithErr(query, containerInput)
+	_, err := r.db.NamedExecWithRowsAffected(query, containerInput)
+
+	return containerInput, err
 }
 
 func (r *ContainerRepository) IncrementTotalFiles(containerUUID uuid.UUID) error {
@@ -112,6 +175,10 @@ func (r *ContainerRepository) DecrementTotalFiles(containerUUID uuid.UUID) error
 	return r.db.ExecWithErr("UPDATE storage.containers SET total_files = total_files - 1 WHERE uuid = $1", containerUUID)
 }
 
+func (r *ContainerRepository) UpdateProvider(containerUUID uuid.UUID, provider, url string) error {
+	return r.db.ExecWithErr("UPDATE storage.containers SET provider = $1, url = $2, updated_at = NOW() WHERE uuid = $3", provider, url, containerUUID)
+}
+
 func (r *ContainerRepository) Delete(containerUUID uuid.UUID) (bool, error) {
 	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.containers WHERE uuid = $1", containerUUID)
 	if err != nil {
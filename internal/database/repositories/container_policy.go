@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/access"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type ContainerPolicyRepository struct {
+	db shared.DB
+}
+
+func NewContainerPolicyRepository(injector *do.Injector) (access.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &ContainerPolicyRepository{db: db}, nil
+}
+
+func (r *ContainerPolicyRepository) ListForContainer(containerUUID uuid.UUID) ([]access.Policy, error) {
+	query := "SELECT %s FROM storage.container_policies WHERE container_uuid = $1 ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[access.Policy]())
+
+	var policies []access.Policy
+	return policies, r.db.Select(&policies, query, containerUUID)
+}
+
+func (r *ContainerPolicyRepository) ListEnabledForContainer(containerUUID uuid.UUID) ([]access.Policy, error) {
+	query := "SELECT %s FROM storage.container_policies WHERE container_uuid = $1 AND is_enabled ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[access.Policy]())
+
+	var policies []access.Policy
+	return policies, r.db.Select(&policies, query, containerUUID)
+}
+
+func (r *ContainerPolicyRepository) GetByUUID(policyUUID uuid.UUID) (access.Policy, error) {
+	query := "SELECT %s FROM storage.container_policies WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[access.Policy]())
+
+	var policy access.Policy
+	return policy, r.db.GetWithNotFound(&policy, "policy.error.notFound", query, policyUUID)
+}
+
+func (r *ContainerPolicyRepository) ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("storage.container_policies", "name = $1 AND container_uuid = $2", name, containerUUID)
+}
+
+func (r *ContainerPolicyRepository) Create(policy *access.Policy) (*access.Policy, error) {
+	return policy, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO storage.container_policies (
+			container_uuid, name, operations, path_pattern, roles, is_enabled, created_by, updated_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		RETURNING uuid, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			policy.ContainerUuid,
+			policy.Name,
+			policy.Operations,
+			policy.PathPattern,
+			policy.Roles,
+			policy.IsEnabled,
+			policy.CreatedBy,
+			policy.UpdatedBy,
+		).Scan(&policy.Uuid, &policy.CreatedAt, &policy.UpdatedAt)
+	})
+}
+
+func (r *ContainerPolicyRepository) Update(policyInput *access.Policy) (*access.Policy, error) {
+	query := `
+		UPDATE storage.container_policies
+		SET
+			name = :name,
+			operations = :operations,
+			path_pattern = :path_pattern,
+			roles = :roles,
+			is_enabled = :is_enabled,
+			updated_at = :updated_at,
+			updated_by = :updated_by
+		WHERE uuid = :uuid`
+
+	_, err := r.db.NamedExecWithRowsAffected(query, policyInput)
+
+	return policyInput, err
+}
+
+func (r *ContainerPolicyRepository) Delete(policyUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.container_policies WHERE uuid = $1", policyUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
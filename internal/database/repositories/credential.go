@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type CredentialRepository struct {
+	db shared.DB
+}
+
+func NewCredentialRepository(injector *do.Injector) (credential.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &CredentialRepository{db: db}, nil
+}
+
+func (r *CredentialRepository) ListForProject(projectUUID uuid.UUID) ([]credential.Credential, error) {
+	query := `
+		SELECT %s FROM storage.credentials WHERE project_uuid = :project_uuid
+		ORDER BY name
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[credential.Credential]())
+
+	params := map[string]interface{}{
+		"project_uuid": projectUUID,
+	}
+
+	var credentials []credential.Credential
+	return credentials, r.db.SelectNamedList(&credentials, query, params)
+}
+
+func (r *CredentialRepository) GetByUUID(credentialUUID uuid.UUID) (credential.Credential, error) {
+	query := "SELECT %s FROM storage.credentials WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[credential.Credential]())
+
+	var fetchedCredential credential.Credential
+	return fetchedCredential, r.db.GetWithNotFound(&fetchedCredential, "credential.error.notFound", query, credentialUUID)
+}
+
+func (r *CredentialRepository) ExistsByNameForProject(name string, projectUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("storage.credentials", "name = $1 AND project_uuid = $2", name, projectUUID)
+}
+
+func (r *CredentialRepository) IsInUse(credentialUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("storage.containers", "credential_uuid = $1", credentialUUID)
+}
+
+func (r *CredentialRepository) Create(credential *credential.Credential) (*credential.Credential, error) {
+	return credential, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO storage.credentials (
+			uuid, project_uuid, name, driver, secrets, created_by, updated_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		RETURNING created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			credential.Uuid,
+			credential.ProjectUuid,
+			credential.Name,
+			credential.Driver,
+			credential.Secrets,
+			credential.CreatedBy,
+			credential.UpdatedBy,
+		).Scan(&credential.CreatedAt, &credential.UpdatedAt)
+	})
+}
+
+func (r *CredentialRepository) Update(credentialInput *credential.Credential) (*credential.Credential, error) {
+	query := `
+		UPDATE storage.credentials
+		SET
+			name = :name,
+			secrets = :secrets,
+			updated_at = :updated_at,
+			updated_by = :updated_by
+		WHERE uuid = :uuid`
+
+	_, err := r.db.NamedExecWithRowsAffected(query, credentialInput)
+
+	return credentialInput, err
+}
+
+func (r *CredentialRepository) Delete(credentialUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.credentials WHERE uuid = $1", credentialUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"strings"
+	"time"
+)
+
+// redactSensitive drops the body of a sensitive message once it's finished, the single-use
+// links it carries must not be readable from the outbox
+const redactSensitive = "payload = CASE WHEN is_sensitive THEN payload - 'Text' - 'HTML' ELSE payload END"
+
+type EmailOutboxRepository struct {
+	db shared.DB
+}
+
+func NewEmailOutboxRepository(injector *do.Injector) (email.OutboxRepository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &EmailOutboxRepository{db: db}, nil
+}
+
+func (r *EmailOutboxRepository) List(
+	input *email.ListOutboxInput,
+	paginationParams shared.PaginationParams,
+) ([]email.OutboxMessage, shared.PaginationDetails, error) {
+	whereClause, params := r.buildFilters(input)
+
+	total, err := r.getFilteredCount(whereClause, params)
+	if err != nil {
+		return nil, shared.PaginationDetails{}, fmt.Errorf("failed to get total count of outbox messages: %w", err)
+	}
+
+	messages, err := r.getFilteredMessages(whereClause, params, paginationParams)
+	if err != nil {
+		return nil, shared.PaginationDetails{}, fmt.Errorf("failed to get outbox messages: %w", err)
+	}
+
+	return messages, shared.PaginationDetails{
+		Total: total,
+		Page:  paginationParams.Page,
+		Limit: paginationParams.Limit,
+	}, nil
+}
+
+func (r *EmailOutboxRepository) buildFilters(input *email.ListOutboxInput) (string, map[string]interface{}) {
+	var filters []string
+	params := make(map[string]interface{})
+
+	filterMappings := []struct {
+		condition bool
+		clause    string
+		paramName string
+		value     interface{}
+	}{
+		{input.Status.Valid, "status = :status", "status", input.Status.String},
+		{input.Recipient.Valid, "recipients ILIKE :recipient", "recipient", "%" + input.Recipient.String + "%"},
+		{input.Template.Valid, "template = :template", "template", input.Template.String},
+	}
+
+	for _, mapping := range filterMappings {
+		if mapping.condition {
+			filters = append(filters, mapping.clause)
+			params[mapping.paramName] = mapping.value
+		}
+	}
+
+	whereClause := ""
+	if len(filters) > 0 {
+		whereClause = "WHERE " + strings.Join(filters, " AND ")
+	}
+
+	return whereClause, params
+}
+
+func (r *EmailOutboxRepository) getFilteredCount(whereClause string, params map[string]interface{}) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM fluxend.email_outbox %s", whereClause)
+
+	var count int
+	rows, err := r.db.NamedQuery(query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	return count, err
+}
+
+func (r *EmailOutboxRepository) getFilteredMessages(whereClause string, params map[string]interface{}, paginationParams shared.PaginationParams) ([]email.OutboxMessage, error) {
+	params["limit"] = paginationParams.Limit
+	params["offset"] = (paginationParams.Page - 1) * paginationParams.Limit
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM fluxend.email_outbox %s ORDER BY %s DESC LIMIT :limit OFFSET :offset",
+		pkg.GetColumns[email.OutboxMessage](),
+		whereClause,
+		r.validateSortColumn(paginationParams.Sort),
+	)
+
+	var messages []email.OutboxMessage
+	err := r.db.SelectNamedList(&messages, query, params)
+	return messages, err
+}
+
+func (r *EmailOutboxRepository) validateSortColumn(sort string) string {
+	allowedSorts := map[string]bool{
+		"created_at":      true,
+		"updated_at":      true,
+		"next_attempt_at": true,
+		"sent_at":         true,
+	}
+
+	if allowedSorts[sort] {
+		return sort
+	}
+	return "created_at" // default
+}
+
+func (r *EmailOutboxRepository) GetByUUID(messageUUID uuid.UUID) (email.OutboxMessage, error) {
+	query := "SELECT %s FROM fluxend.email_outbox WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.OutboxMessage]())
+
+	var message email.OutboxMessage
+	return message, r.db.GetWithNotFound(&message, "emailOutbox.error.notFound", query, messageUUID)
+}
+
+func (r *EmailOutboxRepository) GetPayload(messageUUID uuid.UUID) (email.Payload, error) {
+	var payload email.Payload
+	return payload, r.db.GetWithNotFound(&payload, "emailOutbox.error.notFound", "SELECT payload FROM fluxend.email_outbox WHERE uuid = $1", messageUUID)
+}
+
+func (r *EmailOutboxRepository) ListDeliveries(messageUUID uuid.UUID) ([]email.Delivery, error) {
+	query := "SELECT %s FROM fluxend.email_deliveries WHERE outbox_uuid = $1 ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Delivery]())
+
+	var deliveries []email.Delivery
+	return deliveries, r.db.Select(&deliveries, query, messageUUID)
+}
+
+func (r *EmailOutboxRepository) Create(message *email.OutboxMessage, payload email.Payload) (*email.OutboxMessage, error) {
+	return message, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO fluxend.email_outbox (
+			payload, subject, recipients, template, organization_uuid, is_sensitive, status, max_attempts, next_attempt_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		RETURNING uuid, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			payload,
+			message.Subject,
+			message.Recipients,
+			message.Template,
+			message.OrganizationUuid,
+			message.IsSensitive,
+			message.Status,
+			message.MaxAttempts,
+			message.NextAttemptAt,
+		).Scan(&message.Uuid, &message.CreatedAt, &message.UpdatedAt)
+	})
+}
+
+// ClaimDue marks due messages as sending and counts the attempt, so several API processes never send
+// the same message twice. Messages left sending since staleBefore belong to a worker that died and are claimed again
+func (r *EmailOutboxRepository) ClaimDue(staleBefore time.Time, limit int) ([]email.OutboxMessage, error) {
+	query := `
+		UPDATE fluxend.email_outbox SET status = $1, attempts = attempts + 1, updated_at = NOW()
+		WHERE uuid IN (
+			SELECT uuid FROM fluxend.email_outbox
+			WHERE (status = $2 AND next_attempt_at <= NOW()) OR (status = $1 AND updated_at < $3)
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[email.OutboxMessage]())
+
+	var messages []email.OutboxMessage
+	return messages, r.db.Select(
+		&messages,
+		query,
+		constants.EmailOutboxStatusSending,
+		constants.EmailOutboxStatusPending,
+		staleBefore,
+		limit,
+	)
+}
+
+func (r *EmailOutboxRepository) CreateDelivery(delivery *email.Delivery) (*email.Delivery, error) {
+	return delivery, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO fluxend.email_deliveries (
+			outbox_uuid, attempt, driver, status, provider_message_id, error
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+		RETURNING uuid, created_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			delivery.OutboxUuid,
+			delivery.Attempt,
+			delivery.Driver,
+			delivery.Status,
+			delivery.ProviderMessageId,
+			delivery.Error,
+		).Scan(&delivery.Uuid, &delivery.CreatedAt)
+	})
+}
+
+func (r *EmailOutboxRepository) MarkSent(messageUUID uuid.UUID, driver, providerMessageID string) error {
+	query := `
+		UPDATE fluxend.email_outbox
+		SET status = $1, driver = $2, provider_message_id = $3, last_error = '', sent_at = NOW(), updated_at = NOW(), %s
+		WHERE uuid = $4
+	`
+
+	query = fmt.Sprintf(query, redactSensitive)
+
+	_, err := r.db.Exec(query, constants.EmailOutboxStatusSent, driver, providerMessageID, messageUUID)
+
+	return err
+}
+
+func (r *EmailOutboxRepository) Reschedule(messageUUID uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE fluxend.email_outbox
+		SET status = $1, next_attempt_at = $2, last_error = $3, updated_at = NOW()
+		WHERE uuid = $4
+	`
+
+	_, err := r.db.Exec(query, constants.EmailOutboxStatusPending, nextAttemptAt, lastError, messageUUID)
+
+	return err
+}
+
+func (r *EmailOutboxRepository) MarkFailed(messageUUID uuid.UUID, lastError string) error {
+	query := fmt.Sprintf("UPDATE fluxend.email_outbox SET status = $1, last_error = $2, updated_at = NOW(), %s WHERE uuid = $3", redactSensitive)
+
+	_, err := r.db.Exec(query, constants.EmailOutboxStatusFailed, lastError, messageUUID)
+
+	return err
+}
+
+func (r *EmailOutboxRepository) MarkSuppressed(messageUUID uuid.UUID, lastError string) error {
+	query := fmt.Sprintf("UPDATE fluxend.email_outbox SET status = $1, last_error = $2, updated_at = NOW(), %s WHERE uuid = $3", redactSensitive)
+
+	_, err := r.db.Exec(query, constants.EmailOutboxStatusSuppressed, lastError, messageUUID)
+
+	return err
+}
+
+// Requeue makes a sent, failed or suppressed message due now with no attempts made, false when it's still queued
+func (r *EmailOutboxRepository) Requeue(messageUUID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE fluxend.email_outbox
+		SET status = $1, attempts = 0, next_attempt_at = NOW(), last_error = '', updated_at = NOW()
+		WHERE uuid = $2 AND status IN ($3, $4, $5)
+	`
+
+	rowsAffected, err := r.db.ExecWithRowsAffected(
+		query,
+		constants.EmailOutboxStatusPending,
+		messageUUID,
+		constants.EmailOutboxStatusSent,
+		constants.EmailOutboxStatusFailed,
+		constants.EmailOutboxStatusSuppressed,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *EmailOutboxRepository) PurgeFinishedBefore(before time.Time) (int64, error) {
+	return r.db.ExecWithRowsAffected(
+		"DELETE FROM fluxend.email_outbox WHERE status IN ($1, $2, $3) AND updated_at < $4",
+		constants.EmailOutboxStatusSent,
+		constants.EmailOutboxStatusFailed,
+		constants.EmailOutboxStatusSuppressed,
+		before,
+	)
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/email"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/samber/do"
+	"strings"
+)
+
+type EmailSuppressionRepository struct {
+	db shared.DB
+}
+
+func NewEmailSuppressionRepository(injector *do.Injector) (email.SuppressionRepository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &EmailSuppressionRepository{db: db}, nil
+}
+
+func (r *EmailSuppressionRepository) List(
+	input *email.ListSuppressionsInput,
+	paginationParams shared.PaginationParams,
+) ([]email.Suppression, shared.PaginationDetails, error) {
+	whereClause, params := r.buildFilters(input)
+
+	total, err := r.getFilteredCount(whereClause, params)
+	if err != nil {
+		return nil, shared.PaginationDetails{}, fmt.Errorf("failed to get total count of suppressions: %w", err)
+	}
+
+	suppressions, err := r.getFilteredSuppressions(whereClause, params, paginationParams)
+	if err != nil {
+		return nil, shared.PaginationDetails{}, fmt.Errorf("failed to get suppressions: %w", err)
+	}
+
+	return suppressions, shared.PaginationDetails{
+		Total: total,
+		Page:  paginationParams.Page,
+		Limit: paginationParams.Limit,
+	}, nil
+}
+
+func (r *EmailSuppressionRepository) buildFilters(input *email.ListSuppressionsInput) (string, map[string]interface{}) {
+	var filters []string
+	params := make(map[string]interface{})
+
+	filterMappings := []struct {
+		condition bool
+		clause    string
+		paramName string
+		value     interface{}
+	}{
+		{input.Email.Valid, "email ILIKE :email", "email", "%" + input.Email.String + "%"},
+		{input.Reason.Valid, "reason = :reason", "reason", input.Reason.String},
+	}
+
+	for _, mapping := range filterMappings {
+		if mapping.condition {
+			filters = append(filters, mapping.clause)
+			params[mapping.paramName] = mapping.value
+		}
+	}
+
+	whereClause := ""
+	if len(filters) > 0 {
+		whereClause = "WHERE " + strings.Join(filters, " AND ")
+	}
+
+	return whereClause, params
+}
+
+func (r *EmailSuppressionRepository) getFilteredCount(whereClause string, params map[string]interface{}) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM fluxend.email_suppressions %s", whereClause)
+
+	var count int
+	rows, err := r.db.NamedQuery(query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	return count, err
+}
+
+func (r *EmailSuppressionRepository) getFilteredSuppressions(whereClause string, params map[string]interface{}, paginationParams shared.PaginationParams) ([]email.Suppression, error) {
+	params["limit"] = paginationParams.Limit
+	params["offset"] = (paginationParams.Page - 1) * paginationParams.Limit
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM fluxend.email_suppressions %s ORDER BY %s DESC LIMIT :limit OFFSET :offset",
+		pkg.GetColumns[email.Suppression](),
+		whereClause,
+		r.validateSortColumn(paginationParams.Sort),
+	)
+
+	var suppressions []email.Suppression
+	err := r.db.SelectNamedList(&suppressions, query, params)
+	return suppressions, err
+}
+
+func (r *EmailSuppressionRepository) validateSortColumn(sort string) string {
+	allowedSorts := map[string]bool{
+		"created_at": true,
+		"updated_at": true,
+		"email":      true,
+	}
+
+	if allowedSorts[sort] {
+		return sort
+	}
+	return "updated_at" // default
+}
+
+func (r *EmailSuppressionRepository) GetByUUID(suppressionUUID uuid.UUID) (email.Suppression, error) {
+	query := "SELECT %s FROM fluxend.email_suppressions WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Suppression]())
+
+	var suppression email.Suppression
+	return suppression, r.db.GetWithNotFound(&suppression, "emailSuppression.error.notFound", query, suppressionUUID)
+}
+
+// Save suppresses an address, an address already suppressed takes the latest reason
+func (r *EmailSuppressionRepository) Save(suppression *email.Suppression) (*email.Suppression, error) {
+	return suppression, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO fluxend.email_suppressions (
+			email, reason, driver, provider_message_id, details, created_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+		ON CONFLICT (email) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			driver = EXCLUDED.driver,
+			provider_message_id = EXCLUDED.provider_message_id,
+			details = EXCLUDED.details,
+			created_by = EXCLUDED.created_by,
+			updated_at = NOW()
+		RETURNING uuid, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			suppression.Email,
+			suppression.Reason,
+			suppression.Driver,
+			suppression.ProviderMessageId,
+			suppression.Details,
+			suppression.CreatedBy,
+		).Scan(&suppression.Uuid, &suppression.CreatedAt, &suppression.UpdatedAt)
+	})
+}
+
+func (r *EmailSuppressionRepository) Delete(suppressionUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM fluxend.email_suppressions WHERE uuid = $1", suppressionUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// ListSuppressed returns which of the given addresses are suppressed, they are expected as kept on the list
+func (r *EmailSuppressionRepository) ListSuppressed(addresses []string) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	var suppressed []string
+	return suppressed, r.db.Select(&suppressed, "SELECT email FROM fluxend.email_suppressions WHERE email = ANY($1)", pq.Array(addresses))
+}
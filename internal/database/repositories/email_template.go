@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/email"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type EmailTemplateRepository struct {
+	db shared.DB
+}
+
+func NewEmailTemplateRepository(injector *do.Injector) (email.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &EmailTemplateRepository{db: db}, nil
+}
+
+func (r *EmailTemplateRepository) List() ([]email.Template, error) {
+	query := "SELECT %s FROM fluxend.email_templates ORDER BY type, name"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Template]())
+
+	var templates []email.Template
+	return templates, r.db.Select(&templates, query)
+}
+
+func (r *EmailTemplateRepository) GetByUUID(templateUUID uuid.UUID) (email.Template, error) {
+	query := "SELECT %s FROM fluxend.email_templates WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Template]())
+
+	var template email.Template
+	return template, r.db.GetWithNotFound(&template, "emailTemplate.error.notFound", query, templateUUID)
+}
+
+func (r *EmailTemplateRepository) GetByName(name string) (email.Template, error) {
+	query := "SELECT %s FROM fluxend.email_templates WHERE name = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Template]())
+
+	var template email.Template
+	return template, r.db.GetWithNotFound(&template, "emailTemplate.error.notFound", query, name)
+}
+
+func (r *EmailTemplateRepository) ExistsByName(name string) (bool, error) {
+	return r.db.Exists("fluxend.email_templates", "name = $1", name)
+}
+
+func (r *EmailTemplateRepository) IsLayoutInUse(name string) (bool, error) {
+	return r.db.Exists("fluxend.email_templates", "layout = $1", name)
+}
+
+func (r *EmailTemplateRepository) Create(template *email.Template) (*email.Template, error) {
+	return template, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO fluxend.email_templates (
+			name, type, parameters, subject, message, html_message, layout, created_by, updated_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		RETURNING id, uuid, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			template.Name,
+			template.Type,
+			template.Parameters,
+			template.Subject,
+			template.Message,
+			template.HtmlMessage,
+			template.Layout,
+			template.CreatedBy,
+			template.UpdatedBy,
+		).Scan(&template.Id, &template.Uuid, &template.CreatedAt, &template.UpdatedAt)
+	})
+}
+
+func (r *EmailTemplateRepository) Update(templateInput *email.Template) (*email.Template, error) {
+	query := `
+		UPDATE fluxend.email_templates
+		SET
+			parameters = :parameters,
+			subject = :subject,
+			message = :message,
+			html_message = :html_message,
+			layout = :layout,
+			updated_at = :updated_at,
+			updated_by = :updated_by
+		WHERE uuid = :uuid`
+
+	_, err := r.db.NamedExecWithRowsAffected(query, templateInput)
+
+	return templateInput, err
+}
+
+func (r *EmailTemplateRepository) Delete(templateUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM fluxend.email_templates WHERE uuid = $1", templateUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *EmailTemplateRepository) ListOverridesForOrganization(organizationUUID uuid.UUID) ([]email.Override, error) {
+	query := "SELECT %s FROM fluxend.organization_email_templates WHERE organization_uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Override]())
+
+	var overrides []email.Override
+	return overrides, r.db.Select(&overrides, query, organizationUUID)
+}
+
+func (r *EmailTemplateRepository) GetOverride(templateID int, organizationUUID uuid.UUID) (email.Override, error) {
+	query := "SELECT %s FROM fluxend.organization_email_templates WHERE template_id = $1 AND organization_uuid = $2"
+	query = fmt.Sprintf(query, pkg.GetColumns[email.Override]())
+
+	var override email.Override
+	return override, r.db.GetWithNotFound(&override, "emailTemplate.error.notFound", query, templateID, organizationUUID)
+}
+
+// SaveOverride creates the override of a template for an organization or replaces the existing one
+func (r *EmailTemplateRepository) SaveOverride(override *email.Override) (*email.Override, error) {
+	return override, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO fluxend.organization_email_templates (
+			template_id, organization_uuid, subject, message, html_message, created_by, updated_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		ON CONFLICT (template_id, organization_uuid) DO UPDATE SET
+			subject = EXCLUDED.subject,
+			message = EXCLUDED.message,
+			html_message = EXCLUDED.html_message,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING uuid, created_by, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			override.TemplateId,
+			override.OrganizationUuid,
+			override.Subject,
+			override.Message,
+			override.HtmlMessage,
+			override.CreatedBy,
+			override.UpdatedBy,
+		).Scan(&override.Uuid, &override.CreatedBy, &override.CreatedAt, &override.UpdatedAt)
+	})
+}
+
+func (r *EmailTemplateRepository) DeleteOverride(templateID int, organizationUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected(
+		"DELETE FROM fluxend.organization_email_templates WHERE template_id = $1 AND organization_uuid = $2",
+		templateID,
+		organizationUUID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"encoding/json"
 	"fluxend/internal/domain/shared"
 	"fluxend/internal/domain/storage/file"
 	"fluxend/pkg"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/samber/do"
+	"strings"
+	"time"
 )
 
 type FileRepository struct {
@@ -18,23 +22,21 @@ func NewFileRepository(injector *do.Injector) (file.Repository, error) {
 	return &FileRepository{db: db}, nil
 }
 
-func (r *FileRepository) ListForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID) ([]file.File, error) {
+func (r *FileRepository) ListForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter file.ListFilesInput) ([]file.File, error) {
 	offset := (paginationParams.Page - 1) * paginationParams.Limit
 	query := `
 		SELECT 
 			%s 
 		FROM 
-			storage.files WHERE container_uuid = :container_uuid
+			storage.files WHERE %s
 		ORDER BY 
-			:sort DESC
+			%s
 		LIMIT 
 			:limit 
 		OFFSET 
 			:offset;
 	`
 
-	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
-
 	params := map[string]interface{}{
 		"container_uuid": containerUUID,
 		"sort":           paginationParams.Sort,
@@ -42,33 +44,170 @@ func (r *FileRepository) ListForContainer(paginationParams shared.PaginationPara
 		"offset":         offset,
 	}
 
+	conditions := r.listConditions(filter, params)
+	orderBy := ":sort DESC"
+	if filter.Delimiter != "" {
+		// Only files directly under the prefix, deeper ones are listed as folders
+		conditions += " AND strpos(substr(full_file_name, char_length(:prefix) + 1), :delimiter) = 0"
+		orderBy = "full_file_name"
+	}
+
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File](), conditions, orderBy)
+
 	var files []file.File
 	return files, r.db.SelectNamedList(&files, query, params)
 }
 
+// ListFoldersForContainer returns the distinct names up to the first delimiter after the prefix, ending in the
+// delimiter, of the files matching the filter
+func (r *FileRepository) ListFoldersForContainer(containerUUID uuid.UUID, filter file.ListFilesInput) ([]string, error) {
+	query := `
+		SELECT DISTINCT
+			:prefix || split_part(substr(full_file_name, char_length(:prefix) + 1), :delimiter, 1) || :delimiter AS folder
+		FROM 
+			storage.files WHERE %s AND strpos(substr(full_file_name, char_length(:prefix) + 1), :delimiter) > 0
+		ORDER BY 
+			folder
+	`
+
+	params := map[string]interface{}{
+		"container_uuid": containerUUID,
+	}
+
+	query = fmt.Sprintf(query, r.listConditions(filter, params))
+
+	var folders []string
+	return folders, r.db.SelectNamedList(&folders, query, params)
+}
+
+// listConditions builds the WHERE clause of a filtered listing, filling in the params it refers to
+func (r *FileRepository) listConditions(filter file.ListFilesInput, params map[string]interface{}) string {
+	conditions := []string{"container_uuid = :container_uuid", "deleted_at IS NULL"}
+
+	// Prefix and delimiter are referenced by the folder expressions even when empty
+	params["prefix"] = filter.Prefix
+	params["delimiter"] = filter.Delimiter
+
+	if filter.Prefix != "" {
+		conditions = append(conditions, "full_file_name LIKE :prefix_pattern")
+		params["prefix_pattern"] = escapeLikePattern(filter.Prefix) + "%"
+	}
+
+	if strings.HasSuffix(filter.MimeType, "/*") {
+		conditions = append(conditions, "mime_type LIKE :mime_type_pattern")
+		params["mime_type_pattern"] = escapeLikePattern(strings.TrimSuffix(filter.MimeType, "*")) + "%"
+	} else if filter.MimeType != "" {
+		conditions = append(conditions, "mime_type = :mime_type")
+		params["mime_type"] = filter.MimeType
+	}
+
+	if filter.MinSize > 0 {
+		conditions = append(conditions, "size >= :min_size")
+		params["min_size"] = filter.MinSize
+	}
+
+	if filter.MaxSize > 0 {
+		conditions = append(conditions, "size <= :max_size")
+		params["max_size"] = filter.MaxSize
+	}
+
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= :created_after")
+		params["created_after"] = *filter.CreatedAfter
+	}
+
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < :created_before")
+		params["created_before"] = *filter.CreatedBefore
+	}
+
+	if filter.Tag != "" {
+		conditions = append(conditions, "tags @> ARRAY[CAST(:tag AS TEXT)]")
+		params["tag"] = filter.Tag
+	}
+
+	if len(filter.Metadata) > 0 {
+		// A map of strings always marshals
+		metadata, _ := json.Marshal(filter.Metadata)
+
+		conditions = append(conditions, "metadata @> CAST(:metadata AS JSONB)")
+		params["metadata"] = string(metadata)
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// escapeLikePattern makes wildcards in user input match literally, backslash being the default LIKE escape
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// ListByPrefixForContainer returns the live files of a container whose name starts with the prefix, by name
+func (r *FileRepository) ListByPrefixForContainer(containerUUID uuid.UUID, prefix string, limit int) ([]file.File, error) {
+	query := `
+		SELECT %s FROM storage.files
+		WHERE container_uuid = $1 AND deleted_at IS NULL AND full_file_name LIKE $2
+		ORDER BY full_file_name
+		LIMIT $3
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	var files []file.File
+	return files, r.db.Select(&files, query, containerUUID, escapeLikePattern(prefix)+"%", limit)
+}
+
+func (r *FileRepository) ListByUUIDsForContainer(containerUUID uuid.UUID, fileUUIDs []uuid.UUID) ([]file.File, error) {
+	query := `
+		SELECT %s FROM storage.files
+		WHERE container_uuid = $1 AND deleted_at IS NULL AND uuid = ANY($2::uuid[])
+		ORDER BY full_file_name
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	// UUIDs are byte arrays, which pq would encode as nested arrays
+	uuidStrings := make([]string, len(fileUUIDs))
+	for i, fileUUID := range fileUUIDs {
+		uuidStrings[i] = fileUUID.String()
+	}
+
+	var files []file.File
+	return files, r.db.Select(&files, query, containerUUID, pq.Array(uuidStrings))
+}
+
 func (r *FileRepository) GetByUUID(fileUUID uuid.UUID) (file.File, error) {
-	query := "SELECT %s FROM storage.files WHERE uuid = $1"
+	query := "SELECT %s FROM storage.files WHERE uuid = $1 AND deleted_at IS NULL"
 	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
 
 	var fetchedFile file.File
 	return fetchedFile, r.db.GetWithNotFound(&fetchedFile, "file.error.notFound", query, fileUUID)
 }
 
+func (r *FileRepository) GetByNameForContainer(name string, containerUUID uuid.UUID) (file.File, error) {
+	query := "SELECT %s FROM storage.files WHERE full_file_name = $1 AND container_uuid = $2 AND deleted_at IS NULL"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	var fetchedFile file.File
+	return fetchedFile, r.db.GetWithNotFound(&fetchedFile, "file.error.notFound", query, name, containerUUID)
+}
+
 func (r *FileRepository) ExistsByUUID(containerUUID uuid.UUID) (bool, error) {
 	return r.db.Exists("storage.files", "uuid = $1", containerUUID)
 }
 
 func (r *FileRepository) ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error) {
-	return r.db.Exists("storage.files", "full_file_name = $1 AND container_uuid = $2", name, containerUUID)
+	return r.db.Exists("storage.files", "full_file_name = $1 AND container_uuid = $2 AND deleted_at IS NULL", name, containerUUID)
 }
 
 func (r *FileRepository) Create(file *file.File) (*file.File, error) {
 	return file, r.db.WithTransaction(func(tx shared.Tx) error {
 		query := `
         INSERT INTO storage.files (
-            container_uuid, full_file_name, size, mime_type, created_by, updated_by, created_at, updated_at
+            container_uuid, full_file_name, size, size_in_bytes, mime_type, metadata, tags, checksum, object_key,
+            created_by, updated_by, created_at, updated_at
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
         )
         RETURNING uuid
         `
@@ -78,7 +217,12 @@ func (r *FileRepository) Create(file *file.File) (*file.File, error) {
 			file.ContainerUuid,
 			file.FullFileName,
 			file.Size,
+			file.SizeInBytes,
 			file.MimeType,
+			file.Metadata,
+			file.Tags,
+			file.Checksum,
+			file.ObjectKey,
 			file.CreatedBy,
 			file.UpdatedBy,
 			file.CreatedAt,
@@ -103,6 +247,71 @@ func (r *FileRepository) Rename(inputFile *file.File) (*file.File, error) {
 	return inputFile, err
 }
 
+func (r *FileRepository) UpdateContent(inputFile *file.File) (*file.File, error) {
+	query := `
+       UPDATE storage.files 
+       SET size = $1, size_in_bytes = $2, mime_type = $3, checksum = $4, updated_at = $5, updated_by = $6
+       WHERE uuid = $7`
+
+	err := r.db.ExecWithErr(query,
+		inputFile.Size,
+		inputFile.SizeInBytes,
+		inputFile.MimeType,
+		inputFile.Checksum,
+		inputFile.UpdatedAt,
+		inputFile.UpdatedBy,
+		inputFile.Uuid,
+	)
+
+	return inputFile, err
+}
+
+func (r *FileRepository) UpdateMetadata(inputFile *file.File) (*file.File, error) {
+	query := `
+       UPDATE storage.files 
+       SET metadata = $1, tags = $2, updated_at = $3, updated_by = $4
+       WHERE uuid = $5`
+
+	err := r.db.ExecWithErr(query,
+		inputFile.Metadata,
+		inputFile.Tags,
+		inputFile.UpdatedAt,
+		inputFile.UpdatedBy,
+		inputFile.Uuid,
+	)
+
+	return inputFile, err
+}
+
+func (r *FileRepository) UpdateContainer(inputFile *file.File) (*file.File, error) {
+	query := "UPDATE storage.files SET container_uuid = $1, object_key = $2, updated_at = $3 WHERE uuid = $4"
+
+	return inputFile, r.db.ExecWithErr(query, inputFile.ContainerUuid, inputFile.ObjectKey, inputFile.UpdatedAt, inputFile.Uuid)
+}
+
+// ListModifiedBefore returns the oldest live files of a container under a prefix, last modified before the given time,
+// that come after the given (updated_at, uuid) position
+func (r *FileRepository) ListModifiedBefore(
+	containerUUID uuid.UUID,
+	prefix string,
+	modifiedBefore, afterUpdatedAt time.Time,
+	afterUUID uuid.UUID,
+	limit int,
+) ([]file.File, error) {
+	query := `
+		SELECT %s FROM storage.files
+		WHERE container_uuid = $1 AND deleted_at IS NULL AND starts_with(full_file_name, $2) AND updated_at < $3
+			AND (updated_at, uuid) > ($4, $5)
+		ORDER BY updated_at, uuid
+		LIMIT $6
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	var files []file.File
+	return files, r.db.Select(&files, query, containerUUID, prefix, modifiedBefore, afterUpdatedAt, afterUUID, limit)
+}
+
 func (r *FileRepository) Delete(fileUUID uuid.UUID) (bool, error) {
 	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.files WHERE uuid = $1", fileUUID)
 	if err != nil {
@@ -110,3 +319,248 @@ func (r *FileRepository) Delete(fileUUID uuid.UUID) (bool, error) {
 	}
 	return rowsAffected == 1, nil
 }
+
+func (r *FileRepository) ListTrashedForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID) ([]file.File, error) {
+	offset := (paginationParams.Page - 1) * paginationParams.Limit
+	query := `
+		SELECT 
+			%s 
+		FROM 
+			storage.files WHERE container_uuid = :container_uuid AND deleted_at IS NOT NULL
+		ORDER BY 
+			deleted_at DESC
+		LIMIT 
+			:limit 
+		OFFSET 
+			:offset;
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	params := map[string]interface{}{
+		"container_uuid": containerUUID,
+		"limit":          paginationParams.Limit,
+		"offset":         offset,
+	}
+
+	var files []file.File
+	return files, r.db.SelectNamedList(&files, query, params)
+}
+
+func (r *FileRepository) GetTrashedByUUID(fileUUID uuid.UUID) (file.File, error) {
+	query := "SELECT %s FROM storage.files WHERE uuid = $1 AND deleted_at IS NOT NULL"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.File]())
+
+	var fetchedFile file.File
+	return fetchedFile, r.db.GetWithNotFound(&fetchedFile, "file.error.notInTrash", query, fileUUID)
+}
+
+// ListExpiredTrash returns the trashed files kept longer than the retention of their container
+func (r *FileRepository) ListExpiredTrash() ([]file.File, error) {
+	query := `
+		SELECT %s FROM storage.files
+		JOIN storage.containers ON containers.uuid = files.container_uuid
+		WHERE files.deleted_at IS NOT NULL
+			AND files.deleted_at < NOW() - make_interval(days => containers.trash_retention_days)
+		ORDER BY files.deleted_at
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumnsWithAlias[file.File]("files"))
+
+	var files []file.File
+	return files, r.db.Select(&files, query)
+}
+
+func (r *FileRepository) Trash(inputFile *file.File) error {
+	query := "UPDATE storage.files SET deleted_at = $1, deleted_by = $2 WHERE uuid = $3"
+
+	return r.db.ExecWithErr(query, inputFile.DeletedAt, inputFile.DeletedBy, inputFile.Uuid)
+}
+
+func (r *FileRepository) Restore(inputFile *file.File) (*file.File, error) {
+	query := `
+       UPDATE storage.files 
+       SET full_file_name = $1, deleted_at = NULL, deleted_by = NULL, updated_at = $2, updated_by = $3
+       WHERE uuid = $4`
+
+	err := r.db.ExecWithErr(query,
+		inputFile.FullFileName,
+		inputFile.UpdatedAt,
+		inputFile.UpdatedBy,
+		inputFile.Uuid,
+	)
+
+	return inputFile, err
+}
+
+func (r *FileRepository) ListVersions(fileUUID uuid.UUID) ([]file.Version, error) {
+	query := "SELECT %s FROM storage.file_versions WHERE file_uuid = $1 ORDER BY created_at DESC"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.Version]())
+
+	var versions []file.Version
+	return versions, r.db.Select(&versions, query, fileUUID)
+}
+
+func (r *FileRepository) GetVersion(versionUUID uuid.UUID) (file.Version, error) {
+	query := "SELECT %s FROM storage.file_versions WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.Version]())
+
+	var version file.Version
+	return version, r.db.GetWithNotFound(&version, "file.error.versionNotFound", query, versionUUID)
+}
+
+func (r *FileRepository) CreateVersion(version *file.Version) error {
+	query := `
+        INSERT INTO storage.file_versions (
+            uuid, file_uuid, object_key, size, size_in_bytes, mime_type, checksum, created_by, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
+        )
+        `
+
+	return r.db.ExecWithErr(
+		query,
+		version.Uuid,
+		version.FileUuid,
+		version.ObjectKey,
+		version.Size,
+		version.SizeInBytes,
+		version.MimeType,
+		version.Checksum,
+		version.CreatedBy,
+		version.CreatedAt,
+	)
+}
+
+func (r *FileRepository) DeleteVersion(versionUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.file_versions WHERE uuid = $1", versionUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *FileRepository) GetVariant(fileUUID uuid.UUID, variantKey string) (file.Variant, error) {
+	query := "SELECT %s FROM storage.file_variants WHERE file_uuid = $1 AND variant_key = $2"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.Variant]())
+
+	var variant file.Variant
+	return variant, r.db.GetWithNotFound(&variant, "file.error.variantNotFound", query, fileUUID, variantKey)
+}
+
+func (r *FileRepository) ListVariants(fileUUID uuid.UUID) ([]file.Variant, error) {
+	query := "SELECT %s FROM storage.file_variants WHERE file_uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[file.Variant]())
+
+	var variants []file.Variant
+	return variants, r.db.Select(&variants, query, fileUUID)
+}
+
+func (r *FileRepository) CreateVariant(variant *file.Variant) error {
+	// Concurrent requests may render the same variant, the object key is the same so either row is correct
+	query := `
+        INSERT INTO storage.file_variants (
+            file_uuid, variant_key, object_key, size, mime_type, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6
+        )
+        ON CONFLICT (file_uuid, variant_key) DO NOTHING
+        `
+
+	return r.db.ExecWithErr(
+		query,
+		variant.FileUuid,
+		variant.VariantKey,
+		variant.ObjectKey,
+		variant.Size,
+		variant.MimeType,
+		variant.CreatedAt,
+	)
+}
+
+func (r *FileRepository) DeleteVariants(fileUUID uuid.UUID) error {
+	return r.db.ExecWithErr("DELETE FROM storage.file_variants WHERE file_uuid = $1", fileUUID)
+}
+
+// AcquireObject adds a reference to the shared object with the checksum, recording the object when it's
+// the first one. It returns whether the content of the object was already uploaded
+func (r *FileRepository) AcquireObject(object *file.Object) (bool, error) {
+	query := `
+		INSERT INTO storage.objects (container_uuid, checksum, object_key, size, reference_count, created_at)
+		VALUES ($1, $2, $3, $4, 1, $5)
+		ON CONFLICT (container_uuid, checksum) DO UPDATE SET reference_count = objects.reference_count + 1
+		RETURNING is_stored
+	`
+
+	var isStored bool
+	err := r.db.Get(
+		&isStored,
+		query,
+		object.ContainerUuid,
+		object.Checksum,
+		object.ObjectKey,
+		object.Size,
+		object.CreatedAt,
+	)
+
+	return isStored, err
+}
+
+// MarkObjectStored records that the content of a shared object was uploaded, so later references reuse it
+func (r *FileRepository) MarkObjectStored(containerUUID uuid.UUID, checksum string) error {
+	return r.db.ExecWithErr(
+		"UPDATE storage.objects SET is_stored = TRUE WHERE container_uuid = $1 AND checksum = $2",
+		containerUUID, checksum,
+	)
+}
+
+// ReleaseObject removes a reference to the shared object with the checksum, it returns true when that
+// was the last one and the object was forgotten, its content has to be deleted then
+func (r *FileRepository) ReleaseObject(containerUUID uuid.UUID, checksum string) (bool, error) {
+	var released bool
+	err := r.db.WithTransaction(func(tx shared.Tx) error {
+		_, err := tx.Exec(
+			"UPDATE storage.objects SET reference_count = reference_count - 1 WHERE container_uuid = $1 AND checksum = $2",
+			containerUUID, checksum,
+		)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(
+			"DELETE FROM storage.objects WHERE container_uuid = $1 AND checksum = $2 AND reference_count <= 0",
+			containerUUID, checksum,
+		)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		released = rowsAffected == 1
+
+		return err
+	})
+
+	return released, err
+}
+
+// ListStoredObjects returns every object of a container that has a known content: files, trashed
+// files, uploaded shared objects and file versions. Variants and upload chunks are left out
+func (r *FileRepository) ListStoredObjects(containerUUID uuid.UUID) ([]file.StoredObject, error) {
+	// Trashed files are moved to .trash/<file uuid>, see file.trashObjectKey
+	query := `
+		SELECT uuid AS file_uuid, CASE WHEN deleted_at IS NULL THEN full_file_name ELSE '.trash/' || uuid END AS object_key, checksum
+			FROM storage.files WHERE container_uuid = $1 AND object_key IS NULL
+		UNION ALL
+		SELECT NULL, object_key, checksum FROM storage.objects WHERE container_uuid = $1 AND is_stored
+		UNION ALL
+		SELECT file_versions.file_uuid, file_versions.object_key, file_versions.checksum FROM storage.file_versions
+			JOIN storage.files ON files.uuid = file_versions.file_uuid
+			WHERE files.container_uuid = $1
+		ORDER BY object_key
+	`
+
+	var objects []file.StoredObject
+	return objects, r.db.Select(&objects, query, containerUUID)
+}
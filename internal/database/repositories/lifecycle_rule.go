@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/lifecycle"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type LifecycleRuleRepository struct {
+	db shared.DB
+}
+
+func NewLifecycleRuleRepository(injector *do.Injector) (lifecycle.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &LifecycleRuleRepository{db: db}, nil
+}
+
+func (r *LifecycleRuleRepository) ListForContainer(containerUUID uuid.UUID) ([]lifecycle.Rule, error) {
+	query := "SELECT %s FROM storage.lifecycle_rules WHERE container_uuid = $1 ORDER BY created_at"
+	query = fmt.Sprintf(query, pkg.GetColumns[lifecycle.Rule]())
+
+	var rules []lifecycle.Rule
+	return rules, r.db.Select(&rules, query, containerUUID)
+}
+
+func (r *LifecycleRuleRepository) GetByUUID(ruleUUID uuid.UUID) (lifecycle.Rule, error) {
+	query := "SELECT %s FROM storage.lifecycle_rules WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[lifecycle.Rule]())
+
+	var rule lifecycle.Rule
+	return rule, r.db.GetWithNotFound(&rule, "lifecycle.error.notFound", query, ruleUUID)
+}
+
+func (r *LifecycleRuleRepository) ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("storage.lifecycle_rules", "name = $1 AND container_uuid = $2", name, containerUUID)
+}
+
+// ClaimDue marks the enabled rules last run before the given time as running now and returns them,
+// so several API processes never run the same rule twice
+func (r *LifecycleRuleRepository) ClaimDue(lastRunBefore time.Time) ([]lifecycle.Rule, error) {
+	query := `
+		UPDATE storage.lifecycle_rules SET last_run_at = NOW()
+		WHERE is_enabled AND (last_run_at IS NULL OR last_run_at < $1)
+		RETURNING %s
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[lifecycle.Rule]())
+
+	var rules []lifecycle.Rule
+	return rules, r.db.Select(&rules, query, lastRunBefore)
+}
+
+func (r *LifecycleRuleRepository) Create(rule *lifecycle.Rule) (*lifecycle.Rule, error) {
+	return rule, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+		INSERT INTO storage.lifecycle_rules (
+			container_uuid, name, prefix, action, after_days, target_container_uuid, is_enabled, created_by, updated_by
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		RETURNING uuid, created_at, updated_at
+		`
+
+		return tx.QueryRowx(
+			query,
+			rule.ContainerUuid,
+			rule.Name,
+			rule.Prefix,
+			rule.Action,
+			rule.AfterDays,
+			rule.TargetContainerUuid,
+			rule.IsEnabled,
+			rule.CreatedBy,
+			rule.UpdatedBy,
+		).Scan(&rule.Uuid, &rule.CreatedAt, &rule.UpdatedAt)
+	})
+}
+
+func (r *LifecycleRuleRepository) Update(ruleInput *lifecycle.Rule) (*lifecycle.Rule, error) {
+	query := `
+		UPDATE storage.lifecycle_rules
+		SET
+			name = :name,
+			prefix = :prefix,
+			action = :action,
+			after_days = :after_days,
+			target_container_uuid = :target_container_uuid,
+			is_enabled = :is_enabled,
+			updated_at = :updated_at,
+			updated_by = :updated_by
+		WHERE uuid = :uuid`
+
+	_, err := r.db.NamedExecWithRowsAffected(query, ruleInput)
+
+	return ruleInput, err
+}
+
+func (r *LifecycleRuleRepository) SaveCursor(rule *lifecycle.Rule) error {
+	query := "UPDATE storage.lifecycle_rules SET cursor_updated_at = $1, cursor_file_uuid = $2 WHERE uuid = $3"
+
+	return r.db.ExecWithErr(query, rule.CursorUpdatedAt, rule.CursorFileUuid, rule.Uuid)
+}
+
+func (r *LifecycleRuleRepository) Delete(ruleUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.lifecycle_rules WHERE uuid = $1", ruleUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/bulk"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type StorageBulkJobRepository struct {
+	db shared.DB
+}
+
+func NewStorageBulkJobRepository(injector *do.Injector) (bulk.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &StorageBulkJobRepository{db: db}, nil
+}
+
+func (r *StorageBulkJobRepository) GetByUUID(jobUUID uuid.UUID) (bulk.Job, error) {
+	query := "SELECT %s FROM storage.bulk_jobs WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[bulk.Job]())
+
+	var fetchedJob bulk.Job
+	return fetchedJob, r.db.GetWithNotFound(&fetchedJob, "bulk.error.jobNotFound", query, jobUUID)
+}
+
+func (r *StorageBulkJobRepository) Create(job *bulk.Job) (*bulk.Job, error) {
+	query := `
+		INSERT INTO storage.bulk_jobs (
+			container_uuid, status, total_files, created_by, started_at
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+		RETURNING uuid
+	`
+
+	return job, r.db.WithTransaction(func(tx shared.Tx) error {
+		return tx.QueryRowx(
+			query,
+			job.ContainerUuid,
+			job.Status,
+			job.TotalFiles,
+			job.CreatedBy,
+			job.StartedAt,
+		).Scan(&job.Uuid)
+	})
+}
+
+func (r *StorageBulkJobRepository) UpdateStatus(jobUUID uuid.UUID, status, error string, completedAt *time.Time) error {
+	query := "UPDATE storage.bulk_jobs SET status = $1, error = $2, completed_at = $3 WHERE uuid = $4"
+
+	return r.db.ExecWithErr(query, status, error, completedAt, jobUUID)
+}
+
+func (r *StorageBulkJobRepository) UpdateProgress(jobUUID uuid.UUID, deletedFiles, failedFiles int) error {
+	query := "UPDATE storage.bulk_jobs SET deleted_files = $1, failed_files = $2 WHERE uuid = $3"
+
+	return r.db.ExecWithErr(query, deletedFiles, failedFiles, jobUUID)
+}
+
+// FailStale fails the running jobs that neither started nor recorded a result since the given time
+func (r *StorageBulkJobRepository) FailStale(lastActiveBefore time.Time, error string) (int, error) {
+	query := `
+		UPDATE storage.bulk_jobs SET status = $1, error = $2, completed_at = NOW()
+		WHERE status = $3 AND started_at < $4 AND NOT EXISTS (
+			SELECT 1 FROM storage.bulk_job_results
+			WHERE job_uuid = storage.bulk_jobs.uuid AND processed_at >= $4
+		)
+	`
+
+	rowsAffected, err := r.db.ExecWithRowsAffected(query, constants.BulkJobStatusFailed, error, constants.BulkJobStatusRunning, lastActiveBefore)
+
+	return int(rowsAffected), err
+}
+
+func (r *StorageBulkJobRepository) ListResults(jobUUID uuid.UUID) ([]bulk.Result, error) {
+	query := "SELECT %s FROM storage.bulk_job_results WHERE job_uuid = $1 ORDER BY processed_at, full_file_name"
+	query = fmt.Sprintf(query, pkg.GetColumns[bulk.Result]())
+
+	var results []bulk.Result
+	return results, r.db.Select(&results, query, jobUUID)
+}
+
+func (r *StorageBulkJobRepository) CreateResult(result *bulk.Result) error {
+	query := `
+		INSERT INTO storage.bulk_job_results (
+			job_uuid, file_uuid, full_file_name, status, error, processed_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`
+
+	return r.db.ExecWithErr(
+		query,
+		result.JobUuid,
+		result.FileUuid,
+		result.FullFileName,
+		result.Status,
+		result.Error,
+		result.ProcessedAt,
+	)
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/migration"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type StorageMigrationRepository struct {
+	db shared.DB
+}
+
+func NewStorageMigrationRepository(injector *do.Injector) (migration.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &StorageMigrationRepository{db: db}, nil
+}
+
+func (r *StorageMigrationRepository) List() ([]migration.Migration, error) {
+	query := "SELECT %s FROM storage.migrations ORDER BY started_at DESC"
+	query = fmt.Sprintf(query, pkg.GetColumns[migration.Migration]())
+
+	var migrations []migration.Migration
+	return migrations, r.db.Select(&migrations, query)
+}
+
+func (r *StorageMigrationRepository) GetByUUID(migrationUUID uuid.UUID) (migration.Migration, error) {
+	query := "SELECT %s FROM storage.migrations WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[migration.Migration]())
+
+	var fetchedMigration migration.Migration
+	return fetchedMigration, r.db.GetWithNotFound(&fetchedMigration, "migration.error.notFound", query, migrationUUID)
+}
+
+func (r *StorageMigrationRepository) GetUnfinished(sourceDriver, targetDriver string) (migration.Migration, error) {
+	query := `
+		SELECT %s FROM storage.migrations
+		WHERE source_driver = $1 AND target_driver = $2 AND status != $3
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+
+	query = fmt.Sprintf(query, pkg.GetColumns[migration.Migration]())
+
+	var fetchedMigration migration.Migration
+	return fetchedMigration, r.db.GetWithNotFound(
+		&fetchedMigration,
+		"migration.error.notFound",
+		query,
+		sourceDriver,
+		targetDriver,
+		constants.StorageMigrationStatusCompleted,
+	)
+}
+
+func (r *StorageMigrationRepository) Create(migration *migration.Migration) (*migration.Migration, error) {
+	query := `
+		INSERT INTO storage.migrations (
+			source_driver, target_driver, status, created_by, started_at
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+		RETURNING uuid
+	`
+
+	return migration, r.db.WithTransaction(func(tx shared.Tx) error {
+		return tx.QueryRowx(
+			query,
+			migration.SourceDriver,
+			migration.TargetDriver,
+			migration.Status,
+			migration.CreatedBy,
+			migration.StartedAt,
+		).Scan(&migration.Uuid)
+	})
+}
+
+func (r *StorageMigrationRepository) UpdateStatus(migrationUUID uuid.UUID, status, error string, completedAt *time.Time) error {
+	query := "UPDATE storage.migrations SET status = $1, error = $2, completed_at = $3 WHERE uuid = $4"
+
+	return r.db.ExecWithErr(query, status, error, completedAt, migrationUUID)
+}
+
+func (r *StorageMigrationRepository) UpdateProgress(migrationUUID uuid.UUID, totalObjects, copiedObjects int) error {
+	query := "UPDATE storage.migrations SET total_objects = $1, copied_objects = $2 WHERE uuid = $3"
+
+	return r.db.ExecWithErr(query, totalObjects, copiedObjects, migrationUUID)
+}
+
+func (r *StorageMigrationRepository) ListItems(migrationUUID uuid.UUID) ([]migration.Item, error) {
+	query := "SELECT %s FROM storage.migration_items WHERE migration_uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[migration.Item]())
+
+	var items []migration.Item
+	return items, r.db.Select(&items, query, migrationUUID)
+}
+
+func (r *StorageMigrationRepository) CreateItem(item *migration.Item) error {
+	query := `
+		INSERT INTO storage.migration_items (
+			migration_uuid, container_name, object_key, checksum, copied_at
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+		ON CONFLICT (migration_uuid, container_name, object_key) DO UPDATE
+		SET checksum = EXCLUDED.checksum, copied_at = EXCLUDED.copied_at
+	`
+
+	return r.db.ExecWithErr(query, item.MigrationUuid, item.ContainerName, item.ObjectKey, item.Checksum, item.CopiedAt)
+}
+
+func (r *StorageMigrationRepository) GetContainer(migrationUUID uuid.UUID, containerName string) (migration.Container, error) {
+	query := "SELECT %s FROM storage.migration_containers WHERE migration_uuid = $1 AND container_name = $2"
+	query = fmt.Sprintf(query, pkg.GetColumns[migration.Container]())
+
+	var fetchedContainer migration.Container
+	return fetchedContainer, r.db.GetWithNotFound(&fetchedContainer, "migration.error.containerNotFound", query, migrationUUID, containerName)
+}
+
+func (r *StorageMigrationRepository) CreateContainer(container *migration.Container) error {
+	query := `
+		INSERT INTO storage.migration_containers (
+			migration_uuid, container_name, target_url
+		) VALUES (
+			$1, $2, $3
+		)
+		ON CONFLICT (migration_uuid, container_name) DO NOTHING
+	`
+
+	return r.db.ExecWithErr(query, container.MigrationUuid, container.ContainerName, container.TargetUrl)
+}
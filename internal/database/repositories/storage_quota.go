@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/quota"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+// recalculateQuotaQuery recounts usage from the files of each project, trashed files and versions included,
+// along with the declared length of unfinished uploads
+const recalculateQuotaQuery = `
+	INSERT INTO storage.project_quotas (project_uuid, used_bytes, used_files, updated_at)
+	SELECT
+		p.uuid,
+		COALESCE(SUM(f.size_in_bytes), 0) + COALESCE(SUM(v.size_in_bytes), 0) + COALESCE(MAX(u.upload_length), 0),
+		COUNT(f.uuid),
+		NOW()
+	FROM fluxend.projects p
+	LEFT JOIN storage.containers c ON c.project_uuid = p.uuid
+	LEFT JOIN storage.files f ON f.container_uuid = c.uuid
+	LEFT JOIN (
+		SELECT file_uuid, SUM(size_in_bytes) AS size_in_bytes FROM storage.file_versions GROUP BY file_uuid
+	) v ON v.file_uuid = f.uuid
+	LEFT JOIN (
+		SELECT uc.project_uuid, SUM(up.upload_length) AS upload_length
+		FROM storage.uploads up
+		JOIN storage.containers uc ON uc.uuid = up.container_uuid
+		GROUP BY uc.project_uuid
+	) u ON u.project_uuid = p.uuid
+	%s
+	GROUP BY p.uuid
+	ON CONFLICT (project_uuid) DO UPDATE SET
+		used_bytes = EXCLUDED.used_bytes,
+		used_files = EXCLUDED.used_files,
+		updated_at = EXCLUDED.updated_at
+`
+
+type StorageQuotaRepository struct {
+	db shared.DB
+}
+
+func NewStorageQuotaRepository(injector *do.Injector) (quota.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &StorageQuotaRepository{db: db}, nil
+}
+
+// GetForProject returns the quota of a project, projects without files yet have a zero quota
+func (r *StorageQuotaRepository) GetForProject(projectUUID uuid.UUID) (quota.ProjectQuota, error) {
+	query := `
+		SELECT
+			p.uuid AS project_uuid,
+			COALESCE(q.max_size, 0) AS max_size,
+			COALESCE(q.max_files, 0) AS max_files,
+			COALESCE(q.used_bytes, 0) AS used_bytes,
+			COALESCE(q.used_files, 0) AS used_files,
+			COALESCE(q.updated_at, p.created_at) AS updated_at
+		FROM fluxend.projects p
+		LEFT JOIN storage.project_quotas q ON q.project_uuid = p.uuid
+		WHERE p.uuid = $1
+	`
+
+	var projectQuota quota.ProjectQuota
+	return projectQuota, r.db.GetWithNotFound(&projectQuota, "project.error.notFound", query, projectUUID)
+}
+
+func (r *StorageQuotaRepository) GetForOrganization(organizationUUID uuid.UUID) (quota.OrganizationQuota, error) {
+	query := `
+		SELECT
+			o.uuid AS organization_uuid,
+			COALESCE(q.max_size, 0) AS max_size,
+			COALESCE(q.max_files, 0) AS max_files,
+			(
+				SELECT COALESCE(SUM(pq.used_bytes), 0) FROM storage.project_quotas pq
+				JOIN fluxend.projects p ON p.uuid = pq.project_uuid
+				WHERE p.organization_uuid = o.uuid
+			) AS used_bytes,
+			(
+				SELECT COALESCE(SUM(pq.used_files), 0) FROM storage.project_quotas pq
+				JOIN fluxend.projects p ON p.uuid = pq.project_uuid
+				WHERE p.organization_uuid = o.uuid
+			) AS used_files,
+			COALESCE(q.updated_at, o.created_at) AS updated_at
+		FROM fluxend.organizations o
+		LEFT JOIN storage.organization_quotas q ON q.organization_uuid = o.uuid
+		WHERE o.uuid = $1
+	`
+
+	var organizationQuota quota.OrganizationQuota
+	return organizationQuota, r.db.GetWithNotFound(&organizationQuota, "organization.error.notFound", query, organizationUUID)
+}
+
+func (r *StorageQuotaRepository) UpdateForProject(projectQuota *quota.ProjectQuota) error {
+	query := `
+		INSERT INTO storage.project_quotas (project_uuid, max_size, max_files, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_uuid) DO UPDATE SET
+			max_size = EXCLUDED.max_size,
+			max_files = EXCLUDED.max_files,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	return r.db.ExecWithErr(query, projectQuota.ProjectUuid, projectQuota.MaxSize, projectQuota.MaxFiles, projectQuota.UpdatedAt)
+}
+
+func (r *StorageQuotaRepository) UpdateForOrganization(organizationQuota *quota.OrganizationQuota) error {
+	query := `
+		INSERT INTO storage.organization_quotas (organization_uuid, max_size, max_files, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_uuid) DO UPDATE SET
+			max_size = EXCLUDED.max_size,
+			max_files = EXCLUDED.max_files,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	return r.db.ExecWithErr(
+		query,
+		organizationQuota.OrganizationUuid,
+		organizationQuota.MaxSize,
+		organizationQuota.MaxFiles,
+		organizationQuota.UpdatedAt,
+	)
+}
+
+// Reserve adds bytes and files to the usage of a project when neither it nor its organization goes over
+// its limits. The organization row is locked first, so concurrent uploads to its projects are checked in turn
+func (r *StorageQuotaRepository) Reserve(organizationUsage, projectUsage quota.Usage, bytes int64, files int) error {
+	return r.db.WithTransaction(func(tx shared.Tx) error {
+		_, err := tx.Exec(
+			"INSERT INTO storage.organization_quotas (organization_uuid) VALUES ($1) ON CONFLICT DO NOTHING",
+			organizationUsage.OwnerUuid,
+		)
+		if err != nil {
+			return err
+		}
+
+		var locked uuid.UUID
+		err = tx.QueryRowx(
+			"SELECT organization_uuid FROM storage.organization_quotas WHERE organization_uuid = $1 FOR UPDATE",
+			organizationUsage.OwnerUuid,
+		).Scan(&locked)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			SELECT COALESCE(SUM(pq.used_bytes), 0), COALESCE(SUM(pq.used_files), 0)
+			FROM storage.project_quotas pq
+			JOIN fluxend.projects p ON p.uuid = pq.project_uuid
+			WHERE p.organization_uuid = $1
+		`
+
+		if err = tx.QueryRowx(query, organizationUsage.OwnerUuid).Scan(&organizationUsage.UsedBytes, &organizationUsage.UsedFiles); err != nil {
+			return err
+		}
+
+		if err = organizationUsage.Check(bytes, files); err != nil {
+			return err
+		}
+
+		// The increment is checked after the fact, returning an error rolls it back
+		query = `
+			INSERT INTO storage.project_quotas (project_uuid, used_bytes, used_files)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (project_uuid) DO UPDATE SET
+				used_bytes = project_quotas.used_bytes + EXCLUDED.used_bytes,
+				used_files = project_quotas.used_files + EXCLUDED.used_files,
+				updated_at = NOW()
+			RETURNING used_bytes - $2, used_files - $3
+		`
+
+		if err = tx.QueryRowx(query, projectUsage.OwnerUuid, bytes, files).Scan(&projectUsage.UsedBytes, &projectUsage.UsedFiles); err != nil {
+			return err
+		}
+
+		return projectUsage.Check(bytes, files)
+	})
+}
+
+// Add changes the usage of a project without checking its limits, it's used when space is freed
+func (r *StorageQuotaRepository) Add(projectUUID uuid.UUID, bytes int64, files int) error {
+	query := `
+		INSERT INTO storage.project_quotas (project_uuid, used_bytes, used_files)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_uuid) DO UPDATE SET
+			used_bytes = project_quotas.used_bytes + EXCLUDED.used_bytes,
+			used_files = project_quotas.used_files + EXCLUDED.used_files,
+			updated_at = NOW()
+	`
+
+	return r.db.ExecWithErr(query, projectUUID, bytes, files)
+}
+
+func (r *StorageQuotaRepository) Recalculate(projectUUID uuid.UUID) error {
+	return r.db.ExecWithErr(fmt.Sprintf(recalculateQuotaQuery, "WHERE p.uuid = $1"), projectUUID)
+}
+
+func (r *StorageQuotaRepository) RecalculateAll() (int64, error) {
+	return r.db.ExecWithRowsAffected(fmt.Sprintf(recalculateQuotaQuery, ""))
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/upload"
+	"fluxend/pkg"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type UploadRepository struct {
+	db shared.DB
+}
+
+func NewUploadRepository(injector *do.Injector) (upload.Repository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &UploadRepository{db: db}, nil
+}
+
+func (r *UploadRepository) GetByUUID(uploadUUID uuid.UUID) (upload.Upload, error) {
+	query := "SELECT %s FROM storage.uploads WHERE uuid = $1"
+	query = fmt.Sprintf(query, pkg.GetColumns[upload.Upload]())
+
+	var fetchedUpload upload.Upload
+	return fetchedUpload, r.db.GetWithNotFound(&fetchedUpload, "upload.error.notFound", query, uploadUUID)
+}
+
+func (r *UploadRepository) ListChunks(uploadUUID uuid.UUID) ([]upload.Chunk, error) {
+	query := "SELECT %s FROM storage.upload_chunks WHERE upload_uuid = $1 ORDER BY chunk_offset"
+	query = fmt.Sprintf(query, pkg.GetColumns[upload.Chunk]())
+
+	var chunks []upload.Chunk
+	return chunks, r.db.Select(&chunks, query, uploadUUID)
+}
+
+// ListExpired returns the uploads that expired before the given time, oldest first
+func (r *UploadRepository) ListExpired(before time.Time, limit int) ([]upload.Upload, error) {
+	query := "SELECT %s FROM storage.uploads WHERE expires_at <= $1 ORDER BY expires_at LIMIT $2"
+	query = fmt.Sprintf(query, pkg.GetColumns[upload.Upload]())
+
+	var uploads []upload.Upload
+	return uploads, r.db.Select(&uploads, query, before, limit)
+}
+
+func (r *UploadRepository) Create(upload *upload.Upload) (*upload.Upload, error) {
+	return upload, r.db.WithTransaction(func(tx shared.Tx) error {
+		query := `
+        INSERT INTO storage.uploads (
+            container_uuid, full_file_name, mime_type, upload_length, upload_offset, expires_at, created_by, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
+        )
+        RETURNING uuid
+        `
+
+		return tx.QueryRowx(
+			query,
+			upload.ContainerUuid,
+			upload.FullFileName,
+			upload.MimeType,
+			upload.UploadLength,
+			upload.UploadOffset,
+			upload.ExpiresAt,
+			upload.CreatedBy,
+			upload.CreatedAt,
+			upload.UpdatedAt,
+		).Scan(&upload.Uuid)
+	})
+}
+
+// AppendChunk records a chunk, moves the upload offset past it and pushes back its expiry. It returns false
+// without changes when the upload is no longer at the chunk offset, e.g. another request appended first.
+func (r *UploadRepository) AppendChunk(chunk *upload.Chunk, expiresAt time.Time) (bool, error) {
+	appended := false
+
+	err := r.db.WithTransaction(func(tx shared.Tx) error {
+		result, err := tx.Exec(
+			"UPDATE storage.uploads SET upload_offset = $1, updated_at = $2, expires_at = $3 WHERE uuid = $4 AND upload_offset = $5",
+			chunk.ChunkOffset+chunk.Size, chunk.CreatedAt, expiresAt, chunk.UploadUuid, chunk.ChunkOffset,
+		)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil || rowsAffected == 0 {
+			return err
+		}
+
+		_, err = tx.Exec(
+			"INSERT INTO storage.upload_chunks (upload_uuid, chunk_offset, size, object_key, created_at) VALUES ($1, $2, $3, $4, $5)",
+			chunk.UploadUuid, chunk.ChunkOffset, chunk.Size, chunk.ObjectKey, chunk.CreatedAt,
+		)
+		appended = err == nil
+
+		return err
+	})
+
+	return appended, err
+}
+
+func (r *UploadRepository) Delete(uploadUUID uuid.UUID) (bool, error) {
+	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM storage.uploads WHERE uuid = $1", uploadUUID)
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
@@ -69,7 +69,7 @@ func (r *UserRepository) GetByEmail(email string) (user.User, error) {
 func (r *UserRepository) Create(input *user.User) (*user.User, error) {
 	query := "INSERT INTO authentication.users (username, email, status, role_id, bio, password) VALUES ($1, $2, $3, $4, $5, $6) RETURNING uuid"
 
-	err := r.db.QueryRow(query, input.Username, input.Email, constants.UserStatusActive, input.RoleID, input.Bio, auth.HashPassword(input.Password)).Scan(&input.Uuid)
+	err := r.db.QueryRow(query, input.Username, input.Email, input.Status, input.RoleID, input.Bio, auth.HashPassword(input.Password)).Scan(&input.Uuid)
 	if err != nil {
 		return &user.User{}, fmt.Errorf("could not create row: %v", err)
 	}
@@ -126,6 +126,31 @@ func (r *UserRepository) Update(userUUID uuid.UUID, inputUser *user.User) (*user
 	return inputUser, err
 }
 
+func (r *UserRepository) UpdatePassword(userUUID uuid.UUID, password string) error {
+	query := "UPDATE authentication.users SET password = $1, updated_at = NOW() WHERE uuid = $2"
+
+	_, err := r.db.Exec(query, auth.HashPassword(password), userUUID)
+	return err
+}
+
+// MarkEmailVerified activates a user pending verification, a user made inactive by an admin stays so
+func (r *UserRepository) MarkEmailVerified(userUUID uuid.UUID) error {
+	query := `
+		UPDATE authentication.users
+		SET status = CASE WHEN status = $1 THEN $2 ELSE status END,
+		    email_verified_at = COALESCE(email_verified_at, NOW()),
+		    updated_at = NOW()
+		WHERE uuid = $3
+	`
+
+	_, err := r.db.Exec(query, constants.UserStatusPending, constants.UserStatusActive, userUUID)
+	return err
+}
+
+func (r *UserRepository) IsPendingVerification(userUUID uuid.UUID) (bool, error) {
+	return r.db.Exists("authentication.users", "uuid = $1 AND status = $2", userUUID, constants.UserStatusPending)
+}
+
 func (r *UserRepository) Delete(userUUID uuid.UUID) (bool, error) {
 	rowsAffected, err := r.db.ExecWithRowsAffected("DELETE FROM authentication.users WHERE uuid = $1", userUUID)
 	if err != nil {
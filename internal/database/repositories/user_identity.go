@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"fluxend/internal/domain/account"
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type UserIdentityRepository struct {
+	db shared.DB
+}
+
+func NewUserIdentityRepository(injector *do.Injector) (account.IdentityRepository, error) {
+	db := do.MustInvoke[shared.DB](injector)
+	return &UserIdentityRepository{db: db}, nil
+}
+
+func (r *UserIdentityRepository) GetIdentity(provider, subject string) (account.Identity, error) {
+	query := "SELECT * FROM authentication.user_identities WHERE provider = $1 AND subject = $2"
+
+	var identity account.Identity
+	return identity, r.db.GetWithNotFound(&identity, "account.error.identityNotFound", query, provider, subject)
+}
+
+func (r *UserIdentityRepository) CreateIdentity(identity *account.Identity) (*account.Identity, error) {
+	query := `
+		INSERT INTO authentication.user_identities (
+			user_uuid, provider, subject, email, last_login_at
+		) VALUES (
+			$1, $2, $3, $4, NOW()
+		)
+		RETURNING uuid, last_login_at, created_at, updated_at
+	`
+
+	return identity, r.db.QueryRow(
+		query,
+		identity.UserUuid,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	).Scan(&identity.Uuid, &identity.LastLoginAt, &identity.CreatedAt, &identity.UpdatedAt)
+}
+
+// TouchIdentity records a login, with the email the provider reports now
+func (r *UserIdentityRepository) TouchIdentity(identityUUID uuid.UUID, email string) error {
+	query := "UPDATE authentication.user_identities SET email = $1, last_login_at = NOW(), updated_at = NOW() WHERE uuid = $2"
+
+	_, err := r.db.Exec(query, email, identityUUID)
+	return err
+}
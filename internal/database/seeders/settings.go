@@ -33,8 +33,11 @@ func Settings(container *do.Injector) {
 		{Name: "jwtSecret", Value: os.Getenv("JWT_SECRET"), DefaultValue: os.Getenv("JWT_SECRET")},
 		{Name: "storageDriver", Value: os.Getenv("STORAGE_DRIVER"), DefaultValue: constants.StorageDriverFilesystem},
 		{Name: "mailDriver", Value: os.Getenv("MAIL_DRIVER"), DefaultValue: constants.EmailDriverSES},
+		{Name: "mailFallbackDriver", Value: os.Getenv("MAIL_FALLBACK_DRIVER"), DefaultValue: ""},
 		{Name: "maxProjectsPerOrg", Value: "10", DefaultValue: "10"},
 		{Name: "allowRegistrations", Value: "yes", DefaultValue: "yes"},
+		{Name: "requireEmailVerification", Value: "no", DefaultValue: "no"},
+		{Name: "allowMagicLinks", Value: "no", DefaultValue: "no"},
 		{Name: "allowProjects", Value: "yes", DefaultValue: "yes"},
 		{Name: "allowForms", Value: "yes", DefaultValue: "yes"},
 		{Name: "allowStorage", Value: "yes", DefaultValue: "yes"},
@@ -44,6 +47,11 @@ func Settings(container *do.Injector) {
 		{Name: "storageMaxContainers", Value: "10", DefaultValue: "10"},
 		{Name: "storageMaxFileSizeInKB", Value: "1024", DefaultValue: "1024"},
 		{Name: "storageAllowedMimes", Value: "jpg,png,pdf", DefaultValue: "jpg,png,pdf"},
+		{Name: "storageScanners", Value: os.Getenv("STORAGE_SCANNERS"), DefaultValue: ""},
+		{Name: "storageProjectMaxSizeInKB", Value: "0", DefaultValue: "0"},
+		{Name: "storageProjectMaxFiles", Value: "0", DefaultValue: "0"},
+		{Name: "storageOrganizationMaxSizeInKB", Value: "0", DefaultValue: "0"},
+		{Name: "storageOrganizationMaxFiles", Value: "0", DefaultValue: "0"},
 
 		// API throttle settings
 		{Name: "apiThrottleLimit", Value: "100", DefaultValue: "100"},
@@ -64,6 +72,30 @@ func Settings(container *do.Injector) {
 		{Name: "mailgunEmailSource", Value: os.Getenv("MAILGUN_EMAIL_SOURCE"), DefaultValue: ""},
 		{Name: "mailgunDomain", Value: os.Getenv("MAILGUN_DOMAIN"), DefaultValue: ""},
 		{Name: "mailgunRegion", Value: os.Getenv("MAILGUN_REGION"), DefaultValue: "us"},
+		{Name: "smtpHost", Value: os.Getenv("SMTP_HOST"), DefaultValue: ""},
+		{Name: "smtpPort", Value: os.Getenv("SMTP_PORT"), DefaultValue: "587"},
+		{Name: "smtpUsername", Value: os.Getenv("SMTP_USERNAME"), DefaultValue: ""},
+		{Name: "smtpPassword", Value: os.Getenv("SMTP_PASSWORD"), DefaultValue: ""},
+		{Name: "smtpEncryption", Value: os.Getenv("SMTP_ENCRYPTION"), DefaultValue: constants.SMTPEncryptionStartTLS},
+		{Name: "smtpAuth", Value: os.Getenv("SMTP_AUTH"), DefaultValue: constants.SMTPAuthPlain},
+		{Name: "smtpEmailSource", Value: os.Getenv("SMTP_EMAIL_SOURCE"), DefaultValue: ""},
+		{Name: "sesWebhookTopicArns", Value: os.Getenv("SES_WEBHOOK_TOPIC_ARNS"), DefaultValue: ""},
+		{Name: "sendgridWebhookPublicKey", Value: os.Getenv("SENDGRID_WEBHOOK_PUBLIC_KEY"), DefaultValue: ""},
+		{Name: "mailgunWebhookSigningKey", Value: os.Getenv("MAILGUN_WEBHOOK_SIGNING_KEY"), DefaultValue: ""},
+
+		// Identity provider settings, a provider shows on the login page once its client ID is set
+		{Name: "githubClientId", Value: os.Getenv("GITHUB_CLIENT_ID"), DefaultValue: ""},
+		{Name: "githubClientSecret", Value: os.Getenv("GITHUB_CLIENT_SECRET"), DefaultValue: ""},
+		{Name: "githubAllowedDomains", Value: os.Getenv("GITHUB_ALLOWED_DOMAINS"), DefaultValue: ""},
+		{Name: "googleClientId", Value: os.Getenv("GOOGLE_CLIENT_ID"), DefaultValue: ""},
+		{Name: "googleClientSecret", Value: os.Getenv("GOOGLE_CLIENT_SECRET"), DefaultValue: ""},
+		{Name: "googleAllowedDomains", Value: os.Getenv("GOOGLE_ALLOWED_DOMAINS"), DefaultValue: ""},
+		{Name: "oidcIssuer", Value: os.Getenv("OIDC_ISSUER"), DefaultValue: ""},
+		{Name: "oidcClientId", Value: os.Getenv("OIDC_CLIENT_ID"), DefaultValue: ""},
+		{Name: "oidcClientSecret", Value: os.Getenv("OIDC_CLIENT_SECRET"), DefaultValue: ""},
+		{Name: "oidcScopes", Value: os.Getenv("OIDC_SCOPES"), DefaultValue: "openid email profile"},
+		{Name: "oidcLabel", Value: os.Getenv("OIDC_LABEL"), DefaultValue: "SSO"},
+		{Name: "oidcAllowedDomains", Value: os.Getenv("OIDC_ALLOWED_DOMAINS"), DefaultValue: ""},
 	}
 
 	_, err = settingsService.CreateMany(settings)
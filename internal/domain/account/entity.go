@@ -0,0 +1,63 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Token is a single-use token emailed to a user, Purpose tells the flows apart
+type Token struct {
+	shared.BaseEntity
+	Uuid      uuid.UUID  `db:"uuid"`
+	UserUuid  uuid.UUID  `db:"user_uuid"`
+	Purpose   string     `db:"purpose"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// Identity links a user to their account with an external identity provider. Subject is the ID the
+// provider gave the user, Email is the address it last reported
+type Identity struct {
+	shared.BaseEntity
+	Uuid        uuid.UUID  `db:"uuid"`
+	UserUuid    uuid.UUID  `db:"user_uuid"`
+	Provider    string     `db:"provider"`
+	Subject     string     `db:"subject"`
+	Email       string     `db:"email"`
+	LastLoginAt *time.Time `db:"last_login_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// generateToken returns a URL safe random token and the hash it's stored as
+func generateToken() (string, string, error) {
+	token, err := randomValue()
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, hashToken(token), nil
+}
+
+func randomValue() (string, error) {
+	random := make([]byte, constants.AccountTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
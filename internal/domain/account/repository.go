@@ -0,0 +1,18 @@
+package account
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	CreateToken(token *Token) (*Token, error)
+	CountTokensSince(userUUID uuid.UUID, purpose string, since time.Time) (int, error)
+	ConsumeToken(purpose, tokenHash string) (uuid.UUID, error)
+}
+
+type IdentityRepository interface {
+	GetIdentity(provider, subject string) (Identity, error)
+	CreateIdentity(identity *Identity) (*Identity, error)
+	TouchIdentity(identityUUID uuid.UUID, email string) error
+}
@@ -0,0 +1,253 @@
+package account
+
+import (
+	stdErrors "errors"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/user"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Service runs the account flows that email users a single-use token
+type Service interface {
+	ForgotPassword(input *ForgotPasswordInput) error
+	ResetPassword(input *ResetPasswordInput) error
+	SendVerification(pendingUser user.User) error
+	ResendVerification(input *ResendVerificationInput) error
+	VerifyEmail(input *VerifyEmailInput) error
+	RequestMagicLink(input *RequestMagicLinkInput) error
+	LoginWithMagicLink(input *MagicLinkLoginInput) (user.User, string, error)
+}
+
+type ServiceImpl struct {
+	settingService setting.Service
+	accountRepo    Repository
+	userRepo       user.Repository
+	userService    user.Service
+	mailer         email.Mailer
+}
+
+func NewAccountService(injector *do.Injector) (Service, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+	accountRepo := do.MustInvoke[Repository](injector)
+	userRepo := do.MustInvoke[user.Repository](injector)
+	userService := do.MustInvoke[user.Service](injector)
+	mailer := do.MustInvoke[email.Mailer](injector)
+
+	return &ServiceImpl{
+		settingService: settingService,
+		accountRepo:    accountRepo,
+		userRepo:       userRepo,
+		userService:    userService,
+		mailer:         mailer,
+	}, nil
+}
+
+// ForgotPassword emails a password reset link. Unknown and inactive users are ignored without an error,
+// so the endpoint doesn't tell which addresses have an account
+func (s *ServiceImpl) ForgotPassword(input *ForgotPasswordInput) error {
+	fetchedUser, err := s.userRepo.GetByEmail(input.Email)
+
+	var notFoundErr *errors.NotFoundError
+	if stdErrors.As(err, &notFoundErr) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !fetchedUser.IsActive() {
+		return nil
+	}
+
+	token, err := s.issueToken(fetchedUser.Uuid, constants.AccountTokenPurposePasswordReset, constants.AccountPasswordResetTTL)
+	if err != nil || token == "" {
+		return err
+	}
+
+	return s.mailer.SendSensitiveTemplate(constants.AccountPasswordResetTemplate, email.Params{
+		"username":         fetchedUser.Username,
+		"resetUrl":         s.consoleURL(constants.AccountPasswordResetPath, token),
+		"expiresInMinutes": strconv.Itoa(int(constants.AccountPasswordResetTTL.Minutes())),
+		"appName":          s.settingService.GetValue("appTitle"),
+	}, emailAdapter.Message{To: []string{fetchedUser.Email}}, uuid.NullUUID{})
+}
+
+// ResetPassword sets the password of the user the token was issued to and signs them out everywhere
+func (s *ServiceImpl) ResetPassword(input *ResetPasswordInput) error {
+	userUUID, err := s.accountRepo.ConsumeToken(constants.AccountTokenPurposePasswordReset, hashToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	if err = s.userRepo.UpdatePassword(userUUID, input.Password); err != nil {
+		return err
+	}
+
+	// Tokens carry the JWT version they were issued with, bumping it expires the existing ones
+	_, err = s.userRepo.CreateJWTVersion(userUUID)
+
+	return err
+}
+
+// SendVerification emails the link confirming the address of a user registered while email verification
+// was required, users that don't need it are skipped
+func (s *ServiceImpl) SendVerification(pendingUser user.User) error {
+	if !pendingUser.IsPendingVerification() {
+		return nil
+	}
+
+	token, err := s.issueToken(pendingUser.Uuid, constants.AccountTokenPurposeEmailVerification, constants.AccountEmailVerificationTTL)
+	if err != nil || token == "" {
+		return err
+	}
+
+	return s.mailer.SendSensitiveTemplate(constants.AccountEmailVerificationTemplate, email.Params{
+		"username":       pendingUser.Username,
+		"verifyUrl":      s.consoleURL(constants.AccountEmailVerificationPath, token),
+		"expiresInHours": strconv.Itoa(int(constants.AccountEmailVerificationTTL.Hours())),
+		"appName":        s.settingService.GetValue("appTitle"),
+	}, emailAdapter.Message{To: []string{pendingUser.Email}}, uuid.NullUUID{})
+}
+
+// ResendVerification emails a new verification link, replacing the previous one. Like ForgotPassword it
+// answers the same for every address, and links are sent at most once per resend interval
+func (s *ServiceImpl) ResendVerification(input *ResendVerificationInput) error {
+	fetchedUser, err := s.userRepo.GetByEmail(input.Email)
+
+	var notFoundErr *errors.NotFoundError
+	if stdErrors.As(err, &notFoundErr) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.SendVerification(fetchedUser)
+}
+
+// VerifyEmail confirms the address of the user the token was issued to and activates them
+func (s *ServiceImpl) VerifyEmail(input *VerifyEmailInput) error {
+	userUUID, err := s.accountRepo.ConsumeToken(constants.AccountTokenPurposeEmailVerification, hashToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkEmailVerified(userUUID)
+}
+
+// RequestMagicLink emails a link signing the user in without their password. Like ForgotPassword it answers
+// the same for every address, and a user gets at most AccountMagicLinkMaxPerHour links an hour
+func (s *ServiceImpl) RequestMagicLink(input *RequestMagicLinkInput) error {
+	if !s.settingService.GetBool("allowMagicLinks") {
+		return errors.NewBadRequestError("account.error.magicLinksDisabled")
+	}
+
+	fetchedUser, err := s.userRepo.GetByEmail(input.Email)
+
+	var notFoundErr *errors.NotFoundError
+	if stdErrors.As(err, &notFoundErr) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Users pending verification confirm their address first, the link would do it by the way
+	if !fetchedUser.IsActive() {
+		return nil
+	}
+
+	sentLastHour, err := s.accountRepo.CountTokensSince(fetchedUser.Uuid, constants.AccountTokenPurposeMagicLink, time.Now().Add(-time.Hour))
+	if err != nil || sentLastHour >= constants.AccountMagicLinkMaxPerHour {
+		return err
+	}
+
+	token, err := s.issueToken(fetchedUser.Uuid, constants.AccountTokenPurposeMagicLink, constants.AccountMagicLinkTTL)
+	if err != nil || token == "" {
+		return err
+	}
+
+	return s.mailer.SendSensitiveTemplate(constants.AccountMagicLinkTemplate, email.Params{
+		"username":         fetchedUser.Username,
+		"loginUrl":         s.consoleURL(constants.AccountMagicLinkPath, token),
+		"expiresInMinutes": strconv.Itoa(int(constants.AccountMagicLinkTTL.Minutes())),
+		"appName":          s.settingService.GetValue("appTitle"),
+	}, emailAdapter.Message{To: []string{fetchedUser.Email}}, uuid.NullUUID{})
+}
+
+// LoginWithMagicLink signs in the user the token was issued to
+func (s *ServiceImpl) LoginWithMagicLink(input *MagicLinkLoginInput) (user.User, string, error) {
+	if !s.settingService.GetBool("allowMagicLinks") {
+		return user.User{}, "", errors.NewBadRequestError("account.error.magicLinksDisabled")
+	}
+
+	return signInWithToken(s.accountRepo, s.userRepo, s.userService, constants.AccountTokenPurposeMagicLink, input.Token)
+}
+
+// signInWithToken consumes a single-use token and starts a session for its user, the same way a password
+// login does
+func signInWithToken(accountRepo Repository, userRepo user.Repository, userService user.Service, purpose, token string) (user.User, string, error) {
+	userUUID, err := accountRepo.ConsumeToken(purpose, hashToken(token))
+	if err != nil {
+		return user.User{}, "", err
+	}
+
+	fetchedUser, err := userRepo.GetByID(userUUID)
+	if err != nil {
+		return user.User{}, "", err
+	}
+
+	// Made inactive since the token was issued
+	if !fetchedUser.IsActive() {
+		return user.User{}, "", errors.NewUnauthorizedError("user.error.invalidCredentials")
+	}
+
+	jwt, err := userService.IssueToken(&fetchedUser)
+	if err != nil {
+		return user.User{}, "", err
+	}
+
+	return fetchedUser, jwt, nil
+}
+
+// issueToken stores a new token replacing the unused ones of the purpose, and returns it. No token is
+// issued, and an empty one returned, when the last one was issued too recently
+func (s *ServiceImpl) issueToken(userUUID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	issuedRecently, err := s.accountRepo.CountTokensSince(userUUID, purpose, time.Now().Add(-constants.AccountTokenResendInterval))
+	if err != nil || issuedRecently > 0 {
+		return "", err
+	}
+
+	token, tokenHash, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.accountRepo.CreateToken(&Token{
+		UserUuid:  userUUID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *ServiceImpl) consoleURL(path, token string) string {
+	return strings.TrimRight(s.settingService.GetValue("appUrl"), "/") + path + "?token=" + url.QueryEscape(token)
+}
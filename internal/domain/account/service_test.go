@@ -0,0 +1,383 @@
+package account
+
+import (
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/email"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/user"
+	"fluxend/pkg/errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenStore keeps tokens in memory the way the repository does
+type tokenStore struct {
+	tokens []Token
+}
+
+func (t *tokenStore) CreateToken(token *Token) (*Token, error) {
+	now := time.Now()
+	for i, existing := range t.tokens {
+		if existing.UserUuid == token.UserUuid && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			t.tokens[i].ExpiresAt = now
+		}
+	}
+
+	token.CreatedAt = now
+	t.tokens = append(t.tokens, *token)
+
+	return token, nil
+}
+
+func (t *tokenStore) CountTokensSince(userUUID uuid.UUID, purpose string, since time.Time) (int, error) {
+	count := 0
+	for _, token := range t.tokens {
+		if token.UserUuid == userUUID && token.Purpose == purpose && token.CreatedAt.After(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (t *tokenStore) ConsumeToken(purpose, tokenHash string) (uuid.UUID, error) {
+	for i, token := range t.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash && token.UsedAt == nil && token.ExpiresAt.After(time.Now()) {
+			now := time.Now()
+			t.tokens[i].UsedAt = &now
+
+			return token.UserUuid, nil
+		}
+	}
+
+	return uuid.Nil, errors.NewBadRequestError("account.error.invalidToken")
+}
+
+// userStore holds a single user and the ones created, the methods the account flows don't use panic
+// through the nil interface
+type userStore struct {
+	user.Repository
+	user       user.User
+	created    []user.User
+	password   string
+	jwtVersion int
+}
+
+func (u *userStore) GetByEmail(address string) (user.User, error) {
+	if address != u.user.Email {
+		return user.User{}, errors.NewNotFoundError("user.error.notFound")
+	}
+
+	return u.user, nil
+}
+
+func (u *userStore) GetByID(userUUID uuid.UUID) (user.User, error) {
+	for _, existing := range append([]user.User{u.user}, u.created...) {
+		if existing.Uuid == userUUID {
+			return existing, nil
+		}
+	}
+
+	return user.User{}, errors.NewNotFoundError("user.error.notFound")
+}
+
+func (u *userStore) ExistsByUsername(username string) (bool, error) {
+	for _, existing := range append([]user.User{u.user}, u.created...) {
+		if existing.Username == username {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (u *userStore) Create(newUser *user.User) (*user.User, error) {
+	newUser.Uuid = uuid.New()
+	u.created = append(u.created, *newUser)
+
+	return newUser, nil
+}
+
+func (u *userStore) UpdatePassword(_ uuid.UUID, password string) error {
+	u.password = password
+
+	return nil
+}
+
+func (u *userStore) MarkEmailVerified(userUUID uuid.UUID) error {
+	now := time.Now()
+	for _, existing := range append([]*user.User{&u.user}, pointers(u.created)...) {
+		if existing.Uuid == userUUID {
+			if existing.Status == constants.UserStatusPending {
+				existing.Status = constants.UserStatusActive
+			}
+
+			existing.EmailVerifiedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func pointers(users []user.User) []*user.User {
+	pointed := make([]*user.User, len(users))
+	for i := range users {
+		pointed[i] = &users[i]
+	}
+
+	return pointed
+}
+
+func (u *userStore) CreateJWTVersion(uuid.UUID) (int, error) {
+	u.jwtVersion++
+
+	return u.jwtVersion, nil
+}
+
+type consoleSettings struct {
+	setting.Service
+	magicLinks bool
+}
+
+func (consoleSettings) GetValue(name string) string {
+	return map[string]string{"appUrl": "https://console.fluxend.app/", "appTitle": "Fluxend"}[name]
+}
+
+func (c consoleSettings) GetBool(name string) bool {
+	return name == "allowMagicLinks" && c.magicLinks
+}
+
+// sessions issues the JWT version as the token, the way user.Service starts a session
+type sessions struct {
+	user.Service
+	users *userStore
+}
+
+func (s sessions) IssueToken(issuedTo *user.User) (string, error) {
+	version, err := s.users.CreateJWTVersion(issuedTo.Uuid)
+
+	return strconv.Itoa(version), err
+}
+
+// mailbox records the templates sent instead of queueing them
+type mailbox struct {
+	email.Mailer
+	sent []email.Params
+	to   [][]string
+}
+
+func (m *mailbox) SendSensitiveTemplate(_ string, params email.Params, message emailAdapter.Message, _ uuid.NullUUID) error {
+	m.sent = append(m.sent, params)
+	m.to = append(m.to, message.To)
+
+	return nil
+}
+
+func newTestService(status string) (*ServiceImpl, *userStore, *tokenStore, *mailbox) {
+	users := &userStore{user: user.User{
+		Uuid:     uuid.New(),
+		Username: "jane",
+		Email:    "jane@example.com",
+		Status:   status,
+	}}
+	tokens := &tokenStore{}
+	sent := &mailbox{}
+
+	return &ServiceImpl{
+		settingService: consoleSettings{magicLinks: true},
+		accountRepo:    tokens,
+		userRepo:       users,
+		userService:    sessions{users: users},
+		mailer:         sent,
+	}, users, tokens, sent
+}
+
+// tokenFromLink reads the token back from an emailed link to a console page
+func tokenFromLink(t *testing.T, rawLink interface{}, path string) string {
+	link, err := url.Parse(rawLink.(string))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://console.fluxend.app"+path, link.Scheme+"://"+link.Host+link.Path)
+
+	return link.Query().Get("token")
+}
+
+func TestService_PasswordReset_Suite(t *testing.T) {
+	t.Run("PasswordReset: emailed token resets the password once", func(t *testing.T) {
+		service, users, tokens, sent := newTestService(constants.UserStatusActive)
+
+		require.NoError(t, service.ForgotPassword(&ForgotPasswordInput{Email: "jane@example.com"}))
+		require.Len(t, sent.sent, 1)
+		assert.Equal(t, []string{"jane@example.com"}, sent.to[0])
+		assert.Equal(t, "60", sent.sent[0]["expiresInMinutes"])
+
+		token := tokenFromLink(t, sent.sent[0]["resetUrl"], constants.AccountPasswordResetPath)
+		require.NotEmpty(t, token)
+		require.Len(t, tokens.tokens, 1)
+		assert.NotEqual(t, token, tokens.tokens[0].TokenHash)
+
+		require.NoError(t, service.ResetPassword(&ResetPasswordInput{Token: token, Password: "new-secret"}))
+		assert.Equal(t, "new-secret", users.password)
+		assert.Equal(t, 1, users.jwtVersion)
+
+		err := service.ResetPassword(&ResetPasswordInput{Token: token, Password: "other-secret"})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+		assert.Equal(t, "new-secret", users.password)
+	})
+
+	t.Run("PasswordReset: unknown and inactive accounts get nothing", func(t *testing.T) {
+		service, _, _, sent := newTestService(constants.UserStatusActive)
+		require.NoError(t, service.ForgotPassword(&ForgotPasswordInput{Email: "john@example.com"}))
+		assert.Empty(t, sent.sent)
+
+		service, _, _, sent = newTestService(constants.UserStatusInactive)
+		require.NoError(t, service.ForgotPassword(&ForgotPasswordInput{Email: "jane@example.com"}))
+		assert.Empty(t, sent.sent)
+	})
+
+	t.Run("PasswordReset: repeated requests are throttled", func(t *testing.T) {
+		service, _, _, sent := newTestService(constants.UserStatusActive)
+
+		require.NoError(t, service.ForgotPassword(&ForgotPasswordInput{Email: "jane@example.com"}))
+		require.NoError(t, service.ForgotPassword(&ForgotPasswordInput{Email: "jane@example.com"}))
+		assert.Len(t, sent.sent, 1)
+	})
+
+	t.Run("PasswordReset: expired token", func(t *testing.T) {
+		service, users, tokens, _ := newTestService(constants.UserStatusActive)
+
+		token, tokenHash, err := generateToken()
+		require.NoError(t, err)
+
+		tokens.tokens = append(tokens.tokens, Token{
+			UserUuid:  users.user.Uuid,
+			Purpose:   constants.AccountTokenPurposePasswordReset,
+			TokenHash: tokenHash,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		})
+
+		err = service.ResetPassword(&ResetPasswordInput{Token: token, Password: "new-secret"})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+		assert.Zero(t, users.jwtVersion)
+	})
+}
+
+func TestService_EmailVerification_Suite(t *testing.T) {
+	t.Run("EmailVerification: emailed token activates the user", func(t *testing.T) {
+		service, users, _, sent := newTestService(constants.UserStatusPending)
+
+		require.NoError(t, service.SendVerification(users.user))
+		require.Len(t, sent.sent, 1)
+		assert.Equal(t, "24", sent.sent[0]["expiresInHours"])
+
+		token := tokenFromLink(t, sent.sent[0]["verifyUrl"], constants.AccountEmailVerificationPath)
+
+		require.NoError(t, service.VerifyEmail(&VerifyEmailInput{Token: token}))
+		assert.True(t, users.user.IsActive())
+		assert.False(t, users.user.IsPendingVerification())
+
+		err := service.VerifyEmail(&VerifyEmailInput{Token: token})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+	})
+
+	t.Run("EmailVerification: users that don't need it get nothing", func(t *testing.T) {
+		service, users, _, sent := newTestService(constants.UserStatusActive)
+		require.NoError(t, service.SendVerification(users.user))
+		require.NoError(t, service.ResendVerification(&ResendVerificationInput{Email: "jane@example.com"}))
+		require.NoError(t, service.ResendVerification(&ResendVerificationInput{Email: "john@example.com"}))
+		assert.Empty(t, sent.sent)
+	})
+
+	t.Run("EmailVerification: deactivated user can't reactivate themselves", func(t *testing.T) {
+		service, users, _, sent := newTestService(constants.UserStatusInactive)
+
+		require.NoError(t, service.ResendVerification(&ResendVerificationInput{Email: "jane@example.com"}))
+		assert.Empty(t, sent.sent)
+		assert.False(t, users.user.IsPendingVerification())
+		assert.Equal(t, constants.UserStatusInactive, users.user.Status)
+	})
+
+	t.Run("EmailVerification: resends are throttled", func(t *testing.T) {
+		service, users, tokens, sent := newTestService(constants.UserStatusPending)
+
+		require.NoError(t, service.SendVerification(users.user))
+		require.NoError(t, service.ResendVerification(&ResendVerificationInput{Email: "jane@example.com"}))
+		assert.Len(t, sent.sent, 1)
+
+		// Once the interval passed a new link replaces the first one
+		tokens.tokens[0].CreatedAt = time.Now().Add(-constants.AccountTokenResendInterval - time.Second)
+		first := tokenFromLink(t, sent.sent[0]["verifyUrl"], constants.AccountEmailVerificationPath)
+
+		require.NoError(t, service.ResendVerification(&ResendVerificationInput{Email: "jane@example.com"}))
+		require.Len(t, sent.sent, 2)
+
+		err := service.VerifyEmail(&VerifyEmailInput{Token: first})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+
+		second := tokenFromLink(t, sent.sent[1]["verifyUrl"], constants.AccountEmailVerificationPath)
+		assert.NoError(t, service.VerifyEmail(&VerifyEmailInput{Token: second}))
+	})
+}
+
+func TestService_MagicLink_Suite(t *testing.T) {
+	t.Run("MagicLink: emailed token signs the user in once", func(t *testing.T) {
+		service, users, _, sent := newTestService(constants.UserStatusActive)
+
+		require.NoError(t, service.RequestMagicLink(&RequestMagicLinkInput{Email: "jane@example.com"}))
+		require.Len(t, sent.sent, 1)
+		assert.Equal(t, "15", sent.sent[0]["expiresInMinutes"])
+
+		token := tokenFromLink(t, sent.sent[0]["loginUrl"], constants.AccountMagicLinkPath)
+
+		signedIn, jwt, err := service.LoginWithMagicLink(&MagicLinkLoginInput{Token: token})
+		require.NoError(t, err)
+		assert.Equal(t, users.user.Uuid, signedIn.Uuid)
+		assert.Equal(t, "1", jwt)
+
+		_, _, err = service.LoginWithMagicLink(&MagicLinkLoginInput{Token: token})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+		assert.Equal(t, 1, users.jwtVersion)
+	})
+
+	t.Run("MagicLink: disabled by the setting", func(t *testing.T) {
+		service, _, _, sent := newTestService(constants.UserStatusActive)
+		service.settingService = consoleSettings{}
+
+		err := service.RequestMagicLink(&RequestMagicLinkInput{Email: "jane@example.com"})
+		assert.ErrorContains(t, err, "account.error.magicLinksDisabled")
+
+		_, _, err = service.LoginWithMagicLink(&MagicLinkLoginInput{Token: "token"})
+		assert.ErrorContains(t, err, "account.error.magicLinksDisabled")
+		assert.Empty(t, sent.sent)
+	})
+
+	t.Run("MagicLink: unknown and inactive accounts get nothing", func(t *testing.T) {
+		service, _, _, sent := newTestService(constants.UserStatusActive)
+		require.NoError(t, service.RequestMagicLink(&RequestMagicLinkInput{Email: "john@example.com"}))
+		assert.Empty(t, sent.sent)
+
+		service, _, _, sent = newTestService(constants.UserStatusInactive)
+		require.NoError(t, service.RequestMagicLink(&RequestMagicLinkInput{Email: "jane@example.com"}))
+		assert.Empty(t, sent.sent)
+	})
+
+	t.Run("MagicLink: capped per hour", func(t *testing.T) {
+		service, _, tokens, sent := newTestService(constants.UserStatusActive)
+
+		for range constants.AccountMagicLinkMaxPerHour + 1 {
+			require.NoError(t, service.RequestMagicLink(&RequestMagicLinkInput{Email: "jane@example.com"}))
+
+			// Past the resend interval, only the hourly cap holds the next request back
+			tokens.tokens[len(tokens.tokens)-1].CreatedAt = time.Now().Add(-constants.AccountTokenResendInterval - time.Second)
+		}
+
+		assert.Len(t, sent.sent, constants.AccountMagicLinkMaxPerHour)
+	})
+}
@@ -0,0 +1,374 @@
+package account
+
+import (
+	"context"
+	stdErrors "errors"
+	"fluxend/internal/adapters/identity"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/user"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/samber/do"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SocialService signs users in with external identity providers. Identities are linked to users by the
+// provider's subject, or by a verified email the first time, and users are created when registrations are allowed
+type SocialService interface {
+	Providers() []SocialProvider
+	Authorize(ctx context.Context, providerName string) (string, string, error)
+	Callback(ctx context.Context, input *SocialCallbackInput) (string, error)
+	Login(input *SocialLoginInput) (user.User, string, error)
+	ConsoleURL(query url.Values) string
+}
+
+type SocialServiceImpl struct {
+	settingService      setting.Service
+	accountRepo         Repository
+	identityRepo        IdentityRepository
+	userRepo            user.Repository
+	userService         user.Service
+	organizationService organization.Service
+	createProvider      func(name string) (identity.Provider, error)
+}
+
+func NewSocialService(injector *do.Injector) (SocialService, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+	accountRepo := do.MustInvoke[Repository](injector)
+	identityRepo := do.MustInvoke[IdentityRepository](injector)
+	userRepo := do.MustInvoke[user.Repository](injector)
+	userService := do.MustInvoke[user.Service](injector)
+	organizationService := do.MustInvoke[organization.Service](injector)
+	factory := do.MustInvoke[*identity.Factory](injector)
+
+	return &SocialServiceImpl{
+		settingService:      settingService,
+		accountRepo:         accountRepo,
+		identityRepo:        identityRepo,
+		userRepo:            userRepo,
+		userService:         userService,
+		organizationService: organizationService,
+		createProvider:      factory.CreateProvider,
+	}, nil
+}
+
+// Providers lists the configured providers for the login page
+func (s *SocialServiceImpl) Providers() []SocialProvider {
+	var providers []SocialProvider
+	for _, name := range identity.Providers {
+		if provider, err := s.createProvider(name); err == nil {
+			providers = append(providers, SocialProvider{Name: provider.Name(), Label: provider.Label()})
+		}
+	}
+
+	return providers
+}
+
+// Authorize starts a login, returning the provider URL to send the user to and the sealed state the
+// callback expects back from the same browser
+func (s *SocialServiceImpl) Authorize(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := newSocialState(providerName, constants.IdentityStateTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, identity.AuthRequest{
+		RedirectURL:   callbackURL(providerName),
+		State:         state.State,
+		Nonce:         state.Nonce,
+		CodeChallenge: identity.CodeChallenge(state.CodeVerifier),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", providerName, err)
+	}
+
+	sealedState, err := state.seal()
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, sealedState, nil
+}
+
+// Callback finishes a login and returns the single-use code the console exchanges for a JWT
+func (s *SocialServiceImpl) Callback(ctx context.Context, input *SocialCallbackInput) (string, error) {
+	provider, err := s.provider(input.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	if input.Error != "" {
+		return "", errors.NewUnauthorizedError("account.error.socialLoginDenied")
+	}
+
+	state, err := openSocialState(input.SealedState, input.Provider, input.State)
+	if err != nil {
+		return "", errors.NewBadRequestError("account.error.invalidSocialState")
+	}
+
+	profile, err := provider.Exchange(ctx, identity.ExchangeRequest{
+		RedirectURL:  callbackURL(input.Provider),
+		Code:         input.Code,
+		CodeVerifier: state.CodeVerifier,
+		Nonce:        state.Nonce,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", input.Provider, err)
+	}
+
+	if !s.domainAllowed(input.Provider, profile) {
+		return "", errors.NewForbiddenError("account.error.domainNotAllowed")
+	}
+
+	signedIn, err := s.resolveUser(input.Provider, profile)
+	if err != nil {
+		return "", err
+	}
+
+	code, codeHash, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.accountRepo.CreateToken(&Token{
+		UserUuid:  signedIn.Uuid,
+		Purpose:   constants.AccountTokenPurposeSocialLogin,
+		TokenHash: codeHash,
+		ExpiresAt: time.Now().Add(constants.AccountSocialLoginTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Login exchanges the code of a finished social login for a JWT
+func (s *SocialServiceImpl) Login(input *SocialLoginInput) (user.User, string, error) {
+	return signInWithToken(s.accountRepo, s.userRepo, s.userService, constants.AccountTokenPurposeSocialLogin, input.Token)
+}
+
+// ConsoleURL is the console page a social login ends on
+func (s *SocialServiceImpl) ConsoleURL(query url.Values) string {
+	return strings.TrimRight(s.settingService.GetValue("appUrl"), "/") + constants.AccountSocialLoginPath + "?" + query.Encode()
+}
+
+func (s *SocialServiceImpl) provider(name string) (identity.Provider, error) {
+	provider, err := s.createProvider(name)
+	if err != nil {
+		return nil, errors.NewNotFoundError("account.error.providerNotFound")
+	}
+
+	return provider, nil
+}
+
+// domainAllowed checks the organization domain the provider vouches for against the <provider>AllowedDomains
+// setting, a comma separated list where empty allows everyone
+func (s *SocialServiceImpl) domainAllowed(providerName string, profile identity.Profile) bool {
+	var allowed []string
+	for _, domain := range strings.Split(s.settingService.GetValue(providerName+"AllowedDomains"), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			allowed = append(allowed, domain)
+		}
+	}
+
+	return len(allowed) == 0 || (profile.Domain != "" && slices.Contains(allowed, profile.Domain))
+}
+
+// resolveUser finds the user an identity belongs to. An identity seen for the first time is linked to the user
+// with the same email, or to a new user, but only when the provider verified the email
+func (s *SocialServiceImpl) resolveUser(providerName string, profile identity.Profile) (user.User, error) {
+	var notFoundErr *errors.NotFoundError
+
+	linked, err := s.identityRepo.GetIdentity(providerName, profile.Subject)
+	if err == nil {
+		if err = s.identityRepo.TouchIdentity(linked.Uuid, profile.Email); err != nil {
+			return user.User{}, err
+		}
+
+		fetchedUser, err := s.userRepo.GetByID(linked.UserUuid)
+		if err != nil {
+			return user.User{}, err
+		}
+
+		return s.activeUser(fetchedUser, profile)
+	}
+
+	if !stdErrors.As(err, &notFoundErr) {
+		return user.User{}, err
+	}
+
+	if profile.Email == "" || !profile.EmailVerified {
+		return user.User{}, errors.NewUnauthorizedError("account.error.socialEmailNotVerified")
+	}
+
+	fetchedUser, err := s.userRepo.GetByEmail(profile.Email)
+	switch {
+	case stdErrors.As(err, &notFoundErr):
+		fetchedUser, err = s.provisionUser(profile)
+	case err == nil && fetchedUser.IsPendingVerification():
+		fetchedUser, err = s.claimUnverified(fetchedUser)
+	}
+
+	if err != nil {
+		return user.User{}, err
+	}
+
+	_, err = s.identityRepo.CreateIdentity(&Identity{
+		UserUuid: fetchedUser.Uuid,
+		Provider: providerName,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return s.activeUser(fetchedUser, profile)
+}
+
+// claimUnverified hands a user registered while verification was required, who never confirmed their email,
+// to the owner of the address. Someone could have registered it first to share the account, so the password
+// they chose stops working and their sessions end. The owner can set a password with a reset. Accounts that
+// never had to confirm their address are linked as they are
+func (s *SocialServiceImpl) claimUnverified(fetchedUser user.User) (user.User, error) {
+	password, err := randomValue()
+	if err != nil {
+		return user.User{}, err
+	}
+
+	if err = s.userRepo.UpdatePassword(fetchedUser.Uuid, password); err != nil {
+		return user.User{}, err
+	}
+
+	if _, err = s.userRepo.CreateJWTVersion(fetchedUser.Uuid); err != nil {
+		return user.User{}, err
+	}
+
+	if err = s.userRepo.MarkEmailVerified(fetchedUser.Uuid); err != nil {
+		return user.User{}, err
+	}
+
+	return s.userRepo.GetByID(fetchedUser.Uuid)
+}
+
+// activeUser refuses disabled users. A user still pending email verification is verified by a provider
+// vouching for the same address
+func (s *SocialServiceImpl) activeUser(fetchedUser user.User, profile identity.Profile) (user.User, error) {
+	if fetchedUser.IsPendingVerification() && profile.EmailVerified && strings.EqualFold(fetchedUser.Email, profile.Email) {
+		if err := s.userRepo.MarkEmailVerified(fetchedUser.Uuid); err != nil {
+			return user.User{}, err
+		}
+
+		fetchedUser.Status = constants.UserStatusActive
+	}
+
+	if !fetchedUser.IsActive() {
+		return user.User{}, errors.NewUnauthorizedError("user.error.invalidCredentials")
+	}
+
+	return fetchedUser, nil
+}
+
+// provisionUser registers a user the way the register endpoint does, with a default organization. The
+// password is random, the user can set one through a password reset
+func (s *SocialServiceImpl) provisionUser(profile identity.Profile) (user.User, error) {
+	if !s.settingService.GetBool("allowRegistrations") {
+		return user.User{}, errors.NewBadRequestError("user.error.registrationDisabled")
+	}
+
+	username, err := s.availableUsername(profile)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	password, err := randomValue()
+	if err != nil {
+		return user.User{}, err
+	}
+
+	newUser := user.User{
+		Username: username,
+		Email:    profile.Email,
+		Password: password,
+		Status:   constants.UserStatusActive,
+		RoleID:   constants.UserRoleOwner,
+	}
+
+	if _, err = s.userRepo.Create(&newUser); err != nil {
+		return user.User{}, err
+	}
+
+	if err = s.userRepo.MarkEmailVerified(newUser.Uuid); err != nil {
+		return user.User{}, err
+	}
+
+	_, err = s.organizationService.Create(constants.DefaultOrganizationName, auth.User{Uuid: newUser.Uuid, RoleID: newUser.RoleID})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return newUser, nil
+}
+
+var usernameDisallowed = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// availableUsername derives a username the register endpoint would accept from the provider's username,
+// or the email, numbering it when it's taken
+func (s *SocialServiceImpl) availableUsername(profile identity.Profile) (string, error) {
+	base := profile.Username
+	if base == "" {
+		base, _, _ = strings.Cut(profile.Email, "@")
+	}
+
+	base = strings.Trim(usernameDisallowed.ReplaceAllString(base, "_"), "_")
+	if len(base) > 90 {
+		base = base[:90]
+	}
+
+	if len(base) < 3 {
+		base = "user_" + base
+	}
+
+	for attempt := 1; attempt <= constants.AccountSocialUsernameAttempts; attempt++ {
+		username := base
+		if attempt > 1 {
+			username = base + "-" + strconv.Itoa(attempt)
+		}
+
+		taken, err := s.userRepo.ExistsByUsername(username)
+		if err != nil {
+			return "", err
+		}
+
+		if !taken {
+			return username, nil
+		}
+	}
+
+	suffix, err := randomValue()
+	if err != nil {
+		return "", err
+	}
+
+	return base + "-" + strings.ToLower(suffix[:8]), nil
+}
+
+// callbackURL is where the provider sends the user back to, it must be registered with the provider
+func callbackURL(providerName string) string {
+	return strings.TrimRight(os.Getenv("API_URL"), "/") + "/users/oauth/" + providerName + "/callback"
+}
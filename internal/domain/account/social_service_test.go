@@ -0,0 +1,358 @@
+package account
+
+import (
+	"context"
+	"fluxend/internal/adapters/identity"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/user"
+	"fluxend/pkg/errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityStore keeps identities in memory
+type identityStore struct {
+	identities []Identity
+}
+
+func (i *identityStore) GetIdentity(provider, subject string) (Identity, error) {
+	for _, existing := range i.identities {
+		if existing.Provider == provider && existing.Subject == subject {
+			return existing, nil
+		}
+	}
+
+	return Identity{}, errors.NewNotFoundError("account.error.identityNotFound")
+}
+
+func (i *identityStore) CreateIdentity(linked *Identity) (*Identity, error) {
+	linked.Uuid = uuid.New()
+	i.identities = append(i.identities, *linked)
+
+	return linked, nil
+}
+
+func (i *identityStore) TouchIdentity(identityUUID uuid.UUID, email string) error {
+	for index := range i.identities {
+		if i.identities[index].Uuid == identityUUID {
+			now := time.Now()
+			i.identities[index].Email = email
+			i.identities[index].LastLoginAt = &now
+		}
+	}
+
+	return nil
+}
+
+// organizations records the organizations created for new users
+type organizations struct {
+	organization.Service
+	createdFor []uuid.UUID
+}
+
+func (o *organizations) Create(name string, authUser auth.User) (organization.Organization, error) {
+	o.createdFor = append(o.createdFor, authUser.Uuid)
+
+	return organization.Organization{Name: name}, nil
+}
+
+type socialSettings struct {
+	setting.Service
+	registrations  bool
+	allowedDomains string
+}
+
+func (s socialSettings) GetValue(name string) string {
+	if name == "googleAllowedDomains" {
+		return s.allowedDomains
+	}
+
+	return consoleSettings{}.GetValue(name)
+}
+
+func (s socialSettings) GetBool(name string) bool {
+	return name == "allowRegistrations" && s.registrations
+}
+
+// fakeProvider signs in whoever the test sets as its profile, checking the PKCE challenge and nonce it was sent
+type fakeProvider struct {
+	profile       identity.Profile
+	codeChallenge string
+	nonce         string
+}
+
+func (f *fakeProvider) Name() string {
+	return constants.IdentityProviderGoogle
+}
+
+func (f *fakeProvider) Label() string {
+	return "Google"
+}
+
+func (f *fakeProvider) AuthCodeURL(_ context.Context, request identity.AuthRequest) (string, error) {
+	f.codeChallenge, f.nonce = request.CodeChallenge, request.Nonce
+
+	return "https://accounts.google.com/o/oauth2/v2/auth?" + url.Values{
+		"state":        {request.State},
+		"redirect_uri": {request.RedirectURL},
+	}.Encode(), nil
+}
+
+func (f *fakeProvider) Exchange(_ context.Context, request identity.ExchangeRequest) (identity.Profile, error) {
+	if identity.CodeChallenge(request.CodeVerifier) != f.codeChallenge || request.Nonce != f.nonce {
+		return identity.Profile{}, errors.NewUnauthorizedError("invalid_grant")
+	}
+
+	return f.profile, nil
+}
+
+type socialFixture struct {
+	service    *SocialServiceImpl
+	users      *userStore
+	identities *identityStore
+	orgs       *organizations
+	provider   *fakeProvider
+}
+
+func newSocialFixture(t *testing.T) *socialFixture {
+	t.Setenv("JWT_SECRET", "test_jwt_secret_key_that_is_long_enough_for_validation")
+	t.Setenv("API_URL", "https://api.fluxend.app")
+
+	now := time.Now()
+	fixture := &socialFixture{
+		users: &userStore{user: user.User{
+			Uuid:            uuid.New(),
+			Username:        "jane",
+			Email:           "jane@example.com",
+			Status:          constants.UserStatusActive,
+			EmailVerifiedAt: &now,
+		}},
+		identities: &identityStore{},
+		orgs:       &organizations{},
+		provider: &fakeProvider{profile: identity.Profile{
+			Subject:       "108234",
+			Email:         "jane@example.com",
+			EmailVerified: true,
+			Username:      "jane",
+			Domain:        "example.com",
+		}},
+	}
+
+	fixture.service = &SocialServiceImpl{
+		settingService:      socialSettings{registrations: true},
+		accountRepo:         &tokenStore{},
+		identityRepo:        fixture.identities,
+		userRepo:            fixture.users,
+		userService:         sessions{users: fixture.users},
+		organizationService: fixture.orgs,
+		createProvider: func(name string) (identity.Provider, error) {
+			if name != constants.IdentityProviderGoogle {
+				return nil, identity.ErrProviderNotConfigured
+			}
+
+			return fixture.provider, nil
+		},
+	}
+
+	return fixture
+}
+
+// signIn goes through the login the way the browser does and returns the code the console gets
+func (f *socialFixture) signIn(t *testing.T) (string, error) {
+	authURL, sealedState, err := f.service.Authorize(context.Background(), constants.IdentityProviderGoogle)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.fluxend.app/users/oauth/google/callback", parsed.Query().Get("redirect_uri"))
+
+	return f.service.Callback(context.Background(), &SocialCallbackInput{
+		Provider:    constants.IdentityProviderGoogle,
+		Code:        "code-1",
+		State:       parsed.Query().Get("state"),
+		SealedState: sealedState,
+	})
+}
+
+func TestSocialService_Suite(t *testing.T) {
+	t.Run("Social: verified email links the existing user, the code signs in once", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+
+		code, err := fixture.signIn(t)
+		require.NoError(t, err)
+
+		require.Len(t, fixture.identities.identities, 1)
+		assert.Equal(t, fixture.users.user.Uuid, fixture.identities.identities[0].UserUuid)
+		assert.Equal(t, "108234", fixture.identities.identities[0].Subject)
+		assert.Empty(t, fixture.users.created)
+		assert.Empty(t, fixture.users.password)
+
+		signedIn, jwt, err := fixture.service.Login(&SocialLoginInput{Token: code})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.users.user.Uuid, signedIn.Uuid)
+		assert.Equal(t, "1", jwt)
+
+		_, _, err = fixture.service.Login(&SocialLoginInput{Token: code})
+		assert.ErrorContains(t, err, "account.error.invalidToken")
+	})
+
+	t.Run("Social: linked identity signs in by subject, whatever the email", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.identities.identities = []Identity{{Uuid: uuid.New(), UserUuid: fixture.users.user.Uuid, Provider: constants.IdentityProviderGoogle, Subject: "108234"}}
+		fixture.provider.profile.Email = "jane.doe@example.com"
+		fixture.provider.profile.EmailVerified = false
+
+		code, err := fixture.signIn(t)
+		require.NoError(t, err)
+
+		signedIn, _, err := fixture.service.Login(&SocialLoginInput{Token: code})
+		require.NoError(t, err)
+		assert.Equal(t, fixture.users.user.Uuid, signedIn.Uuid)
+		assert.Equal(t, "jane.doe@example.com", fixture.identities.identities[0].Email)
+		assert.NotNil(t, fixture.identities.identities[0].LastLoginAt)
+	})
+
+	t.Run("Social: pending user is claimed by the owner of the address", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.users.user.Status = constants.UserStatusPending
+		fixture.users.user.EmailVerifiedAt = nil
+
+		_, err := fixture.signIn(t)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, fixture.users.password)
+		assert.Equal(t, 1, fixture.users.jwtVersion)
+		assert.NotNil(t, fixture.users.user.EmailVerifiedAt)
+		assert.True(t, fixture.users.user.IsActive())
+	})
+
+	t.Run("Social: account that never needed verification keeps its password", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.users.user.EmailVerifiedAt = nil
+
+		_, err := fixture.signIn(t)
+		require.NoError(t, err)
+
+		assert.Empty(t, fixture.users.password)
+		assert.Zero(t, fixture.users.jwtVersion)
+		require.Len(t, fixture.identities.identities, 1)
+		assert.Equal(t, fixture.users.user.Uuid, fixture.identities.identities[0].UserUuid)
+	})
+
+	t.Run("Social: new user created with a default organization", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.provider.profile = identity.Profile{Subject: "4417", Email: "john.doe@example.com", EmailVerified: true}
+		fixture.users.user.Username = "john_doe"
+
+		code, err := fixture.signIn(t)
+		require.NoError(t, err)
+
+		require.Len(t, fixture.users.created, 1)
+		created := fixture.users.created[0]
+		assert.Equal(t, "john_doe-2", created.Username)
+		assert.Equal(t, "john.doe@example.com", created.Email)
+		assert.Equal(t, constants.UserRoleOwner, created.RoleID)
+		assert.NotNil(t, created.EmailVerifiedAt)
+		assert.Equal(t, []uuid.UUID{created.Uuid}, fixture.orgs.createdFor)
+
+		signedIn, _, err := fixture.service.Login(&SocialLoginInput{Token: code})
+		require.NoError(t, err)
+		assert.Equal(t, created.Uuid, signedIn.Uuid)
+	})
+
+	t.Run("Social: no new user when registrations are disabled", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.service.settingService = socialSettings{}
+		fixture.provider.profile.Email = "john@example.com"
+
+		_, err := fixture.signIn(t)
+		assert.ErrorContains(t, err, "user.error.registrationDisabled")
+		assert.Empty(t, fixture.identities.identities)
+	})
+
+	t.Run("Social: unverified provider email is refused", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.provider.profile.EmailVerified = false
+
+		_, err := fixture.signIn(t)
+		assert.ErrorContains(t, err, "account.error.socialEmailNotVerified")
+		assert.Empty(t, fixture.identities.identities)
+	})
+
+	t.Run("Social: only allowed domains sign in", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+		fixture.service.settingService = socialSettings{registrations: true, allowedDomains: " Fluxend.app, example.org"}
+
+		_, err := fixture.signIn(t)
+		assert.ErrorContains(t, err, "account.error.domainNotAllowed")
+
+		fixture.provider.profile.Domain = "fluxend.app"
+		_, err = fixture.signIn(t)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Social: state must come back from the same browser", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+
+		_, sealedState, err := fixture.service.Authorize(context.Background(), constants.IdentityProviderGoogle)
+		require.NoError(t, err)
+
+		_, err = fixture.service.Callback(context.Background(), &SocialCallbackInput{
+			Provider:    constants.IdentityProviderGoogle,
+			Code:        "code-1",
+			State:       "forged",
+			SealedState: sealedState,
+		})
+		assert.ErrorContains(t, err, "account.error.invalidSocialState")
+
+		_, err = fixture.service.Callback(context.Background(), &SocialCallbackInput{
+			Provider: constants.IdentityProviderGoogle,
+			Error:    "access_denied",
+		})
+		assert.ErrorContains(t, err, "account.error.socialLoginDenied")
+		assert.Empty(t, fixture.identities.identities)
+	})
+
+	t.Run("Social: only configured providers", func(t *testing.T) {
+		fixture := newSocialFixture(t)
+
+		assert.Equal(t, []SocialProvider{{Name: constants.IdentityProviderGoogle, Label: "Google"}}, fixture.service.Providers())
+
+		_, _, err := fixture.service.Authorize(context.Background(), constants.IdentityProviderGitHub)
+		assert.ErrorContains(t, err, "account.error.providerNotFound")
+	})
+}
+
+func TestSocialState_SealedForItsProvider(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_jwt_secret_key_that_is_long_enough_for_validation")
+
+	state, err := newSocialState(constants.IdentityProviderGoogle, time.Minute)
+	require.NoError(t, err)
+
+	sealed, err := state.seal()
+	require.NoError(t, err)
+
+	opened, err := openSocialState(sealed, constants.IdentityProviderGoogle, state.State)
+	require.NoError(t, err)
+	assert.Equal(t, state.CodeVerifier, opened.CodeVerifier)
+
+	_, err = openSocialState(sealed, constants.IdentityProviderGitHub, state.State)
+	assert.Error(t, err)
+
+	expired, err := newSocialState(constants.IdentityProviderGoogle, -time.Second)
+	require.NoError(t, err)
+
+	sealed, err = expired.seal()
+	require.NoError(t, err)
+
+	_, err = openSocialState(sealed, constants.IdentityProviderGoogle, expired.State)
+	assert.ErrorContains(t, err, "expired")
+}
@@ -0,0 +1,93 @@
+package account
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fluxend/pkg"
+	"fmt"
+	"os"
+	"time"
+)
+
+// socialState is kept in a cookie between the start of a social login and its callback, encrypted so the
+// code verifier stays secret and tied to the provider so it can't be replayed against another one
+type socialState struct {
+	Provider     string    `json:"provider"`
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"codeVerifier"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+func newSocialState(provider string, ttl time.Duration) (socialState, error) {
+	state := socialState{Provider: provider, ExpiresAt: time.Now().Add(ttl)}
+
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		random, err := randomValue()
+		if err != nil {
+			return socialState{}, err
+		}
+
+		*value = random
+	}
+
+	return state, nil
+}
+
+func (s socialState) seal() (string, error) {
+	key, err := socialStateKey()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := pkg.Encrypt(key, payload, []byte(s.Provider))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// openSocialState reads back the state sealed for a provider, if it's still valid and matches the
+// state the provider sent back
+func openSocialState(sealed, provider, returnedState string) (socialState, error) {
+	key, err := socialStateKey()
+	if err != nil {
+		return socialState{}, err
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return socialState{}, err
+	}
+
+	payload, err := pkg.Decrypt(key, ciphertext, []byte(provider))
+	if err != nil {
+		return socialState{}, err
+	}
+
+	var state socialState
+	if err = json.Unmarshal(payload, &state); err != nil {
+		return socialState{}, err
+	}
+
+	if time.Now().After(state.ExpiresAt) {
+		return socialState{}, fmt.Errorf("state expired")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state.State), []byte(returnedState)) != 1 {
+		return socialState{}, fmt.Errorf("state mismatch")
+	}
+
+	return state, nil
+}
+
+func socialStateKey() ([]byte, error) {
+	return pkg.DeriveKey([]byte(os.Getenv("JWT_SECRET")), nil, "social login state")
+}
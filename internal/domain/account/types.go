@@ -0,0 +1,46 @@
+package account
+
+type ForgotPasswordInput struct {
+	Email string
+}
+
+type ResetPasswordInput struct {
+	Token    string
+	Password string
+}
+
+type ResendVerificationInput struct {
+	Email string
+}
+
+type VerifyEmailInput struct {
+	Token string
+}
+
+type RequestMagicLinkInput struct {
+	Email string
+}
+
+type MagicLinkLoginInput struct {
+	Token string
+}
+
+// SocialProvider is an identity provider users can sign in with, Label is shown on its button
+type SocialProvider struct {
+	Name  string
+	Label string
+}
+
+// SocialCallbackInput is what the provider redirected back with, SealedState comes from the cookie
+// set when the login started. Error is set instead of Code when the user didn't sign in
+type SocialCallbackInput struct {
+	Provider    string
+	Code        string
+	State       string
+	Error       string
+	SealedState string
+}
+
+type SocialLoginInput struct {
+	Token string
+}
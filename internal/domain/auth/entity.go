@@ -3,6 +3,8 @@ package auth
 import (
 	"fluxend/internal/config/constants"
 	"github.com/google/uuid"
+	"math"
+	"strconv"
 )
 
 type User struct {
@@ -29,3 +31,64 @@ func (au User) IsDeveloperOrMore() bool {
 func (au User) IsExplorerOrMore() bool {
 	return au.RoleID <= constants.UserRoleExplorer
 }
+
+// EndUser is a user of an app built on a project, identified by the role and claims of a PostgREST-style JWT.
+// Requests without a token are made by an anonymous end user with the default PostgREST role
+type EndUser struct {
+	Role      string
+	Claims    map[string]interface{}
+	Anonymous bool
+}
+
+// ProjectUUID returns the project the token was issued for, from its project_uuid claim or else its audience
+func (eu EndUser) ProjectUUID() (uuid.UUID, bool) {
+	value, ok := eu.Claim("project_uuid")
+	if !ok {
+		value, ok = eu.audience()
+	}
+
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	projectUUID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return projectUUID, true
+}
+
+// Claim returns a string or whole number claim of the token as a string
+func (eu EndUser) Claim(name string) (string, bool) {
+	switch value := eu.Claims[name].(type) {
+	case string:
+		return value, true
+	case float64:
+		if value != math.Trunc(value) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// audience returns the aud claim, which is either a string or a list holding a single string
+func (eu EndUser) audience() (string, bool) {
+	switch value := eu.Claims["aud"].(type) {
+	case string:
+		return value, true
+	case []interface{}:
+		if len(value) != 1 {
+			return "", false
+		}
+
+		audience, ok := value[0].(string)
+
+		return audience, ok
+	default:
+		return "", false
+	}
+}
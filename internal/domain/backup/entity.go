@@ -8,10 +8,12 @@ import (
 
 type Backup struct {
 	shared.BaseEntity
-	Uuid        uuid.UUID  `db:"uuid" json:"uuid"`
-	ProjectUuid uuid.UUID  `db:"project_uuid" json:"projectUuid"`
-	Status      string     `db:"status" json:"status"`
-	Error       string     `db:"error" json:"error"`
-	StartedAt   time.Time  `db:"started_at" json:"startedAt"`
-	CompletedAt *time.Time `db:"completed_at" json:"completedAt"`
+	Uuid        uuid.UUID `db:"uuid" json:"uuid"`
+	ProjectUuid uuid.UUID `db:"project_uuid" json:"projectUuid"`
+	Status      string    `db:"status" json:"status"`
+	Error       string    `db:"error" json:"error"`
+	// Driver the dump was uploaded with, empty for backups taken before it was recorded
+	StorageDriver string     `db:"storage_driver" json:"storageDriver"`
+	StartedAt     time.Time  `db:"started_at" json:"startedAt"`
+	CompletedAt   *time.Time `db:"completed_at" json:"completedAt"`
 }
@@ -8,8 +8,10 @@ import (
 type Repository interface {
 	ListForProject(projectUUID uuid.UUID) ([]Backup, error)
 	GetByUUID(backupUUID uuid.UUID) (Backup, error)
+	ListForStorageDriver(storageDriver string, includeUnrecorded bool) ([]Backup, error)
 	ExistsByUUID(backupUUID uuid.UUID) (bool, error)
 	Create(backup *Backup) (*Backup, error)
 	UpdateStatus(backupUUID uuid.UUID, status, error string, completedAt time.Time) error
+	UpdateStorageDriver(backupUUID uuid.UUID, storageDriver string) error
 	Delete(backupUUID uuid.UUID) (bool, error)
 }
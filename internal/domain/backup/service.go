@@ -4,6 +4,7 @@ import (
 	"fluxend/internal/config/constants"
 	"fluxend/internal/domain/auth"
 	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/setting"
 	"fluxend/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/samber/do"
@@ -18,6 +19,7 @@ type Service interface {
 }
 
 type ServiceImpl struct {
+	settingService        setting.Service
 	projectPolicy         *project.Policy
 	backupRepo            Repository
 	projectRepo           project.Repository
@@ -25,12 +27,18 @@ type ServiceImpl struct {
 }
 
 func NewBackupService(injector *do.Injector) (Service, error) {
+	settingService, err := setting.NewSettingService(injector)
+	if err != nil {
+		return nil, err
+	}
+
 	policy := do.MustInvoke[*project.Policy](injector)
 	backupRepo := do.MustInvoke[Repository](injector)
 	projectRepo := do.MustInvoke[project.Repository](injector)
 	backupWorkFlowService := do.MustInvoke[WorkflowService](injector)
 
 	return &ServiceImpl{
+		settingService:        settingService,
 		projectPolicy:         policy,
 		backupRepo:            backupRepo,
 		projectRepo:           projectRepo,
@@ -80,10 +88,11 @@ func (s *ServiceImpl) Create(projectUUID uuid.UUID, authUser auth.User) (Backup,
 	}
 
 	backup := Backup{
-		ProjectUuid: projectUUID,
-		Status:      constants.BackupStatusCreating,
-		Error:       "",
-		StartedAt:   time.Now(),
+		ProjectUuid:   projectUUID,
+		Status:        constants.BackupStatusCreating,
+		Error:         "",
+		StorageDriver: s.settingService.GetStorageDriver(),
+		StartedAt:     time.Now(),
 	}
 
 	createdBackup, err := s.backupRepo.Create(&backup)
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/do"
+	"io"
 	"os"
 	"time"
 )
@@ -59,21 +60,23 @@ func (s *WorkflowServiceImpl) Create(databaseName string, backupUUID uuid.UUID)
 	}
 
 	// 3. Ensure backup container exists
-	if err := s.ensureBackupContainerExists(); err != nil {
+	if err := s.ensureBackupContainerExists(backupUUID); err != nil {
 		s.handleBackupFailure(backupUUID, constants.BackupStatusCreatingFailed, err.Error())
 
 		return
 	}
 
-	fileBytes, err := s.readBackupFile(backupUUID)
+	backupFile, fileSize, err := s.openBackupFile(backupUUID)
 	if err != nil {
 		s.handleBackupFailure(backupUUID, constants.BackupStatusCreatingFailed, err.Error())
 
 		return
 	}
 
-	// 4. Upload backup to S3
-	if err = s.uploadBackup(databaseName, backupUUID, fileBytes); err != nil {
+	// 4. Upload backup to S3, streamed from disk so large dumps don't have to fit in memory
+	err = s.uploadBackup(databaseName, backupUUID, backupFile, fileSize)
+	backupFile.Close()
+	if err != nil {
 		s.handleBackupFailure(backupUUID, constants.BackupStatusCreatingFailed, err.Error())
 
 		return
@@ -99,7 +102,7 @@ func (s *WorkflowServiceImpl) Create(databaseName string, backupUUID uuid.UUID)
 func (s *WorkflowServiceImpl) Delete(databaseName string, backupUUID uuid.UUID) {
 	filePath := fmt.Sprintf("%s/%s.sql", databaseName, backupUUID)
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+	storageService, err := s.storageProvider(backupUUID)
 	if err != nil {
 		log.Error().
 			Str("action", constants.ActionBackup).
@@ -176,8 +179,8 @@ func (s *WorkflowServiceImpl) copyBackupToAppContainer(backupFilePath string, ba
 	return nil
 }
 
-func (s *WorkflowServiceImpl) ensureBackupContainerExists() error {
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+func (s *WorkflowServiceImpl) ensureBackupContainerExists(backupUUID uuid.UUID) error {
+	storageService, err := s.storageProvider(backupUUID)
 	if err != nil {
 		log.Error().
 			Str("action", constants.ActionBackup).
@@ -204,8 +207,24 @@ func (s *WorkflowServiceImpl) ensureBackupContainerExists() error {
 	return nil
 }
 
-func (s *WorkflowServiceImpl) readBackupFile(backupUUID uuid.UUID) ([]byte, error) {
-	fileBytes, err := os.ReadFile(fmt.Sprintf("/tmp/%s.sql", backupUUID))
+// storageProvider returns the provider a backup was written with, so changing the
+// storage driver doesn't leave earlier backups unreachable
+func (s *WorkflowServiceImpl) storageProvider(backupUUID uuid.UUID) (storage.Provider, error) {
+	fetchedBackup, err := s.backupRepo.GetByUUID(backupUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	storageDriver := fetchedBackup.StorageDriver
+	if storageDriver == "" {
+		storageDriver = s.settingService.GetStorageDriver()
+	}
+
+	return s.storageFactory.CreateProvider(storageDriver)
+}
+
+func (s *WorkflowServiceImpl) openBackupFile(backupUUID uuid.UUID) (*os.File, int64, error) {
+	backupFile, err := os.Open(fmt.Sprintf("/tmp/%s.sql", backupUUID))
 	if err != nil {
 		log.Error().
 			Str("action", constants.ActionBackup).
@@ -213,16 +232,28 @@ func (s *WorkflowServiceImpl) readBackupFile(backupUUID uuid.UUID) ([]byte, erro
 			Str("error", err.Error()).
 			Msg("failed to read backup file from fluxend_api container")
 
-		return nil, err
+		return nil, 0, err
+	}
+
+	fileInfo, err := backupFile.Stat()
+	if err != nil {
+		backupFile.Close()
+		log.Error().
+			Str("action", constants.ActionBackup).
+			Str("backup_uuid", backupUUID.String()).
+			Str("error", err.Error()).
+			Msg("failed to stat backup file in fluxend_api container")
+
+		return nil, 0, err
 	}
 
-	return fileBytes, err
+	return backupFile, fileInfo.Size(), nil
 }
 
-func (s *WorkflowServiceImpl) uploadBackup(databaseName string, backupUUID uuid.UUID, fileBytes []byte) error {
+func (s *WorkflowServiceImpl) uploadBackup(databaseName string, backupUUID uuid.UUID, body io.Reader, fileSize int64) error {
 	filePath := fmt.Sprintf("%s/%s.sql", databaseName, backupUUID)
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+	storageService, err := s.storageProvider(backupUUID)
 	if err != nil {
 		log.Error().
 			Str("action", constants.ActionBackup).
@@ -237,7 +268,9 @@ func (s *WorkflowServiceImpl) uploadBackup(databaseName string, backupUUID uuid.
 	err = storageService.UploadFile(storage.UploadFileInput{
 		ContainerName: constants.BackupContainerName,
 		FileName:      filePath,
-		FileBytes:     fileBytes,
+		Body:          body,
+		ContentLength: fileSize,
+		ContentType:   "application/sql",
 	})
 	if err != nil {
 		log.Error().
@@ -0,0 +1,174 @@
+package email
+
+import (
+	stdErrors "errors"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg/errors"
+	"github.com/samber/do"
+)
+
+// CaptureService browses the messages captured by the FILE mail driver
+type CaptureService interface {
+	List(paginationParams shared.PaginationParams, authUser auth.User) ([]CapturedMessage, shared.PaginationDetails, error)
+	GetByID(messageID string, authUser auth.User) (CapturedMessage, error)
+	Raw(messageID string, authUser auth.User) ([]byte, error)
+	Delete(messageID string, authUser auth.User) (bool, error)
+	Clear(authUser auth.User) (int, error)
+}
+
+type CaptureServiceImpl struct {
+	adminPolicy *admin.Policy
+}
+
+func NewCaptureService(injector *do.Injector) (CaptureService, error) {
+	return &CaptureServiceImpl{
+		adminPolicy: admin.NewAdminPolicy(),
+	}, nil
+}
+
+// List returns a page of captured messages, newest first, with their headers only
+func (s *CaptureServiceImpl) List(paginationParams shared.PaginationParams, authUser auth.User) ([]CapturedMessage, shared.PaginationDetails, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, shared.PaginationDetails{}, errors.NewForbiddenError("capturedEmail.error.listForbidden")
+	}
+
+	mailbox, err := emailAdapter.NewMailbox()
+	if err != nil {
+		return nil, shared.PaginationDetails{}, err
+	}
+
+	entries, err := mailbox.List()
+	if err != nil {
+		return nil, shared.PaginationDetails{}, err
+	}
+
+	paginationDetails := shared.PaginationDetails{
+		Total: len(entries),
+		Page:  paginationParams.Page,
+		Limit: paginationParams.Limit,
+	}
+
+	start := min((paginationParams.Page-1)*paginationParams.Limit, len(entries))
+	end := min(start+paginationParams.Limit, len(entries))
+
+	messages := make([]CapturedMessage, 0, end-start)
+	for _, entry := range entries[start:end] {
+		header, err := readCapturedHeader(mailbox, entry.ID)
+		if stdErrors.Is(err, emailAdapter.ErrCapturedMessageNotFound) {
+			// Deleted since the mailbox was listed
+			continue
+		}
+
+		if err != nil {
+			return nil, shared.PaginationDetails{}, err
+		}
+
+		messages = append(messages, CapturedMessage{
+			ID:        entry.ID,
+			Size:      entry.Size,
+			CreatedAt: entry.CreatedAt,
+			Message:   header,
+		})
+	}
+
+	return messages, paginationDetails, nil
+}
+
+func (s *CaptureServiceImpl) GetByID(messageID string, authUser auth.User) (CapturedMessage, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return CapturedMessage{}, errors.NewForbiddenError("capturedEmail.error.viewForbidden")
+	}
+
+	mailbox, err := emailAdapter.NewMailbox()
+	if err != nil {
+		return CapturedMessage{}, err
+	}
+
+	entry, err := mailbox.Get(messageID)
+	if err != nil {
+		return CapturedMessage{}, capturedError(err)
+	}
+
+	raw, err := mailbox.Read(messageID)
+	if err != nil {
+		return CapturedMessage{}, capturedError(err)
+	}
+
+	message, err := emailAdapter.ParseMessage(raw)
+	if err != nil {
+		return CapturedMessage{}, err
+	}
+
+	return CapturedMessage{ID: entry.ID, Size: entry.Size, CreatedAt: entry.CreatedAt, Message: message}, nil
+}
+
+// Raw returns a captured message as the .eml file it's stored in
+func (s *CaptureServiceImpl) Raw(messageID string, authUser auth.User) ([]byte, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, errors.NewForbiddenError("capturedEmail.error.viewForbidden")
+	}
+
+	mailbox, err := emailAdapter.NewMailbox()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := mailbox.Read(messageID)
+	if err != nil {
+		return nil, capturedError(err)
+	}
+
+	return raw, nil
+}
+
+func (s *CaptureServiceImpl) Delete(messageID string, authUser auth.User) (bool, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return false, errors.NewForbiddenError("capturedEmail.error.deleteForbidden")
+	}
+
+	mailbox, err := emailAdapter.NewMailbox()
+	if err != nil {
+		return false, err
+	}
+
+	if err = mailbox.Delete(messageID); err != nil {
+		return false, capturedError(err)
+	}
+
+	return true, nil
+}
+
+// Clear deletes every captured message and returns how many there were
+func (s *CaptureServiceImpl) Clear(authUser auth.User) (int, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return 0, errors.NewForbiddenError("capturedEmail.error.deleteForbidden")
+	}
+
+	mailbox, err := emailAdapter.NewMailbox()
+	if err != nil {
+		return 0, err
+	}
+
+	return mailbox.Clear()
+}
+
+func readCapturedHeader(mailbox *emailAdapter.Mailbox, messageID string) (emailAdapter.Message, error) {
+	reader, err := mailbox.Open(messageID)
+	if err != nil {
+		return emailAdapter.Message{}, err
+	}
+	defer reader.Close()
+
+	return emailAdapter.ParseHeader(reader)
+}
+
+func capturedError(err error) error {
+	if stdErrors.Is(err, emailAdapter.ErrCapturedMessageNotFound) {
+		return errors.NewNotFoundError("capturedEmail.error.notFound")
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+package email
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fluxend/internal/domain/shared"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"time"
+)
+
+// Template is an email rendered with Go templates. Messages are wrapped by their Layout, a template of the
+// layout type whose content gets the rendered message as {{ .Content }}
+type Template struct {
+	shared.BaseEntity
+	Id          int           `db:"id" json:"id"`
+	Uuid        uuid.UUID     `db:"uuid" json:"uuid"`
+	Name        string        `db:"name" json:"name"`
+	Type        string        `db:"type" json:"type"`
+	Parameters  Parameters    `db:"parameters" json:"parameters"`
+	Subject     string        `db:"subject" json:"subject"`
+	Message     string        `db:"message" json:"message"` // plain text body
+	HtmlMessage string        `db:"html_message" json:"htmlMessage"`
+	Layout      null.String   `db:"layout" json:"layout"`
+	CreatedBy   uuid.NullUUID `db:"created_by" json:"createdBy"`
+	UpdatedBy   uuid.NullUUID `db:"updated_by" json:"updatedBy"`
+	CreatedAt   time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time     `db:"updated_at" json:"updatedAt"`
+}
+
+// Override replaces parts of a template for the emails of an organization, empty parts keep the template ones
+type Override struct {
+	shared.BaseEntity
+	Uuid             uuid.UUID `db:"uuid" json:"uuid"`
+	TemplateId       int       `db:"template_id" json:"templateId"`
+	OrganizationUuid uuid.UUID `db:"organization_uuid" json:"organizationUuid"`
+	Subject          string    `db:"subject" json:"subject"`
+	Message          string    `db:"message" json:"message"`
+	HtmlMessage      string    `db:"html_message" json:"htmlMessage"`
+	CreatedBy        uuid.UUID `db:"created_by" json:"createdBy"`
+	UpdatedBy        uuid.UUID `db:"updated_by" json:"updatedBy"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Parameter is a value callers pass when rendering a template, Example stands in for it in previews
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Example     string `json:"example,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Parameters are stored as a JSON array in a text column
+type Parameters []Parameter
+
+// Params are the values a template is rendered with
+type Params map[string]interface{}
+
+func (p Parameters) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+
+	encoded, err := json.Marshal(p)
+
+	return string(encoded), err
+}
+
+func (p *Parameters) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*p = Parameters{}
+
+		return nil
+	case []byte:
+		return json.Unmarshal(data, p)
+	case string:
+		return json.Unmarshal([]byte(data), p)
+	default:
+		return fmt.Errorf("unsupported parameters type %T", value)
+	}
+}
+
+// Apply returns the template with the parts of the override that are set
+func (t Template) Apply(override Override) Template {
+	if override.Subject != "" {
+		t.Subject = override.Subject
+	}
+
+	if override.Message != "" {
+		t.Message = override.Message
+	}
+
+	if override.HtmlMessage != "" {
+		t.HtmlMessage = override.HtmlMessage
+	}
+
+	return t
+}
@@ -0,0 +1,63 @@
+package email
+
+import (
+	emailAdapter "fluxend/internal/adapters/email"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/samber/do"
+)
+
+// Mailer queues emails in the outbox, the outbox worker sends them through the driver picked by the mailDriver setting
+type Mailer interface {
+	Send(message emailAdapter.Message) error
+	SendTemplate(name string, params Params, message emailAdapter.Message, organizationUUID uuid.NullUUID) error
+	SendSensitiveTemplate(name string, params Params, message emailAdapter.Message, organizationUUID uuid.NullUUID) error
+}
+
+type MailerImpl struct {
+	templateService Service
+	outboxService   OutboxService
+}
+
+func NewMailer(injector *do.Injector) (Mailer, error) {
+	templateService := do.MustInvoke[Service](injector)
+	outboxService := do.MustInvoke[OutboxService](injector)
+
+	return &MailerImpl{
+		templateService: templateService,
+		outboxService:   outboxService,
+	}, nil
+}
+
+func (m *MailerImpl) Send(message emailAdapter.Message) error {
+	_, err := m.outboxService.Enqueue(message, null.String{}, uuid.NullUUID{}, false)
+
+	return err
+}
+
+// SendTemplate renders a template, customized by the organization when one is given, into the subject and
+// bodies of message before queueing it. Recipients and attachments come from message
+func (m *MailerImpl) SendTemplate(name string, params Params, message emailAdapter.Message, organizationUUID uuid.NullUUID) error {
+	return m.sendTemplate(name, params, message, organizationUUID, false)
+}
+
+// SendSensitiveTemplate is SendTemplate for emails carrying secrets like single-use links, their
+// bodies are dropped from the outbox once the message is finished and never shown to admins
+func (m *MailerImpl) SendSensitiveTemplate(name string, params Params, message emailAdapter.Message, organizationUUID uuid.NullUUID) error {
+	return m.sendTemplate(name, params, message, organizationUUID, true)
+}
+
+func (m *MailerImpl) sendTemplate(name string, params Params, message emailAdapter.Message, organizationUUID uuid.NullUUID, sensitive bool) error {
+	rendered, err := m.templateService.Render(name, params, organizationUUID)
+	if err != nil {
+		return err
+	}
+
+	message.Subject = rendered.Subject
+	message.Text = rendered.Text
+	message.HTML = rendered.HTML
+
+	_, err = m.outboxService.Enqueue(message, null.StringFrom(name), organizationUUID, sensitive)
+
+	return err
+}
@@ -0,0 +1,74 @@
+package email
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/domain/shared"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"time"
+)
+
+// OutboxMessage is a queued email, its payload is only loaded when it's sent or inspected
+type OutboxMessage struct {
+	shared.BaseEntity
+	Uuid              uuid.UUID     `db:"uuid" json:"uuid"`
+	Subject           string        `db:"subject" json:"subject"`
+	Recipients        string        `db:"recipients" json:"recipients"` // To, Cc and Bcc addresses, comma separated
+	Template          null.String   `db:"template" json:"template"`
+	OrganizationUuid  uuid.NullUUID `db:"organization_uuid" json:"organizationUuid"`
+	IsSensitive       bool          `db:"is_sensitive" json:"isSensitive"` // the body is dropped once finished
+	Status            string        `db:"status" json:"status"`
+	Attempts          int           `db:"attempts" json:"attempts"`
+	MaxAttempts       int           `db:"max_attempts" json:"maxAttempts"`
+	NextAttemptAt     time.Time     `db:"next_attempt_at" json:"nextAttemptAt"`
+	Driver            string        `db:"driver" json:"driver"` // the one the message was sent with
+	ProviderMessageId string        `db:"provider_message_id" json:"providerMessageId"`
+	LastError         string        `db:"last_error" json:"lastError"`
+	SentAt            *time.Time    `db:"sent_at" json:"sentAt"`
+	CreatedAt         time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updatedAt"`
+}
+
+// Delivery is a single try of a driver to send an outbox message
+type Delivery struct {
+	shared.BaseEntity
+	Uuid              uuid.UUID `db:"uuid" json:"uuid"`
+	OutboxUuid        uuid.UUID `db:"outbox_uuid" json:"outboxUuid"`
+	Attempt           int       `db:"attempt" json:"attempt"`
+	Driver            string    `db:"driver" json:"driver"`
+	Status            string    `db:"status" json:"status"`
+	ProviderMessageId string    `db:"provider_message_id" json:"providerMessageId"`
+	Error             string    `db:"error" json:"error"`
+	CreatedAt         time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Payload is the queued message as given to the mailer, stored as JSON
+type Payload emailAdapter.Message
+
+// Redacted leaves out the bodies, which may carry secrets
+func (p Payload) Redacted() Payload {
+	p.Text = ""
+	p.HTML = ""
+
+	return p
+}
+
+func (p Payload) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(p)
+
+	return string(encoded), err
+}
+
+func (p *Payload) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, p)
+	case string:
+		return json.Unmarshal([]byte(data), p)
+	default:
+		return fmt.Errorf("unsupported payload type %T", value)
+	}
+}
@@ -0,0 +1,23 @@
+package email
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OutboxRepository interface {
+	List(input *ListOutboxInput, paginationParams shared.PaginationParams) ([]OutboxMessage, shared.PaginationDetails, error)
+	GetByUUID(messageUUID uuid.UUID) (OutboxMessage, error)
+	GetPayload(messageUUID uuid.UUID) (Payload, error)
+	ListDeliveries(messageUUID uuid.UUID) ([]Delivery, error)
+	Create(message *OutboxMessage, payload Payload) (*OutboxMessage, error)
+	ClaimDue(staleBefore time.Time, limit int) ([]OutboxMessage, error)
+	CreateDelivery(delivery *Delivery) (*Delivery, error)
+	MarkSent(messageUUID uuid.UUID, driver, providerMessageID string) error
+	Reschedule(messageUUID uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(messageUUID uuid.UUID, lastError string) error
+	MarkSuppressed(messageUUID uuid.UUID, lastError string) error
+	Requeue(messageUUID uuid.UUID) (bool, error)
+	PurgeFinishedBefore(before time.Time) (int64, error)
+}
@@ -0,0 +1,325 @@
+package email
+
+import (
+	"context"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"slices"
+	"strings"
+	"time"
+)
+
+type OutboxService interface {
+	Enqueue(message emailAdapter.Message, template null.String, organizationUUID uuid.NullUUID, sensitive bool) (OutboxMessage, error)
+	List(input *ListOutboxInput, paginationParams shared.PaginationParams, authUser auth.User) ([]OutboxMessage, shared.PaginationDetails, error)
+	GetByUUID(messageUUID uuid.UUID, authUser auth.User) (OutboxDetails, error)
+	Resend(messageUUID uuid.UUID, authUser auth.User) (OutboxMessage, error)
+	DeliverDue() (int, error)
+	Work(ctx context.Context)
+}
+
+type OutboxServiceImpl struct {
+	adminPolicy     *admin.Policy
+	settingService  setting.Service
+	outboxRepo      OutboxRepository
+	suppressionRepo SuppressionRepository
+	createProvider  func(driver string) (emailAdapter.Provider, error)
+}
+
+func NewOutboxService(injector *do.Injector) (OutboxService, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+	outboxRepo := do.MustInvoke[OutboxRepository](injector)
+	suppressionRepo := do.MustInvoke[SuppressionRepository](injector)
+	factory := do.MustInvoke[*emailAdapter.Factory](injector)
+
+	return &OutboxServiceImpl{
+		adminPolicy:     admin.NewAdminPolicy(),
+		settingService:  settingService,
+		outboxRepo:      outboxRepo,
+		suppressionRepo: suppressionRepo,
+		createProvider:  factory.CreateProvider,
+	}, nil
+}
+
+// Enqueue stores a message for the worker to send, the sender is only checked once a driver is picked.
+// The body of a sensitive message is dropped once it's finished
+func (s *OutboxServiceImpl) Enqueue(message emailAdapter.Message, template null.String, organizationUUID uuid.NullUUID, sensitive bool) (OutboxMessage, error) {
+	var err error
+	if message.From != "" {
+		err = message.Validate(message.From)
+	} else {
+		err = message.ValidateContent()
+	}
+
+	if err != nil {
+		return OutboxMessage{}, fmt.Errorf("invalid email: %w", err)
+	}
+
+	outboxMessage := OutboxMessage{
+		Subject:          message.Subject,
+		Recipients:       strings.Join(message.Recipients(), ", "),
+		Template:         template,
+		OrganizationUuid: organizationUUID,
+		IsSensitive:      sensitive,
+		Status:           constants.EmailOutboxStatusPending,
+		MaxAttempts:      constants.EmailOutboxMaxAttempts,
+		NextAttemptAt:    time.Now(),
+	}
+
+	if _, err = s.outboxRepo.Create(&outboxMessage, Payload(message)); err != nil {
+		return OutboxMessage{}, err
+	}
+
+	return outboxMessage, nil
+}
+
+func (s *OutboxServiceImpl) List(input *ListOutboxInput, paginationParams shared.PaginationParams, authUser auth.User) ([]OutboxMessage, shared.PaginationDetails, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, shared.PaginationDetails{}, errors.NewForbiddenError("emailOutbox.error.listForbidden")
+	}
+
+	return s.outboxRepo.List(input, paginationParams)
+}
+
+func (s *OutboxServiceImpl) GetByUUID(messageUUID uuid.UUID, authUser auth.User) (OutboxDetails, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return OutboxDetails{}, errors.NewForbiddenError("emailOutbox.error.viewForbidden")
+	}
+
+	fetchedMessage, err := s.outboxRepo.GetByUUID(messageUUID)
+	if err != nil {
+		return OutboxDetails{}, err
+	}
+
+	payload, err := s.outboxRepo.GetPayload(messageUUID)
+	if err != nil {
+		return OutboxDetails{}, err
+	}
+
+	deliveries, err := s.outboxRepo.ListDeliveries(messageUUID)
+	if err != nil {
+		return OutboxDetails{}, err
+	}
+
+	// A queued sensitive message still has its body, it's needed to send it but isn't shown
+	if fetchedMessage.IsSensitive {
+		payload = payload.Redacted()
+	}
+
+	return OutboxDetails{Message: fetchedMessage, Payload: payload, Deliveries: deliveries}, nil
+}
+
+// Resend queues a sent, failed or suppressed message again with a fresh set of attempts, its deliveries are kept
+func (s *OutboxServiceImpl) Resend(messageUUID uuid.UUID, authUser auth.User) (OutboxMessage, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return OutboxMessage{}, errors.NewForbiddenError("emailOutbox.error.resendForbidden")
+	}
+
+	fetchedMessage, err := s.outboxRepo.GetByUUID(messageUUID)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	// The body is gone, the user asks for a new link instead
+	if fetchedMessage.IsSensitive {
+		return OutboxMessage{}, errors.NewBadRequestError("emailOutbox.error.resendSensitive")
+	}
+
+	requeued, err := s.outboxRepo.Requeue(fetchedMessage.Uuid)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	// Only finished messages are requeued, the others are still on their way
+	if !requeued {
+		return OutboxMessage{}, errors.NewBadRequestError("emailOutbox.error.alreadyQueued")
+	}
+
+	return s.outboxRepo.GetByUUID(fetchedMessage.Uuid)
+}
+
+// DeliverDue sends a batch of due messages and returns how many were sent, a failing message doesn't stop the others
+func (s *OutboxServiceImpl) DeliverDue() (int, error) {
+	messages, err := s.outboxRepo.ClaimDue(time.Now().Add(-constants.EmailOutboxClaimTimeout), constants.EmailOutboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, message := range messages {
+		delivered, err := s.deliver(message)
+		if err != nil {
+			log.Error().
+				Str("action", constants.ActionEmailOutbox).
+				Str("message_uuid", message.Uuid.String()).
+				Str("error", err.Error()).
+				Msg("failed to deliver outbox message")
+
+			continue
+		}
+
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// Work sends due messages and purges old ones every tick until the context is done, it's started
+// by the API server so no separate cron job is needed
+func (s *OutboxServiceImpl) Work(ctx context.Context) {
+	ticker := time.NewTicker(constants.EmailOutboxWorkerTick)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverDue(); err != nil {
+			log.Error().
+				Str("action", constants.ActionEmailOutbox).
+				Str("error", err.Error()).
+				Msg("failed to claim outbox messages")
+		}
+
+		if _, err := s.outboxRepo.PurgeFinishedBefore(time.Now().Add(-constants.EmailOutboxRetention)); err != nil {
+			log.Error().
+				Str("action", constants.ActionEmailOutbox).
+				Str("error", err.Error()).
+				Msg("failed to purge outbox messages")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver tries the drivers in order and records each try. The message is rescheduled when all of them
+// fail, or marked failed once it ran out of attempts. Suppressed recipients are left out, checked on
+// every attempt as addresses may bounce while a message waits
+func (s *OutboxServiceImpl) deliver(message OutboxMessage) (bool, error) {
+	payload, err := s.outboxRepo.GetPayload(message.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	sendable, suppressed, err := s.withoutSuppressed(emailAdapter.Message(payload))
+	if err != nil {
+		return false, err
+	}
+
+	if len(sendable.Recipients()) == 0 {
+		return false, s.outboxRepo.MarkSuppressed(message.Uuid, "suppressed: "+strings.Join(suppressed, ", "))
+	}
+
+	lastError := ""
+	for _, driver := range s.drivers() {
+		providerMessageID, err := s.send(driver, sendable)
+
+		delivery := Delivery{
+			OutboxUuid:        message.Uuid,
+			Attempt:           message.Attempts,
+			Driver:            driver,
+			Status:            constants.EmailOutboxStatusSent,
+			ProviderMessageId: providerMessageID,
+		}
+
+		if err != nil {
+			delivery.Status = constants.EmailOutboxStatusFailed
+			delivery.Error = err.Error()
+			lastError = fmt.Sprintf("%s: %s", driver, err.Error())
+		}
+
+		if _, createErr := s.outboxRepo.CreateDelivery(&delivery); createErr != nil {
+			return false, createErr
+		}
+
+		if err == nil {
+			return true, s.outboxRepo.MarkSent(message.Uuid, driver, providerMessageID)
+		}
+	}
+
+	if message.Attempts >= message.MaxAttempts {
+		return false, s.outboxRepo.MarkFailed(message.Uuid, lastError)
+	}
+
+	return false, s.outboxRepo.Reschedule(message.Uuid, time.Now().Add(retryDelay(message.Attempts)), lastError)
+}
+
+// withoutSuppressed removes the suppressed addresses from the recipients and returns them
+func (s *OutboxServiceImpl) withoutSuppressed(message emailAdapter.Message) (emailAdapter.Message, []string, error) {
+	addresses := make([]string, 0, len(message.To)+len(message.Cc)+len(message.Bcc))
+	for _, recipients := range [][]string{message.To, message.Cc, message.Bcc} {
+		for _, recipient := range recipients {
+			addresses = append(addresses, SuppressionAddress(recipient))
+		}
+	}
+
+	suppressed, err := s.suppressionRepo.ListSuppressed(addresses)
+	if err != nil || len(suppressed) == 0 {
+		return message, nil, err
+	}
+
+	keep := func(recipients []string) []string {
+		var kept []string
+		for _, recipient := range recipients {
+			if !slices.Contains(suppressed, SuppressionAddress(recipient)) {
+				kept = append(kept, recipient)
+			}
+		}
+
+		return kept
+	}
+
+	message.To = keep(message.To)
+	message.Cc = keep(message.Cc)
+	message.Bcc = keep(message.Bcc)
+
+	return message, suppressed, nil
+}
+
+func (s *OutboxServiceImpl) send(driver string, message emailAdapter.Message) (string, error) {
+	provider, err := s.createProvider(driver)
+	if err != nil {
+		return "", err
+	}
+
+	return provider.Send(message)
+}
+
+// drivers are the mailDriver followed by the mailFallbackDriver when one is set
+func (s *OutboxServiceImpl) drivers() []string {
+	mailDriver := s.settingService.Get("mailDriver")
+	primary := mailDriver.Value
+	if primary == "" {
+		primary = mailDriver.DefaultValue
+	}
+
+	drivers := []string{primary}
+	if fallback := s.settingService.GetValue("mailFallbackDriver"); fallback != "" && fallback != primary {
+		drivers = append(drivers, fallback)
+	}
+
+	return drivers
+}
+
+// retryDelay doubles with every failed attempt, starting at the base delay and capped at the max one
+func retryDelay(attempt int) time.Duration {
+	delay := constants.EmailOutboxBaseDelay
+	for i := 1; i < attempt && delay < constants.EmailOutboxMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, constants.EmailOutboxMaxDelay)
+}
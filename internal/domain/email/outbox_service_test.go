@@ -0,0 +1,346 @@
+package email
+
+import (
+	stdErrors "errors"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/setting"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outboxStore keeps a single message in memory, the methods a test doesn't need panic through the nil interface
+type outboxStore struct {
+	OutboxRepository
+	message    OutboxMessage
+	payload    Payload
+	deliveries []Delivery
+}
+
+func (o *outboxStore) GetByUUID(uuid.UUID) (OutboxMessage, error) {
+	return o.message, nil
+}
+
+func (o *outboxStore) ListDeliveries(uuid.UUID) ([]Delivery, error) {
+	return o.deliveries, nil
+}
+
+func (o *outboxStore) GetPayload(uuid.UUID) (Payload, error) {
+	return o.payload, nil
+}
+
+func (o *outboxStore) CreateDelivery(delivery *Delivery) (*Delivery, error) {
+	o.deliveries = append(o.deliveries, *delivery)
+
+	return delivery, nil
+}
+
+func (o *outboxStore) MarkSent(_ uuid.UUID, driver, providerMessageID string) error {
+	o.message.Status = constants.EmailOutboxStatusSent
+	o.message.Driver = driver
+	o.message.ProviderMessageId = providerMessageID
+
+	return nil
+}
+
+func (o *outboxStore) Reschedule(_ uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	o.message.Status = constants.EmailOutboxStatusPending
+	o.message.NextAttemptAt = nextAttemptAt
+	o.message.LastError = lastError
+
+	return nil
+}
+
+func (o *outboxStore) MarkFailed(_ uuid.UUID, lastError string) error {
+	o.message.Status = constants.EmailOutboxStatusFailed
+	o.message.LastError = lastError
+
+	return nil
+}
+
+func (o *outboxStore) MarkSuppressed(_ uuid.UUID, lastError string) error {
+	o.message.Status = constants.EmailOutboxStatusSuppressed
+	o.message.LastError = lastError
+
+	return nil
+}
+
+// suppressionList answers which addresses are suppressed from a fixed list
+type suppressionList struct {
+	SuppressionRepository
+	addresses []string
+}
+
+func (s suppressionList) ListSuppressed(addresses []string) ([]string, error) {
+	var suppressed []string
+	for _, address := range addresses {
+		if slices.Contains(s.addresses, address) {
+			suppressed = append(suppressed, address)
+		}
+	}
+
+	return suppressed, nil
+}
+
+// driverSettings answers the mail driver settings, the rest of setting.Service isn't used by the outbox
+type driverSettings struct {
+	setting.Service
+	primary  string
+	fallback string
+}
+
+func (d driverSettings) Get(name string) setting.Setting {
+	if name == "mailDriver" {
+		return setting.Setting{Name: name, DefaultValue: d.primary}
+	}
+
+	return setting.Setting{Name: name, Value: d.GetValue(name)}
+}
+
+func (d driverSettings) GetValue(name string) string {
+	if name == "mailFallbackDriver" {
+		return d.fallback
+	}
+
+	return ""
+}
+
+type providerFunc func(message emailAdapter.Message) (string, error)
+
+func (f providerFunc) Send(message emailAdapter.Message) (string, error) {
+	return f(message)
+}
+
+func newTestOutboxService(store *outboxStore, settings driverSettings, providers map[string]providerFunc, suppressed ...string) *OutboxServiceImpl {
+	return &OutboxServiceImpl{
+		settingService:  settings,
+		outboxRepo:      store,
+		suppressionRepo: suppressionList{addresses: suppressed},
+		createProvider: func(driver string) (emailAdapter.Provider, error) {
+			provider, ok := providers[driver]
+			if !ok {
+				return nil, stdErrors.New("unsupported email provider: " + driver)
+			}
+
+			return provider, nil
+		},
+	}
+}
+
+func newOutboxStore(attempts int) *outboxStore {
+	return &outboxStore{
+		message: OutboxMessage{
+			Uuid:        uuid.New(),
+			Status:      constants.EmailOutboxStatusSending,
+			Attempts:    attempts,
+			MaxAttempts: constants.EmailOutboxMaxAttempts,
+		},
+		payload: Payload(emailAdapter.NewTextMessage("jane@example.com", "Hi", "Hello")),
+	}
+}
+
+func TestOutboxService_Deliver_Suite(t *testing.T) {
+	failing := providerFunc(func(emailAdapter.Message) (string, error) {
+		return "", stdErrors.New("connection refused")
+	})
+
+	t.Run("Deliver: sent with the primary driver", func(t *testing.T) {
+		store := newOutboxStore(1)
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP}, map[string]providerFunc{
+			constants.EmailDriverSMTP: func(message emailAdapter.Message) (string, error) {
+				assert.Equal(t, []string{"jane@example.com"}, message.To)
+
+				return "<id@fluxend.app>", nil
+			},
+		})
+
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.True(t, delivered)
+		assert.Equal(t, constants.EmailOutboxStatusSent, store.message.Status)
+		assert.Equal(t, constants.EmailDriverSMTP, store.message.Driver)
+		assert.Equal(t, "<id@fluxend.app>", store.message.ProviderMessageId)
+		require.Len(t, store.deliveries, 1)
+		assert.Equal(t, constants.EmailOutboxStatusSent, store.deliveries[0].Status)
+	})
+
+	t.Run("Deliver: fails over to the fallback driver", func(t *testing.T) {
+		store := newOutboxStore(1)
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP, fallback: constants.EmailDriverSES}, map[string]providerFunc{
+			constants.EmailDriverSMTP: failing,
+			constants.EmailDriverSES: func(emailAdapter.Message) (string, error) {
+				return "ses-id", nil
+			},
+		})
+
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.True(t, delivered)
+		assert.Equal(t, constants.EmailDriverSES, store.message.Driver)
+		assert.Equal(t, "ses-id", store.message.ProviderMessageId)
+
+		require.Len(t, store.deliveries, 2)
+		assert.Equal(t, constants.EmailDriverSMTP, store.deliveries[0].Driver)
+		assert.Equal(t, constants.EmailOutboxStatusFailed, store.deliveries[0].Status)
+		assert.Equal(t, "connection refused", store.deliveries[0].Error)
+		assert.Equal(t, constants.EmailDriverSES, store.deliveries[1].Driver)
+		assert.Equal(t, constants.EmailOutboxStatusSent, store.deliveries[1].Status)
+	})
+
+	t.Run("Deliver: rescheduled while attempts are left", func(t *testing.T) {
+		store := newOutboxStore(2)
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP}, map[string]providerFunc{
+			constants.EmailDriverSMTP: failing,
+		})
+
+		before := time.Now()
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.False(t, delivered)
+		assert.Equal(t, constants.EmailOutboxStatusPending, store.message.Status)
+		assert.Equal(t, "SMTP: connection refused", store.message.LastError)
+		assert.WithinDuration(t, before.Add(2*constants.EmailOutboxBaseDelay), store.message.NextAttemptAt, time.Second)
+	})
+
+	t.Run("Deliver: failed after the last attempt", func(t *testing.T) {
+		store := newOutboxStore(constants.EmailOutboxMaxAttempts)
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP, fallback: "UNKNOWN"}, map[string]providerFunc{
+			constants.EmailDriverSMTP: failing,
+		})
+
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.False(t, delivered)
+		assert.Equal(t, constants.EmailOutboxStatusFailed, store.message.Status)
+		assert.Equal(t, "UNKNOWN: unsupported email provider: UNKNOWN", store.message.LastError)
+		assert.Len(t, store.deliveries, 2)
+	})
+
+	t.Run("Deliver: suppressed recipients are left out", func(t *testing.T) {
+		store := newOutboxStore(1)
+		store.payload.To = []string{"Jane <Jane@Example.com>", "john@example.com"}
+		store.payload.Bcc = []string{"audit@example.com"}
+
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP}, map[string]providerFunc{
+			constants.EmailDriverSMTP: func(message emailAdapter.Message) (string, error) {
+				assert.Equal(t, []string{"john@example.com"}, message.To)
+				assert.Empty(t, message.Bcc)
+
+				return "<id@fluxend.app>", nil
+			},
+		}, "jane@example.com", "audit@example.com")
+
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.True(t, delivered)
+		assert.Equal(t, constants.EmailOutboxStatusSent, store.message.Status)
+	})
+
+	t.Run("Deliver: not sent when every recipient is suppressed", func(t *testing.T) {
+		store := newOutboxStore(1)
+		service := newTestOutboxService(store, driverSettings{primary: constants.EmailDriverSMTP}, map[string]providerFunc{
+			constants.EmailDriverSMTP: func(emailAdapter.Message) (string, error) {
+				t.Fatal("suppressed message was sent")
+
+				return "", nil
+			},
+		}, "jane@example.com")
+
+		delivered, err := service.deliver(store.message)
+		require.NoError(t, err)
+
+		assert.False(t, delivered)
+		assert.Equal(t, constants.EmailOutboxStatusSuppressed, store.message.Status)
+		assert.Equal(t, "suppressed: jane@example.com", store.message.LastError)
+		assert.Empty(t, store.deliveries)
+	})
+}
+
+func TestSuppressionAddress(t *testing.T) {
+	assert.Equal(t, "jane@example.com", SuppressionAddress("Jane <Jane@Example.com>"))
+	assert.Equal(t, "jane@example.com", SuppressionAddress(" JANE@example.com "))
+	assert.Equal(t, "not an address", SuppressionAddress("Not an Address"))
+}
+
+func TestOutboxService_Enqueue_InvalidMessage(t *testing.T) {
+	service := newTestOutboxService(&outboxStore{}, driverSettings{}, nil)
+
+	_, err := service.Enqueue(emailAdapter.Message{Subject: "Hi", Text: "Hello"}, null.String{}, uuid.NullUUID{}, false)
+	assert.ErrorContains(t, err, "at least one recipient is required")
+
+	_, err = service.Enqueue(emailAdapter.Message{From: "nobody", To: []string{"jane@example.com"}, Text: "Hello"}, null.String{}, uuid.NullUUID{}, false)
+	assert.ErrorContains(t, err, "invalid sender")
+}
+
+func TestOutboxService_Sensitive_Suite(t *testing.T) {
+	superman := auth.User{RoleID: constants.UserRoleSuperman}
+
+	newSensitiveStore := func() *outboxStore {
+		store := newOutboxStore(1)
+		store.message.Status = constants.EmailOutboxStatusSent
+		store.message.IsSensitive = true
+		store.payload.HTML = "<a href=\"https://console.example.com/reset-password?token=secret\">Reset</a>"
+
+		return store
+	}
+
+	t.Run("GetByUUID: body of a sensitive message is left out", func(t *testing.T) {
+		store := newSensitiveStore()
+		service := newTestOutboxService(store, driverSettings{}, nil)
+		service.adminPolicy = admin.NewAdminPolicy()
+
+		details, err := service.GetByUUID(store.message.Uuid, superman)
+		require.NoError(t, err)
+
+		assert.Empty(t, details.Payload.Text)
+		assert.Empty(t, details.Payload.HTML)
+		assert.Equal(t, []string{"jane@example.com"}, details.Payload.To)
+	})
+
+	t.Run("Resend: sensitive message is refused", func(t *testing.T) {
+		store := newSensitiveStore()
+		service := newTestOutboxService(store, driverSettings{}, nil)
+		service.adminPolicy = admin.NewAdminPolicy()
+
+		_, err := service.Resend(store.message.Uuid, superman)
+
+		assert.EqualError(t, err, "emailOutbox.error.resendSensitive")
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, constants.EmailOutboxBaseDelay, retryDelay(1))
+	assert.Equal(t, 2*constants.EmailOutboxBaseDelay, retryDelay(2))
+	assert.Equal(t, 16*constants.EmailOutboxBaseDelay, retryDelay(5))
+	assert.Equal(t, constants.EmailOutboxMaxDelay, retryDelay(7))
+	assert.Equal(t, constants.EmailOutboxMaxDelay, retryDelay(100))
+}
+
+func TestPayload_ValueScan(t *testing.T) {
+	payload := Payload(emailAdapter.Message{
+		To:          []string{"Jane <jane@example.com>"},
+		Subject:     "Report",
+		HTML:        "<p>Attached</p>",
+		Attachments: []emailAdapter.Attachment{{Filename: "report.pdf", Content: []byte{0x25, 0x50, 0x00, 0xff}}},
+	})
+
+	value, err := payload.Value()
+	require.NoError(t, err)
+
+	var scanned Payload
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, payload, scanned)
+}
@@ -0,0 +1,176 @@
+package email
+
+import (
+	"bytes"
+	"fluxend/pkg/errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"sort"
+	textTemplate "text/template"
+)
+
+// render executes a message template with params, wrapped by layout unless it's nil. Subjects and text bodies
+// are plain text, HTML bodies are escaped as they're rendered
+func render(message Template, layout *Template, params Params) (Rendered, error) {
+	declared := message.Parameters
+	if layout != nil {
+		declared = append(append(Parameters{}, declared...), layout.Parameters...)
+	}
+
+	if err := validateParams(declared, params); err != nil {
+		return Rendered{}, err
+	}
+
+	// Optional parameters left out render empty, while templates using undeclared ones fail
+	params = withOptional(declared, params)
+
+	subject, err := executeText(message.Name, message.Subject, params)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	text, err := executeText(message.Name, message.Message, params)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	html, err := executeHTML(message.Name, message.HtmlMessage, params)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	if layout != nil {
+		if text != "" && layout.Message != "" {
+			text, err = executeText(layout.Name, layout.Message, withContent(params, text))
+			if err != nil {
+				return Rendered{}, err
+			}
+		}
+
+		if html != "" && layout.HtmlMessage != "" {
+			html, err = executeHTML(layout.Name, layout.HtmlMessage, withContent(params, htmlTemplate.HTML(html)))
+			if err != nil {
+				return Rendered{}, err
+			}
+		}
+	}
+
+	return Rendered{Subject: subject, Text: text, HTML: html}, nil
+}
+
+// exampleParams fills the parameters missing from params with their example, or their name when they have none
+func exampleParams(declared Parameters, params Params) Params {
+	examples := Params{}
+	for _, parameter := range declared {
+		examples[parameter.Name] = parameter.Example
+		if parameter.Example == "" {
+			examples[parameter.Name] = "[" + parameter.Name + "]"
+		}
+	}
+
+	for name, value := range params {
+		examples[name] = value
+	}
+
+	return examples
+}
+
+// validateParams requires every required parameter and rejects undeclared ones, which are most likely typos
+func validateParams(declared Parameters, params Params) error {
+	known := map[string]bool{}
+	for _, parameter := range declared {
+		known[parameter.Name] = true
+
+		if _, ok := params[parameter.Name]; parameter.Required && !ok {
+			return errors.NewBadRequestError(fmt.Sprintf("Missing template parameter %s", parameter.Name))
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !known[name] {
+			return errors.NewBadRequestError(fmt.Sprintf("Unknown template parameter %s", name))
+		}
+	}
+
+	return nil
+}
+
+func executeText(name, content string, data interface{}) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	parsed, err := textTemplate.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err = parsed.Execute(&output, data); err != nil {
+		return "", errors.NewBadRequestError(err.Error())
+	}
+
+	return output.String(), nil
+}
+
+func executeHTML(name, content string, data interface{}) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	parsed, err := htmlTemplate.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err = parsed.Execute(&output, data); err != nil {
+		return "", errors.NewBadRequestError(err.Error())
+	}
+
+	return output.String(), nil
+}
+
+func withOptional(declared Parameters, params Params) Params {
+	data := Params{}
+	for _, parameter := range declared {
+		data[parameter.Name] = ""
+	}
+
+	for name, value := range params {
+		data[name] = value
+	}
+
+	return data
+}
+
+// withContent adds the rendered message to the params of a layout
+func withContent(params Params, content interface{}) Params {
+	data := Params{}
+	for name, value := range params {
+		data[name] = value
+	}
+
+	data["Content"] = content
+
+	return data
+}
+
+// Parse checks the syntax of a template part, HTML parts are parsed the way they're rendered
+func Parse(content string, html bool) error {
+	if html {
+		_, err := htmlTemplate.New("").Parse(content)
+
+		return err
+	}
+
+	_, err := textTemplate.New("").Parse(content)
+
+	return err
+}
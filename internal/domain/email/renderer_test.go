@@ -0,0 +1,113 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplates() (Template, Template) {
+	message := Template{
+		Name: "password_reset",
+		Parameters: Parameters{
+			{Name: "name", Required: true},
+			{Name: "resetUrl", Required: true, Example: "https://example.com/reset"},
+			{Name: "expiresIn"},
+		},
+		Subject:     "Reset your password, {{ .name }}",
+		Message:     "Open {{ .resetUrl }}{{ if .expiresIn }} within {{ .expiresIn }}{{ end }}",
+		HtmlMessage: `<p>Hi {{ .name }}, <a href="{{ .resetUrl }}">reset</a></p>`,
+		Layout:      null.StringFrom("default"),
+	}
+
+	layout := Template{
+		Name:        "default",
+		Parameters:  Parameters{{Name: "appName", Example: "Fluxend"}},
+		Message:     "{{ .Content }}\n--\n{{ .appName }}",
+		HtmlMessage: "<html><body>{{ .Content }}<footer>{{ .appName }}</footer></body></html>",
+	}
+
+	return message, layout
+}
+
+func TestRender(t *testing.T) {
+	message, layout := testTemplates()
+
+	rendered, err := render(message, &layout, Params{
+		"name":     "<Jane>",
+		"resetUrl": "https://fluxend.app/reset?token=a&b",
+		"appName":  "Acme",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Reset your password, <Jane>", rendered.Subject)
+	assert.Equal(t, "Open https://fluxend.app/reset?token=a&b\n--\nAcme", rendered.Text)
+	assert.Equal(
+		t,
+		`<html><body><p>Hi &lt;Jane&gt;, <a href="https://fluxend.app/reset?token=a&amp;b">reset</a></p><footer>Acme</footer></body></html>`,
+		rendered.HTML,
+	)
+}
+
+func TestRender_WithoutLayout(t *testing.T) {
+	message, _ := testTemplates()
+
+	rendered, err := render(message, nil, Params{"name": "Jane", "resetUrl": "https://fluxend.app/reset", "expiresIn": "1 hour"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Open https://fluxend.app/reset within 1 hour", rendered.Text)
+}
+
+func TestRender_InvalidParams(t *testing.T) {
+	message, layout := testTemplates()
+
+	_, err := render(message, &layout, Params{"name": "Jane"})
+	assert.EqualError(t, err, "Missing template parameter resetUrl")
+
+	_, err = render(message, &layout, Params{"name": "Jane", "resetUrl": "https://fluxend.app", "nmae": "typo"})
+	assert.EqualError(t, err, "Unknown template parameter nmae")
+}
+
+func TestRender_UndeclaredParameterInTemplate(t *testing.T) {
+	message, _ := testTemplates()
+	message.Subject = "Hi {{ .nickname }}"
+
+	_, err := render(message, nil, Params{"name": "Jane", "resetUrl": "https://fluxend.app"})
+	assert.Error(t, err)
+}
+
+func TestExampleParams(t *testing.T) {
+	message, layout := testTemplates()
+	declared := append(append(Parameters{}, message.Parameters...), layout.Parameters...)
+
+	params := exampleParams(declared, Params{"name": "Jane"})
+
+	assert.Equal(t, Params{
+		"name":      "Jane",
+		"resetUrl":  "https://example.com/reset",
+		"expiresIn": "[expiresIn]",
+		"appName":   "Fluxend",
+	}, params)
+}
+
+func TestTemplate_Apply(t *testing.T) {
+	message, _ := testTemplates()
+
+	customized := message.Apply(Override{Subject: "Password reset for {{ .name }}"})
+
+	assert.Equal(t, "Password reset for {{ .name }}", customized.Subject)
+	assert.Equal(t, message.Message, customized.Message)
+	assert.Equal(t, message.HtmlMessage, customized.HtmlMessage)
+}
+
+func TestParameters_Scan(t *testing.T) {
+	var parameters Parameters
+	require.NoError(t, parameters.Scan(`[{"name":"resetUrl","required":true}]`))
+	assert.Equal(t, Parameters{{Name: "resetUrl", Required: true}}, parameters)
+
+	value, err := Parameters(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", value)
+}
@@ -0,0 +1,20 @@
+package email
+
+import (
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	List() ([]Template, error)
+	GetByUUID(templateUUID uuid.UUID) (Template, error)
+	GetByName(name string) (Template, error)
+	ExistsByName(name string) (bool, error)
+	IsLayoutInUse(name string) (bool, error)
+	Create(template *Template) (*Template, error)
+	Update(template *Template) (*Template, error)
+	Delete(templateUUID uuid.UUID) (bool, error)
+	ListOverridesForOrganization(organizationUUID uuid.UUID) ([]Override, error)
+	GetOverride(templateID int, organizationUUID uuid.UUID) (Override, error)
+	SaveOverride(override *Override) (*Override, error)
+	DeleteOverride(templateID int, organizationUUID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,339 @@
+package email
+
+import (
+	stdErrors "errors"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"time"
+)
+
+type Service interface {
+	List(authUser auth.User) ([]Template, error)
+	GetByUUID(templateUUID uuid.UUID, authUser auth.User) (Template, error)
+	Create(request *CreateTemplateInput, authUser auth.User) (Template, error)
+	Update(templateUUID uuid.UUID, request *CreateTemplateInput, authUser auth.User) (*Template, error)
+	Delete(templateUUID uuid.UUID, authUser auth.User) (bool, error)
+	ListForOrganization(organizationUUID uuid.UUID, authUser auth.User) ([]Template, error)
+	Customize(templateUUID, organizationUUID uuid.UUID, request *CustomizeTemplateInput, authUser auth.User) (Template, error)
+	ResetCustomization(templateUUID, organizationUUID uuid.UUID, authUser auth.User) (bool, error)
+	Preview(templateUUID uuid.UUID, organizationUUID uuid.NullUUID, params Params, authUser auth.User) (Rendered, error)
+	Render(name string, params Params, organizationUUID uuid.NullUUID) (Rendered, error)
+}
+
+type ServiceImpl struct {
+	adminPolicy        *admin.Policy
+	organizationPolicy *organization.Policy
+	templateRepo       Repository
+}
+
+func NewEmailTemplateService(injector *do.Injector) (Service, error) {
+	organizationPolicy := do.MustInvoke[*organization.Policy](injector)
+	templateRepo := do.MustInvoke[Repository](injector)
+
+	return &ServiceImpl{
+		adminPolicy:        admin.NewAdminPolicy(),
+		organizationPolicy: organizationPolicy,
+		templateRepo:       templateRepo,
+	}, nil
+}
+
+func (s *ServiceImpl) List(authUser auth.User) ([]Template, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return []Template{}, errors.NewForbiddenError("emailTemplate.error.listForbidden")
+	}
+
+	return s.templateRepo.List()
+}
+
+func (s *ServiceImpl) GetByUUID(templateUUID uuid.UUID, authUser auth.User) (Template, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return Template{}, errors.NewForbiddenError("emailTemplate.error.viewForbidden")
+	}
+
+	return s.templateRepo.GetByUUID(templateUUID)
+}
+
+func (s *ServiceImpl) Create(request *CreateTemplateInput, authUser auth.User) (Template, error) {
+	if !s.adminPolicy.CanCreate(authUser) {
+		return Template{}, errors.NewForbiddenError("emailTemplate.error.createForbidden")
+	}
+
+	exists, err := s.templateRepo.ExistsByName(request.Name)
+	if err != nil {
+		return Template{}, err
+	}
+
+	if exists {
+		return Template{}, errors.NewBadRequestError("emailTemplate.error.duplicateName")
+	}
+
+	if err = s.validateLayout(request); err != nil {
+		return Template{}, err
+	}
+
+	templateInput := Template{
+		CreatedBy: uuid.NullUUID{UUID: authUser.Uuid, Valid: true},
+		UpdatedBy: uuid.NullUUID{UUID: authUser.Uuid, Valid: true},
+	}
+
+	fill(&templateInput, request)
+
+	if _, err = s.templateRepo.Create(&templateInput); err != nil {
+		return Template{}, err
+	}
+
+	return templateInput, nil
+}
+
+func (s *ServiceImpl) Update(templateUUID uuid.UUID, request *CreateTemplateInput, authUser auth.User) (*Template, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return nil, errors.NewForbiddenError("emailTemplate.error.updateForbidden")
+	}
+
+	fetchedTemplate, err := s.templateRepo.GetByUUID(templateUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Code sends templates by name and messages refer to layouts by name, so both stay as they are
+	if request.Name != fetchedTemplate.Name || request.Type != fetchedTemplate.Type {
+		return nil, errors.NewBadRequestError("emailTemplate.error.immutableName")
+	}
+
+	if err = s.validateLayout(request); err != nil {
+		return nil, err
+	}
+
+	fill(&fetchedTemplate, request)
+
+	fetchedTemplate.UpdatedAt = time.Now()
+	fetchedTemplate.UpdatedBy = uuid.NullUUID{UUID: authUser.Uuid, Valid: true}
+
+	return s.templateRepo.Update(&fetchedTemplate)
+}
+
+func (s *ServiceImpl) Delete(templateUUID uuid.UUID, authUser auth.User) (bool, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return false, errors.NewForbiddenError("emailTemplate.error.deleteForbidden")
+	}
+
+	fetchedTemplate, err := s.templateRepo.GetByUUID(templateUUID)
+	if err != nil {
+		return false, err
+	}
+
+	if fetchedTemplate.Type == constants.EmailTemplateTypeLayout {
+		inUse, err := s.templateRepo.IsLayoutInUse(fetchedTemplate.Name)
+		if err != nil {
+			return false, err
+		}
+
+		if inUse || fetchedTemplate.Name == constants.EmailTemplateDefaultLayout {
+			return false, errors.NewBadRequestError("emailTemplate.error.layoutInUse")
+		}
+	}
+
+	return s.templateRepo.Delete(fetchedTemplate.Uuid)
+}
+
+// ListForOrganization lists the templates as the organization sends them, with its overrides applied
+func (s *ServiceImpl) ListForOrganization(organizationUUID uuid.UUID, authUser auth.User) ([]Template, error) {
+	if !s.organizationPolicy.CanAccess(organizationUUID, authUser) {
+		return []Template{}, errors.NewForbiddenError("emailTemplate.error.listForbidden")
+	}
+
+	templates, err := s.templateRepo.List()
+	if err != nil {
+		return []Template{}, err
+	}
+
+	overrides, err := s.templateRepo.ListOverridesForOrganization(organizationUUID)
+	if err != nil {
+		return []Template{}, err
+	}
+
+	overridesByTemplate := make(map[int]Override, len(overrides))
+	for _, override := range overrides {
+		overridesByTemplate[override.TemplateId] = override
+	}
+
+	for i, template := range templates {
+		if override, ok := overridesByTemplate[template.Id]; ok {
+			templates[i] = template.Apply(override)
+		}
+	}
+
+	return templates, nil
+}
+
+// Customize saves the override of a template for an organization, replacing the previous one
+func (s *ServiceImpl) Customize(templateUUID, organizationUUID uuid.UUID, request *CustomizeTemplateInput, authUser auth.User) (Template, error) {
+	if !s.organizationPolicy.CanUpdate(organizationUUID, authUser) {
+		return Template{}, errors.NewForbiddenError("emailTemplate.error.updateForbidden")
+	}
+
+	fetchedTemplate, err := s.templateRepo.GetByUUID(templateUUID)
+	if err != nil {
+		return Template{}, err
+	}
+
+	// Layouts are shared by every template, so only messages are customized
+	if fetchedTemplate.Type != constants.EmailTemplateTypeMessage {
+		return Template{}, errors.NewBadRequestError("emailTemplate.error.notCustomizable")
+	}
+
+	override := Override{
+		TemplateId:       fetchedTemplate.Id,
+		OrganizationUuid: organizationUUID,
+		Subject:          request.Subject,
+		Message:          request.Message,
+		HtmlMessage:      request.HtmlMessage,
+		CreatedBy:        authUser.Uuid,
+		UpdatedBy:        authUser.Uuid,
+	}
+
+	if _, err = s.templateRepo.SaveOverride(&override); err != nil {
+		return Template{}, err
+	}
+
+	return fetchedTemplate.Apply(override), nil
+}
+
+func (s *ServiceImpl) ResetCustomization(templateUUID, organizationUUID uuid.UUID, authUser auth.User) (bool, error) {
+	if !s.organizationPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("emailTemplate.error.updateForbidden")
+	}
+
+	fetchedTemplate, err := s.templateRepo.GetByUUID(templateUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.templateRepo.DeleteOverride(fetchedTemplate.Id, organizationUUID)
+}
+
+// Preview renders a template as an organization would send it, or as is without one. Parameters left
+// out of params take their example
+func (s *ServiceImpl) Preview(templateUUID uuid.UUID, organizationUUID uuid.NullUUID, params Params, authUser auth.User) (Rendered, error) {
+	if organizationUUID.Valid {
+		if !s.organizationPolicy.CanAccess(organizationUUID.UUID, authUser) {
+			return Rendered{}, errors.NewForbiddenError("emailTemplate.error.viewForbidden")
+		}
+	} else if !s.adminPolicy.CanAccess(authUser) {
+		return Rendered{}, errors.NewForbiddenError("emailTemplate.error.viewForbidden")
+	}
+
+	fetchedTemplate, err := s.templateRepo.GetByUUID(templateUUID)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	if fetchedTemplate.Type != constants.EmailTemplateTypeMessage {
+		return Rendered{}, errors.NewBadRequestError("emailTemplate.error.notAMessage")
+	}
+
+	fetchedTemplate, layout, err := s.resolve(fetchedTemplate, organizationUUID)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	declared := fetchedTemplate.Parameters
+	if layout != nil {
+		declared = append(append(Parameters{}, declared...), layout.Parameters...)
+	}
+
+	return render(fetchedTemplate, layout, exampleParams(declared, params))
+}
+
+// Render renders a message template by name for sending, with the overrides of the organization when one is given
+func (s *ServiceImpl) Render(name string, params Params, organizationUUID uuid.NullUUID) (Rendered, error) {
+	fetchedTemplate, err := s.templateRepo.GetByName(name)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	if fetchedTemplate.Type != constants.EmailTemplateTypeMessage {
+		return Rendered{}, errors.NewBadRequestError("emailTemplate.error.notAMessage")
+	}
+
+	fetchedTemplate, layout, err := s.resolve(fetchedTemplate, organizationUUID)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	return render(fetchedTemplate, layout, params)
+}
+
+// resolve applies the override of the organization to a message template and fetches its layout
+func (s *ServiceImpl) resolve(message Template, organizationUUID uuid.NullUUID) (Template, *Template, error) {
+	if organizationUUID.Valid {
+		override, err := s.templateRepo.GetOverride(message.Id, organizationUUID.UUID)
+
+		var notFoundErr *errors.NotFoundError
+		if err != nil && !stdErrors.As(err, &notFoundErr) {
+			return Template{}, nil, err
+		}
+
+		if err == nil {
+			message = message.Apply(override)
+		}
+	}
+
+	if !message.Layout.Valid {
+		return message, nil, nil
+	}
+
+	layout, err := s.templateRepo.GetByName(message.Layout.String)
+	if err != nil {
+		return Template{}, nil, err
+	}
+
+	return message, &layout, nil
+}
+
+// validateLayout requires messages to name an existing layout, and layouts not to be wrapped themselves
+func (s *ServiceImpl) validateLayout(request *CreateTemplateInput) error {
+	if !request.Layout.Valid {
+		return nil
+	}
+
+	if request.Type == constants.EmailTemplateTypeLayout {
+		return errors.NewBadRequestError("emailTemplate.error.nestedLayout")
+	}
+
+	layout, err := s.templateRepo.GetByName(request.Layout.String)
+	if err != nil {
+		var notFoundErr *errors.NotFoundError
+		if stdErrors.As(err, &notFoundErr) {
+			return errors.NewBadRequestError("emailTemplate.error.layoutNotFound")
+		}
+
+		return err
+	}
+
+	if layout.Type != constants.EmailTemplateTypeLayout {
+		return errors.NewBadRequestError("emailTemplate.error.layoutNotFound")
+	}
+
+	return nil
+}
+
+// fill copies the input onto a template
+func fill(template *Template, request *CreateTemplateInput) {
+	template.Name = request.Name
+	template.Type = request.Type
+	template.Parameters = request.Parameters
+	template.Subject = request.Subject
+	template.Message = request.Message
+	template.HtmlMessage = request.HtmlMessage
+	template.Layout = request.Layout
+
+	if template.Parameters == nil {
+		template.Parameters = Parameters{}
+	}
+}
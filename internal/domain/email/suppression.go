@@ -0,0 +1,33 @@
+package email
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Suppression is an address nothing is sent to anymore, after it bounced, complained or was added by an admin
+type Suppression struct {
+	shared.BaseEntity
+	Uuid              uuid.UUID     `db:"uuid" json:"uuid"`
+	Email             string        `db:"email" json:"email"`
+	Reason            string        `db:"reason" json:"reason"`
+	Driver            string        `db:"driver" json:"driver"` // the one that reported it
+	ProviderMessageId string        `db:"provider_message_id" json:"providerMessageId"`
+	Details           string        `db:"details" json:"details"`
+	CreatedBy         uuid.NullUUID `db:"created_by" json:"createdBy"`
+	CreatedAt         time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updatedAt"`
+}
+
+// SuppressionAddress is how addresses are kept on the suppression list, bare and lowercased, so
+// "Jane <Jane@Example.com>" matches jane@example.com
+func SuppressionAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+
+	return strings.ToLower(strings.TrimSpace(address))
+}
@@ -0,0 +1,14 @@
+package email
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+type SuppressionRepository interface {
+	List(input *ListSuppressionsInput, paginationParams shared.PaginationParams) ([]Suppression, shared.PaginationDetails, error)
+	GetByUUID(suppressionUUID uuid.UUID) (Suppression, error)
+	Save(suppression *Suppression) (*Suppression, error)
+	Delete(suppressionUUID uuid.UUID) (bool, error)
+	ListSuppressed(addresses []string) ([]string, error)
+}
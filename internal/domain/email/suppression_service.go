@@ -0,0 +1,74 @@
+package email
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+// SuppressionService manages the addresses the outbox doesn't send to anymore
+type SuppressionService interface {
+	List(input *ListSuppressionsInput, paginationParams shared.PaginationParams, authUser auth.User) ([]Suppression, shared.PaginationDetails, error)
+	Create(input *CreateSuppressionInput, authUser auth.User) (Suppression, error)
+	Delete(suppressionUUID uuid.UUID, authUser auth.User) (bool, error)
+}
+
+type SuppressionServiceImpl struct {
+	adminPolicy     *admin.Policy
+	suppressionRepo SuppressionRepository
+}
+
+func NewSuppressionService(injector *do.Injector) (SuppressionService, error) {
+	suppressionRepo := do.MustInvoke[SuppressionRepository](injector)
+
+	return &SuppressionServiceImpl{
+		adminPolicy:     admin.NewAdminPolicy(),
+		suppressionRepo: suppressionRepo,
+	}, nil
+}
+
+func (s *SuppressionServiceImpl) List(input *ListSuppressionsInput, paginationParams shared.PaginationParams, authUser auth.User) ([]Suppression, shared.PaginationDetails, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, shared.PaginationDetails{}, errors.NewForbiddenError("emailSuppression.error.listForbidden")
+	}
+
+	return s.suppressionRepo.List(input, paginationParams)
+}
+
+// Create suppresses an address by hand, an address already on the list gets the manual reason
+func (s *SuppressionServiceImpl) Create(input *CreateSuppressionInput, authUser auth.User) (Suppression, error) {
+	if !s.adminPolicy.CanCreate(authUser) {
+		return Suppression{}, errors.NewForbiddenError("emailSuppression.error.createForbidden")
+	}
+
+	suppression := Suppression{
+		Email:     SuppressionAddress(input.Email),
+		Reason:    constants.EmailSuppressionReasonManual,
+		Details:   input.Details,
+		CreatedBy: uuid.NullUUID{UUID: authUser.Uuid, Valid: true},
+	}
+
+	if _, err := s.suppressionRepo.Save(&suppression); err != nil {
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+// Delete lifts a suppression, messages to the address are sent again
+func (s *SuppressionServiceImpl) Delete(suppressionUUID uuid.UUID, authUser auth.User) (bool, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return false, errors.NewForbiddenError("emailSuppression.error.deleteForbidden")
+	}
+
+	fetchedSuppression, err := s.suppressionRepo.GetByUUID(suppressionUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.suppressionRepo.Delete(fetchedSuppression.Uuid)
+}
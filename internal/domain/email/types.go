@@ -0,0 +1,61 @@
+package email
+
+import (
+	emailAdapter "fluxend/internal/adapters/email"
+	"github.com/guregu/null/v6"
+	"time"
+)
+
+type CreateTemplateInput struct {
+	Name        string
+	Type        string
+	Parameters  Parameters
+	Subject     string
+	Message     string
+	HtmlMessage string
+	Layout      null.String
+}
+
+type CustomizeTemplateInput struct {
+	Subject     string
+	Message     string
+	HtmlMessage string
+}
+
+// Rendered is a template ready to be sent
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type ListOutboxInput struct {
+	Status    null.String
+	Recipient null.String
+	Template  null.String
+}
+
+// OutboxDetails is an outbox message with what it sends and every driver tried so far
+type OutboxDetails struct {
+	Message    OutboxMessage
+	Payload    Payload
+	Deliveries []Delivery
+}
+
+// CapturedMessage is a message saved by the FILE mail driver, listings leave its bodies and attachments out
+type CapturedMessage struct {
+	ID        string
+	Size      int64
+	CreatedAt time.Time
+	Message   emailAdapter.Message
+}
+
+type ListSuppressionsInput struct {
+	Email  null.String
+	Reason null.String
+}
+
+type CreateSuppressionInput struct {
+	Email   string
+	Details string
+}
@@ -0,0 +1,167 @@
+package email
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	emailAdapter "fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/setting"
+	"fluxend/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"slices"
+	"strings"
+)
+
+// WebhookService suppresses the addresses providers report as bounced or complaining, after checking
+// the report was signed by the provider
+type WebhookService interface {
+	HandleSES(payload []byte) error
+	HandleSendGrid(payload []byte, signature, timestamp string) error
+	HandleMailgun(payload []byte) error
+}
+
+type WebhookServiceImpl struct {
+	settingService  setting.Service
+	suppressionRepo SuppressionRepository
+	snsVerifier     *emailAdapter.SNSVerifier
+}
+
+func NewWebhookService(injector *do.Injector) (WebhookService, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+	suppressionRepo := do.MustInvoke[SuppressionRepository](injector)
+
+	return &WebhookServiceImpl{
+		settingService:  settingService,
+		suppressionRepo: suppressionRepo,
+		snsVerifier:     emailAdapter.NewSNSVerifier(),
+	}, nil
+}
+
+// HandleSES accepts SNS notifications of the configured topics only, anyone can have SNS sign a
+// message for a topic of their own
+func (s *WebhookServiceImpl) HandleSES(payload []byte) error {
+	topicArns := s.topicArns()
+	if len(topicArns) == 0 {
+		return errors.NewForbiddenError("emailWebhook.error.notConfigured")
+	}
+
+	var message emailAdapter.SNSMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return errors.NewBadRequestError("emailWebhook.error.invalidPayload")
+	}
+
+	if !slices.Contains(topicArns, message.TopicArn) {
+		return errors.NewForbiddenError("emailWebhook.error.unknownTopic")
+	}
+
+	if err := s.snsVerifier.Verify(message); err != nil {
+		return s.verificationError(err)
+	}
+
+	switch message.Type {
+	case "SubscriptionConfirmation":
+		log.Info().
+			Str("action", constants.ActionEmailWebhook).
+			Str("topic_arn", message.TopicArn).
+			Msg("confirming SNS subscription")
+
+		return s.snsVerifier.ConfirmSubscription(message)
+	case "Notification":
+		feedback, err := emailAdapter.ParseSESFeedback(message.Message)
+		if err != nil {
+			return errors.NewBadRequestError("emailWebhook.error.invalidPayload")
+		}
+
+		return s.suppress(constants.EmailDriverSES, feedback)
+	}
+
+	return nil
+}
+
+func (s *WebhookServiceImpl) HandleSendGrid(payload []byte, signature, timestamp string) error {
+	publicKey := s.settingService.GetValue("sendgridWebhookPublicKey")
+	if publicKey == "" {
+		return errors.NewForbiddenError("emailWebhook.error.notConfigured")
+	}
+
+	if err := emailAdapter.VerifySendGridSignature(publicKey, payload, signature, timestamp); err != nil {
+		return s.verificationError(err)
+	}
+
+	feedback, err := emailAdapter.ParseSendGridFeedback(payload)
+	if err != nil {
+		return errors.NewBadRequestError("emailWebhook.error.invalidPayload")
+	}
+
+	return s.suppress(constants.EmailDriverSendGrid, feedback)
+}
+
+func (s *WebhookServiceImpl) HandleMailgun(payload []byte) error {
+	signingKey := s.settingService.GetValue("mailgunWebhookSigningKey")
+	if signingKey == "" {
+		return errors.NewForbiddenError("emailWebhook.error.notConfigured")
+	}
+
+	feedback, err := emailAdapter.ParseMailgunFeedback(signingKey, payload)
+	if err != nil {
+		return s.verificationError(err)
+	}
+
+	return s.suppress(constants.EmailDriverMailgun, feedback)
+}
+
+func (s *WebhookServiceImpl) suppress(driver string, feedback []emailAdapter.Feedback) error {
+	for _, item := range feedback {
+		address := SuppressionAddress(item.Address)
+		if address == "" {
+			continue
+		}
+
+		suppression := Suppression{
+			Email:             address,
+			Reason:            item.Type,
+			Driver:            driver,
+			ProviderMessageId: item.ProviderMessageID,
+			Details:           item.Details,
+		}
+
+		if _, err := s.suppressionRepo.Save(&suppression); err != nil {
+			return err
+		}
+
+		log.Info().
+			Str("action", constants.ActionEmailWebhook).
+			Str("driver", driver).
+			Str("reason", item.Type).
+			Str("email", address).
+			Msg("email address suppressed")
+	}
+
+	return nil
+}
+
+// verificationError tells bad signatures apart from payloads that couldn't be read to check them
+func (s *WebhookServiceImpl) verificationError(err error) error {
+	if stdErrors.Is(err, emailAdapter.ErrInvalidWebhookSignature) {
+		log.Warn().
+			Str("action", constants.ActionEmailWebhook).
+			Str("error", err.Error()).
+			Msg("rejected email webhook")
+
+		return errors.NewUnauthorizedError("emailWebhook.error.invalidSignature")
+	}
+
+	return errors.NewBadRequestError("emailWebhook.error.invalidPayload")
+}
+
+func (s *WebhookServiceImpl) topicArns() []string {
+	var topicArns []string
+	for _, topicArn := range strings.Split(s.settingService.GetValue("sesWebhookTopicArns"), ",") {
+		if topicArn = strings.TrimSpace(topicArn); topicArn != "" {
+			topicArns = append(topicArns, topicArn)
+		}
+	}
+
+	return topicArns
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type Service interface {
-	List() ([]Setting, error)
+	List(authUser auth.User) ([]Setting, error)
 	Get(name string) Setting
 	GetValue(name string) string
 	GetBool(name string) bool
@@ -35,7 +35,12 @@ func NewSettingService(injector *do.Injector) (Service, error) {
 	}, nil
 }
 
-func (s *ServiceImpl) List() ([]Setting, error) {
+// List returns every setting, credentials of external services included, so only admins see them
+func (s *ServiceImpl) List(authUser auth.User) ([]Setting, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, errors.NewForbiddenError("setting.error.listForbidden")
+	}
+
 	// TODO: cache using Redis instead of context
 	settings, err := s.settingRepo.List()
 	if err != nil {
@@ -100,7 +105,7 @@ func (s *ServiceImpl) Update(authUser auth.User, request *setting.UpdateRequest)
 		return nil, err
 	}
 
-	return s.List()
+	return s.settingRepo.List()
 }
 
 func (s *ServiceImpl) Reset(authUser auth.User) ([]Setting, error) {
@@ -123,7 +128,7 @@ func (s *ServiceImpl) Reset(authUser auth.User) ([]Setting, error) {
 		return []Setting{}, err
 	}
 
-	return s.List()
+	return s.settingRepo.List()
 }
 
 func (s *ServiceImpl) GetStorageDriver() string {
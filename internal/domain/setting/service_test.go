@@ -0,0 +1,41 @@
+package setting
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/pkg/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settingStore struct {
+	Repository
+	settings []Setting
+}
+
+func (ss *settingStore) List() ([]Setting, error) {
+	return ss.settings, nil
+}
+
+func TestServiceImpl_List_Suite(t *testing.T) {
+	service := &ServiceImpl{
+		adminPolicy: admin.NewAdminPolicy(),
+		settingRepo: &settingStore{settings: []Setting{{Name: "awsSecretAccessKey", Value: "secret"}}},
+	}
+
+	t.Run("List: superman sees the settings", func(t *testing.T) {
+		settings, err := service.List(auth.User{RoleID: constants.UserRoleSuperman})
+
+		assert.NoError(t, err)
+		assert.Len(t, settings, 1)
+	})
+
+	t.Run("List: other roles are refused", func(t *testing.T) {
+		settings, err := service.List(auth.User{RoleID: constants.UserRoleAdmin})
+
+		assert.IsType(t, &errors.ForbiddenError{}, err)
+		assert.Nil(t, settings)
+	})
+}
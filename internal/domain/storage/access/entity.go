@@ -0,0 +1,25 @@
+package access
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+// Policy lets the end users of a project read, upload or delete the files of a container whose name matches
+// PathPattern. Placeholders like {sub} in the pattern are replaced with the claims of the end user JWT
+type Policy struct {
+	shared.BaseEntity
+	Uuid          uuid.UUID      `db:"uuid" json:"uuid"`
+	ContainerUuid uuid.UUID      `db:"container_uuid" json:"containerUuid"`
+	Name          string         `db:"name" json:"name"`
+	Operations    pq.StringArray `db:"operations" json:"operations"`
+	PathPattern   string         `db:"path_pattern" json:"pathPattern"`
+	Roles         pq.StringArray `db:"roles" json:"roles"` // empty for every role
+	IsEnabled     bool           `db:"is_enabled" json:"isEnabled"`
+	CreatedBy     uuid.UUID      `db:"created_by" json:"createdBy"`
+	UpdatedBy     uuid.UUID      `db:"updated_by" json:"updatedBy"`
+	CreatedAt     time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,99 @@
+package access
+
+import (
+	"fluxend/internal/domain/auth"
+	"slices"
+	"strings"
+)
+
+// Allows tells whether a policy grants an end user the operation on a file name
+func (p Policy) Allows(endUser auth.EndUser, operation, fullFileName string) bool {
+	if !p.IsEnabled || !slices.Contains(p.Operations, operation) {
+		return false
+	}
+
+	if len(p.Roles) > 0 && !slices.Contains(p.Roles, endUser.Role) {
+		return false
+	}
+
+	if !isPlainPath(fullFileName) {
+		return false
+	}
+
+	pattern, ok := expandClaims(p.PathPattern, endUser)
+	if !ok {
+		return false
+	}
+
+	return matchWildcard(pattern, fullFileName)
+}
+
+// expandClaims replaces every {claim} of a pattern with the claim of the end user. A missing claim or
+// one that could reach further than a single path segment never matches
+func expandClaims(pattern string, endUser auth.EndUser) (string, bool) {
+	var expanded strings.Builder
+
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start == -1 {
+			expanded.WriteString(pattern)
+
+			return expanded.String(), true
+		}
+
+		end := strings.IndexByte(pattern[start:], '}')
+		if end == -1 {
+			return "", false
+		}
+
+		value, ok := endUser.Claim(pattern[start+1 : start+end])
+		if !ok || value == "" || value == "." || value == ".." || strings.ContainsAny(value, `/\*{}`) {
+			return "", false
+		}
+
+		expanded.WriteString(pattern[:start])
+		expanded.WriteString(value)
+		pattern = pattern[start+end+1:]
+	}
+}
+
+// matchWildcard matches a name against a pattern where * stands for any characters, slashes included
+func matchWildcard(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(name, part)
+		if index == -1 {
+			return false
+		}
+
+		name = name[index+len(part):]
+	}
+
+	return strings.HasSuffix(name, last)
+}
+
+// isPlainPath rejects names whose segments would resolve elsewhere once cleaned by a storage driver,
+// so users/{sub}/* can't be used to reach users/someone-else/
+func isPlainPath(name string) bool {
+	if name == "" || strings.Contains(name, `\`) {
+		return false
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,116 @@
+package access
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Allows_Suite(t *testing.T) {
+	alice := auth.EndUser{
+		Role:   "authenticated",
+		Claims: map[string]interface{}{"sub": "alice", "org_id": float64(7)},
+	}
+	anonymous := auth.EndUser{Role: "web_anon", Claims: map[string]interface{}{}}
+
+	ownFiles := Policy{
+		Operations:  []string{constants.PolicyOperationRead, constants.PolicyOperationUpload},
+		PathPattern: "users/{sub}/*",
+		Roles:       []string{"authenticated"},
+		IsEnabled:   true,
+	}
+
+	t.Run("Allows: own folder", func(t *testing.T) {
+		assert.True(t, ownFiles.Allows(alice, constants.PolicyOperationUpload, "users/alice/avatar.png"))
+		assert.True(t, ownFiles.Allows(alice, constants.PolicyOperationRead, "users/alice/docs/cv.pdf"))
+	})
+
+	t.Run("Allows: someone else's folder", func(t *testing.T) {
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationRead, "users/bob/avatar.png"))
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationRead, "users/alice2/avatar.png"))
+	})
+
+	t.Run("Allows: operation not granted", func(t *testing.T) {
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationDelete, "users/alice/avatar.png"))
+	})
+
+	t.Run("Allows: role not granted", func(t *testing.T) {
+		anonymous.Claims["sub"] = "alice"
+		assert.False(t, ownFiles.Allows(anonymous, constants.PolicyOperationRead, "users/alice/avatar.png"))
+	})
+
+	t.Run("Allows: disabled policy", func(t *testing.T) {
+		disabled := ownFiles
+		disabled.IsEnabled = false
+
+		assert.False(t, disabled.Allows(alice, constants.PolicyOperationRead, "users/alice/avatar.png"))
+	})
+
+	t.Run("Allows: missing claim", func(t *testing.T) {
+		policy := ownFiles
+		policy.PathPattern = "teams/{team_id}/*"
+
+		assert.False(t, policy.Allows(alice, constants.PolicyOperationRead, "teams//avatar.png"))
+		assert.False(t, policy.Allows(alice, constants.PolicyOperationRead, "teams/{team_id}/avatar.png"))
+	})
+
+	t.Run("Allows: numeric claim", func(t *testing.T) {
+		policy := ownFiles
+		policy.PathPattern = "orgs/{org_id}/*.pdf"
+
+		assert.True(t, policy.Allows(alice, constants.PolicyOperationRead, "orgs/7/invoices/march.pdf"))
+		assert.False(t, policy.Allows(alice, constants.PolicyOperationRead, "orgs/7/invoices/march.csv"))
+	})
+
+	t.Run("Allows: claims reaching past a segment", func(t *testing.T) {
+		for _, sub := range []string{"alice/../bob", "*", "..", ""} {
+			mallory := auth.EndUser{Role: "authenticated", Claims: map[string]interface{}{"sub": sub}}
+
+			assert.False(t, ownFiles.Allows(mallory, constants.PolicyOperationRead, "users/bob/avatar.png"), sub)
+		}
+	})
+
+	t.Run("Allows: names resolving elsewhere", func(t *testing.T) {
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationUpload, "users/alice/../bob/avatar.png"))
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationUpload, "users/alice/./avatar.png"))
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationUpload, "users/alice//avatar.png"))
+		assert.False(t, ownFiles.Allows(alice, constants.PolicyOperationUpload, `users/alice/..\bob\avatar.png`))
+	})
+
+	t.Run("Allows: every role without a pattern placeholder", func(t *testing.T) {
+		public := Policy{
+			Operations:  []string{constants.PolicyOperationRead},
+			PathPattern: "public/*",
+			Roles:       []string{},
+			IsEnabled:   true,
+		}
+
+		assert.True(t, public.Allows(anonymous, constants.PolicyOperationRead, "public/logo.svg"))
+		assert.False(t, public.Allows(anonymous, constants.PolicyOperationRead, "private/logo.svg"))
+	})
+}
+
+func TestMatchWildcard(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{"avatar.png", "avatar.png", true},
+		{"avatar.png", "avatar.jpg", false},
+		{"users/alice/*", "users/alice/a/b/c.txt", true},
+		{"users/alice/*", "users/alice", false},
+		{"*.png", "users/alice/avatar.png", true},
+		{"users/*/avatar.png", "users/alice/avatar.png", true},
+		{"users/*/avatar.png", "users/alice/old/avatar.png", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{"a*a", "a", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.matches, matchWildcard(c.pattern, c.name), "%s ~ %s", c.pattern, c.name)
+	}
+}
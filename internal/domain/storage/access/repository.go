@@ -0,0 +1,15 @@
+package access
+
+import (
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	ListForContainer(containerUUID uuid.UUID) ([]Policy, error)
+	ListEnabledForContainer(containerUUID uuid.UUID) ([]Policy, error)
+	GetByUUID(policyUUID uuid.UUID) (Policy, error)
+	ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error)
+	Create(policy *Policy) (*Policy, error)
+	Update(policy *Policy) (*Policy, error)
+	Delete(policyUUID uuid.UUID) (bool, error)
+}
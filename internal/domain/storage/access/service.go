@@ -0,0 +1,303 @@
+package access
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"time"
+)
+
+type Service interface {
+	List(containerUUID uuid.UUID, authUser auth.User) ([]Policy, error)
+	GetByUUID(policyUUID, containerUUID uuid.UUID, authUser auth.User) (Policy, error)
+	Create(containerUUID uuid.UUID, request *CreatePolicyInput, authUser auth.User) (Policy, error)
+	Update(policyUUID, containerUUID uuid.UUID, request *CreatePolicyInput, authUser auth.User) (*Policy, error)
+	Delete(policyUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	Upload(containerUUID uuid.UUID, request *file.StoreFileInput, endUser auth.EndUser) (file.File, error)
+	Download(containerUUID uuid.UUID, fullFileName string, endUser auth.EndUser, byteRange *storage.ByteRange) (file.File, *storage.FileObject, error)
+	Remove(containerUUID uuid.UUID, fullFileName string, endUser auth.EndUser) error
+}
+
+type ServiceImpl struct {
+	projectPolicy *project.Policy
+	policyRepo    Repository
+	containerRepo container.Repository
+	fileRepo      file.Repository
+	projectRepo   project.Repository
+	fileService   file.Service
+}
+
+func NewAccessService(injector *do.Injector) (Service, error) {
+	policy := do.MustInvoke[*project.Policy](injector)
+	policyRepo := do.MustInvoke[Repository](injector)
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	fileRepo := do.MustInvoke[file.Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &ServiceImpl{
+		projectPolicy: policy,
+		policyRepo:    policyRepo,
+		containerRepo: containerRepo,
+		fileRepo:      fileRepo,
+		projectRepo:   projectRepo,
+		fileService:   fileService,
+	}, nil
+}
+
+func (s *ServiceImpl) List(containerUUID uuid.UUID, authUser auth.User) ([]Policy, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return []Policy{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return []Policy{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return []Policy{}, errors.NewForbiddenError("policy.error.listForbidden")
+	}
+
+	return s.policyRepo.ListForContainer(containerUUID)
+}
+
+func (s *ServiceImpl) GetByUUID(policyUUID, containerUUID uuid.UUID, authUser auth.User) (Policy, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return Policy{}, errors.NewForbiddenError("policy.error.viewForbidden")
+	}
+
+	return s.getForContainer(policyUUID, containerUUID)
+}
+
+func (s *ServiceImpl) Create(containerUUID uuid.UUID, request *CreatePolicyInput, authUser auth.User) (Policy, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if !s.projectPolicy.CanCreate(organizationUUID, authUser) {
+		return Policy{}, errors.NewForbiddenError("policy.error.createForbidden")
+	}
+
+	if err = s.validateNameForDuplication(request.Name, containerUUID); err != nil {
+		return Policy{}, err
+	}
+
+	policyInput := Policy{
+		ContainerUuid: containerUUID,
+		CreatedBy:     authUser.Uuid,
+		UpdatedBy:     authUser.Uuid,
+	}
+
+	fill(&policyInput, request)
+
+	if _, err = s.policyRepo.Create(&policyInput); err != nil {
+		return Policy{}, err
+	}
+
+	return policyInput, nil
+}
+
+func (s *ServiceImpl) Update(policyUUID, containerUUID uuid.UUID, request *CreatePolicyInput, authUser auth.User) (*Policy, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("policy.error.updateForbidden")
+	}
+
+	fetchedPolicy, err := s.getForContainer(policyUUID, containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != fetchedPolicy.Name {
+		if err = s.validateNameForDuplication(request.Name, containerUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	fill(&fetchedPolicy, request)
+
+	fetchedPolicy.UpdatedAt = time.Now()
+	fetchedPolicy.UpdatedBy = authUser.Uuid
+
+	return s.policyRepo.Update(&fetchedPolicy)
+}
+
+func (s *ServiceImpl) Delete(policyUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("policy.error.deleteForbidden")
+	}
+
+	fetchedPolicy, err := s.getForContainer(policyUUID, containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.policyRepo.Delete(fetchedPolicy.Uuid)
+}
+
+// Upload stores a file of an end user allowed by a policy of the container. End users have no platform
+// account, so the file is recorded as created by the author of the policy
+func (s *ServiceImpl) Upload(containerUUID uuid.UUID, request *file.StoreFileInput, endUser auth.EndUser) (file.File, error) {
+	matchedPolicy, err := s.authorize(containerUUID, endUser, constants.PolicyOperationUpload, request.FullFileName)
+	if err != nil {
+		return file.File{}, err
+	}
+
+	return s.fileService.StoreOnBehalf(containerUUID, request, auth.User{Uuid: matchedPolicy.CreatedBy})
+}
+
+// Download reads a file of the container for an end user allowed by one of its policies
+func (s *ServiceImpl) Download(containerUUID uuid.UUID, fullFileName string, endUser auth.EndUser, byteRange *storage.ByteRange) (file.File, *storage.FileObject, error) {
+	if _, err := s.authorize(containerUUID, endUser, constants.PolicyOperationRead, fullFileName); err != nil {
+		return file.File{}, nil, err
+	}
+
+	fetchedFile, err := s.fileRepo.GetByNameForContainer(fullFileName, containerUUID)
+	if err != nil {
+		return file.File{}, nil, err
+	}
+
+	fileObject, err := s.fileService.Open(fetchedFile, byteRange)
+	if err != nil {
+		return file.File{}, nil, err
+	}
+
+	return fetchedFile, fileObject, nil
+}
+
+// Remove deletes a file of the container for an end user allowed by one of its policies, containers with
+// a trash retention keep it in the trash
+func (s *ServiceImpl) Remove(containerUUID uuid.UUID, fullFileName string, endUser auth.EndUser) error {
+	if _, err := s.authorize(containerUUID, endUser, constants.PolicyOperationDelete, fullFileName); err != nil {
+		return err
+	}
+
+	fetchedFile, err := s.fileRepo.GetByNameForContainer(fullFileName, containerUUID)
+	if err != nil {
+		return err
+	}
+
+	return s.fileService.Expire(fetchedFile)
+}
+
+// authorize returns the first enabled policy of the container granting the operation on a file name.
+// Tokens share the platform secret, so they are only honoured for the project they were issued for.
+// Containers without a matching policy are closed to end users
+func (s *ServiceImpl) authorize(containerUUID uuid.UUID, endUser auth.EndUser, operation, fullFileName string) (Policy, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if !endUser.Anonymous {
+		projectUUID, ok := endUser.ProjectUUID()
+		if !ok || projectUUID != fetchedContainer.ProjectUuid {
+			return Policy{}, errors.NewForbiddenError("policy.error.projectMismatch")
+		}
+	}
+
+	policies, err := s.policyRepo.ListEnabledForContainer(containerUUID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	for _, policy := range policies {
+		if policy.Allows(endUser, operation, fullFileName) {
+			log.Info().
+				Str("action", constants.ActionStoragePolicy).
+				Str("container_uuid", containerUUID.String()).
+				Str("policy_uuid", policy.Uuid.String()).
+				Str("role", endUser.Role).
+				Str("operation", operation).
+				Str("file", fullFileName).
+				Msg("end user access granted")
+
+			return policy, nil
+		}
+	}
+
+	return Policy{}, errors.NewForbiddenError("policy.error.accessDenied")
+}
+
+func (s *ServiceImpl) getForContainer(policyUUID, containerUUID uuid.UUID) (Policy, error) {
+	fetchedPolicy, err := s.policyRepo.GetByUUID(policyUUID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if fetchedPolicy.ContainerUuid != containerUUID {
+		return Policy{}, errors.NewNotFoundError("policy.error.notFound")
+	}
+
+	return fetchedPolicy, nil
+}
+
+func (s *ServiceImpl) validateNameForDuplication(name string, containerUUID uuid.UUID) error {
+	exists, err := s.policyRepo.ExistsByNameForContainer(name, containerUUID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.NewUnprocessableError("policy.error.duplicateName")
+	}
+
+	return nil
+}
+
+// fill copies the input onto a policy
+func fill(policy *Policy, request *CreatePolicyInput) {
+	policy.Name = request.Name
+	policy.Operations = request.Operations
+	policy.PathPattern = request.PathPattern
+	policy.Roles = request.Roles
+	policy.IsEnabled = request.IsEnabled
+
+	if policy.Roles == nil {
+		policy.Roles = []string{}
+	}
+}
@@ -0,0 +1,82 @@
+package access
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg/errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type containerStore struct {
+	container.Repository
+	container container.Container
+}
+
+func (cs *containerStore) GetByUUID(containerUUID uuid.UUID) (container.Container, error) {
+	return cs.container, nil
+}
+
+type policyStore struct {
+	Repository
+	policies []Policy
+}
+
+func (ps *policyStore) ListEnabledForContainer(containerUUID uuid.UUID) ([]Policy, error) {
+	return ps.policies, nil
+}
+
+func TestServiceImpl_Authorize_Suite(t *testing.T) {
+	projectUUID := uuid.New()
+	containerUUID := uuid.New()
+
+	service := &ServiceImpl{
+		containerRepo: &containerStore{container: container.Container{Uuid: containerUUID, ProjectUuid: projectUUID}},
+		policyRepo: &policyStore{policies: []Policy{{
+			Operations:  []string{constants.PolicyOperationRead},
+			PathPattern: "public/*",
+			IsEnabled:   true,
+		}}},
+	}
+
+	endUser := func(claims map[string]interface{}) auth.EndUser {
+		return auth.EndUser{Role: "authenticated", Claims: claims}
+	}
+
+	t.Run("Authorize: token of the project", func(t *testing.T) {
+		for _, claims := range []map[string]interface{}{
+			{"project_uuid": projectUUID.String()},
+			{"aud": projectUUID.String()},
+			{"aud": []interface{}{projectUUID.String()}},
+		} {
+			_, err := service.authorize(containerUUID, endUser(claims), constants.PolicyOperationRead, "public/logo.png")
+
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("Authorize: token of another project", func(t *testing.T) {
+		_, err := service.authorize(containerUUID, endUser(map[string]interface{}{
+			"project_uuid": uuid.New().String(),
+		}), constants.PolicyOperationRead, "public/logo.png")
+
+		assert.IsType(t, &errors.ForbiddenError{}, err)
+	})
+
+	t.Run("Authorize: token without a project", func(t *testing.T) {
+		_, err := service.authorize(containerUUID, endUser(map[string]interface{}{"sub": "alice"}), constants.PolicyOperationRead, "public/logo.png")
+
+		assert.IsType(t, &errors.ForbiddenError{}, err)
+	})
+
+	t.Run("Authorize: anonymous end user", func(t *testing.T) {
+		anonymous := auth.EndUser{Role: "web_anon", Claims: map[string]interface{}{}, Anonymous: true}
+
+		_, err := service.authorize(containerUUID, anonymous, constants.PolicyOperationRead, "public/logo.png")
+
+		assert.NoError(t, err)
+	})
+}
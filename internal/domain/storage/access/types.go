@@ -0,0 +1,9 @@
+package access
+
+type CreatePolicyInput struct {
+	Name        string
+	Operations  []string
+	PathPattern string
+	Roles       []string
+	IsEnabled   bool
+}
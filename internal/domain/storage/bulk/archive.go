@@ -0,0 +1,71 @@
+package bulk
+
+import (
+	"archive/zip"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/file"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Archive is a ZIP of files fetched one by one from the storage provider of their container, nothing is
+// buffered so archives of any size can be streamed
+type Archive struct {
+	Name          string
+	Files         []file.File
+	provider      storage.Provider
+	containerName string
+}
+
+// Stream writes the archive to w. Once writing started an error can only abort the archive, so everything
+// that can be checked beforehand is checked when the archive is created
+func (a *Archive) Stream(w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+
+	for _, currentFile := range a.Files {
+		if err := a.add(zipWriter, currentFile); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", currentFile.FullFileName, err)
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+func (a *Archive) add(zipWriter *zip.Writer, currentFile file.File) error {
+	fileObject, err := a.provider.DownloadFile(storage.DownloadFileInput{
+		ContainerName: a.containerName,
+		FileName:      currentFile.StorageKey(),
+	})
+	if err != nil {
+		return err
+	}
+	defer fileObject.Body.Close()
+
+	entry, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     entryName(currentFile),
+		Method:   zip.Deflate,
+		Modified: currentFile.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, fileObject.Body)
+
+	return err
+}
+
+// entryName keeps the entries of an archive inside the folder it's extracted to, whatever the file names are:
+// names are made relative and ".." segments can't climb above the root
+func entryName(currentFile file.File) string {
+	name := strings.ReplaceAll(currentFile.FullFileName, "\\", "/")
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	if name == "" {
+		return currentFile.Uuid.String()
+	}
+
+	return name
+}
@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bytes"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/file"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryProvider serves downloads from a map, the other provider methods aren't used by archives
+type memoryProvider struct {
+	storage.Provider
+	objects map[string]string
+}
+
+func (p *memoryProvider) DownloadFile(input storage.DownloadFileInput) (*storage.FileObject, error) {
+	content, ok := p.objects[input.ContainerName+"/"+input.FileName]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", input.FileName)
+	}
+
+	return &storage.FileObject{
+		Body:          io.NopCloser(strings.NewReader(content)),
+		ContentLength: int64(len(content)),
+	}, nil
+}
+
+func TestArchive_Stream_Suite(t *testing.T) {
+	provider := &memoryProvider{objects: map[string]string{
+		"bucket/reports/january.csv":  "month,total\njanuary,10\n",
+		"bucket/reports/february.csv": "month,total\nfebruary,12\n",
+	}}
+
+	t.Run("Stream: every file becomes an entry", func(t *testing.T) {
+		modified := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+		archive := &Archive{
+			Name: "bucket.zip",
+			Files: []file.File{
+				{FullFileName: "reports/january.csv", UpdatedAt: modified},
+				{FullFileName: "reports/february.csv", UpdatedAt: modified},
+			},
+			provider:      provider,
+			containerName: "bucket",
+		}
+
+		var buffer bytes.Buffer
+		require.NoError(t, archive.Stream(&buffer))
+
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		require.NoError(t, err)
+		require.Len(t, reader.File, 2)
+
+		assert.Equal(t, "reports/january.csv", reader.File[0].Name)
+		assert.True(t, reader.File[0].Modified.Equal(modified))
+
+		entry, err := reader.File[1].Open()
+		require.NoError(t, err)
+		defer entry.Close()
+
+		content, err := io.ReadAll(entry)
+		require.NoError(t, err)
+		assert.Equal(t, "month,total\nfebruary,12\n", string(content))
+	})
+
+	t.Run("Stream: names can't leave the extraction folder", func(t *testing.T) {
+		fileUUID := uuid.New()
+		archive := &Archive{
+			Name: "bucket.zip",
+			Files: []file.File{
+				{FullFileName: "../../etc/cron.d/evil"},
+				{FullFileName: "/reports/./../reports/january.csv"},
+				{FullFileName: `..\..\windows\evil.dll`},
+				{Uuid: fileUUID, FullFileName: "../.."},
+			},
+			provider: &memoryProvider{objects: map[string]string{
+				"bucket/../../etc/cron.d/evil":             "a",
+				"bucket//reports/./../reports/january.csv": "b",
+				`bucket/..\..\windows\evil.dll`:            "c",
+				"bucket/../..":                             "d",
+			}},
+			containerName: "bucket",
+		}
+
+		var buffer bytes.Buffer
+		require.NoError(t, archive.Stream(&buffer))
+
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		require.NoError(t, err)
+		require.Len(t, reader.File, 4)
+
+		assert.Equal(t, "etc/cron.d/evil", reader.File[0].Name)
+		assert.Equal(t, "reports/january.csv", reader.File[1].Name)
+		assert.Equal(t, "windows/evil.dll", reader.File[2].Name)
+		assert.Equal(t, fileUUID.String(), reader.File[3].Name)
+	})
+
+	t.Run("Stream: empty selection is a valid archive", func(t *testing.T) {
+		archive := &Archive{Name: "bucket.zip", provider: provider, containerName: "bucket"}
+
+		var buffer bytes.Buffer
+		require.NoError(t, archive.Stream(&buffer))
+
+		reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		require.NoError(t, err)
+		assert.Len(t, reader.File, 0)
+	})
+
+	t.Run("Stream: missing object aborts the archive", func(t *testing.T) {
+		archive := &Archive{
+			Name:          "bucket.zip",
+			Files:         []file.File{{FullFileName: "reports/march.csv"}},
+			provider:      provider,
+			containerName: "bucket",
+		}
+
+		err := archive.Stream(io.Discard)
+		assert.ErrorContains(t, err, "failed to archive reports/march.csv")
+	})
+}
@@ -0,0 +1,30 @@
+package bulk
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Job deletes a selection of files of a container in the background
+type Job struct {
+	Uuid          uuid.UUID     `db:"uuid" json:"uuid"`
+	ContainerUuid uuid.UUID     `db:"container_uuid" json:"containerUuid"`
+	Status        string        `db:"status" json:"status"`
+	Error         string        `db:"error" json:"error"` // set when the job stopped before going through every file
+	TotalFiles    int           `db:"total_files" json:"totalFiles"`
+	DeletedFiles  int           `db:"deleted_files" json:"deletedFiles"`
+	FailedFiles   int           `db:"failed_files" json:"failedFiles"`
+	CreatedBy     uuid.NullUUID `db:"created_by" json:"createdBy"`
+	StartedAt     time.Time     `db:"started_at" json:"startedAt"`
+	CompletedAt   *time.Time    `db:"completed_at" json:"completedAt"`
+}
+
+// Result is the outcome of a job for one file
+type Result struct {
+	JobUuid      uuid.UUID `db:"job_uuid" json:"jobUuid"`
+	FileUuid     uuid.UUID `db:"file_uuid" json:"fileUuid"`
+	FullFileName string    `db:"full_file_name" json:"fullFileName"`
+	Status       string    `db:"status" json:"status"`
+	Error        string    `db:"error" json:"error"`
+	ProcessedAt  time.Time `db:"processed_at" json:"processedAt"`
+}
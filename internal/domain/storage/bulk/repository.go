@@ -0,0 +1,16 @@
+package bulk
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	GetByUUID(jobUUID uuid.UUID) (Job, error)
+	Create(job *Job) (*Job, error)
+	UpdateStatus(jobUUID uuid.UUID, status, error string, completedAt *time.Time) error
+	UpdateProgress(jobUUID uuid.UUID, deletedFiles, failedFiles int) error
+	FailStale(lastActiveBefore time.Time, error string) (int, error)
+	ListResults(jobUUID uuid.UUID) ([]Result, error)
+	CreateResult(result *Result) error
+}
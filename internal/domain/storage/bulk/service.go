@@ -0,0 +1,270 @@
+package bulk
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"time"
+)
+
+type Service interface {
+	CreateArchive(containerUUID uuid.UUID, request *SelectFilesInput, authUser auth.User) (*Archive, error)
+	CreateDeleteJob(containerUUID uuid.UUID, request *SelectFilesInput, authUser auth.User) (Job, error)
+	GetJob(jobUUID, containerUUID uuid.UUID, authUser auth.User) (Job, []Result, error)
+	FailStaleJobs() (int, error)
+}
+
+type ServiceImpl struct {
+	projectPolicy     *project.Policy
+	jobRepo           Repository
+	containerRepo     container.Repository
+	fileRepo          file.Repository
+	projectRepo       project.Repository
+	credentialService credential.Service
+	fileService       file.Service
+}
+
+func NewBulkService(injector *do.Injector) (Service, error) {
+	policy := do.MustInvoke[*project.Policy](injector)
+	jobRepo := do.MustInvoke[Repository](injector)
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	fileRepo := do.MustInvoke[file.Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	credentialService := do.MustInvoke[credential.Service](injector)
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &ServiceImpl{
+		projectPolicy:     policy,
+		jobRepo:           jobRepo,
+		containerRepo:     containerRepo,
+		fileRepo:          fileRepo,
+		projectRepo:       projectRepo,
+		credentialService: credentialService,
+		fileService:       fileService,
+	}, nil
+}
+
+// CreateArchive selects the files to download, the archive is only built while it's streamed
+func (s *ServiceImpl) CreateArchive(containerUUID uuid.UUID, request *SelectFilesInput, authUser auth.User) (*Archive, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	files, err := s.selectFiles(containerUUID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archive{
+		Name:          fetchedContainer.Name + ".zip",
+		Files:         files,
+		provider:      storageService,
+		containerName: fetchedContainer.NameKey,
+	}, nil
+}
+
+// CreateDeleteJob deletes the selected files in the background, each file is deleted like a single delete
+// would, so containers with a trash retention keep them in the trash
+func (s *ServiceImpl) CreateDeleteJob(containerUUID uuid.UUID, request *SelectFilesInput, authUser auth.User) (Job, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Job{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return Job{}, errors.NewForbiddenError("file.error.deleteForbidden")
+	}
+
+	files, err := s.selectFiles(containerUUID, request)
+	if err != nil {
+		return Job{}, err
+	}
+
+	jobInput := Job{
+		ContainerUuid: containerUUID,
+		Status:        constants.BulkJobStatusRunning,
+		TotalFiles:    len(files),
+		CreatedBy:     uuid.NullUUID{UUID: authUser.Uuid, Valid: true},
+		StartedAt:     time.Now(),
+	}
+
+	if _, err = s.jobRepo.Create(&jobInput); err != nil {
+		return Job{}, err
+	}
+
+	go s.runDeleteJob(jobInput, files, authUser)
+
+	return jobInput, nil
+}
+
+func (s *ServiceImpl) GetJob(jobUUID, containerUUID uuid.UUID, authUser auth.User) (Job, []Result, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Job{}, nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Job{}, nil, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return Job{}, nil, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	fetchedJob, err := s.jobRepo.GetByUUID(jobUUID)
+	if err != nil {
+		return Job{}, nil, err
+	}
+
+	if fetchedJob.ContainerUuid != containerUUID {
+		return Job{}, nil, errors.NewNotFoundError("bulk.error.jobNotFound")
+	}
+
+	results, err := s.jobRepo.ListResults(jobUUID)
+	if err != nil {
+		return Job{}, nil, err
+	}
+
+	return fetchedJob, results, nil
+}
+
+// FailStaleJobs fails the delete jobs left running by a process that went away, so they don't show as running forever
+func (s *ServiceImpl) FailStaleJobs() (int, error) {
+	return s.jobRepo.FailStale(time.Now().Add(-constants.BulkJobStaleAfter), "interrupted before going through every file")
+}
+
+// runDeleteJob goes through every file even when some fail, the job only fails when its progress can't be recorded
+func (s *ServiceImpl) runDeleteJob(job Job, files []file.File, authUser auth.User) {
+	// Nothing else would recover a panic of this goroutine, the job would stay running and the process go down
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.failDeleteJob(job, fmt.Errorf("bulk delete panicked: %v", recovered))
+		}
+	}()
+
+	deletedFiles, failedFiles := 0, 0
+
+	for _, currentFile := range files {
+		result := Result{
+			JobUuid:      job.Uuid,
+			FileUuid:     currentFile.Uuid,
+			FullFileName: currentFile.FullFileName,
+			Status:       constants.BulkResultDeleted,
+		}
+
+		if _, err := s.fileService.Delete(currentFile.Uuid, job.ContainerUuid, authUser); err != nil {
+			result.Status = constants.BulkResultFailed
+			result.Error = err.Error()
+			failedFiles++
+		} else {
+			deletedFiles++
+		}
+
+		result.ProcessedAt = time.Now()
+
+		err := s.jobRepo.CreateResult(&result)
+		if err == nil {
+			err = s.jobRepo.UpdateProgress(job.Uuid, deletedFiles, failedFiles)
+		}
+
+		if err != nil {
+			s.failDeleteJob(job, err)
+
+			return
+		}
+	}
+
+	completedAt := time.Now()
+	if err := s.jobRepo.UpdateStatus(job.Uuid, constants.BulkJobStatusCompleted, "", &completedAt); err != nil {
+		s.failDeleteJob(job, err)
+
+		return
+	}
+
+	log.Info().
+		Str("action", constants.ActionBulkDelete).
+		Str("job_uuid", job.Uuid.String()).
+		Str("container_uuid", job.ContainerUuid.String()).
+		Int("deleted_files", deletedFiles).
+		Int("failed_files", failedFiles).
+		Msg("bulk delete completed")
+}
+
+func (s *ServiceImpl) failDeleteJob(job Job, err error) {
+	log.Error().
+		Str("action", constants.ActionBulkDelete).
+		Str("job_uuid", job.Uuid.String()).
+		Str("error", err.Error()).
+		Msg("bulk delete failed")
+
+	completedAt := time.Now()
+	if updateErr := s.jobRepo.UpdateStatus(job.Uuid, constants.BulkJobStatusFailed, err.Error(), &completedAt); updateErr != nil {
+		log.Error().
+			Str("action", constants.ActionBulkDelete).
+			Str("job_uuid", job.Uuid.String()).
+			Str("error", updateErr.Error()).
+			Msg("failed to record bulk delete failure")
+	}
+}
+
+// selectFiles resolves a selection to live files of the container, UUIDs of other containers count as missing
+func (s *ServiceImpl) selectFiles(containerUUID uuid.UUID, request *SelectFilesInput) ([]file.File, error) {
+	if len(request.FileUUIDs) == 0 {
+		// One more than allowed tells a selection that's too large apart from one that fits exactly
+		files, err := s.fileRepo.ListByPrefixForContainer(containerUUID, request.Prefix, constants.MaxBulkFiles+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) > constants.MaxBulkFiles {
+			return nil, errors.NewUnprocessableError("bulk.error.tooManyFiles")
+		}
+
+		return files, nil
+	}
+
+	uniqueUUIDs := make(map[uuid.UUID]bool, len(request.FileUUIDs))
+	for _, fileUUID := range request.FileUUIDs {
+		uniqueUUIDs[fileUUID] = true
+	}
+
+	files, err := s.fileRepo.ListByUUIDsForContainer(containerUUID, request.FileUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) != len(uniqueUUIDs) {
+		return nil, errors.NewNotFoundError("file.error.notFound")
+	}
+
+	return files, nil
+}
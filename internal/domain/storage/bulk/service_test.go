@@ -0,0 +1,58 @@
+package bulk
+
+import (
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/storage/file"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jobStore records the status updates of jobs, results and progress are accepted as is
+type jobStore struct {
+	Repository
+	statuses map[uuid.UUID]string
+	errors   map[uuid.UUID]string
+}
+
+func (js *jobStore) UpdateStatus(jobUUID uuid.UUID, status, error string, completedAt *time.Time) error {
+	js.statuses[jobUUID] = status
+	js.errors[jobUUID] = error
+
+	return nil
+}
+
+func (js *jobStore) UpdateProgress(jobUUID uuid.UUID, deletedFiles, failedFiles int) error {
+	return nil
+}
+
+func (js *jobStore) CreateResult(result *Result) error {
+	return nil
+}
+
+type panickingDeleter struct {
+	file.Service
+}
+
+func (pd *panickingDeleter) Delete(fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	panic("provider went away")
+}
+
+func TestServiceImpl_RunDeleteJob_Suite(t *testing.T) {
+	t.Run("runDeleteJob: a panic fails the job instead of leaving it running", func(t *testing.T) {
+		jobs := &jobStore{statuses: map[uuid.UUID]string{}, errors: map[uuid.UUID]string{}}
+		service := &ServiceImpl{jobRepo: jobs, fileService: &panickingDeleter{}}
+		job := Job{Uuid: uuid.New(), ContainerUuid: uuid.New(), Status: constants.BulkJobStatusRunning}
+
+		require.NotPanics(t, func() {
+			service.runDeleteJob(job, []file.File{{Uuid: uuid.New(), FullFileName: "report.pdf"}}, auth.User{})
+		})
+
+		assert.Equal(t, constants.BulkJobStatusFailed, jobs.statuses[job.Uuid])
+		assert.Contains(t, jobs.errors[job.Uuid], "provider went away")
+	})
+}
@@ -0,0 +1,11 @@
+package bulk
+
+import (
+	"github.com/google/uuid"
+)
+
+// SelectFilesInput picks the live files of a container either by name prefix or by UUID
+type SelectFilesInput struct {
+	Prefix    string
+	FileUUIDs []uuid.UUID
+}
@@ -13,13 +13,21 @@ type Container struct {
 	Name        string    `db:"name" json:"name"`
 	NameKey     string    `db:"name_key" json:"nameKey"`
 	Provider    string    `db:"provider" json:"provider"`
-	Description string    `db:"description" json:"description"`
-	IsPublic    bool      `db:"is_public" json:"isPublic"`
-	Url         string    `db:"url" json:"url"`
-	TotalFiles  int       `db:"total_files" json:"totalFiles"`
-	MaxFileSize int       `db:"max_file_size" json:"maxFileSize"` // in KB
-	CreatedBy   uuid.UUID `db:"created_by" json:"createdBy"`
-	UpdatedBy   uuid.UUID `db:"updated_by" json:"updatedBy"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+	// Containers without a credential use the credentials of their provider from the environment
+	CredentialUuid     uuid.NullUUID `db:"credential_uuid" json:"credentialUuid"`
+	Description        string        `db:"description" json:"description"`
+	IsPublic           bool          `db:"is_public" json:"isPublic"`
+	Url                string        `db:"url" json:"url"`
+	TotalFiles         int           `db:"total_files" json:"totalFiles"`
+	MaxFileSize        int           `db:"max_file_size" json:"maxFileSize"`               // in KB
+	Versioning         bool          `db:"versioning" json:"versioning"`                   // keeps the previous content when a file name is uploaded again
+	TrashRetentionDays int           `db:"trash_retention_days" json:"trashRetentionDays"` // days deleted files are kept, 0 deletes them right away
+	Deduplication      bool          `db:"deduplication" json:"deduplication"`             // files with the same content share one stored object
+	CreatedBy          uuid.UUID     `db:"created_by" json:"createdBy"`
+	UpdatedBy          uuid.UUID     `db:"updated_by" json:"updatedBy"`
+	CreatedAt          time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt          time.Time     `db:"updated_at" json:"updatedAt"`
+
+	// Uploads flagged by a scanner go to this container of the same project, without it they're rejected
+	QuarantineContainerUuid uuid.NullUUID `db:"quarantine_container_uuid" json:"quarantineContainerUuid"`
 }
@@ -8,11 +8,16 @@ import (
 type Repository interface {
 	ListForProject(paginationParams shared.PaginationParams, projectUUID uuid.UUID) ([]Container, error)
 	GetByUUID(containerUUID uuid.UUID) (Container, error)
+	ListForProvider(provider string) ([]Container, error)
+	ListAll() ([]Container, error)
+	ListObjectKeys(containerUUID uuid.UUID) ([]string, error)
+	HasSharedObjects(containerUUID uuid.UUID) (bool, error)
 	ExistsByUUID(containerUUID uuid.UUID) (bool, error)
 	ExistsByNameForProject(name string, projectUUID uuid.UUID) (bool, error)
 	Create(container *Container) (*Container, error)
 	Update(container *Container) (*Container, error)
 	IncrementTotalFiles(containerUUID uuid.UUID) error
 	DecrementTotalFiles(containerUUID uuid.UUID) error
+	UpdateProvider(containerUUID uuid.UUID, provider, url string) error
 	Delete(containerUUID uuid.UUID) (bool, error)
 }
@@ -1,11 +1,12 @@
 package container
 
 import (
-	"fluxend/internal/adapters/storage"
 	"fluxend/internal/domain/auth"
 	"fluxend/internal/domain/project"
 	"fluxend/internal/domain/setting"
 	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/quota"
 	"fluxend/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/samber/do"
@@ -22,11 +23,13 @@ type Service interface {
 }
 
 type ServiceImpl struct {
-	settingService setting.Service
-	projectPolicy  *project.Policy
-	containerRepo  Repository
-	projectRepo    project.Repository
-	storageFactory *storage.Factory
+	settingService    setting.Service
+	credentialService credential.Service
+	projectPolicy     *project.Policy
+	containerRepo     Repository
+	projectRepo       project.Repository
+	credentialRepo    credential.Repository
+	quotaService      quota.Service
 }
 
 func NewContainerService(injector *do.Injector) (Service, error) {
@@ -35,17 +38,21 @@ func NewContainerService(injector *do.Injector) (Service, error) {
 		return nil, err
 	}
 
+	credentialService := do.MustInvoke[credential.Service](injector)
 	policy := do.MustInvoke[*project.Policy](injector)
 	containerRepo := do.MustInvoke[Repository](injector)
 	projectRepo := do.MustInvoke[project.Repository](injector)
-	storageFactory := do.MustInvoke[*storage.Factory](injector)
+	credentialRepo := do.MustInvoke[credential.Repository](injector)
+	quotaService := do.MustInvoke[quota.Service](injector)
 
 	return &ServiceImpl{
-		settingService: settingService,
-		projectPolicy:  policy,
-		containerRepo:  containerRepo,
-		projectRepo:    projectRepo,
-		storageFactory: storageFactory,
+		settingService:    settingService,
+		credentialService: credentialService,
+		projectPolicy:     policy,
+		containerRepo:     containerRepo,
+		projectRepo:       projectRepo,
+		credentialRepo:    credentialRepo,
+		quotaService:      quotaService,
 	}, nil
 }
 
@@ -94,20 +101,38 @@ func (s *ServiceImpl) Create(request *CreateContainerInput, authUser auth.User)
 		return Container{}, err
 	}
 
-	storageDriver := s.settingService.GetStorageDriver()
+	storageDriver, err := s.resolveStorageDriver(request)
+	if err != nil {
+		return Container{}, err
+	}
+
+	if err = s.validateQuarantineContainer(request.QuarantineContainerUUID, request.ProjectUUID, uuid.Nil); err != nil {
+		return Container{}, err
+	}
+
+	if request.Versioning && request.Deduplication {
+		return Container{}, errors.NewUnprocessableError("container.error.deduplicationWithVersioning")
+	}
+
 	containerInput := Container{
-		ProjectUuid: request.ProjectUUID,
-		Name:        request.Name,
-		NameKey:     s.generateContainerName(),
-		Provider:    storageDriver,
-		IsPublic:    request.IsPublic,
-		Description: request.Description,
-		MaxFileSize: request.MaxFileSize,
-		CreatedBy:   authUser.Uuid,
-		UpdatedBy:   authUser.Uuid,
+		ProjectUuid:        request.ProjectUUID,
+		Name:               request.Name,
+		NameKey:            s.generateContainerName(),
+		Provider:           storageDriver,
+		CredentialUuid:     request.CredentialUUID,
+		IsPublic:           request.IsPublic,
+		Description:        request.Description,
+		MaxFileSize:        request.MaxFileSize,
+		Versioning:         request.Versioning,
+		TrashRetentionDays: request.TrashRetentionDays,
+		Deduplication:      request.Deduplication,
+		CreatedBy:          authUser.Uuid,
+		UpdatedBy:          authUser.Uuid,
+
+		QuarantineContainerUuid: request.QuarantineContainerUUID,
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(storageDriver)
+	storageService, err := s.credentialService.CreateProvider(storageDriver, request.CredentialUUID)
 	if err != nil {
 		return Container{}, err
 	}
@@ -146,6 +171,16 @@ func (s *ServiceImpl) Update(containerUUID uuid.UUID, authUser auth.User, reques
 		return nil, err
 	}
 
+	if err = s.validateQuarantineContainer(request.QuarantineContainerUUID, fetchedContainer.ProjectUuid, fetchedContainer.Uuid); err != nil {
+		return &Container{}, err
+	}
+
+	if err = s.validateVersioning(fetchedContainer); err != nil {
+		return &Container{}, err
+	}
+
+	fetchedContainer.QuarantineContainerUuid = request.QuarantineContainerUUID
+
 	fetchedContainer.UpdatedAt = time.Now()
 	fetchedContainer.UpdatedBy = authUser.Uuid
 
@@ -175,7 +210,7 @@ func (s *ServiceImpl) Delete(containerUUID uuid.UUID, authUser auth.User) (bool,
 		return false, errors.NewForbiddenError("container.error.deleteForbidden")
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
 	if err != nil {
 		return false, err
 	}
@@ -184,7 +219,63 @@ func (s *ServiceImpl) Delete(containerUUID uuid.UUID, authUser auth.User) (bool,
 		return false, err
 	}
 
-	return s.containerRepo.Delete(containerUUID)
+	containerDeleted, err := s.containerRepo.Delete(containerUUID)
+	if err != nil || !containerDeleted {
+		return containerDeleted, err
+	}
+
+	// Files still in the trash go with the container, so usage is counted again
+	return true, s.quotaService.Recalculate(fetchedContainer.ProjectUuid)
+}
+
+// resolveStorageDriver picks the driver of a new container, credentials decide it when given and
+// the instance wide driver is used otherwise
+func (s *ServiceImpl) resolveStorageDriver(request *CreateContainerInput) (string, error) {
+	if !request.CredentialUUID.Valid {
+		if request.Driver != "" {
+			return request.Driver, nil
+		}
+
+		return s.settingService.GetStorageDriver(), nil
+	}
+
+	fetchedCredential, err := s.credentialRepo.GetByUUID(request.CredentialUUID.UUID)
+	if err != nil {
+		return "", err
+	}
+
+	if fetchedCredential.ProjectUuid != request.ProjectUUID {
+		return "", errors.NewNotFoundError("credential.error.notFound")
+	}
+
+	if request.Driver != "" && request.Driver != fetchedCredential.Driver {
+		return "", errors.NewUnprocessableError("container.error.driverMismatch")
+	}
+
+	return fetchedCredential.Driver, nil
+}
+
+// validateVersioning keeps versioning off while files share their content, a new version moves
+// the object of the current content aside which would take it from the other files too
+func (s *ServiceImpl) validateVersioning(fetchedContainer Container) error {
+	if !fetchedContainer.Versioning {
+		return nil
+	}
+
+	if fetchedContainer.Deduplication {
+		return errors.NewUnprocessableError("container.error.deduplicationWithVersioning")
+	}
+
+	hasSharedObjects, err := s.containerRepo.HasSharedObjects(fetchedContainer.Uuid)
+	if err != nil {
+		return err
+	}
+
+	if hasSharedObjects {
+		return errors.NewUnprocessableError("container.error.deduplicationWithVersioning")
+	}
+
+	return nil
 }
 
 func (s *ServiceImpl) generateContainerName() string {
@@ -205,3 +296,21 @@ func (s *ServiceImpl) validateNameForDuplication(name string, projectUUID uuid.U
 
 	return nil
 }
+
+// validateQuarantineContainer makes sure flagged uploads stay within the project, in another container
+func (s *ServiceImpl) validateQuarantineContainer(quarantineUUID uuid.NullUUID, projectUUID, containerUUID uuid.UUID) error {
+	if !quarantineUUID.Valid {
+		return nil
+	}
+
+	quarantineContainer, err := s.containerRepo.GetByUUID(quarantineUUID.UUID)
+	if err != nil {
+		return err
+	}
+
+	if quarantineContainer.Uuid == containerUUID || quarantineContainer.ProjectUuid != projectUUID {
+		return errors.NewUnprocessableError("container.error.invalidQuarantine")
+	}
+
+	return nil
+}
@@ -12,4 +12,14 @@ type CreateContainerInput struct {
 	Description string    `json:"description"`
 	IsPublic    bool      `json:"is_public"`
 	MaxFileSize int       `json:"max_file_size"`
+
+	Versioning         bool `json:"versioning"`
+	TrashRetentionDays int  `json:"trash_retention_days"`
+	Deduplication      bool `json:"deduplication"`
+
+	QuarantineContainerUUID uuid.NullUUID `json:"quarantine_container_uuid"`
+
+	// Only used on creation, a container can't change its driver afterwards
+	Driver         string        `json:"driver"`
+	CredentialUUID uuid.NullUUID `json:"credential_uuid"`
 }
@@ -0,0 +1,20 @@
+package credential
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Credential struct {
+	shared.BaseEntity
+	Uuid        uuid.UUID `db:"uuid" json:"uuid"`
+	ProjectUuid uuid.UUID `db:"project_uuid" json:"projectUuid"`
+	Name        string    `db:"name" json:"name"`
+	Driver      string    `db:"driver" json:"driver"`
+	Secrets     []byte    `db:"secrets" json:"-"` // storage.Credentials encrypted with the project key
+	CreatedBy   uuid.UUID `db:"created_by" json:"createdBy"`
+	UpdatedBy   uuid.UUID `db:"updated_by" json:"updatedBy"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,15 @@
+package credential
+
+import (
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	ListForProject(projectUUID uuid.UUID) ([]Credential, error)
+	GetByUUID(credentialUUID uuid.UUID) (Credential, error)
+	ExistsByNameForProject(name string, projectUUID uuid.UUID) (bool, error)
+	IsInUse(credentialUUID uuid.UUID) (bool, error)
+	Create(credential *Credential) (*Credential, error)
+	Update(credential *Credential) (*Credential, error)
+	Delete(credentialUUID uuid.UUID) (bool, error)
+}
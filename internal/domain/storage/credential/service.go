@@ -0,0 +1,268 @@
+package credential
+
+import (
+	"encoding/json"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/project"
+	"fluxend/pkg"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"os"
+	"time"
+)
+
+// Every project derives its own key from the master secret, so secrets can't be moved between projects
+const encryptionKeyPurpose = "fluxend storage credentials"
+
+type Service interface {
+	List(projectUUID uuid.UUID, authUser auth.User) ([]Credential, error)
+	GetByUUID(credentialUUID uuid.UUID, authUser auth.User) (Credential, error)
+	Create(request *CreateCredentialInput, authUser auth.User) (Credential, error)
+	Update(credentialUUID uuid.UUID, authUser auth.User, request *UpdateCredentialInput) (*Credential, error)
+	Delete(credentialUUID uuid.UUID, authUser auth.User) (bool, error)
+	CreateProvider(driver string, credentialUUID uuid.NullUUID) (storage.Provider, error)
+}
+
+type ServiceImpl struct {
+	projectPolicy  *project.Policy
+	credentialRepo Repository
+	projectRepo    project.Repository
+	storageFactory *storage.Factory
+}
+
+func NewCredentialService(injector *do.Injector) (Service, error) {
+	policy := do.MustInvoke[*project.Policy](injector)
+	credentialRepo := do.MustInvoke[Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	storageFactory := do.MustInvoke[*storage.Factory](injector)
+
+	return &ServiceImpl{
+		projectPolicy:  policy,
+		credentialRepo: credentialRepo,
+		projectRepo:    projectRepo,
+		storageFactory: storageFactory,
+	}, nil
+}
+
+func (s *ServiceImpl) List(projectUUID uuid.UUID, authUser auth.User) ([]Credential, error) {
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(projectUUID)
+	if err != nil {
+		return []Credential{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return []Credential{}, errors.NewForbiddenError("credential.error.listForbidden")
+	}
+
+	return s.credentialRepo.ListForProject(projectUUID)
+}
+
+func (s *ServiceImpl) GetByUUID(credentialUUID uuid.UUID, authUser auth.User) (Credential, error) {
+	fetchedCredential, err := s.credentialRepo.GetByUUID(credentialUUID)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedCredential.ProjectUuid)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return Credential{}, errors.NewForbiddenError("credential.error.viewForbidden")
+	}
+
+	return fetchedCredential, nil
+}
+
+func (s *ServiceImpl) Create(request *CreateCredentialInput, authUser auth.User) (Credential, error) {
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(request.ProjectUUID)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	if !s.projectPolicy.CanCreate(organizationUUID, authUser) {
+		return Credential{}, errors.NewForbiddenError("credential.error.createForbidden")
+	}
+
+	if err = request.Credentials.Validate(request.Driver); err != nil {
+		return Credential{}, err
+	}
+
+	if err = s.validateNameForDuplication(request.Name, request.ProjectUUID); err != nil {
+		return Credential{}, err
+	}
+
+	credentialInput := Credential{
+		Uuid:        uuid.New(),
+		ProjectUuid: request.ProjectUUID,
+		Name:        request.Name,
+		Driver:      request.Driver,
+		CreatedBy:   authUser.Uuid,
+		UpdatedBy:   authUser.Uuid,
+	}
+
+	credentialInput.Secrets, err = s.encrypt(credentialInput, request.Credentials)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	_, err = s.credentialRepo.Create(&credentialInput)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return credentialInput, nil
+}
+
+func (s *ServiceImpl) Update(credentialUUID uuid.UUID, authUser auth.User, request *UpdateCredentialInput) (*Credential, error) {
+	fetchedCredential, err := s.credentialRepo.GetByUUID(credentialUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedCredential.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("credential.error.updateForbidden")
+	}
+
+	// The driver is fixed, containers bound to the credential would otherwise point at another service
+	if err = request.Credentials.Validate(fetchedCredential.Driver); err != nil {
+		return nil, err
+	}
+
+	if request.Name != fetchedCredential.Name {
+		if err = s.validateNameForDuplication(request.Name, fetchedCredential.ProjectUuid); err != nil {
+			return nil, err
+		}
+	}
+
+	fetchedCredential.Name = request.Name
+	fetchedCredential.Secrets, err = s.encrypt(fetchedCredential, request.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedCredential.UpdatedAt = time.Now()
+	fetchedCredential.UpdatedBy = authUser.Uuid
+
+	return s.credentialRepo.Update(&fetchedCredential)
+}
+
+func (s *ServiceImpl) Delete(credentialUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedCredential, err := s.credentialRepo.GetByUUID(credentialUUID)
+	if err != nil {
+		return false, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedCredential.ProjectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("credential.error.deleteForbidden")
+	}
+
+	inUse, err := s.credentialRepo.IsInUse(credentialUUID)
+	if err != nil {
+		return false, err
+	}
+
+	if inUse {
+		return false, errors.NewUnprocessableError("credential.error.inUse")
+	}
+
+	return s.credentialRepo.Delete(credentialUUID)
+}
+
+// CreateProvider returns the provider of a container, falling back to the credentials from the environment
+// for containers that aren't bound to a credential set
+func (s *ServiceImpl) CreateProvider(driver string, credentialUUID uuid.NullUUID) (storage.Provider, error) {
+	if !credentialUUID.Valid {
+		return s.storageFactory.CreateProvider(driver)
+	}
+
+	fetchedCredential, err := s.credentialRepo.GetByUUID(credentialUUID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fetchedCredential.Driver != driver {
+		return nil, fmt.Errorf("credential %s is for driver %s, not %s", fetchedCredential.Uuid, fetchedCredential.Driver, driver)
+	}
+
+	credentials, err := s.decrypt(fetchedCredential)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storageFactory.CreateProviderWithCredentials(fetchedCredential.Driver, credentials)
+}
+
+func (s *ServiceImpl) encrypt(credential Credential, credentials storage.Credentials) ([]byte, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.projectKey(credential.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The credential UUID is authenticated as well, so secrets can't be swapped between rows
+	return pkg.Encrypt(key, plaintext, credential.Uuid[:])
+}
+
+func (s *ServiceImpl) decrypt(credential Credential) (storage.Credentials, error) {
+	key, err := s.projectKey(credential.ProjectUuid)
+	if err != nil {
+		return storage.Credentials{}, err
+	}
+
+	plaintext, err := pkg.Decrypt(key, credential.Secrets, credential.Uuid[:])
+	if err != nil {
+		return storage.Credentials{}, fmt.Errorf("unable to decrypt credential %s: %w", credential.Uuid, err)
+	}
+
+	var credentials storage.Credentials
+	if err = json.Unmarshal(plaintext, &credentials); err != nil {
+		return storage.Credentials{}, err
+	}
+
+	return credentials, nil
+}
+
+func (s *ServiceImpl) projectKey(projectUUID uuid.UUID) ([]byte, error) {
+	secret := os.Getenv("STORAGE_ENCRYPTION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("storage encryption key not found in environment variables")
+	}
+
+	return pkg.DeriveKey([]byte(secret), projectUUID[:], encryptionKeyPurpose)
+}
+
+func (s *ServiceImpl) validateNameForDuplication(name string, projectUUID uuid.UUID) error {
+	exists, err := s.credentialRepo.ExistsByNameForProject(name, projectUUID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.NewUnprocessableError("credential.error.duplicateName")
+	}
+
+	return nil
+}
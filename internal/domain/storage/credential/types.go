@@ -0,0 +1,18 @@
+package credential
+
+import (
+	"fluxend/internal/adapters/storage"
+	"github.com/google/uuid"
+)
+
+type CreateCredentialInput struct {
+	ProjectUUID uuid.UUID
+	Name        string
+	Driver      string
+	Credentials storage.Credentials
+}
+
+type UpdateCredentialInput struct {
+	Name        string
+	Credentials storage.Credentials
+}
@@ -0,0 +1,37 @@
+package file
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/auth"
+	"github.com/google/uuid"
+)
+
+// StoreOnBehalf stores a file for a caller whose access was decided elsewhere, like a project end user
+// allowed by a container policy. The file is recorded as created by owner
+func (s *ServiceImpl) StoreOnBehalf(containerUUID uuid.UUID, request *StoreFileInput, owner auth.User) (File, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	return s.store(fetchedContainer, request, owner)
+}
+
+// Open reads the content of a file for a caller whose access was decided elsewhere, so no user is checked
+func (s *ServiceImpl) Open(fetchedFile File, byteRange *storage.ByteRange) (*storage.FileObject, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(fetchedFile.ContainerUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return storageService.DownloadFile(storage.DownloadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fetchedFile.StorageKey(),
+		Range:         byteRange,
+	})
+}
@@ -0,0 +1,55 @@
+package file
+
+import (
+	"fluxend/internal/adapters/email"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"io"
+	"path"
+)
+
+// Attach reads a file of a container into an email attachment, as long as it fits in a message
+func (s *ServiceImpl) Attach(fileUUID, containerUUID uuid.UUID, authUser auth.User) (email.Attachment, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return email.Attachment{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return email.Attachment{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return email.Attachment{}, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return email.Attachment{}, err
+	}
+
+	fileObject, err := s.Open(fetchedFile, nil)
+	if err != nil {
+		return email.Attachment{}, err
+	}
+	defer fileObject.Body.Close()
+
+	// Sizes are stored in KB, so the content itself is what's checked against the limit
+	content, err := io.ReadAll(io.LimitReader(fileObject.Body, constants.EmailMaxAttachmentsSize+1))
+	if err != nil {
+		return email.Attachment{}, err
+	}
+
+	if len(content) > constants.EmailMaxAttachmentsSize {
+		return email.Attachment{}, errors.NewUnprocessableError("file.error.attachmentTooLarge")
+	}
+
+	return email.Attachment{
+		Filename:    path.Base(fetchedFile.FullFileName),
+		ContentType: fetchedFile.MimeType,
+		Content:     content,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/container"
+	"fmt"
+	"github.com/guregu/null/v6"
+	"github.com/rs/zerolog/log"
+	"io"
+	"os"
+	"time"
+)
+
+// uploadObject stores the content of a request under objectKey and returns its SHA-256, hex encoded
+func (s *ServiceImpl) uploadObject(storageService storage.Provider, fetchedContainer container.Container, objectKey string, request *StoreFileInput) (string, error) {
+	hash := sha256.New()
+
+	err := storageService.UploadFile(storage.UploadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      objectKey,
+		Body:          io.TeeReader(request.Body, hash),
+		ContentLength: request.Size,
+		ContentType:   request.MimeType,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// uploadShared stores content once per checksum in a deduplicated container, a file whose content is
+// already stored only adds a reference to it
+func (s *ServiceImpl) uploadShared(storageService storage.Provider, fetchedContainer container.Container, request *StoreFileInput, fileInput *File) error {
+	checksum, cleanup, err := spoolWithChecksum(request)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	object := Object{
+		ContainerUuid: fetchedContainer.Uuid,
+		Checksum:      checksum,
+		ObjectKey:     sharedObjectKey(checksum),
+		Size:          fileInput.Size,
+		CreatedAt:     time.Now(),
+	}
+
+	isStored, err := s.fileRepo.AcquireObject(&object)
+	if err != nil {
+		return err
+	}
+
+	// Content is uploaded until one upload succeeded, concurrent uploads write the same bytes to the same key
+	if !isStored {
+		_, err = s.uploadObject(storageService, fetchedContainer, object.ObjectKey, request)
+		if err == nil {
+			err = s.fileRepo.MarkObjectStored(fetchedContainer.Uuid, checksum)
+		}
+
+		if err != nil {
+			s.forgetObject(storageService, fetchedContainer, checksum)
+
+			return err
+		}
+	}
+
+	fileInput.Checksum = null.StringFrom(checksum)
+	fileInput.ObjectKey = null.StringFrom(object.ObjectKey)
+
+	return nil
+}
+
+// releaseObject drops the reference of a file to its shared object, the content is deleted along with the last one
+func (s *ServiceImpl) releaseObject(storageService storage.Provider, fetchedContainer container.Container, fetchedFile File) error {
+	released, err := s.fileRepo.ReleaseObject(fetchedContainer.Uuid, fetchedFile.Checksum.String)
+	if err != nil || !released {
+		return err
+	}
+
+	return storageService.DeleteFile(storage.FileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fetchedFile.ObjectKey.String,
+	})
+}
+
+// forgetObject drops a reference taken for content that couldn't be stored or recorded, along with the content
+// when it was the last one. A failure only leaves the object around longer than needed so it's logged and the
+// original error is returned
+func (s *ServiceImpl) forgetObject(storageService storage.Provider, fetchedContainer container.Container, checksum string) {
+	released, err := s.fileRepo.ReleaseObject(fetchedContainer.Uuid, checksum)
+	if err == nil && released {
+		err = storageService.DeleteFile(storage.FileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      sharedObjectKey(checksum),
+		})
+	}
+
+	if err != nil {
+		log.Error().
+			Str("container_uuid", fetchedContainer.Uuid.String()).
+			Str("checksum", checksum).
+			Str("error", err.Error()).
+			Msg("failed to release shared object")
+	}
+}
+
+// spoolWithChecksum copies the content to a temporary file while hashing it, so the checksum is known
+// before anything is uploaded. The returned cleanup removes that file
+func spoolWithChecksum(request *StoreFileInput) (string, func(), error) {
+	spool, err := os.CreateTemp("", "fluxend-checksum-*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(spool, hash), request.Body); err != nil {
+		cleanup()
+
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if _, err = spool.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+
+		return "", nil, err
+	}
+
+	request.Body = spool
+
+	return hex.EncodeToString(hash.Sum(nil)), cleanup, nil
+}
+
+// sharedObjectKey addresses shared content by its checksum, out of the way of user file names
+func sharedObjectKey(checksum string) string {
+	return ".objects/" + checksum
+}
@@ -1,20 +1,127 @@
 package file
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fluxend/internal/domain/shared"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/lib/pq"
+	"path"
+	"strings"
 	"time"
 )
 
+// reservedPrefixes hold the internal objects of a container: trashed files, versions, cached variants,
+// shared contents of deduplicated containers and chunks of resumable uploads
+var reservedPrefixes = []string{".trash", ".versions", variantObjectPrefix, ".objects", ".uploads"}
+
 type File struct {
 	shared.BaseEntity
-	Uuid          uuid.UUID `db:"uuid" json:"uuid"`
-	ContainerUuid uuid.UUID `db:"container_uuid" json:"containerUuid"`
-	FullFileName  string    `db:"full_file_name" json:"fullFileName"`
-	Size          int       `db:"size" json:"size"` // in KB
-	MimeType      string    `db:"mime_type" json:"mimeType"`
-	CreatedBy     uuid.UUID `db:"created_by" json:"createdBy"`
-	UpdatedBy     uuid.UUID `db:"updated_by" json:"updatedBy"`
-	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+	Uuid          uuid.UUID      `db:"uuid" json:"uuid"`
+	ContainerUuid uuid.UUID      `db:"container_uuid" json:"containerUuid"`
+	FullFileName  string         `db:"full_file_name" json:"fullFileName"`
+	Size          int            `db:"size" json:"size"` // in KB
+	SizeInBytes   int64          `db:"size_in_bytes" json:"sizeInBytes"`
+	MimeType      string         `db:"mime_type" json:"mimeType"`
+	CreatedBy     uuid.UUID      `db:"created_by" json:"createdBy"`
+	UpdatedBy     uuid.UUID      `db:"updated_by" json:"updatedBy"`
+	CreatedAt     time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updatedAt"`
+	DeletedAt     *time.Time     `db:"deleted_at" json:"deletedAt"` // set while the file is in the trash
+	DeletedBy     uuid.NullUUID  `db:"deleted_by" json:"deletedBy"`
+	Metadata      Metadata       `db:"metadata" json:"metadata"`
+	Tags          pq.StringArray `db:"tags" json:"tags"`
+	Checksum      null.String    `db:"checksum" json:"checksum"`    // SHA-256 of the content, hex encoded
+	ObjectKey     null.String    `db:"object_key" json:"objectKey"` // set when the content is a shared Object
+}
+
+// StorageKey is where the content of a live file is stored
+func (f File) StorageKey() string {
+	if f.ObjectKey.Valid {
+		return f.ObjectKey.String
+	}
+
+	return f.FullFileName
+}
+
+// IsReservedName tells whether a file name would be stored among the internal objects of a container
+func IsReservedName(fullFileName string) bool {
+	cleaned := strings.TrimPrefix(path.Clean("/"+fullFileName), "/")
+	for _, prefix := range reservedPrefixes {
+		if cleaned == prefix || strings.HasPrefix(cleaned, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Shared tells whether the content is an Object other files may reference too
+func (f File) Shared() bool {
+	return f.ObjectKey.Valid
+}
+
+// Metadata holds user-defined key/value pairs of a file, stored as a JSON object
+type Metadata map[string]string
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(m)
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*m = Metadata{}
+
+		return nil
+	case []byte:
+		return json.Unmarshal(data, m)
+	case string:
+		return json.Unmarshal([]byte(data), m)
+	default:
+		return fmt.Errorf("unsupported metadata type %T", value)
+	}
+}
+
+// Variant is a transformed copy of an image file, cached in the same container
+type Variant struct {
+	shared.BaseEntity
+	FileUuid   uuid.UUID `db:"file_uuid" json:"fileUuid"`
+	VariantKey string    `db:"variant_key" json:"variantKey"`
+	ObjectKey  string    `db:"object_key" json:"objectKey"`
+	Size       int64     `db:"size" json:"size"` // in bytes
+	MimeType   string    `db:"mime_type" json:"mimeType"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Version is a previous content of a file, kept when the file name is uploaded again in a versioned container
+type Version struct {
+	shared.BaseEntity
+	Uuid        uuid.UUID   `db:"uuid" json:"uuid"`
+	FileUuid    uuid.UUID   `db:"file_uuid" json:"fileUuid"`
+	ObjectKey   string      `db:"object_key" json:"objectKey"`
+	Size        int         `db:"size" json:"size"` // in KB
+	SizeInBytes int64       `db:"size_in_bytes" json:"sizeInBytes"`
+	MimeType    string      `db:"mime_type" json:"mimeType"`
+	CreatedBy   uuid.UUID   `db:"created_by" json:"createdBy"`
+	CreatedAt   time.Time   `db:"created_at" json:"createdAt"`
+	Checksum    null.String `db:"checksum" json:"checksum"`
+}
+
+// Object is content stored once for all files with the same checksum in a deduplicated container
+type Object struct {
+	shared.BaseEntity
+	ContainerUuid  uuid.UUID `db:"container_uuid" json:"containerUuid"`
+	Checksum       string    `db:"checksum" json:"checksum"`
+	ObjectKey      string    `db:"object_key" json:"objectKey"`
+	Size           int       `db:"size" json:"size"` // in KB
+	ReferenceCount int       `db:"reference_count" json:"referenceCount"`
+	IsStored       bool      `db:"is_stored" json:"isStored"` // set once the content was uploaded
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
 }
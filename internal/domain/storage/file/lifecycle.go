@@ -0,0 +1,162 @@
+package file
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/container"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// Expire deletes a file on behalf of a lifecycle rule or a container policy, so no user is checked.
+// Containers with a trash retention keep the file in the trash like any other delete
+func (s *ServiceImpl) Expire(fetchedFile File) error {
+	fetchedContainer, err := s.containerRepo.GetByUUID(fetchedFile.ContainerUuid)
+	if err != nil {
+		return err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return err
+	}
+
+	if fetchedContainer.TrashRetentionDays > 0 {
+		return s.trash(storageService, fetchedContainer, fetchedFile, uuid.NullUUID{})
+	}
+
+	fileDeleted, err := s.purge(storageService, fetchedContainer, fetchedFile, fetchedFile.FullFileName)
+	if err != nil || !fileDeleted {
+		return err
+	}
+
+	return s.containerRepo.DecrementTotalFiles(fetchedContainer.Uuid)
+}
+
+// MoveToContainer moves a file with its versions to another container on behalf of a lifecycle rule,
+// the containers may use different drivers. The file keeps its UUID and history
+func (s *ServiceImpl) MoveToContainer(fetchedFile File, targetContainerUUID uuid.UUID) (File, error) {
+	sourceContainer, err := s.containerRepo.GetByUUID(fetchedFile.ContainerUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	targetContainer, err := s.containerRepo.GetByUUID(targetContainerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	if err = s.validateFileSize(fetchedFile.Size, targetContainer); err != nil {
+		return File{}, err
+	}
+
+	if err = s.validateNameForDuplication(fetchedFile.FullFileName, targetContainer.Uuid); err != nil {
+		return File{}, err
+	}
+
+	sourceStorage, err := s.credentialService.CreateProvider(sourceContainer.Provider, sourceContainer.CredentialUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	targetStorage, err := s.credentialService.CreateProvider(targetContainer.Provider, targetContainer.CredentialUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	versions, err := s.fileRepo.ListVersions(fetchedFile.Uuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	// Shared content is copied under the file name, the file stops sharing it once moved
+	objectKeys := []string{fetchedFile.StorageKey()}
+	targetKeys := []string{fetchedFile.FullFileName}
+	for _, version := range versions {
+		objectKeys = append(objectKeys, version.ObjectKey)
+		targetKeys = append(targetKeys, version.ObjectKey)
+	}
+
+	// Usage only moves when the file leaves its project
+	size := storedSize(fetchedFile, versions)
+	changesProject := sourceContainer.ProjectUuid != targetContainer.ProjectUuid
+	if changesProject {
+		if err = s.quotaService.Reserve(targetContainer.ProjectUuid, size, 1); err != nil {
+			return File{}, err
+		}
+	}
+
+	for i, objectKey := range objectKeys {
+		err = storage.CopyFile(
+			sourceStorage,
+			storage.FileInput{ContainerName: sourceContainer.NameKey, FileName: objectKey},
+			targetStorage,
+			storage.FileInput{ContainerName: targetContainer.NameKey, FileName: targetKeys[i]},
+		)
+		if err != nil {
+			if changesProject {
+				s.releaseQuota(targetContainer.ProjectUuid, size, 1)
+			}
+
+			return File{}, err
+		}
+	}
+
+	if err = s.deleteVariants(sourceStorage, sourceContainer, fetchedFile.Uuid); err != nil {
+		return File{}, err
+	}
+
+	sourceFile := fetchedFile
+	fetchedFile.ContainerUuid = targetContainer.Uuid
+	fetchedFile.ObjectKey = null.String{}
+	fetchedFile.UpdatedAt = time.Now()
+
+	if _, err = s.fileRepo.UpdateContainer(&fetchedFile); err != nil {
+		return File{}, err
+	}
+
+	if err = s.containerRepo.DecrementTotalFiles(sourceContainer.Uuid); err != nil {
+		return File{}, err
+	}
+
+	if err = s.containerRepo.IncrementTotalFiles(targetContainer.Uuid); err != nil {
+		return File{}, err
+	}
+
+	if changesProject {
+		s.releaseQuota(sourceContainer.ProjectUuid, size, 1)
+	}
+
+	if sourceFile.Shared() {
+		// Other files may still reference the content, only the reference of this one goes
+		if err = s.releaseObject(sourceStorage, sourceContainer, sourceFile); err != nil {
+			log.Error().
+				Str("container_name", sourceContainer.NameKey).
+				Str("object_key", sourceFile.ObjectKey.String).
+				Str("error", err.Error()).
+				Msg("failed to release moved shared object")
+		}
+
+		objectKeys = objectKeys[1:]
+	}
+
+	s.deleteObjects(sourceStorage, sourceContainer, objectKeys)
+
+	return fetchedFile, nil
+}
+
+// deleteObjects removes objects left behind once their records point elsewhere, failures only leave
+// unreferenced objects so they're logged
+func (s *ServiceImpl) deleteObjects(storageService storage.Provider, fetchedContainer container.Container, objectKeys []string) {
+	for _, objectKey := range objectKeys {
+		err := storageService.DeleteFile(storage.FileInput{ContainerName: fetchedContainer.NameKey, FileName: objectKey})
+		if err != nil {
+			log.Error().
+				Str("container_name", fetchedContainer.NameKey).
+				Str("object_key", objectKey).
+				Str("error", err.Error()).
+				Msg("failed to delete moved object")
+		}
+	}
+}
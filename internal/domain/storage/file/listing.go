@@ -0,0 +1,107 @@
+package file
+
+import (
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/shared"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+func (s *ServiceImpl) List(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter ListFilesInput, authUser auth.User) ([]File, error) {
+	if err := s.authorizeList(containerUUID, authUser); err != nil {
+		return []File{}, err
+	}
+
+	return s.fileRepo.ListForContainer(paginationParams, containerUUID, filter)
+}
+
+// Browse lists one level of the virtual folder hierarchy, the folders under the prefix are returned in full
+// while its files are paginated
+func (s *ServiceImpl) Browse(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter ListFilesInput, authUser auth.User) (Listing, error) {
+	if err := s.authorizeList(containerUUID, authUser); err != nil {
+		return Listing{}, err
+	}
+
+	folders, err := s.fileRepo.ListFoldersForContainer(containerUUID, filter)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	files, err := s.fileRepo.ListForContainer(paginationParams, containerUUID, filter)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	return Listing{Prefix: filter.Prefix, Folders: folders, Files: files}, nil
+}
+
+func (s *ServiceImpl) UpdateMetadata(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *UpdateMetadataInput) (*File, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedFile, err := s.fileRepo.GetByUUID(fileUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fetchedFile.ContainerUuid != containerUUID {
+		return nil, errors.NewNotFoundError("file.error.notFound")
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("file.error.updateForbidden")
+	}
+
+	fetchedFile.Metadata = request.Metadata
+	fetchedFile.Tags = normalizeTags(request.Tags)
+	fetchedFile.UpdatedAt = time.Now()
+	fetchedFile.UpdatedBy = authUser.Uuid
+
+	return s.fileRepo.UpdateMetadata(&fetchedFile)
+}
+
+func (s *ServiceImpl) authorizeList(containerUUID uuid.UUID, authUser auth.User) error {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return errors.NewForbiddenError("file.error.listForbidden")
+	}
+
+	return nil
+}
+
+// normalizeTags trims and de-duplicates tags, never returning nil as the column doesn't take NULL
+func normalizeTags(tags []string) pq.StringArray {
+	normalized := pq.StringArray{}
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -3,14 +3,40 @@ package file
 import (
 	"fluxend/internal/domain/shared"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Repository interface {
-	ListForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID) ([]File, error)
+	ListForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter ListFilesInput) ([]File, error)
+	ListFoldersForContainer(containerUUID uuid.UUID, filter ListFilesInput) ([]string, error)
+	ListByPrefixForContainer(containerUUID uuid.UUID, prefix string, limit int) ([]File, error)
+	ListByUUIDsForContainer(containerUUID uuid.UUID, fileUUIDs []uuid.UUID) ([]File, error)
 	GetByUUID(fileUUID uuid.UUID) (File, error)
 	ExistsByUUID(containerUUID uuid.UUID) (bool, error)
 	ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error)
 	Create(file *File) (*File, error)
+	GetByNameForContainer(name string, containerUUID uuid.UUID) (File, error)
 	Rename(container *File) (*File, error)
+	UpdateContent(file *File) (*File, error)
+	UpdateMetadata(file *File) (*File, error)
+	UpdateContainer(file *File) (*File, error)
+	ListModifiedBefore(containerUUID uuid.UUID, prefix string, modifiedBefore, afterUpdatedAt time.Time, afterUUID uuid.UUID, limit int) ([]File, error)
 	Delete(fileUUID uuid.UUID) (bool, error)
+	ListTrashedForContainer(paginationParams shared.PaginationParams, containerUUID uuid.UUID) ([]File, error)
+	GetTrashedByUUID(fileUUID uuid.UUID) (File, error)
+	ListExpiredTrash() ([]File, error)
+	Trash(file *File) error
+	Restore(file *File) (*File, error)
+	ListVersions(fileUUID uuid.UUID) ([]Version, error)
+	GetVersion(versionUUID uuid.UUID) (Version, error)
+	CreateVersion(version *Version) error
+	DeleteVersion(versionUUID uuid.UUID) (bool, error)
+	GetVariant(fileUUID uuid.UUID, variantKey string) (Variant, error)
+	ListVariants(fileUUID uuid.UUID) ([]Variant, error)
+	CreateVariant(variant *Variant) error
+	DeleteVariants(fileUUID uuid.UUID) error
+	AcquireObject(object *Object) (bool, error)
+	MarkObjectStored(containerUUID uuid.UUID, checksum string) error
+	ReleaseObject(containerUUID uuid.UUID, checksum string) (bool, error)
+	ListStoredObjects(containerUUID uuid.UUID) ([]StoredObject, error)
 }
@@ -0,0 +1,126 @@
+package file
+
+import (
+	"bytes"
+	stdErrors "errors"
+	"fluxend/internal/adapters/mimetype"
+	"fluxend/internal/adapters/scanner"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"io"
+	"os"
+)
+
+// sniffMimeType replaces the declared MIME type with the one detected from the first bytes of the content
+func (s *ServiceImpl) sniffMimeType(request *StoreFileInput) error {
+	head := make([]byte, constants.MimeSniffLength)
+
+	n, err := io.ReadFull(request.Body, head)
+	if err != nil && !stdErrors.Is(err, io.EOF) && !stdErrors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	request.MimeType = mimetype.Detect(head[:n], request.MimeType)
+	request.Body = io.MultiReader(bytes.NewReader(head[:n]), request.Body)
+
+	return nil
+}
+
+// scan runs the configured scanners over the content, which is spooled to a temporary file first so it can
+// still be uploaded afterwards. The returned cleanup removes that file once the upload is done. Flagged content
+// is moved to the quarantine container when the container has one and rejected otherwise
+func (s *ServiceImpl) scan(fetchedContainer container.Container, request *StoreFileInput, authUser auth.User) (func(), error) {
+	pipeline, err := scanner.NewPipeline(s.settingService.GetValue("storageScanners"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !pipeline.Enabled() {
+		return func() {}, nil
+	}
+
+	spool, err := os.CreateTemp("", "fluxend-scan-*")
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	if _, err = io.Copy(spool, request.Body); err != nil {
+		cleanup()
+
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Files are never stored unchecked, a scanner being unavailable fails the upload
+	result, err := pipeline.Scan(spool)
+	if err != nil {
+		cleanup()
+
+		return nil, fmt.Errorf("failed to scan file: %w", err)
+	}
+
+	if _, err = spool.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+
+		return nil, err
+	}
+
+	request.Body = spool
+
+	if !result.Infected {
+		return cleanup, nil
+	}
+
+	defer cleanup()
+
+	log.Warn().
+		Str("action", constants.ActionFileScan).
+		Str("container_uuid", fetchedContainer.Uuid.String()).
+		Str("file_name", request.FullFileName).
+		Str("scanner", result.Scanner).
+		Str("signature", result.Signature).
+		Str("user_uuid", authUser.Uuid.String()).
+		Msg("upload flagged by scanner")
+
+	if !fetchedContainer.QuarantineContainerUuid.Valid {
+		return nil, errors.NewUnprocessableError("file.error.infected")
+	}
+
+	if err = s.quarantine(fetchedContainer, request, authUser); err != nil {
+		return nil, err
+	}
+
+	return nil, errors.NewUnprocessableError("file.error.quarantined")
+}
+
+// quarantine stores flagged content in the quarantine container of its container, under a name that
+// can't clash with earlier flagged uploads
+func (s *ServiceImpl) quarantine(fetchedContainer container.Container, request *StoreFileInput, authUser auth.User) error {
+	quarantineContainer, err := s.containerRepo.GetByUUID(fetchedContainer.QuarantineContainerUuid.UUID)
+	if err != nil {
+		return err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(quarantineContainer.Provider, quarantineContainer.CredentialUuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.put(storageService, quarantineContainer, &StoreFileInput{
+		FullFileName: fmt.Sprintf("%s/%s/%s", fetchedContainer.Name, uuid.New(), request.FullFileName),
+		MimeType:     request.MimeType,
+		Size:         request.Size,
+		Body:         request.Body,
+	}, authUser)
+
+	return err
+}
@@ -1,79 +1,88 @@
 package file
 
 import (
+	stdErrors "errors"
+	"fluxend/internal/adapters/email"
+	"fluxend/internal/adapters/imaging"
+	"fluxend/internal/adapters/mimetype"
 	"fluxend/internal/adapters/storage"
 	"fluxend/internal/domain/auth"
 	"fluxend/internal/domain/project"
 	"fluxend/internal/domain/setting"
 	"fluxend/internal/domain/shared"
 	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/quota"
 	"fluxend/pkg"
 	"fluxend/pkg/errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/rs/zerolog/log"
 	"github.com/samber/do"
-	"io"
+	"strings"
 	"time"
 )
 
 type Service interface {
-	List(paginationParams shared.PaginationParams, containerUUID uuid.UUID, authUser auth.User) ([]File, error)
+	List(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter ListFilesInput, authUser auth.User) ([]File, error)
+	Browse(paginationParams shared.PaginationParams, containerUUID uuid.UUID, filter ListFilesInput, authUser auth.User) (Listing, error)
 	GetByUUID(fileUUID, containerUUID uuid.UUID, authUser auth.User) (File, error)
 	Create(containerUUID uuid.UUID, request *CreateFileInput, authUser auth.User) (File, error)
+	Store(containerUUID uuid.UUID, request *StoreFileInput, authUser auth.User) (File, error)
+	Validate(containerUUID uuid.UUID, request *StoreFileInput, authUser auth.User) error
 	Rename(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *RenameFileInput) (*File, error)
+	UpdateMetadata(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *UpdateMetadataInput) (*File, error)
 	CreatePresignedURL(fileUUID, containerUUID uuid.UUID, authUser auth.User) (string, error)
+	Download(fileUUID, containerUUID uuid.UUID, authUser auth.User, byteRange *storage.ByteRange) (File, *storage.FileObject, error)
+	Transform(fileUUID, containerUUID uuid.UUID, authUser auth.User, options imaging.TransformOptions) (*storage.FileObject, error)
+	CreateTransformURL(fileUUID, containerUUID uuid.UUID, authUser auth.User, options imaging.TransformOptions, expiration time.Duration) (string, error)
+	TransformSigned(fileUUID uuid.UUID, options imaging.TransformOptions, expires, signature string) (*storage.FileObject, error)
 	Delete(fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	ListVersions(fileUUID, containerUUID uuid.UUID, authUser auth.User) ([]Version, error)
+	RestoreVersion(versionUUID, fileUUID, containerUUID uuid.UUID, authUser auth.User) (File, error)
+	DeleteVersion(versionUUID, fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	ListTrash(paginationParams shared.PaginationParams, containerUUID uuid.UUID, authUser auth.User) ([]File, error)
+	RestoreFromTrash(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *RestoreFileInput) (File, error)
+	Purge(fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	PurgeExpiredTrash() (int, error)
+	Expire(fetchedFile File) error
+	MoveToContainer(fetchedFile File, targetContainerUUID uuid.UUID) (File, error)
+	StoreOnBehalf(containerUUID uuid.UUID, request *StoreFileInput, owner auth.User) (File, error)
+	Open(fetchedFile File, byteRange *storage.ByteRange) (*storage.FileObject, error)
+	Attach(fileUUID, containerUUID uuid.UUID, authUser auth.User) (email.Attachment, error)
 }
 
 type ServiceImpl struct {
-	settingService setting.Service
-	projectPolicy  *project.Policy
-	containerRepo  container.Repository
-	fileRepo       Repository
-	projectRepo    project.Repository
-	storageFactory *storage.Factory
+	settingService    setting.Service
+	projectPolicy     *project.Policy
+	containerRepo     container.Repository
+	fileRepo          Repository
+	projectRepo       project.Repository
+	credentialService credential.Service
+	quotaService      quota.Service
 }
 
 func NewFileService(injector *do.Injector) (Service, error) {
-	settingService, err := setting.NewSettingService(injector)
-	if err != nil {
-		return nil, err
-	}
-
+	settingService := do.MustInvoke[setting.Service](injector)
 	policy := do.MustInvoke[*project.Policy](injector)
 	containerRepo := do.MustInvoke[container.Repository](injector)
 	fileRepo := do.MustInvoke[Repository](injector)
 	projectRepo := do.MustInvoke[project.Repository](injector)
-	storageFactory := do.MustInvoke[*storage.Factory](injector)
+	credentialService := do.MustInvoke[credential.Service](injector)
+	quotaService := do.MustInvoke[quota.Service](injector)
 
 	return &ServiceImpl{
-		settingService: settingService,
-		projectPolicy:  policy,
-		containerRepo:  containerRepo,
-		fileRepo:       fileRepo,
-		projectRepo:    projectRepo,
-		storageFactory: storageFactory,
+		settingService:    settingService,
+		projectPolicy:     policy,
+		containerRepo:     containerRepo,
+		fileRepo:          fileRepo,
+		projectRepo:       projectRepo,
+		credentialService: credentialService,
+		quotaService:      quotaService,
 	}, nil
 }
 
-func (s *ServiceImpl) List(paginationParams shared.PaginationParams, containerUUID uuid.UUID, authUser auth.User) ([]File, error) {
-	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
-	if err != nil {
-		return []File{}, err
-	}
-
-	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
-	if err != nil {
-		return []File{}, err
-	}
-
-	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
-		return []File{}, errors.NewForbiddenError("file.error.listForbidden")
-	}
-
-	return s.fileRepo.ListForContainer(paginationParams, containerUUID)
-}
-
 func (s *ServiceImpl) GetByUUID(fileUUID, containerUUID uuid.UUID, authUser auth.User) (File, error) {
 	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
 	if err != nil {
@@ -98,6 +107,23 @@ func (s *ServiceImpl) GetByUUID(fileUUID, containerUUID uuid.UUID, authUser auth
 }
 
 func (s *ServiceImpl) Create(containerUUID uuid.UUID, request *CreateFileInput, authUser auth.User) (File, error) {
+	fileHandler, err := request.File.Open()
+	if err != nil {
+		return File{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer fileHandler.Close()
+
+	return s.Store(containerUUID, &StoreFileInput{
+		FullFileName: request.FullFileName,
+		MimeType:     request.File.Header.Get("Content-Type"),
+		Size:         request.File.Size,
+		Body:         fileHandler,
+		Metadata:     request.Metadata,
+		Tags:         request.Tags,
+	}, authUser)
+}
+
+func (s *ServiceImpl) Store(containerUUID uuid.UUID, request *StoreFileInput, authUser auth.User) (File, error) {
 	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
 	if err != nil {
 		return File{}, err
@@ -112,52 +138,138 @@ func (s *ServiceImpl) Create(containerUUID uuid.UUID, request *CreateFileInput,
 		return File{}, errors.NewForbiddenError("file.error.createForbidden")
 	}
 
+	return s.store(fetchedContainer, request, authUser)
+}
+
+// store checks, scans and uploads a file once the caller is allowed to add it to the container
+func (s *ServiceImpl) store(fetchedContainer container.Container, request *StoreFileInput, authUser auth.User) (File, error) {
+	// The content decides the MIME type, whatever the client declared
+	err := s.sniffMimeType(request)
+	if err != nil {
+		return File{}, err
+	}
+
 	if err = s.validate(request, fetchedContainer); err != nil {
 		return File{}, err
 	}
 
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	cleanup, err := s.scan(fetchedContainer, request, authUser)
+	if err != nil {
+		return File{}, err
+	}
+	defer cleanup()
+
+	if fetchedContainer.Versioning {
+		currentFile, err := s.fileRepo.GetByNameForContainer(request.FullFileName, fetchedContainer.Uuid)
+		if err == nil {
+			return s.storeVersion(storageService, fetchedContainer, currentFile, request, authUser)
+		}
+
+		var notFoundErr *errors.NotFoundError
+		if !stdErrors.As(err, &notFoundErr) {
+			return File{}, err
+		}
+	}
+
+	return s.put(storageService, fetchedContainer, request, authUser)
+}
+
+// put uploads a new file to a container and records it, once the content passed all checks
+func (s *ServiceImpl) put(storageService storage.Provider, fetchedContainer container.Container, request *StoreFileInput, authUser auth.User) (File, error) {
 	fileInput := File{
-		ContainerUuid: containerUUID,
+		ContainerUuid: fetchedContainer.Uuid,
 		FullFileName:  request.FullFileName,
-		Size:          pkg.ConvertBytesToKiloBytes(int(request.File.Size)),
-		MimeType:      request.File.Header.Get("Content-Type"),
+		Size:          pkg.ConvertBytesToKiloBytes(int(request.Size)),
+		SizeInBytes:   request.Size,
+		MimeType:      request.MimeType,
+		Metadata:      request.Metadata,
+		Tags:          normalizeTags(request.Tags),
 		CreatedBy:     authUser.Uuid,
 		UpdatedBy:     authUser.Uuid,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	fileBytes, err := s.getFileContents(*request)
-	if err != nil {
+	if err := s.quotaService.Reserve(fetchedContainer.ProjectUuid, fileInput.SizeInBytes, 1); err != nil {
 		return File{}, err
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
-	if err != nil {
-		return File{}, err
+	// Usage counts every file in full, shared content included
+	var err error
+	if fetchedContainer.Deduplication {
+		err = s.uploadShared(storageService, fetchedContainer, request, &fileInput)
+	} else {
+		var checksum string
+		checksum, err = s.uploadObject(storageService, fetchedContainer, request.FullFileName, request)
+		fileInput.Checksum = null.StringFrom(checksum)
 	}
 
-	err = storageService.UploadFile(storage.UploadFileInput{
-		ContainerName: fetchedContainer.NameKey,
-		FileName:      request.FullFileName,
-		FileBytes:     fileBytes,
-	})
 	if err != nil {
+		s.releaseQuota(fetchedContainer.ProjectUuid, fileInput.SizeInBytes, 1)
+
 		return File{}, err
 	}
 
 	_, err = s.fileRepo.Create(&fileInput)
 	if err != nil {
+		s.releaseQuota(fetchedContainer.ProjectUuid, fileInput.SizeInBytes, 1)
+
+		if fileInput.Shared() {
+			s.forgetObject(storageService, fetchedContainer, fileInput.Checksum.String)
+		}
+
 		return File{}, err
 	}
 
-	if err = s.containerRepo.IncrementTotalFiles(containerUUID); err != nil {
+	if err = s.containerRepo.IncrementTotalFiles(fetchedContainer.Uuid); err != nil {
 		return File{}, err
 	}
 
 	return fileInput, nil
 }
 
+// Validate checks a file against the container rules without storing it, so uploads
+// spread over several requests can be rejected before any content is sent
+func (s *ServiceImpl) Validate(containerUUID uuid.UUID, request *StoreFileInput, authUser auth.User) error {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return err
+	}
+
+	if !s.projectPolicy.CanCreate(organizationUUID, authUser) {
+		return errors.NewForbiddenError("file.error.createForbidden")
+	}
+
+	if err = s.validate(request, fetchedContainer); err != nil {
+		return err
+	}
+
+	// A new version of an existing file doesn't add to the file count
+	files := 1
+	if fetchedContainer.Versioning {
+		exists, err := s.fileRepo.ExistsByNameForContainer(request.FullFileName, containerUUID)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			files = 0
+		}
+	}
+
+	return s.quotaService.Check(fetchedContainer.ProjectUuid, request.Size, files)
+}
+
 func (s *ServiceImpl) Rename(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *RenameFileInput) (*File, error) {
 	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
 	if err != nil {
@@ -182,18 +294,21 @@ func (s *ServiceImpl) Rename(fileUUID, containerUUID uuid.UUID, authUser auth.Us
 		return &File{}, err
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
-	if err != nil {
-		return &File{}, err
-	}
+	// Shared content isn't stored under the file name, only the record changes
+	if !fetchedFile.Shared() {
+		storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+		if err != nil {
+			return &File{}, err
+		}
 
-	err = storageService.RenameFile(storage.RenameFileInput{
-		ContainerName: fetchedContainer.NameKey,
-		FileName:      fetchedFile.FullFileName,
-		NewFileName:   request.FullFileName,
-	})
-	if err != nil {
-		return nil, err
+		err = storageService.RenameFile(storage.RenameFileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      fetchedFile.FullFileName,
+			NewFileName:   request.FullFileName,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	fetchedFile.FullFileName = request.FullFileName
@@ -223,12 +338,12 @@ func (s *ServiceImpl) CreatePresignedURL(fileUUID, containerUUID uuid.UUID, auth
 		return "", errors.NewForbiddenError("file.error.updateForbidden")
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
 	if err != nil {
 		return "", err
 	}
 
-	fileInput := storage.FileInput{ContainerName: fetchedContainer.NameKey, FileName: fetchedFile.FullFileName}
+	fileInput := storage.FileInput{ContainerName: fetchedContainer.NameKey, FileName: fetchedFile.StorageKey()}
 	downloadURL, err := storageService.CreatePresignedURL(fileInput, time.Hour*1)
 	if err != nil {
 		return "", fmt.Errorf("failed to create presigned URL: %w", err)
@@ -237,6 +352,43 @@ func (s *ServiceImpl) CreatePresignedURL(fileUUID, containerUUID uuid.UUID, auth
 	return downloadURL, nil
 }
 
+func (s *ServiceImpl) Download(fileUUID, containerUUID uuid.UUID, authUser auth.User, byteRange *storage.ByteRange) (File, *storage.FileObject, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return File{}, nil, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	fileObject, err := storageService.DownloadFile(storage.DownloadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fetchedFile.StorageKey(),
+		Range:         byteRange,
+	})
+	if err != nil {
+		return File{}, nil, err
+	}
+
+	return fetchedFile, fileObject, nil
+}
+
 func (s *ServiceImpl) Delete(fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
 	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
 	if err != nil {
@@ -257,20 +409,20 @@ func (s *ServiceImpl) Delete(fileUUID, containerUUID uuid.UUID, authUser auth.Us
 		return false, err
 	}
 
-	storageService, err := s.storageFactory.CreateProvider(s.settingService.GetStorageDriver())
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
 	if err != nil {
 		return false, err
 	}
 
-	err = storageService.DeleteFile(storage.FileInput{
-		ContainerName: fetchedContainer.NameKey,
-		FileName:      fetchedFile.FullFileName,
-	})
-	if err != nil {
-		return false, err
+	if fetchedContainer.TrashRetentionDays > 0 {
+		if err = s.trash(storageService, fetchedContainer, fetchedFile, uuid.NullUUID{UUID: authUser.Uuid, Valid: true}); err != nil {
+			return false, err
+		}
+
+		return true, nil
 	}
 
-	fileDeleted, err := s.fileRepo.Delete(fileUUID)
+	fileDeleted, err := s.purge(storageService, fetchedContainer, fetchedFile, fetchedFile.FullFileName)
 	if err != nil {
 		return false, err
 	}
@@ -285,25 +437,10 @@ func (s *ServiceImpl) Delete(fileUUID, containerUUID uuid.UUID, authUser auth.Us
 	return fileDeleted, nil
 }
 
-func (s *ServiceImpl) getFileContents(request CreateFileInput) ([]byte, error) {
-	fileHandler, err := request.File.Open() // Open the file
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer fileHandler.Close()
-
-	fileBytes, err := io.ReadAll(fileHandler) // Read the file content as bytes
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
-
-	return fileBytes, nil
-}
-
-func (s *ServiceImpl) validate(request *CreateFileInput, container container.Container) error {
-	fileSize := pkg.ConvertBytesToKiloBytes(int(request.File.Size))
+func (s *ServiceImpl) validate(request *StoreFileInput, container container.Container) error {
+	fileSize := pkg.ConvertBytesToKiloBytes(int(request.Size))
 
-	err := s.validateMimeType(request.File.Header.Get("Content-Type"), container)
+	err := s.validateMimeType(request.MimeType)
 	if err != nil {
 		return err
 	}
@@ -312,6 +449,11 @@ func (s *ServiceImpl) validate(request *CreateFileInput, container container.Con
 		return err
 	}
 
+	// Versioned containers keep the current content as a version instead of rejecting the name
+	if container.Versioning {
+		return nil
+	}
+
 	if err = s.validateNameForDuplication(request.FullFileName, container.Uuid); err != nil {
 		return err
 	}
@@ -319,11 +461,11 @@ func (s *ServiceImpl) validate(request *CreateFileInput, container container.Con
 	return nil
 }
 
-func (s *ServiceImpl) validateMimeType(mimeType string, container container.Container) error {
-	// TODO: implement mime type validation
-	// if !container.AllowedMimeTypes[mimeType] {
-	//	return errors.NewUnprocessableError("file.error.invalidMimeType")
-	// }
+func (s *ServiceImpl) validateMimeType(mimeType string) error {
+	allowedMimes := strings.Split(s.settingService.GetValue("storageAllowedMimes"), ",")
+	if !mimetype.Allowed(mimeType, allowedMimes) {
+		return errors.NewUnprocessableError("file.error.invalidMimeType")
+	}
 
 	return nil
 }
@@ -348,3 +490,14 @@ func (s *ServiceImpl) validateNameForDuplication(name string, containerUUID uuid
 
 	return nil
 }
+
+// releaseQuota gives back space reserved for content that couldn't be stored, a failure only leaves the
+// usage too high until it's recalculated so it's logged and the original error is returned
+func (s *ServiceImpl) releaseQuota(projectUUID uuid.UUID, bytes int64, files int) {
+	if err := s.quotaService.Release(projectUUID, bytes, files); err != nil {
+		log.Error().
+			Str("project_uuid", projectUUID.String()).
+			Str("error", err.Error()).
+			Msg("failed to release storage quota")
+	}
+}
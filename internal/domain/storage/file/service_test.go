@@ -0,0 +1,206 @@
+package file
+
+import (
+	"fluxend/internal/adapters/imaging"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/pkg/errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type containerStore struct {
+	container.Repository
+	container container.Container
+}
+
+func (cs *containerStore) GetByUUID(containerUUID uuid.UUID) (container.Container, error) {
+	return cs.container, nil
+}
+
+type projectStore struct {
+	project.Repository
+	organizationUUID uuid.UUID
+}
+
+func (ps *projectStore) GetOrganizationUUIDByProjectUUID(projectUUID uuid.UUID) (uuid.UUID, error) {
+	return ps.organizationUUID, nil
+}
+
+type memberStore struct {
+	organization.Repository
+}
+
+func (ms *memberStore) IsOrganizationMember(organizationUUID, authUserID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+type objectStore struct {
+	storage.Provider
+	renamed    []storage.RenameFileInput
+	downloaded []storage.DownloadFileInput
+}
+
+func (ob *objectStore) DownloadFile(input storage.DownloadFileInput) (*storage.FileObject, error) {
+	ob.downloaded = append(ob.downloaded, input)
+
+	return &storage.FileObject{}, nil
+}
+
+func (ob *objectStore) RenameFile(input storage.RenameFileInput) error {
+	ob.renamed = append(ob.renamed, input)
+
+	return nil
+}
+
+type providerStore struct {
+	credential.Service
+	provider storage.Provider
+}
+
+func (ps *providerStore) CreateProvider(driver string, credentialUUID uuid.NullUUID) (storage.Provider, error) {
+	return ps.provider, nil
+}
+
+type fileStore struct {
+	Repository
+	files    map[uuid.UUID]File
+	versions map[uuid.UUID]Version
+	updated  *File
+}
+
+func (fs *fileStore) GetByUUID(fileUUID uuid.UUID) (File, error) {
+	return fs.files[fileUUID], nil
+}
+
+func (fs *fileStore) GetVersion(versionUUID uuid.UUID) (Version, error) {
+	return fs.versions[versionUUID], nil
+}
+
+func (fs *fileStore) CreateVersion(version *Version) error {
+	fs.versions[version.Uuid] = *version
+
+	return nil
+}
+
+func (fs *fileStore) DeleteVersion(versionUUID uuid.UUID) (bool, error) {
+	delete(fs.versions, versionUUID)
+
+	return true, nil
+}
+
+func (fs *fileStore) GetVariant(fileUUID uuid.UUID, variantKey string) (Variant, error) {
+	return Variant{}, errors.NewNotFoundError("file.error.variantNotFound")
+}
+
+func (fs *fileStore) ListVariants(fileUUID uuid.UUID) ([]Variant, error) {
+	return []Variant{}, nil
+}
+
+func (fs *fileStore) DeleteVariants(fileUUID uuid.UUID) error {
+	return nil
+}
+
+func (fs *fileStore) UpdateContent(file *File) (*File, error) {
+	updated := *file
+	fs.updated = &updated
+
+	return file, nil
+}
+
+func newTestService(t *testing.T, containerUUID uuid.UUID, fileRepo *fileStore) *ServiceImpl {
+	injector := do.New()
+	do.ProvideValue[organization.Repository](injector, &memberStore{})
+
+	policy, err := project.NewProjectPolicy(injector)
+	require.NoError(t, err)
+
+	return &ServiceImpl{
+		projectPolicy:     policy,
+		containerRepo:     &containerStore{container: container.Container{Uuid: containerUUID, NameKey: "uploads"}},
+		fileRepo:          fileRepo,
+		projectRepo:       &projectStore{organizationUUID: uuid.New()},
+		credentialService: &providerStore{provider: &objectStore{}},
+	}
+}
+
+func TestServiceImpl_Download_Suite(t *testing.T) {
+	containerUUID := uuid.New()
+	authUser := auth.User{Uuid: uuid.New(), RoleID: constants.UserRoleDeveloper}
+
+	t.Run("Download: file of another container is not found", func(t *testing.T) {
+		other := File{Uuid: uuid.New(), ContainerUuid: uuid.New(), FullFileName: "secret.pdf"}
+		service := newTestService(t, containerUUID, &fileStore{files: map[uuid.UUID]File{other.Uuid: other}})
+		objects := service.credentialService.(*providerStore).provider.(*objectStore)
+
+		_, _, err := service.Download(other.Uuid, containerUUID, authUser, nil)
+		assert.IsType(t, &errors.NotFoundError{}, err)
+		assert.Empty(t, objects.downloaded)
+	})
+
+	t.Run("Download: file of the container is read from its object", func(t *testing.T) {
+		stored := File{Uuid: uuid.New(), ContainerUuid: containerUUID, FullFileName: "report.pdf"}
+		service := newTestService(t, containerUUID, &fileStore{files: map[uuid.UUID]File{stored.Uuid: stored}})
+		objects := service.credentialService.(*providerStore).provider.(*objectStore)
+
+		fetchedFile, _, err := service.Download(stored.Uuid, containerUUID, authUser, nil)
+		require.NoError(t, err)
+		assert.Equal(t, stored.Uuid, fetchedFile.Uuid)
+		require.Len(t, objects.downloaded, 1)
+		assert.Equal(t, stored.StorageKey(), objects.downloaded[0].FileName)
+	})
+}
+
+func TestServiceImpl_Transform_Suite(t *testing.T) {
+	containerUUID := uuid.New()
+	authUser := auth.User{Uuid: uuid.New(), RoleID: constants.UserRoleDeveloper}
+
+	t.Run("Transform: source is refused by its size in bytes", func(t *testing.T) {
+		// Size is kept in kilobytes and rounded, only SizeInBytes tells the real size
+		source := File{Uuid: uuid.New(), ContainerUuid: containerUUID, FullFileName: "photo.png", Size: 1, SizeInBytes: maxTransformSourceSize + 1, MimeType: "image/png"}
+		service := newTestService(t, containerUUID, &fileStore{files: map[uuid.UUID]File{source.Uuid: source}})
+		objects := service.credentialService.(*providerStore).provider.(*objectStore)
+
+		_, err := service.Transform(source.Uuid, containerUUID, authUser, imaging.TransformOptions{Width: 100})
+		assert.IsType(t, &errors.UnprocessableError{}, err)
+		assert.Empty(t, objects.downloaded)
+	})
+}
+
+func TestServiceImpl_RestoreVersion_Suite(t *testing.T) {
+	containerUUID := uuid.New()
+	authUser := auth.User{Uuid: uuid.New(), RoleID: constants.UserRoleDeveloper}
+
+	t.Run("RestoreVersion: takes over the size of the version", func(t *testing.T) {
+		current := File{Uuid: uuid.New(), ContainerUuid: containerUUID, FullFileName: "report.pdf", Size: 2, SizeInBytes: 2048, MimeType: "application/pdf"}
+		version := Version{Uuid: uuid.New(), FileUuid: current.Uuid, ObjectKey: versionObjectKey(current.Uuid, uuid.New()), Size: 1, SizeInBytes: 300, MimeType: "application/pdf"}
+
+		fileRepo := &fileStore{
+			files:    map[uuid.UUID]File{current.Uuid: current},
+			versions: map[uuid.UUID]Version{version.Uuid: version},
+		}
+		service := newTestService(t, containerUUID, fileRepo)
+
+		restored, err := service.RestoreVersion(version.Uuid, current.Uuid, containerUUID, authUser)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(300), restored.SizeInBytes)
+		require.NotNil(t, fileRepo.updated)
+		assert.Equal(t, int64(300), fileRepo.updated.SizeInBytes)
+
+		// The replaced content is kept as a version with its own size
+		require.Len(t, fileRepo.versions, 1)
+		for _, replaced := range fileRepo.versions {
+			assert.Equal(t, int64(2048), replaced.SizeInBytes)
+		}
+	})
+}
@@ -0,0 +1,335 @@
+package file
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"fluxend/internal/adapters/imaging"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Variants are cached next to the original, under a prefix files can't be listed from
+	variantObjectPrefix = ".variants"
+
+	// Images are decoded in memory, so larger files are refused
+	maxTransformSourceSize = 50 * 1024 * 1024
+
+	// Transform URLs are signed with a key of their own, derived from the JWT secret
+	transformKeyPurpose = "fluxend storage transform"
+)
+
+func (s *ServiceImpl) Transform(fileUUID, containerUUID uuid.UUID, authUser auth.User, options imaging.TransformOptions) (*storage.FileObject, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	fetchedFile, err := s.fileRepo.GetByUUID(fileUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transform(fetchedContainer, fetchedFile, options)
+}
+
+// CreateTransformURL signs a URL serving a variant without authentication, only files of public containers can be shared this way
+func (s *ServiceImpl) CreateTransformURL(fileUUID, containerUUID uuid.UUID, authUser auth.User, options imaging.TransformOptions, expiration time.Duration) (string, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return "", err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return "", err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return "", errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	if !fetchedContainer.IsPublic {
+		return "", errors.NewForbiddenError("file.error.transformNotPublic")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return "", err
+	}
+
+	options, err = s.normalizeTransformOptions(fetchedFile, options)
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(expiration).Unix()
+
+	signature, err := signTransform(fetchedFile.Uuid, options, expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	query := transformQuery(options)
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", signature)
+
+	return fmt.Sprintf("%s/storage/transform/%s?%s", strings.TrimRight(os.Getenv("API_URL"), "/"), fetchedFile.Uuid, query.Encode()), nil
+}
+
+// TransformSigned serves a variant requested through a URL created by CreateTransformURL
+func (s *ServiceImpl) TransformSigned(fileUUID uuid.UUID, options imaging.TransformOptions, expires, signature string) (*storage.FileObject, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("file.error.signatureInvalid")
+	}
+
+	fetchedFile, err := s.fileRepo.GetByUUID(fileUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedOptions, err := s.normalizeTransformOptions(fetchedFile, options)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedSignature, err := signTransform(fetchedFile.Uuid, normalizedOptions, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal([]byte(expectedSignature), []byte(signature)) {
+		return nil, errors.NewUnauthorizedError("file.error.signatureInvalid")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return nil, errors.NewUnauthorizedError("file.error.signatureExpired")
+	}
+
+	fetchedContainer, err := s.containerRepo.GetByUUID(fetchedFile.ContainerUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// A container made private after the URL was signed stops serving it
+	if !fetchedContainer.IsPublic {
+		return nil, errors.NewForbiddenError("file.error.transformNotPublic")
+	}
+
+	return s.transform(fetchedContainer, fetchedFile, normalizedOptions)
+}
+
+// transform returns the cached variant of a file, rendering and caching it first when needed
+func (s *ServiceImpl) transform(fetchedContainer container.Container, fetchedFile File, options imaging.TransformOptions) (*storage.FileObject, error) {
+	if fetchedFile.ContainerUuid != fetchedContainer.Uuid {
+		return nil, errors.NewNotFoundError("file.error.notFound")
+	}
+
+	options, err := s.normalizeTransformOptions(fetchedFile, options)
+	if err != nil {
+		return nil, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	variantKey := options.Key()
+	variant, err := s.fileRepo.GetVariant(fetchedFile.Uuid, variantKey)
+
+	var notFoundErr *errors.NotFoundError
+	if err != nil && !stdErrors.As(err, &notFoundErr) {
+		return nil, err
+	}
+
+	if err == nil {
+		fileObject, err := storageService.DownloadFile(storage.DownloadFileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      variant.ObjectKey,
+		})
+		if err == nil {
+			fileObject.ContentType = variant.MimeType
+			return fileObject, nil
+		}
+
+		// The cached object is gone, render it again below
+	}
+
+	if fetchedFile.SizeInBytes > maxTransformSourceSize {
+		return nil, errors.NewUnprocessableError("image.error.tooLarge")
+	}
+
+	original, err := storageService.DownloadFile(storage.DownloadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fetchedFile.StorageKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer original.Body.Close()
+
+	output, err := imaging.Transform(original.Body, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheVariant(storageService, fetchedContainer, Variant{
+		FileUuid:   fetchedFile.Uuid,
+		VariantKey: variantKey,
+		ObjectKey:  variantObjectKey(fetchedFile.Uuid, options),
+		Size:       int64(len(output)),
+		MimeType:   imaging.ContentType(options.Format),
+		CreatedAt:  time.Now(),
+	}, output)
+
+	return &storage.FileObject{
+		Body:          io.NopCloser(bytes.NewReader(output)),
+		ContentLength: int64(len(output)),
+		TotalSize:     int64(len(output)),
+		ContentType:   imaging.ContentType(options.Format),
+	}, nil
+}
+
+// cacheVariant stores a rendered variant, failures are only logged since the variant can be rendered again
+func (s *ServiceImpl) cacheVariant(storageService storage.Provider, fetchedContainer container.Container, variant Variant, output []byte) {
+	err := storageService.UploadFile(storage.UploadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      variant.ObjectKey,
+		Body:          bytes.NewReader(output),
+		ContentLength: variant.Size,
+		ContentType:   variant.MimeType,
+	})
+	if err == nil {
+		err = s.fileRepo.CreateVariant(&variant)
+	}
+
+	if err != nil {
+		log.Error().
+			Str("file_uuid", variant.FileUuid.String()).
+			Str("variant_key", variant.VariantKey).
+			Str("error", err.Error()).
+			Msg("failed to cache image variant")
+	}
+}
+
+func (s *ServiceImpl) deleteVariants(storageService storage.Provider, fetchedContainer container.Container, fileUUID uuid.UUID) error {
+	variants, err := s.fileRepo.ListVariants(fileUUID)
+	if err != nil {
+		return err
+	}
+
+	for _, variant := range variants {
+		err := storageService.DeleteFile(storage.FileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      variant.ObjectKey,
+		})
+		if err != nil {
+			log.Error().
+				Str("file_uuid", fileUUID.String()).
+				Str("variant_key", variant.VariantKey).
+				Str("error", err.Error()).
+				Msg("failed to delete image variant")
+		}
+	}
+
+	// The content changed or is gone, so variants must be rendered again
+	return s.fileRepo.DeleteVariants(fileUUID)
+}
+
+// normalizeTransformOptions fills in defaults, so equivalent requests share one cached variant and one signature
+func (s *ServiceImpl) normalizeTransformOptions(fetchedFile File, options imaging.TransformOptions) (imaging.TransformOptions, error) {
+	defaultFormat, ok := imaging.SupportedMimeTypes[fetchedFile.MimeType]
+	if !ok {
+		return options, errors.NewUnprocessableError("file.error.notAnImage")
+	}
+
+	if options.Format == "" {
+		options.Format = defaultFormat
+	}
+
+	// PNG and WebP are encoded losslessly, a quality would silently be ignored
+	switch {
+	case options.Format != imaging.FormatJPEG && options.Quality != 0:
+		return options, errors.NewUnprocessableError("image.error.qualityJpegOnly")
+	case options.Format == imaging.FormatJPEG && options.Quality == 0:
+		options.Quality = imaging.DefaultQuality
+	}
+
+	switch {
+	case options.Width == 0 || options.Height == 0:
+		// With a single dimension the aspect ratio is kept, every fit gives the same result
+		options.Fit = imaging.FitContain
+	case options.Fit == "":
+		options.Fit = imaging.FitCover
+	}
+
+	return options, nil
+}
+
+func variantObjectKey(fileUUID uuid.UUID, options imaging.TransformOptions) string {
+	hash := sha256.Sum256([]byte(options.Key()))
+
+	return fmt.Sprintf("%s/%s/%s.%s", variantObjectPrefix, fileUUID, hex.EncodeToString(hash[:16]), imaging.Extension(options.Format))
+}
+
+func transformQuery(options imaging.TransformOptions) url.Values {
+	query := url.Values{}
+	query.Set("format", options.Format)
+	query.Set("fit", options.Fit)
+
+	if options.Width > 0 {
+		query.Set("width", strconv.Itoa(options.Width))
+	}
+
+	if options.Height > 0 {
+		query.Set("height", strconv.Itoa(options.Height))
+	}
+
+	if options.Quality > 0 {
+		query.Set("quality", strconv.Itoa(options.Quality))
+	}
+
+	if options.Crop != nil {
+		query.Set("crop", fmt.Sprintf("%d,%d,%d,%d", options.Crop.Min.X, options.Crop.Min.Y, options.Crop.Dx(), options.Crop.Dy()))
+	}
+
+	return query
+}
+
+func signTransform(fileUUID uuid.UUID, options imaging.TransformOptions, expiresAt int64) (string, error) {
+	key, err := pkg.DeriveKey([]byte(os.Getenv("JWT_SECRET")), nil, transformKeyPurpose)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprintf("transform\n%s\n%s\n%d", fileUUID, options.Key(), expiresAt)))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
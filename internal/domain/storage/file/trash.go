@@ -0,0 +1,249 @@
+package file
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/shared"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+func (s *ServiceImpl) ListTrash(paginationParams shared.PaginationParams, containerUUID uuid.UUID, authUser auth.User) ([]File, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return []File{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return []File{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return []File{}, errors.NewForbiddenError("file.error.listForbidden")
+	}
+
+	return s.fileRepo.ListTrashedForContainer(paginationParams, containerUUID)
+}
+
+func (s *ServiceImpl) RestoreFromTrash(fileUUID, containerUUID uuid.UUID, authUser auth.User, request *RestoreFileInput) (File, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return File{}, errors.NewForbiddenError("file.error.updateForbidden")
+	}
+
+	trashedFile, err := s.getTrashedForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	// The name may have been taken since the file was deleted, it can be restored under another one then
+	if request.FullFileName != "" {
+		trashedFile.FullFileName = request.FullFileName
+	}
+
+	if err = s.validateNameForDuplication(trashedFile.FullFileName, containerUUID); err != nil {
+		return File{}, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	if !trashedFile.Shared() {
+		if err = s.moveObject(storageService, fetchedContainer, trashObjectKey(trashedFile.Uuid), trashedFile.FullFileName); err != nil {
+			return File{}, err
+		}
+	}
+
+	trashedFile.DeletedAt = nil
+	trashedFile.DeletedBy = uuid.NullUUID{}
+	trashedFile.UpdatedAt = time.Now()
+	trashedFile.UpdatedBy = authUser.Uuid
+
+	if _, err = s.fileRepo.Restore(&trashedFile); err != nil {
+		return File{}, err
+	}
+
+	if err = s.containerRepo.IncrementTotalFiles(containerUUID); err != nil {
+		return File{}, err
+	}
+
+	return trashedFile, nil
+}
+
+// Purge deletes a trashed file for good, along with its versions
+func (s *ServiceImpl) Purge(fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("file.error.deleteForbidden")
+	}
+
+	trashedFile, err := s.getTrashedForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return false, err
+	}
+
+	return s.purge(storageService, fetchedContainer, trashedFile, trashObjectKey(trashedFile.Uuid))
+}
+
+// PurgeExpiredTrash deletes the files kept in the trash longer than their container allows, it returns
+// how many were purged and keeps going when a file fails so one broken object doesn't block the rest
+func (s *ServiceImpl) PurgeExpiredTrash() (int, error) {
+	expiredFiles, err := s.fileRepo.ListExpiredTrash()
+	if err != nil {
+		return 0, err
+	}
+
+	containers := map[uuid.UUID]container.Container{}
+	providers := map[uuid.UUID]storage.Provider{}
+
+	purged := 0
+	for _, expiredFile := range expiredFiles {
+		fetchedContainer, ok := containers[expiredFile.ContainerUuid]
+		if !ok {
+			fetchedContainer, err = s.containerRepo.GetByUUID(expiredFile.ContainerUuid)
+			if err != nil {
+				return purged, err
+			}
+
+			storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+			if err != nil {
+				return purged, err
+			}
+
+			containers[expiredFile.ContainerUuid] = fetchedContainer
+			providers[expiredFile.ContainerUuid] = storageService
+		}
+
+		_, err = s.purge(providers[expiredFile.ContainerUuid], fetchedContainer, expiredFile, trashObjectKey(expiredFile.Uuid))
+		if err != nil {
+			log.Error().
+				Str("action", constants.ActionTrashPurge).
+				Str("file_uuid", expiredFile.Uuid.String()).
+				Str("error", err.Error()).
+				Msg("failed to purge trashed file")
+
+			continue
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// trash moves the object of a file aside, so the name can be reused while the file can still be restored.
+// Shared content stays where it is, the trashed file keeps its reference until it's purged
+func (s *ServiceImpl) trash(storageService storage.Provider, fetchedContainer container.Container, fetchedFile File, deletedBy uuid.NullUUID) error {
+	if !fetchedFile.Shared() {
+		if err := s.moveObject(storageService, fetchedContainer, fetchedFile.FullFileName, trashObjectKey(fetchedFile.Uuid)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.deleteVariants(storageService, fetchedContainer, fetchedFile.Uuid); err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	fetchedFile.DeletedAt = &deletedAt
+	fetchedFile.DeletedBy = deletedBy
+
+	if err := s.fileRepo.Trash(&fetchedFile); err != nil {
+		return err
+	}
+
+	return s.containerRepo.DecrementTotalFiles(fetchedContainer.Uuid)
+}
+
+// purge deletes the object of a file stored under objectKey, its versions and variants, then the file itself.
+// Shared content is only deleted when no other file references it
+func (s *ServiceImpl) purge(storageService storage.Provider, fetchedContainer container.Container, fetchedFile File, objectKey string) (bool, error) {
+	var err error
+	if fetchedFile.Shared() {
+		err = s.releaseObject(storageService, fetchedContainer, fetchedFile)
+	} else {
+		err = storageService.DeleteFile(storage.FileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      objectKey,
+		})
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	versions, err := s.fileRepo.ListVersions(fetchedFile.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	for _, version := range versions {
+		err = storageService.DeleteFile(storage.FileInput{
+			ContainerName: fetchedContainer.NameKey,
+			FileName:      version.ObjectKey,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Variant rows go with the file, so their objects are removed first
+	if err = s.deleteVariants(storageService, fetchedContainer, fetchedFile.Uuid); err != nil {
+		return false, err
+	}
+
+	fileDeleted, err := s.fileRepo.Delete(fetchedFile.Uuid)
+	if err != nil || !fileDeleted {
+		return fileDeleted, err
+	}
+
+	return true, s.quotaService.Release(fetchedContainer.ProjectUuid, storedSize(fetchedFile, versions), 1)
+}
+
+func (s *ServiceImpl) getTrashedForContainer(fileUUID, containerUUID uuid.UUID) (File, error) {
+	trashedFile, err := s.fileRepo.GetTrashedByUUID(fileUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	if trashedFile.ContainerUuid != containerUUID {
+		return File{}, errors.NewNotFoundError("file.error.notInTrash")
+	}
+
+	return trashedFile, nil
+}
+
+// trashObjectKey is where a trashed file is kept, repositories.ContainerRepository.ListObjectKeys builds the same key
+func trashObjectKey(fileUUID uuid.UUID) string {
+	return ".trash/" + fileUUID.String()
+}
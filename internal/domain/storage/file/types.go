@@ -2,8 +2,11 @@ package file
 
 import (
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
 	"github.com/labstack/echo/v4"
+	"io"
 	"mime/multipart"
+	"time"
 )
 
 type CreateFileInput struct {
@@ -11,6 +14,8 @@ type CreateFileInput struct {
 	ProjectUUID  uuid.UUID             `db:"project_uuid" json:"projectUUID"`
 	FullFileName string                `json:"-" form:"full_file_name"`
 	File         *multipart.FileHeader `json:"-" form:"file"`
+	Metadata     Metadata              `json:"-"`
+	Tags         []string              `json:"-"`
 }
 
 type RenameFileInput struct {
@@ -18,3 +23,52 @@ type RenameFileInput struct {
 	ProjectUUID  uuid.UUID `db:"project_uuid" json:"projectUUID"`
 	FullFileName string    `json:"full_file_name"`
 }
+
+// RestoreFileInput restores a trashed file under another name when FullFileName is set
+type RestoreFileInput struct {
+	FullFileName string
+}
+
+// StoreFileInput describes a file whose contents are streamed from Body rather than a multipart form
+type StoreFileInput struct {
+	FullFileName string
+	MimeType     string
+	Size         int64 // in bytes
+	Body         io.Reader
+	Metadata     Metadata
+	Tags         []string
+}
+
+// UpdateMetadataInput replaces the metadata and tags of a file
+type UpdateMetadataInput struct {
+	Metadata Metadata
+	Tags     []string
+}
+
+// ListFilesInput filters the files of a container, zero values don't filter. With a Delimiter, only files
+// directly under Prefix are listed and deeper names are grouped into folders
+type ListFilesInput struct {
+	Prefix        string
+	Delimiter     string
+	MimeType      string // either a full type or a wildcard like image/*
+	MinSize       int    // in KB
+	MaxSize       int    // in KB
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Tag           string
+	Metadata      Metadata // files have to hold every pair
+}
+
+// StoredObject is an object of a container along with the checksum its content was stored with
+type StoredObject struct {
+	FileUuid  uuid.NullUUID `db:"file_uuid"` // not set for shared objects
+	ObjectKey string        `db:"object_key"`
+	Checksum  null.String   `db:"checksum"` // not set for content stored before checksums were recorded
+}
+
+// Listing is one level of the virtual folder hierarchy of a container
+type Listing struct {
+	Prefix  string
+	Folders []string
+	Files   []File
+}
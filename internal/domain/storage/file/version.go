@@ -0,0 +1,274 @@
+package file
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+func (s *ServiceImpl) ListVersions(fileUUID, containerUUID uuid.UUID, authUser auth.User) ([]Version, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return []Version{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return []Version{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return []Version{}, errors.NewForbiddenError("file.error.viewForbidden")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return []Version{}, err
+	}
+
+	return s.fileRepo.ListVersions(fetchedFile.Uuid)
+}
+
+// RestoreVersion makes a version the current content, the content it replaces is kept as a new version
+func (s *ServiceImpl) RestoreVersion(versionUUID, fileUUID, containerUUID uuid.UUID, authUser auth.User) (File, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return File{}, errors.NewForbiddenError("file.error.updateForbidden")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	fetchedVersion, err := s.getVersionForFile(versionUUID, fetchedFile.Uuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return File{}, err
+	}
+
+	replacedVersion := s.newVersion(fetchedFile)
+	if err = s.moveObject(storageService, fetchedContainer, fetchedFile.FullFileName, replacedVersion.ObjectKey); err != nil {
+		return File{}, err
+	}
+
+	if err = s.moveObject(storageService, fetchedContainer, fetchedVersion.ObjectKey, fetchedFile.FullFileName); err != nil {
+		s.rollbackMove(storageService, fetchedContainer, replacedVersion.ObjectKey, fetchedFile.FullFileName)
+
+		return File{}, err
+	}
+
+	if err = s.fileRepo.CreateVersion(&replacedVersion); err != nil {
+		return File{}, err
+	}
+
+	if _, err = s.fileRepo.DeleteVersion(fetchedVersion.Uuid); err != nil {
+		return File{}, err
+	}
+
+	if err = s.deleteVariants(storageService, fetchedContainer, fetchedFile.Uuid); err != nil {
+		return File{}, err
+	}
+
+	fetchedFile.Size = fetchedVersion.Size
+	fetchedFile.SizeInBytes = fetchedVersion.SizeInBytes
+	fetchedFile.MimeType = fetchedVersion.MimeType
+	fetchedFile.Checksum = fetchedVersion.Checksum
+	fetchedFile.UpdatedAt = time.Now()
+	fetchedFile.UpdatedBy = authUser.Uuid
+
+	if _, err = s.fileRepo.UpdateContent(&fetchedFile); err != nil {
+		return File{}, err
+	}
+
+	return fetchedFile, nil
+}
+
+func (s *ServiceImpl) DeleteVersion(versionUUID, fileUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("file.error.deleteForbidden")
+	}
+
+	fetchedFile, err := s.getForContainer(fileUUID, containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	fetchedVersion, err := s.getVersionForFile(versionUUID, fetchedFile.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return false, err
+	}
+
+	err = storageService.DeleteFile(storage.FileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fetchedVersion.ObjectKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	versionDeleted, err := s.fileRepo.DeleteVersion(fetchedVersion.Uuid)
+	if err != nil || !versionDeleted {
+		return versionDeleted, err
+	}
+
+	return true, s.quotaService.Release(fetchedContainer.ProjectUuid, fetchedVersion.SizeInBytes, 0)
+}
+
+// storeVersion replaces the content of an existing file, the previous content is moved aside as a version first
+func (s *ServiceImpl) storeVersion(storageService storage.Provider, fetchedContainer container.Container, currentFile File, request *StoreFileInput, authUser auth.User) (File, error) {
+	// The previous content stays stored as a version, so only the new content takes space
+	size := request.Size
+	if err := s.quotaService.Reserve(fetchedContainer.ProjectUuid, size, 0); err != nil {
+		return File{}, err
+	}
+
+	previousVersion := s.newVersion(currentFile)
+	if err := s.moveObject(storageService, fetchedContainer, currentFile.FullFileName, previousVersion.ObjectKey); err != nil {
+		s.releaseQuota(fetchedContainer.ProjectUuid, size, 0)
+
+		return File{}, err
+	}
+
+	checksum, err := s.uploadObject(storageService, fetchedContainer, request.FullFileName, request)
+	if err != nil {
+		s.rollbackMove(storageService, fetchedContainer, previousVersion.ObjectKey, currentFile.FullFileName)
+		s.releaseQuota(fetchedContainer.ProjectUuid, size, 0)
+
+		return File{}, err
+	}
+
+	if err = s.fileRepo.CreateVersion(&previousVersion); err != nil {
+		return File{}, err
+	}
+
+	if err = s.deleteVariants(storageService, fetchedContainer, currentFile.Uuid); err != nil {
+		return File{}, err
+	}
+
+	currentFile.Size = pkg.ConvertBytesToKiloBytes(int(size))
+	currentFile.SizeInBytes = size
+	currentFile.MimeType = request.MimeType
+	currentFile.Checksum = null.StringFrom(checksum)
+	currentFile.UpdatedAt = time.Now()
+	currentFile.UpdatedBy = authUser.Uuid
+
+	if _, err = s.fileRepo.UpdateContent(&currentFile); err != nil {
+		return File{}, err
+	}
+
+	return currentFile, nil
+}
+
+// newVersion describes the current content of a file as a version, it was written by the last update
+func (s *ServiceImpl) newVersion(currentFile File) Version {
+	versionUUID := uuid.New()
+
+	return Version{
+		Uuid:        versionUUID,
+		FileUuid:    currentFile.Uuid,
+		ObjectKey:   versionObjectKey(currentFile.Uuid, versionUUID),
+		Size:        currentFile.Size,
+		SizeInBytes: currentFile.SizeInBytes,
+		MimeType:    currentFile.MimeType,
+		CreatedBy:   currentFile.UpdatedBy,
+		CreatedAt:   currentFile.UpdatedAt,
+		Checksum:    currentFile.Checksum,
+	}
+}
+
+// storedSize is what a file takes up in bytes along with its versions
+func storedSize(fetchedFile File, versions []Version) int64 {
+	size := fetchedFile.SizeInBytes
+	for _, version := range versions {
+		size += version.SizeInBytes
+	}
+
+	return size
+}
+
+func (s *ServiceImpl) getVersionForFile(versionUUID, fileUUID uuid.UUID) (Version, error) {
+	fetchedVersion, err := s.fileRepo.GetVersion(versionUUID)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if fetchedVersion.FileUuid != fileUUID {
+		return Version{}, errors.NewNotFoundError("file.error.versionNotFound")
+	}
+
+	return fetchedVersion, nil
+}
+
+// getForContainer fetches a live file and makes sure it's stored in the given container
+func (s *ServiceImpl) getForContainer(fileUUID, containerUUID uuid.UUID) (File, error) {
+	fetchedFile, err := s.fileRepo.GetByUUID(fileUUID)
+	if err != nil {
+		return File{}, err
+	}
+
+	if fetchedFile.ContainerUuid != containerUUID {
+		return File{}, errors.NewNotFoundError("file.error.notFound")
+	}
+
+	return fetchedFile, nil
+}
+
+func (s *ServiceImpl) moveObject(storageService storage.Provider, fetchedContainer container.Container, fromKey, toKey string) error {
+	return storageService.RenameFile(storage.RenameFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      fromKey,
+		NewFileName:   toKey,
+	})
+}
+
+// rollbackMove puts an object back after a failed step, a failure is only logged so the original error is returned
+func (s *ServiceImpl) rollbackMove(storageService storage.Provider, fetchedContainer container.Container, fromKey, toKey string) {
+	if err := s.moveObject(storageService, fetchedContainer, fromKey, toKey); err != nil {
+		log.Error().
+			Str("container_name", fetchedContainer.NameKey).
+			Str("object_key", fromKey).
+			Str("error", err.Error()).
+			Msg("failed to move object back to " + toKey)
+	}
+}
+
+// versionObjectKey keeps versions next to variants, out of the way of user file names
+func versionObjectKey(fileUUID, versionUUID uuid.UUID) string {
+	return fmt.Sprintf(".versions/%s/%s", fileUUID, versionUUID)
+}
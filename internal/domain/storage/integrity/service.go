@@ -0,0 +1,158 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"io"
+	"sort"
+)
+
+type Service interface {
+	Verify(driver string) ([]Report, error)
+}
+
+type ServiceImpl struct {
+	containerRepo     container.Repository
+	fileRepo          file.Repository
+	credentialService credential.Service
+}
+
+func NewIntegrityService(injector *do.Injector) (Service, error) {
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	fileRepo := do.MustInvoke[file.Repository](injector)
+	credentialService := do.MustInvoke[credential.Service](injector)
+
+	return &ServiceImpl{
+		containerRepo:     containerRepo,
+		fileRepo:          fileRepo,
+		credentialService: credentialService,
+	}, nil
+}
+
+// Verify downloads every file, trashed file and version and compares its content with the checksum recorded
+// when it was stored. Reports are grouped by driver, an empty driver verifies the containers of all drivers
+func (s *ServiceImpl) Verify(driver string) ([]Report, error) {
+	containers, err := s.containerRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := map[string]*Report{}
+	for _, currentContainer := range containers {
+		if driver != "" && currentContainer.Provider != driver {
+			continue
+		}
+
+		report, ok := reports[currentContainer.Provider]
+		if !ok {
+			report = &Report{Driver: currentContainer.Provider, Problems: []Problem{}}
+			reports[currentContainer.Provider] = report
+		}
+
+		if err = s.verifyContainer(currentContainer, report); err != nil {
+			return nil, err
+		}
+	}
+
+	drivers := make([]string, 0, len(reports))
+	for reportDriver := range reports {
+		drivers = append(drivers, reportDriver)
+	}
+	sort.Strings(drivers)
+
+	sortedReports := make([]Report, len(drivers))
+	for i, reportDriver := range drivers {
+		sortedReports[i] = *reports[reportDriver]
+	}
+
+	return sortedReports, nil
+}
+
+// verifyContainer adds the objects of a container to the report of its driver, a container whose storage
+// can't be reached reports every object as failed so the others are still verified
+func (s *ServiceImpl) verifyContainer(fetchedContainer container.Container, report *Report) error {
+	objects, err := s.fileRepo.ListStoredObjects(fetchedContainer.Uuid)
+	if err != nil {
+		return err
+	}
+
+	report.Containers++
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	for _, object := range objects {
+		if err != nil {
+			report.add(newProblem(fetchedContainer, object, StatusFailed, err), false)
+
+			continue
+		}
+
+		report.add(verifyObject(storageService, fetchedContainer, object))
+	}
+
+	log.Info().
+		Str("action", constants.ActionStorageVerify).
+		Str("container_uuid", fetchedContainer.Uuid.String()).
+		Str("driver", fetchedContainer.Provider).
+		Int("objects", len(objects)).
+		Msg("container verified")
+
+	return nil
+}
+
+// verifyObject reads an object back in full, it returns the problem found if any and whether the content
+// could be compared with a recorded checksum
+func verifyObject(storageService storage.Provider, fetchedContainer container.Container, object file.StoredObject) (*Problem, bool) {
+	fileObject, err := storageService.DownloadFile(storage.DownloadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      object.ObjectKey,
+	})
+	if err != nil {
+		var notFoundErr *errors.NotFoundError
+		if stdErrors.As(err, &notFoundErr) {
+			return newProblem(fetchedContainer, object, StatusMissing, nil), false
+		}
+
+		return newProblem(fetchedContainer, object, StatusFailed, err), false
+	}
+	defer fileObject.Body.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, fileObject.Body); err != nil {
+		return newProblem(fetchedContainer, object, StatusFailed, err), false
+	}
+
+	if !object.Checksum.Valid {
+		return nil, false
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != object.Checksum.String {
+		return newProblem(fetchedContainer, object, StatusCorrupted, nil), true
+	}
+
+	return nil, true
+}
+
+func newProblem(fetchedContainer container.Container, object file.StoredObject, status string, err error) *Problem {
+	problem := &Problem{
+		ContainerUuid: fetchedContainer.Uuid,
+		ContainerName: fetchedContainer.Name,
+		ObjectKey:     object.ObjectKey,
+		FileUuid:      object.FileUuid,
+		Status:        status,
+	}
+
+	if err != nil {
+		problem.Error = err.Error()
+	}
+
+	return problem
+}
@@ -0,0 +1,125 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryProvider serves downloads from a map, objects listed in broken fail to download
+type memoryProvider struct {
+	storage.Provider
+	objects map[string]string
+	broken  map[string]bool
+}
+
+func (p *memoryProvider) DownloadFile(input storage.DownloadFileInput) (*storage.FileObject, error) {
+	if p.broken[input.FileName] {
+		return nil, fmt.Errorf("connection reset while reading %s", input.FileName)
+	}
+
+	content, ok := p.objects[input.FileName]
+	if !ok {
+		return nil, errors.NewNotFoundError("file.error.notFound")
+	}
+
+	return &storage.FileObject{
+		Body:          io.NopCloser(strings.NewReader(content)),
+		ContentLength: int64(len(content)),
+	}, nil
+}
+
+func checksumOf(content string) null.String {
+	sum := sha256.Sum256([]byte(content))
+
+	return null.StringFrom(hex.EncodeToString(sum[:]))
+}
+
+func TestVerifyObject_Suite(t *testing.T) {
+	provider := &memoryProvider{
+		objects: map[string]string{
+			"reports/january.csv":  "month,total\njanuary,10\n",
+			"reports/february.csv": "month,total\nfebruary,12\n",
+			"legacy.txt":           "stored before checksums",
+		},
+		broken: map[string]bool{"reports/march.csv": true},
+	}
+	bucket := container.Container{Uuid: uuid.New(), Name: "reports", NameKey: "bucket"}
+
+	t.Run("verifyObject: intact content", func(t *testing.T) {
+		problem, verified := verifyObject(provider, bucket, file.StoredObject{
+			ObjectKey: "reports/january.csv",
+			Checksum:  checksumOf("month,total\njanuary,10\n"),
+		})
+
+		assert.Nil(t, problem)
+		assert.True(t, verified)
+	})
+
+	t.Run("verifyObject: corrupted content", func(t *testing.T) {
+		problem, verified := verifyObject(provider, bucket, file.StoredObject{
+			ObjectKey: "reports/february.csv",
+			Checksum:  checksumOf("month,total\nfebruary,13\n"),
+		})
+
+		require.NotNil(t, problem)
+		assert.Equal(t, StatusCorrupted, problem.Status)
+		assert.Equal(t, bucket.Uuid, problem.ContainerUuid)
+		assert.True(t, verified)
+	})
+
+	t.Run("verifyObject: missing object", func(t *testing.T) {
+		problem, _ := verifyObject(provider, bucket, file.StoredObject{
+			ObjectKey: "reports/april.csv",
+			Checksum:  checksumOf("anything"),
+		})
+
+		require.NotNil(t, problem)
+		assert.Equal(t, StatusMissing, problem.Status)
+	})
+
+	t.Run("verifyObject: unreadable object", func(t *testing.T) {
+		problem, _ := verifyObject(provider, bucket, file.StoredObject{ObjectKey: "reports/march.csv"})
+
+		require.NotNil(t, problem)
+		assert.Equal(t, StatusFailed, problem.Status)
+		assert.Contains(t, problem.Error, "connection reset")
+	})
+
+	t.Run("verifyObject: no recorded checksum only checks existence", func(t *testing.T) {
+		problem, verified := verifyObject(provider, bucket, file.StoredObject{ObjectKey: "legacy.txt"})
+
+		assert.Nil(t, problem)
+		assert.False(t, verified)
+	})
+}
+
+func TestReport_Add(t *testing.T) {
+	report := Report{Driver: "S3", Problems: []Problem{}}
+
+	report.add(nil, true)
+	report.add(nil, false)
+	report.add(&Problem{Status: StatusMissing}, false)
+	report.add(&Problem{Status: StatusCorrupted}, true)
+	report.add(&Problem{Status: StatusFailed}, false)
+
+	assert.Equal(t, 5, report.Checked)
+	assert.Equal(t, 1, report.Verified)
+	assert.Equal(t, 1, report.Unverified)
+	assert.Equal(t, 1, report.Missing)
+	assert.Equal(t, 1, report.Corrupted)
+	assert.Equal(t, 1, report.Failed)
+	assert.Len(t, report.Problems, 3)
+}
@@ -0,0 +1,59 @@
+package integrity
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	StatusMissing   = "missing"
+	StatusCorrupted = "corrupted"
+	StatusFailed    = "failed" // the object couldn't be read, it may still be intact
+)
+
+// Report sums up the verification of every container stored with one driver
+type Report struct {
+	Driver     string    `json:"driver"`
+	Containers int       `json:"containers"`
+	Checked    int       `json:"checked"`
+	Verified   int       `json:"verified"`   // read back with the recorded checksum
+	Unverified int       `json:"unverified"` // found, but stored before checksums were recorded
+	Missing    int       `json:"missing"`
+	Corrupted  int       `json:"corrupted"`
+	Failed     int       `json:"failed"`
+	Problems   []Problem `json:"problems"`
+}
+
+// Problem is an object that is missing, doesn't match its checksum or couldn't be read
+type Problem struct {
+	ContainerUuid uuid.UUID     `json:"containerUuid"`
+	ContainerName string        `json:"containerName"`
+	ObjectKey     string        `json:"objectKey"`
+	FileUuid      uuid.NullUUID `json:"fileUuid"`
+	Status        string        `json:"status"`
+	Error         string        `json:"error,omitempty"`
+}
+
+func (r *Report) add(problem *Problem, verified bool) {
+	r.Checked++
+
+	if problem == nil {
+		if verified {
+			r.Verified++
+		} else {
+			r.Unverified++
+		}
+
+		return
+	}
+
+	switch problem.Status {
+	case StatusMissing:
+		r.Missing++
+	case StatusCorrupted:
+		r.Corrupted++
+	default:
+		r.Failed++
+	}
+
+	r.Problems = append(r.Problems, *problem)
+}
@@ -0,0 +1,27 @@
+package lifecycle
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Rule deletes or moves the files of a container under a prefix once they weren't modified for AfterDays
+type Rule struct {
+	shared.BaseEntity
+	Uuid                uuid.UUID     `db:"uuid" json:"uuid"`
+	ContainerUuid       uuid.UUID     `db:"container_uuid" json:"containerUuid"`
+	Name                string        `db:"name" json:"name"`
+	Prefix              string        `db:"prefix" json:"prefix"`
+	Action              string        `db:"action" json:"action"`
+	AfterDays           int           `db:"after_days" json:"afterDays"`
+	TargetContainerUuid uuid.NullUUID `db:"target_container_uuid" json:"targetContainerUuid"` // only set for move rules
+	IsEnabled           bool          `db:"is_enabled" json:"isEnabled"`
+	LastRunAt           *time.Time    `db:"last_run_at" json:"lastRunAt"`
+	CursorUpdatedAt     *time.Time    `db:"cursor_updated_at" json:"-"` // where the last run stopped, unset once it reached the end
+	CursorFileUuid      uuid.NullUUID `db:"cursor_file_uuid" json:"-"`
+	CreatedBy           uuid.UUID     `db:"created_by" json:"createdBy"`
+	UpdatedBy           uuid.UUID     `db:"updated_by" json:"updatedBy"`
+	CreatedAt           time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt           time.Time     `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,17 @@
+package lifecycle
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	ListForContainer(containerUUID uuid.UUID) ([]Rule, error)
+	GetByUUID(ruleUUID uuid.UUID) (Rule, error)
+	ExistsByNameForContainer(name string, containerUUID uuid.UUID) (bool, error)
+	ClaimDue(lastRunBefore time.Time) ([]Rule, error)
+	Create(rule *Rule) (*Rule, error)
+	Update(rule *Rule) (*Rule, error)
+	SaveCursor(rule *Rule) error
+	Delete(ruleUUID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,407 @@
+package lifecycle
+
+import (
+	"context"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"time"
+)
+
+type Service interface {
+	List(containerUUID uuid.UUID, authUser auth.User) ([]Rule, error)
+	GetByUUID(ruleUUID, containerUUID uuid.UUID, authUser auth.User) (Rule, error)
+	Create(containerUUID uuid.UUID, request *CreateRuleInput, authUser auth.User) (Rule, error)
+	Update(ruleUUID, containerUUID uuid.UUID, request *CreateRuleInput, authUser auth.User) (*Rule, error)
+	Delete(ruleUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	DryRun(containerUUID uuid.UUID, authUser auth.User) ([]Report, error)
+	ApplyDue() ([]Report, error)
+	Work(ctx context.Context)
+}
+
+type ServiceImpl struct {
+	projectPolicy *project.Policy
+	ruleRepo      Repository
+	containerRepo container.Repository
+	fileRepo      file.Repository
+	projectRepo   project.Repository
+	fileService   file.Service
+}
+
+func NewLifecycleService(injector *do.Injector) (Service, error) {
+	policy := do.MustInvoke[*project.Policy](injector)
+	ruleRepo := do.MustInvoke[Repository](injector)
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	fileRepo := do.MustInvoke[file.Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	fileService := do.MustInvoke[file.Service](injector)
+
+	return &ServiceImpl{
+		projectPolicy: policy,
+		ruleRepo:      ruleRepo,
+		containerRepo: containerRepo,
+		fileRepo:      fileRepo,
+		projectRepo:   projectRepo,
+		fileService:   fileService,
+	}, nil
+}
+
+func (s *ServiceImpl) List(containerUUID uuid.UUID, authUser auth.User) ([]Rule, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return []Rule{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return []Rule{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return []Rule{}, errors.NewForbiddenError("lifecycle.error.listForbidden")
+	}
+
+	return s.ruleRepo.ListForContainer(containerUUID)
+}
+
+func (s *ServiceImpl) GetByUUID(ruleUUID, containerUUID uuid.UUID, authUser auth.User) (Rule, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return Rule{}, errors.NewForbiddenError("lifecycle.error.viewForbidden")
+	}
+
+	return s.getForContainer(ruleUUID, containerUUID)
+}
+
+func (s *ServiceImpl) Create(containerUUID uuid.UUID, request *CreateRuleInput, authUser auth.User) (Rule, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if !s.projectPolicy.CanCreate(organizationUUID, authUser) {
+		return Rule{}, errors.NewForbiddenError("lifecycle.error.createForbidden")
+	}
+
+	if err = s.validateNameForDuplication(request.Name, containerUUID); err != nil {
+		return Rule{}, err
+	}
+
+	ruleInput := Rule{
+		ContainerUuid: containerUUID,
+		CreatedBy:     authUser.Uuid,
+		UpdatedBy:     authUser.Uuid,
+	}
+
+	if err = s.fill(&ruleInput, fetchedContainer, request); err != nil {
+		return Rule{}, err
+	}
+
+	if _, err = s.ruleRepo.Create(&ruleInput); err != nil {
+		return Rule{}, err
+	}
+
+	return ruleInput, nil
+}
+
+func (s *ServiceImpl) Update(ruleUUID, containerUUID uuid.UUID, request *CreateRuleInput, authUser auth.User) (*Rule, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return nil, errors.NewForbiddenError("lifecycle.error.updateForbidden")
+	}
+
+	fetchedRule, err := s.getForContainer(ruleUUID, containerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != fetchedRule.Name {
+		if err = s.validateNameForDuplication(request.Name, containerUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.fill(&fetchedRule, fetchedContainer, request); err != nil {
+		return nil, err
+	}
+
+	fetchedRule.UpdatedAt = time.Now()
+	fetchedRule.UpdatedBy = authUser.Uuid
+
+	return s.ruleRepo.Update(&fetchedRule)
+}
+
+func (s *ServiceImpl) Delete(ruleUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !s.projectPolicy.CanUpdate(organizationUUID, authUser) {
+		return false, errors.NewForbiddenError("lifecycle.error.deleteForbidden")
+	}
+
+	fetchedRule, err := s.getForContainer(ruleUUID, containerUUID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.ruleRepo.Delete(fetchedRule.Uuid)
+}
+
+// DryRun reports the files every rule of a container would handle now, disabled rules included
+func (s *ServiceImpl) DryRun(containerUUID uuid.UUID, authUser auth.User) ([]Report, error) {
+	rules, err := s.List(containerUUID, authUser)
+	if err != nil {
+		return []Report{}, err
+	}
+
+	reports := make([]Report, 0, len(rules))
+	for _, rule := range rules {
+		report, err := s.run(rule, true)
+		if err != nil {
+			return []Report{}, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// ApplyDue runs the enabled rules that haven't run for an interval, a failing rule doesn't stop the others
+func (s *ServiceImpl) ApplyDue() ([]Report, error) {
+	rules, err := s.ruleRepo.ClaimDue(time.Now().Add(-constants.LifecycleRuleInterval))
+	if err != nil {
+		return []Report{}, err
+	}
+
+	reports := make([]Report, 0, len(rules))
+	for _, rule := range rules {
+		report, err := s.run(rule, false)
+		if err != nil {
+			log.Error().
+				Str("action", constants.ActionLifecycle).
+				Str("rule_uuid", rule.Uuid.String()).
+				Str("error", err.Error()).
+				Msg("failed to run lifecycle rule")
+
+			continue
+		}
+
+		if len(report.Items) > 0 {
+			log.Info().
+				Str("action", constants.ActionLifecycle).
+				Str("rule_uuid", rule.Uuid.String()).
+				Str("rule_action", rule.Action).
+				Int("applied", report.Applied).
+				Int("failed", report.Failed).
+				Msg("lifecycle rule applied")
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Work runs due rules and purges expired trash every tick until the context is done, it's started
+// by the API server so no separate cron job is needed
+func (s *ServiceImpl) Work(ctx context.Context) {
+	ticker := time.NewTicker(constants.LifecycleWorkerTick)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ApplyDue(); err != nil {
+			log.Error().
+				Str("action", constants.ActionLifecycle).
+				Str("error", err.Error()).
+				Msg("failed to claim lifecycle rules")
+		}
+
+		if _, err := s.fileService.PurgeExpiredTrash(); err != nil {
+			log.Error().
+				Str("action", constants.ActionTrashPurge).
+				Str("error", err.Error()).
+				Msg("failed to purge expired trash")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run handles a batch of files after where the last run stopped, so files that keep failing don't hold back
+// the ones after them; once a run reaches the end the next one starts over and retries them
+func (s *ServiceImpl) run(rule Rule, dryRun bool) (Report, error) {
+	modifiedBefore := time.Now().AddDate(0, 0, -rule.AfterDays)
+
+	var afterUpdatedAt time.Time
+	if rule.CursorUpdatedAt != nil && rule.CursorFileUuid.Valid {
+		afterUpdatedAt = *rule.CursorUpdatedAt
+	}
+
+	files, err := s.fileRepo.ListModifiedBefore(
+		rule.ContainerUuid,
+		rule.Prefix,
+		modifiedBefore,
+		afterUpdatedAt,
+		rule.CursorFileUuid.UUID,
+		constants.LifecycleRuleBatchSize,
+	)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		RuleUuid: rule.Uuid,
+		RuleName: rule.Name,
+		Action:   rule.Action,
+		DryRun:   dryRun,
+		Items:    make([]ReportItem, 0, len(files)),
+	}
+
+	for _, matchedFile := range files {
+		item := ReportItem{
+			FileUuid:     matchedFile.Uuid,
+			FullFileName: matchedFile.FullFileName,
+			Size:         matchedFile.Size,
+			UpdatedAt:    matchedFile.UpdatedAt,
+		}
+
+		if !dryRun {
+			if err = s.apply(rule, matchedFile); err != nil {
+				item.Error = err.Error()
+				report.Failed++
+			} else {
+				report.Applied++
+			}
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	return report, s.saveCursor(&rule, files)
+}
+
+// saveCursor remembers the last file of a full batch, a shorter batch means the run reached the end
+func (s *ServiceImpl) saveCursor(rule *Rule, files []file.File) error {
+	rule.CursorUpdatedAt = nil
+	rule.CursorFileUuid = uuid.NullUUID{}
+
+	if len(files) == constants.LifecycleRuleBatchSize {
+		last := files[len(files)-1]
+		rule.CursorUpdatedAt = &last.UpdatedAt
+		rule.CursorFileUuid = uuid.NullUUID{UUID: last.Uuid, Valid: true}
+	}
+
+	return s.ruleRepo.SaveCursor(rule)
+}
+
+func (s *ServiceImpl) apply(rule Rule, matchedFile file.File) error {
+	if rule.Action == constants.LifecycleActionMove {
+		_, err := s.fileService.MoveToContainer(matchedFile, rule.TargetContainerUuid.UUID)
+
+		return err
+	}
+
+	return s.fileService.Expire(matchedFile)
+}
+
+// fill copies the input onto a rule, a move rule needs another container of the same project
+func (s *ServiceImpl) fill(rule *Rule, fetchedContainer container.Container, request *CreateRuleInput) error {
+	rule.Name = request.Name
+	rule.Prefix = request.Prefix
+	rule.Action = request.Action
+	rule.AfterDays = request.AfterDays
+	rule.IsEnabled = request.IsEnabled
+	rule.TargetContainerUuid = uuid.NullUUID{}
+
+	if request.Action != constants.LifecycleActionMove {
+		return nil
+	}
+
+	if !request.TargetContainerUUID.Valid {
+		return errors.NewUnprocessableError("lifecycle.error.targetRequired")
+	}
+
+	targetContainer, err := s.containerRepo.GetByUUID(request.TargetContainerUUID.UUID)
+	if err != nil {
+		return err
+	}
+
+	if targetContainer.Uuid == fetchedContainer.Uuid || targetContainer.ProjectUuid != fetchedContainer.ProjectUuid {
+		return errors.NewUnprocessableError("lifecycle.error.targetInvalid")
+	}
+
+	rule.TargetContainerUuid = request.TargetContainerUUID
+
+	return nil
+}
+
+func (s *ServiceImpl) getForContainer(ruleUUID, containerUUID uuid.UUID) (Rule, error) {
+	fetchedRule, err := s.ruleRepo.GetByUUID(ruleUUID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if fetchedRule.ContainerUuid != containerUUID {
+		return Rule{}, errors.NewNotFoundError("lifecycle.error.notFound")
+	}
+
+	return fetchedRule, nil
+}
+
+func (s *ServiceImpl) validateNameForDuplication(name string, containerUUID uuid.UUID) error {
+	exists, err := s.ruleRepo.ExistsByNameForContainer(name, containerUUID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.NewUnprocessableError("lifecycle.error.duplicateName")
+	}
+
+	return nil
+}
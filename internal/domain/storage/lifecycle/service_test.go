@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"errors"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/storage/file"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ruleStore struct {
+	Repository
+	saved []Rule
+}
+
+func (rs *ruleStore) SaveCursor(rule *Rule) error {
+	rs.saved = append(rs.saved, *rule)
+
+	return nil
+}
+
+// fileStore keeps its files ordered by (updated_at, uuid) like the database does
+type fileStore struct {
+	file.Repository
+	files []file.File
+}
+
+func (fs *fileStore) ListModifiedBefore(
+	containerUUID uuid.UUID,
+	prefix string,
+	modifiedBefore, afterUpdatedAt time.Time,
+	afterUUID uuid.UUID,
+	limit int,
+) ([]file.File, error) {
+	files := make([]file.File, 0, limit)
+	for _, storedFile := range fs.files {
+		after := storedFile.UpdatedAt.After(afterUpdatedAt) ||
+			storedFile.UpdatedAt.Equal(afterUpdatedAt) && storedFile.Uuid.String() > afterUUID.String()
+		if !after || !storedFile.UpdatedAt.Before(modifiedBefore) || len(files) == limit {
+			continue
+		}
+
+		files = append(files, storedFile)
+	}
+
+	return files, nil
+}
+
+type fileExpirer struct {
+	file.Service
+	store   *fileStore
+	failing map[uuid.UUID]bool
+}
+
+func (fe *fileExpirer) Expire(expiredFile file.File) error {
+	if fe.failing[expiredFile.Uuid] {
+		return errors.New("object store unavailable")
+	}
+
+	for i, storedFile := range fe.store.files {
+		if storedFile.Uuid == expiredFile.Uuid {
+			fe.store.files = append(fe.store.files[:i], fe.store.files[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func TestServiceImpl_Run_Suite(t *testing.T) {
+	t.Run("files that keep failing don't hold back the ones after them", func(t *testing.T) {
+		store := &fileStore{}
+		expirer := &fileExpirer{store: store, failing: map[uuid.UUID]bool{}}
+		oldest := time.Now().AddDate(-1, 0, 0)
+
+		for i := 0; i <= constants.LifecycleRuleBatchSize; i++ {
+			storedFile := file.File{Uuid: uuid.New(), UpdatedAt: oldest.Add(time.Duration(i) * time.Second)}
+			store.files = append(store.files, storedFile)
+
+			if i < constants.LifecycleRuleBatchSize {
+				expirer.failing[storedFile.Uuid] = true
+			}
+		}
+
+		rules := &ruleStore{}
+		service := &ServiceImpl{ruleRepo: rules, fileRepo: store, fileService: expirer}
+		rule := Rule{Uuid: uuid.New(), Action: constants.LifecycleActionDelete, AfterDays: 30}
+
+		report, err := service.run(rule, false)
+		require.NoError(t, err)
+		assert.Equal(t, constants.LifecycleRuleBatchSize, report.Failed)
+		require.Len(t, rules.saved, 1)
+		assert.True(t, rules.saved[0].CursorFileUuid.Valid)
+
+		report, err = service.run(rules.saved[0], false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Applied)
+		assert.Equal(t, 0, report.Failed)
+		assert.Len(t, store.files, constants.LifecycleRuleBatchSize)
+
+		require.Len(t, rules.saved, 2)
+		assert.False(t, rules.saved[1].CursorFileUuid.Valid, "reaching the end starts the next run over")
+		assert.Nil(t, rules.saved[1].CursorUpdatedAt)
+	})
+
+	t.Run("dry run leaves the cursor alone", func(t *testing.T) {
+		store := &fileStore{files: []file.File{{Uuid: uuid.New(), UpdatedAt: time.Now().AddDate(-1, 0, 0)}}}
+		rules := &ruleStore{}
+		service := &ServiceImpl{ruleRepo: rules, fileRepo: store}
+		rule := Rule{Uuid: uuid.New(), Action: constants.LifecycleActionDelete, AfterDays: 30}
+
+		report, err := service.run(rule, true)
+		require.NoError(t, err)
+		assert.Len(t, report.Items, 1)
+		assert.Empty(t, rules.saved)
+	})
+}
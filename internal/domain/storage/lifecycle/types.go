@@ -0,0 +1,34 @@
+package lifecycle
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateRuleInput struct {
+	Name                string
+	Prefix              string
+	Action              string
+	AfterDays           int
+	TargetContainerUUID uuid.NullUUID
+	IsEnabled           bool
+}
+
+// Report lists the files a rule matched in one run, a dry run only matches them
+type Report struct {
+	RuleUuid uuid.UUID
+	RuleName string
+	Action   string
+	DryRun   bool
+	Applied  int
+	Failed   int
+	Items    []ReportItem
+}
+
+type ReportItem struct {
+	FileUuid     uuid.UUID
+	FullFileName string
+	Size         int // in KB
+	UpdatedAt    time.Time
+	Error        string
+}
@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Migration struct {
+	Uuid          uuid.UUID     `db:"uuid" json:"uuid"`
+	SourceDriver  string        `db:"source_driver" json:"sourceDriver"`
+	TargetDriver  string        `db:"target_driver" json:"targetDriver"`
+	Status        string        `db:"status" json:"status"`
+	Error         string        `db:"error" json:"error"`
+	TotalObjects  int           `db:"total_objects" json:"totalObjects"`
+	CopiedObjects int           `db:"copied_objects" json:"copiedObjects"`
+	CreatedBy     uuid.NullUUID `db:"created_by" json:"createdBy"` // empty when started from the CLI
+	StartedAt     time.Time     `db:"started_at" json:"startedAt"`
+	CompletedAt   *time.Time    `db:"completed_at" json:"completedAt"`
+}
+
+// Item records an object copied and verified on the target, so an interrupted migration can skip it
+type Item struct {
+	MigrationUuid uuid.UUID `db:"migration_uuid" json:"migrationUuid"`
+	ContainerName string    `db:"container_name" json:"containerName"`
+	ObjectKey     string    `db:"object_key" json:"objectKey"`
+	Checksum      string    `db:"checksum" json:"checksum"` // hex encoded SHA-256
+	CopiedAt      time.Time `db:"copied_at" json:"copiedAt"`
+}
+
+// Container records a container created on the target, its URL is only known at creation
+type Container struct {
+	MigrationUuid uuid.UUID `db:"migration_uuid" json:"migrationUuid"`
+	ContainerName string    `db:"container_name" json:"containerName"`
+	TargetUrl     string    `db:"target_url" json:"targetUrl"`
+}
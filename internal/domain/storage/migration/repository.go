@@ -0,0 +1,19 @@
+package migration
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	List() ([]Migration, error)
+	GetByUUID(migrationUUID uuid.UUID) (Migration, error)
+	GetUnfinished(sourceDriver, targetDriver string) (Migration, error)
+	Create(migration *Migration) (*Migration, error)
+	UpdateStatus(migrationUUID uuid.UUID, status, error string, completedAt *time.Time) error
+	UpdateProgress(migrationUUID uuid.UUID, totalObjects, copiedObjects int) error
+	ListItems(migrationUUID uuid.UUID) ([]Item, error)
+	CreateItem(item *Item) error
+	GetContainer(migrationUUID uuid.UUID, containerName string) (Container, error)
+	CreateContainer(container *Container) error
+}
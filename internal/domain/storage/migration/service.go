@@ -0,0 +1,537 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/backup"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/setting"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errSourceUnreadable wraps failures to download an object from the source, which is then usually gone
+var errSourceUnreadable = stdErrors.New("unable to download")
+
+type Service interface {
+	List(authUser auth.User) ([]Migration, error)
+	GetByUUID(migrationUUID uuid.UUID, authUser auth.User) (Migration, error)
+	Create(request *CreateMigrationInput, authUser auth.User) (Migration, error)
+	Prepare(request *CreateMigrationInput, createdBy uuid.NullUUID) (Migration, error)
+	Run(migrationUUID uuid.UUID) error
+}
+
+type ServiceImpl struct {
+	settingService setting.Service
+	adminPolicy    *admin.Policy
+	migrationRepo  Repository
+	containerRepo  container.Repository
+	backupRepo     backup.Repository
+	projectRepo    project.Repository
+	storageFactory *storage.Factory
+
+	// Only one migration runs at a time, two would race on the same containers
+	running sync.Mutex
+}
+
+// objectSet lists the objects of one container on the source, along with what to update once they're copied
+type objectSet struct {
+	containerName string
+	objectKeys    []string
+	container     *container.Container
+	backups       []backup.Backup
+}
+
+func NewStorageMigrationService(injector *do.Injector) (Service, error) {
+	settingService, err := setting.NewSettingService(injector)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationRepo := do.MustInvoke[Repository](injector)
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	backupRepo := do.MustInvoke[backup.Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	storageFactory := do.MustInvoke[*storage.Factory](injector)
+
+	return &ServiceImpl{
+		settingService: settingService,
+		adminPolicy:    admin.NewAdminPolicy(),
+		migrationRepo:  migrationRepo,
+		containerRepo:  containerRepo,
+		backupRepo:     backupRepo,
+		projectRepo:    projectRepo,
+		storageFactory: storageFactory,
+	}, nil
+}
+
+func (s *ServiceImpl) List(authUser auth.User) ([]Migration, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return nil, errors.NewForbiddenError("migration.error.listForbidden")
+	}
+
+	return s.migrationRepo.List()
+}
+
+func (s *ServiceImpl) GetByUUID(migrationUUID uuid.UUID, authUser auth.User) (Migration, error) {
+	if !s.adminPolicy.CanAccess(authUser) {
+		return Migration{}, errors.NewForbiddenError("migration.error.viewForbidden")
+	}
+
+	return s.migrationRepo.GetByUUID(migrationUUID)
+}
+
+// Create starts a migration in the background, an unfinished migration between the same drivers is resumed instead
+func (s *ServiceImpl) Create(request *CreateMigrationInput, authUser auth.User) (Migration, error) {
+	if !s.adminPolicy.CanCreate(authUser) {
+		return Migration{}, errors.NewForbiddenError("migration.error.createForbidden")
+	}
+
+	if !s.running.TryLock() {
+		return Migration{}, errors.NewUnprocessableError("migration.error.alreadyRunning")
+	}
+
+	preparedMigration, err := s.Prepare(request, uuid.NullUUID{UUID: authUser.Uuid, Valid: true})
+	if err != nil {
+		s.running.Unlock()
+
+		return Migration{}, err
+	}
+
+	go func() {
+		defer s.running.Unlock()
+
+		// Failures are recorded on the migration itself
+		_ = s.run(preparedMigration.Uuid)
+	}()
+
+	return preparedMigration, nil
+}
+
+func (s *ServiceImpl) Prepare(request *CreateMigrationInput, createdBy uuid.NullUUID) (Migration, error) {
+	if !isStorageDriver(request.SourceDriver) || !isStorageDriver(request.TargetDriver) {
+		return Migration{}, errors.NewUnprocessableError("migration.error.invalidDriver")
+	}
+
+	if request.SourceDriver == request.TargetDriver {
+		return Migration{}, errors.NewUnprocessableError("migration.error.sameDriver")
+	}
+
+	unfinishedMigration, err := s.migrationRepo.GetUnfinished(request.SourceDriver, request.TargetDriver)
+
+	var notFoundErr *errors.NotFoundError
+	switch {
+	case err == nil:
+		err = s.migrationRepo.UpdateStatus(unfinishedMigration.Uuid, constants.StorageMigrationStatusRunning, "", nil)
+		unfinishedMigration.Status = constants.StorageMigrationStatusRunning
+
+		return unfinishedMigration, err
+	case !stdErrors.As(err, &notFoundErr):
+		return Migration{}, err
+	}
+
+	migrationInput := Migration{
+		SourceDriver: request.SourceDriver,
+		TargetDriver: request.TargetDriver,
+		Status:       constants.StorageMigrationStatusRunning,
+		CreatedBy:    createdBy,
+		StartedAt:    time.Now(),
+	}
+
+	if _, err = s.migrationRepo.Create(&migrationInput); err != nil {
+		return Migration{}, err
+	}
+
+	return migrationInput, nil
+}
+
+// Run copies every object of the migration and blocks until it's done
+func (s *ServiceImpl) Run(migrationUUID uuid.UUID) error {
+	if !s.running.TryLock() {
+		return errors.NewUnprocessableError("migration.error.alreadyRunning")
+	}
+	defer s.running.Unlock()
+
+	return s.run(migrationUUID)
+}
+
+func (s *ServiceImpl) run(migrationUUID uuid.UUID) error {
+	fetchedMigration, err := s.migrationRepo.GetByUUID(migrationUUID)
+	if err != nil {
+		return err
+	}
+
+	if fetchedMigration.Status == constants.StorageMigrationStatusCompleted {
+		return nil
+	}
+
+	if err = s.copyAll(fetchedMigration); err != nil {
+		log.Error().
+			Str("action", constants.ActionStorageMigration).
+			Str("migration_uuid", migrationUUID.String()).
+			Str("error", err.Error()).
+			Msg("storage migration failed, it can be resumed")
+
+		if updateErr := s.migrationRepo.UpdateStatus(migrationUUID, constants.StorageMigrationStatusFailed, err.Error(), nil); updateErr != nil {
+			return updateErr
+		}
+
+		return err
+	}
+
+	completedAt := time.Now()
+
+	return s.migrationRepo.UpdateStatus(migrationUUID, constants.StorageMigrationStatusCompleted, "", &completedAt)
+}
+
+func (s *ServiceImpl) copyAll(fetchedMigration Migration) error {
+	source, err := s.storageFactory.CreateProvider(fetchedMigration.SourceDriver)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.storageFactory.CreateProvider(fetchedMigration.TargetDriver)
+	if err != nil {
+		return err
+	}
+
+	objectSets, err := s.listObjectSets(fetchedMigration)
+	if err != nil {
+		return err
+	}
+
+	copiedItems, err := s.migrationRepo.ListItems(fetchedMigration.Uuid)
+	if err != nil {
+		return err
+	}
+
+	copied := make(map[string]bool, len(copiedItems))
+	for _, item := range copiedItems {
+		copied[itemKey(item.ContainerName, item.ObjectKey)] = true
+	}
+
+	// Containers finished in an earlier run aren't listed anymore, their items still count towards the total
+	totalObjects := len(copiedItems)
+	for _, objectSet := range objectSets {
+		for _, objectKey := range objectSet.objectKeys {
+			if !copied[itemKey(objectSet.containerName, objectKey)] {
+				totalObjects++
+			}
+		}
+	}
+
+	copiedObjects := len(copiedItems)
+	if err = s.migrationRepo.UpdateProgress(fetchedMigration.Uuid, totalObjects, copiedObjects); err != nil {
+		return err
+	}
+
+	// Cached objects that are gone from the source are skipped rather than failing the migration
+	skipped := map[string]bool{}
+
+	for _, objectSet := range objectSets {
+		targetURL, err := s.ensureTargetContainer(fetchedMigration, target, objectSet.containerName)
+		if err != nil {
+			return err
+		}
+
+		// Objects written while the container is copied are only seen by listing it again, so it's only
+		// switched to the target once a pass finds nothing left to copy
+		for pass := 0; ; pass++ {
+			var pendingKeys []string
+			for _, objectKey := range objectSet.objectKeys {
+				key := itemKey(objectSet.containerName, objectKey)
+				if !copied[key] && !skipped[key] {
+					pendingKeys = append(pendingKeys, objectKey)
+				}
+			}
+
+			if len(pendingKeys) == 0 {
+				break
+			}
+
+			if pass == constants.StorageMigrationMaxPasses {
+				return fmt.Errorf("container %s kept changing while it was copied", objectSet.containerName)
+			}
+
+			// Keys of the first pass are already counted
+			if pass > 0 {
+				totalObjects += len(pendingKeys)
+			}
+
+			for _, objectKey := range pendingKeys {
+				key := itemKey(objectSet.containerName, objectKey)
+
+				checksum, err := s.copyObject(source, target, objectSet.containerName, objectKey)
+				switch {
+				case stdErrors.Is(err, errSourceUnreadable) && isCachedObject(objectKey):
+					log.Warn().
+						Str("action", constants.ActionStorageMigration).
+						Str("migration_uuid", fetchedMigration.Uuid.String()).
+						Str("container_name", objectSet.containerName).
+						Str("object_key", objectKey).
+						Str("error", err.Error()).
+						Msg("cached object missing on the source, skipped")
+
+					skipped[key] = true
+				case err != nil:
+					return err
+				default:
+					err = s.migrationRepo.CreateItem(&Item{
+						MigrationUuid: fetchedMigration.Uuid,
+						ContainerName: objectSet.containerName,
+						ObjectKey:     objectKey,
+						Checksum:      checksum,
+						CopiedAt:      time.Now(),
+					})
+					if err != nil {
+						return err
+					}
+
+					copied[key] = true
+				}
+
+				copiedObjects++
+				if err = s.migrationRepo.UpdateProgress(fetchedMigration.Uuid, totalObjects, copiedObjects); err != nil {
+					return err
+				}
+			}
+
+			objectSet, err = s.relist(fetchedMigration, objectSet)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = s.switchToTarget(fetchedMigration, objectSet, targetURL); err != nil {
+			return err
+		}
+
+		log.Info().
+			Str("action", constants.ActionStorageMigration).
+			Str("migration_uuid", fetchedMigration.Uuid.String()).
+			Str("container_name", objectSet.containerName).
+			Int("copied_objects", copiedObjects).
+			Int("total_objects", totalObjects).
+			Msg("container migrated")
+	}
+
+	return nil
+}
+
+// listObjectSets returns the objects still stored on the source, grouped by container
+func (s *ServiceImpl) listObjectSets(fetchedMigration Migration) ([]objectSet, error) {
+	containers, err := s.containerRepo.ListForProvider(fetchedMigration.SourceDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	var objectSets []objectSet
+	for _, currentContainer := range containers {
+		objectKeys, err := s.containerRepo.ListObjectKeys(currentContainer.Uuid)
+		if err != nil {
+			return nil, err
+		}
+
+		objectSets = append(objectSets, objectSet{
+			containerName: currentContainer.NameKey,
+			objectKeys:    objectKeys,
+			container:     &currentContainer,
+		})
+	}
+
+	backupSet, err := s.listBackupSet(fetchedMigration)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backupSet.backups) > 0 {
+		objectSets = append(objectSets, backupSet)
+	}
+
+	return objectSets, nil
+}
+
+// listBackupSet returns the dumps of backups still stored on the source
+func (s *ServiceImpl) listBackupSet(fetchedMigration Migration) (objectSet, error) {
+	// Backups taken before drivers were recorded were stored with the driver configured at the time
+	includeUnrecorded := s.settingService.GetStorageDriver() == fetchedMigration.SourceDriver
+
+	backups, err := s.backupRepo.ListForStorageDriver(fetchedMigration.SourceDriver, includeUnrecorded)
+	if err != nil {
+		return objectSet{}, err
+	}
+
+	backupSet := objectSet{containerName: constants.BackupContainerName}
+	for _, currentBackup := range backups {
+		if !hasBackupObject(currentBackup) {
+			continue
+		}
+
+		databaseName, err := s.projectRepo.GetDatabaseNameByUUID(currentBackup.ProjectUuid)
+		if err != nil {
+			return objectSet{}, err
+		}
+
+		backupSet.objectKeys = append(backupSet.objectKeys, fmt.Sprintf("%s/%s.sql", databaseName, currentBackup.Uuid))
+		backupSet.backups = append(backupSet.backups, currentBackup)
+	}
+
+	return backupSet, nil
+}
+
+// relist lists the objects of a set again, picking up the ones written since it was last listed
+func (s *ServiceImpl) relist(fetchedMigration Migration, currentSet objectSet) (objectSet, error) {
+	if currentSet.container == nil {
+		return s.listBackupSet(fetchedMigration)
+	}
+
+	objectKeys, err := s.containerRepo.ListObjectKeys(currentSet.container.Uuid)
+	if err != nil {
+		return objectSet{}, err
+	}
+
+	currentSet.objectKeys = objectKeys
+
+	return currentSet, nil
+}
+
+func (s *ServiceImpl) ensureTargetContainer(fetchedMigration Migration, target storage.Provider, containerName string) (string, error) {
+	migratedContainer, err := s.migrationRepo.GetContainer(fetchedMigration.Uuid, containerName)
+	if err == nil {
+		return migratedContainer.TargetUrl, nil
+	}
+
+	var notFoundErr *errors.NotFoundError
+	if !stdErrors.As(err, &notFoundErr) {
+		return "", err
+	}
+
+	// A container created outside the migration is reused, its URL isn't known then
+	targetURL := ""
+	if !target.ContainerExists(containerName) {
+		targetURL, err = target.CreateContainer(containerName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = s.migrationRepo.CreateContainer(&Container{
+		MigrationUuid: fetchedMigration.Uuid,
+		ContainerName: containerName,
+		TargetUrl:     targetURL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return targetURL, nil
+}
+
+// switchToTarget points the records of a fully copied container at the target, the source objects are left in place
+func (s *ServiceImpl) switchToTarget(fetchedMigration Migration, objectSet objectSet, targetURL string) error {
+	if objectSet.container != nil {
+		if targetURL == "" {
+			targetURL = objectSet.container.Url
+		}
+
+		return s.containerRepo.UpdateProvider(objectSet.container.Uuid, fetchedMigration.TargetDriver, targetURL)
+	}
+
+	for _, currentBackup := range objectSet.backups {
+		if err := s.backupRepo.UpdateStorageDriver(currentBackup.Uuid, fetchedMigration.TargetDriver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyObject streams an object from source to target and reads it back, so the copy is only
+// recorded when the target returns the same bytes
+func (s *ServiceImpl) copyObject(source, target storage.Provider, containerName, objectKey string) (string, error) {
+	sourceObject, err := source.DownloadFile(storage.DownloadFileInput{ContainerName: containerName, FileName: objectKey})
+	if err != nil {
+		return "", fmt.Errorf("%w %s/%s: %w", errSourceUnreadable, containerName, objectKey, err)
+	}
+	defer sourceObject.Body.Close()
+
+	body, contentLength, cleanup, err := storage.SizedBody(sourceObject)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	sourceHash := sha256.New()
+	err = target.UploadFile(storage.UploadFileInput{
+		ContainerName: containerName,
+		FileName:      objectKey,
+		Body:          io.TeeReader(body, sourceHash),
+		ContentLength: contentLength,
+		ContentType:   sourceObject.ContentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to upload %s/%s: %w", containerName, objectKey, err)
+	}
+
+	checksum := hex.EncodeToString(sourceHash.Sum(nil))
+
+	targetObject, err := target.DownloadFile(storage.DownloadFileInput{ContainerName: containerName, FileName: objectKey})
+	if err != nil {
+		return "", fmt.Errorf("unable to verify %s/%s: %w", containerName, objectKey, err)
+	}
+	defer targetObject.Body.Close()
+
+	targetHash := sha256.New()
+	if _, err = io.Copy(targetHash, targetObject.Body); err != nil {
+		return "", fmt.Errorf("unable to verify %s/%s: %w", containerName, objectKey, err)
+	}
+
+	if hex.EncodeToString(targetHash.Sum(nil)) != checksum {
+		return "", fmt.Errorf("checksum mismatch for %s/%s", containerName, objectKey)
+	}
+
+	return checksum, nil
+}
+
+// hasBackupObject tells whether the dump of a backup was uploaded and not deleted since
+func hasBackupObject(fetchedBackup backup.Backup) bool {
+	switch fetchedBackup.Status {
+	case constants.BackupStatusCreating, constants.BackupStatusCreatingFailed, constants.BackupStatusDeleting:
+		return false
+	default:
+		return true
+	}
+}
+
+// isCachedObject tells whether an object can be done without: image variants are rendered again, and an
+// upload missing a chunk fails to complete and is dropped with its quota once it expires after
+// constants.UploadExpiration. See file.variantObjectPrefix and upload.chunkObjectPrefix
+func isCachedObject(objectKey string) bool {
+	return strings.HasPrefix(objectKey, ".variants/") || strings.HasPrefix(objectKey, ".uploads/")
+}
+
+func isStorageDriver(driver string) bool {
+	switch driver {
+	case constants.StorageDriverFilesystem, constants.StorageDriverS3, constants.StorageDriverBackBlaze, constants.StorageDriverDropbox:
+		return true
+	default:
+		return false
+	}
+}
+
+func itemKey(containerName, objectKey string) string {
+	return containerName + "/" + objectKey
+}
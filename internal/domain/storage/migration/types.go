@@ -0,0 +1,6 @@
+package migration
+
+type CreateMigrationInput struct {
+	SourceDriver string
+	TargetDriver string
+}
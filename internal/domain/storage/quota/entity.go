@@ -0,0 +1,25 @@
+package quota
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ProjectQuota struct {
+	ProjectUuid uuid.UUID `db:"project_uuid"`
+	MaxSize     int       `db:"max_size"` // in KB
+	MaxFiles    int       `db:"max_files"`
+	UsedBytes   int64     `db:"used_bytes"`
+	UsedFiles   int       `db:"used_files"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// OrganizationQuota carries the summed usage of the projects of an organization next to its own limits
+type OrganizationQuota struct {
+	OrganizationUuid uuid.UUID `db:"organization_uuid"`
+	MaxSize          int       `db:"max_size"` // in KB
+	MaxFiles         int       `db:"max_files"`
+	UsedBytes        int64     `db:"used_bytes"`
+	UsedFiles        int       `db:"used_files"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
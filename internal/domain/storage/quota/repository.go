@@ -0,0 +1,16 @@
+package quota
+
+import (
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	GetForProject(projectUUID uuid.UUID) (ProjectQuota, error)
+	GetForOrganization(organizationUUID uuid.UUID) (OrganizationQuota, error)
+	UpdateForProject(quota *ProjectQuota) error
+	UpdateForOrganization(quota *OrganizationQuota) error
+	Reserve(organizationUsage, projectUsage Usage, bytes int64, files int) error
+	Add(projectUUID uuid.UUID, bytes int64, files int) error
+	Recalculate(projectUUID uuid.UUID) error
+	RecalculateAll() (int64, error)
+}
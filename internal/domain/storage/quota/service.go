@@ -0,0 +1,218 @@
+package quota
+
+import (
+	"fluxend/internal/domain/admin"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/setting"
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	GetProjectUsage(projectUUID uuid.UUID, authUser auth.User) (Usage, error)
+	GetOrganizationUsage(organizationUUID uuid.UUID, authUser auth.User) (Usage, error)
+	UpdateProjectQuota(projectUUID uuid.UUID, request *UpdateQuotaInput, authUser auth.User) (Usage, error)
+	UpdateOrganizationQuota(organizationUUID uuid.UUID, request *UpdateQuotaInput, authUser auth.User) (Usage, error)
+	Check(projectUUID uuid.UUID, bytes int64, files int) error
+	Reserve(projectUUID uuid.UUID, bytes int64, files int) error
+	Release(projectUUID uuid.UUID, bytes int64, files int) error
+	Recalculate(projectUUID uuid.UUID) error
+	RecalculateAll() (int64, error)
+}
+
+type ServiceImpl struct {
+	settingService     setting.Service
+	adminPolicy        *admin.Policy
+	projectPolicy      *project.Policy
+	organizationPolicy *organization.Policy
+	quotaRepo          Repository
+	projectRepo        project.Repository
+}
+
+func NewStorageQuotaService(injector *do.Injector) (Service, error) {
+	settingService := do.MustInvoke[setting.Service](injector)
+	projectPolicy := do.MustInvoke[*project.Policy](injector)
+	organizationPolicy := do.MustInvoke[*organization.Policy](injector)
+	quotaRepo := do.MustInvoke[Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+
+	return &ServiceImpl{
+		settingService:     settingService,
+		adminPolicy:        admin.NewAdminPolicy(),
+		projectPolicy:      projectPolicy,
+		organizationPolicy: organizationPolicy,
+		quotaRepo:          quotaRepo,
+		projectRepo:        projectRepo,
+	}, nil
+}
+
+func (s *ServiceImpl) GetProjectUsage(projectUUID uuid.UUID, authUser auth.User) (Usage, error) {
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(projectUUID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	if !s.adminPolicy.CanAccess(authUser) && !s.projectPolicy.CanAccess(organizationUUID, authUser) {
+		return Usage{}, errors.NewForbiddenError("quota.error.viewForbidden")
+	}
+
+	return s.projectUsage(projectUUID)
+}
+
+func (s *ServiceImpl) GetOrganizationUsage(organizationUUID uuid.UUID, authUser auth.User) (Usage, error) {
+	if !s.adminPolicy.CanAccess(authUser) && !s.organizationPolicy.CanAccess(organizationUUID, authUser) {
+		return Usage{}, errors.NewForbiddenError("quota.error.viewForbidden")
+	}
+
+	return s.organizationUsage(organizationUUID)
+}
+
+func (s *ServiceImpl) UpdateProjectQuota(projectUUID uuid.UUID, request *UpdateQuotaInput, authUser auth.User) (Usage, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return Usage{}, errors.NewForbiddenError("quota.error.updateForbidden")
+	}
+
+	projectQuota, err := s.quotaRepo.GetForProject(projectUUID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	projectQuota.MaxSize = request.MaxSize
+	projectQuota.MaxFiles = request.MaxFiles
+	projectQuota.UpdatedAt = time.Now()
+
+	if err = s.quotaRepo.UpdateForProject(&projectQuota); err != nil {
+		return Usage{}, err
+	}
+
+	return s.projectUsage(projectUUID)
+}
+
+func (s *ServiceImpl) UpdateOrganizationQuota(organizationUUID uuid.UUID, request *UpdateQuotaInput, authUser auth.User) (Usage, error) {
+	if !s.adminPolicy.CanUpdate(authUser) {
+		return Usage{}, errors.NewForbiddenError("quota.error.updateForbidden")
+	}
+
+	organizationQuota, err := s.quotaRepo.GetForOrganization(organizationUUID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	organizationQuota.MaxSize = request.MaxSize
+	organizationQuota.MaxFiles = request.MaxFiles
+	organizationQuota.UpdatedAt = time.Now()
+
+	if err = s.quotaRepo.UpdateForOrganization(&organizationQuota); err != nil {
+		return Usage{}, err
+	}
+
+	return s.organizationUsage(organizationUUID)
+}
+
+// Check tells whether bytes and files still fit in a project and its organization without taking
+// the space, uploads spread over several requests use it to fail before any content is sent
+func (s *ServiceImpl) Check(projectUUID uuid.UUID, bytes int64, files int) error {
+	organizationUsage, projectUsage, err := s.usages(projectUUID)
+	if err != nil {
+		return err
+	}
+
+	if err = organizationUsage.Check(bytes, files); err != nil {
+		return err
+	}
+
+	return projectUsage.Check(bytes, files)
+}
+
+// Reserve takes bytes and files from the quotas of a project and its organization before content is
+// stored, it has to be released when storing fails
+func (s *ServiceImpl) Reserve(projectUUID uuid.UUID, bytes int64, files int) error {
+	organizationUsage, projectUsage, err := s.usages(projectUUID)
+	if err != nil {
+		return err
+	}
+
+	return s.quotaRepo.Reserve(organizationUsage, projectUsage, bytes, files)
+}
+
+func (s *ServiceImpl) Release(projectUUID uuid.UUID, bytes int64, files int) error {
+	return s.quotaRepo.Add(projectUUID, -bytes, -files)
+}
+
+func (s *ServiceImpl) Recalculate(projectUUID uuid.UUID) error {
+	return s.quotaRepo.Recalculate(projectUUID)
+}
+
+func (s *ServiceImpl) RecalculateAll() (int64, error) {
+	return s.quotaRepo.RecalculateAll()
+}
+
+func (s *ServiceImpl) usages(projectUUID uuid.UUID) (Usage, Usage, error) {
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(projectUUID)
+	if err != nil {
+		return Usage{}, Usage{}, err
+	}
+
+	organizationUsage, err := s.organizationUsage(organizationUUID)
+	if err != nil {
+		return Usage{}, Usage{}, err
+	}
+
+	projectUsage, err := s.projectUsage(projectUUID)
+	if err != nil {
+		return Usage{}, Usage{}, err
+	}
+
+	return organizationUsage, projectUsage, nil
+}
+
+func (s *ServiceImpl) projectUsage(projectUUID uuid.UUID) (Usage, error) {
+	projectQuota, err := s.quotaRepo.GetForProject(projectUUID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		Scope:     ScopeProject,
+		OwnerUuid: projectUUID,
+		UsedBytes: projectQuota.UsedBytes,
+		UsedFiles: projectQuota.UsedFiles,
+		MaxSize:   s.limit(projectQuota.MaxSize, "storageProjectMaxSizeInKB"),
+		MaxFiles:  s.limit(projectQuota.MaxFiles, "storageProjectMaxFiles"),
+	}, nil
+}
+
+func (s *ServiceImpl) organizationUsage(organizationUUID uuid.UUID) (Usage, error) {
+	organizationQuota, err := s.quotaRepo.GetForOrganization(organizationUUID)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		Scope:     ScopeOrganization,
+		OwnerUuid: organizationUUID,
+		UsedBytes: organizationQuota.UsedBytes,
+		UsedFiles: organizationQuota.UsedFiles,
+		MaxSize:   s.limit(organizationQuota.MaxSize, "storageOrganizationMaxSizeInKB"),
+		MaxFiles:  s.limit(organizationQuota.MaxFiles, "storageOrganizationMaxFiles"),
+	}, nil
+}
+
+// limit picks the limit set on a project or organization, or the instance wide setting when it has none
+func (s *ServiceImpl) limit(value int, settingName string) int {
+	if value > 0 {
+		return value
+	}
+
+	defaultValue, err := strconv.Atoi(s.settingService.GetValue(settingName))
+	if err != nil {
+		return 0
+	}
+
+	return defaultValue
+}
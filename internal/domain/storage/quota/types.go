@@ -0,0 +1,47 @@
+package quota
+
+import (
+	"fluxend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeProject      = "project"
+	ScopeOrganization = "organization"
+)
+
+type UpdateQuotaInput struct {
+	MaxSize  int
+	MaxFiles int
+}
+
+// Usage is what a project or organization takes up next to the limits that apply to it, 0 means unlimited
+type Usage struct {
+	Scope     string
+	OwnerUuid uuid.UUID
+	UsedBytes int64
+	UsedFiles int
+	MaxSize   int // in KB
+	MaxFiles  int
+}
+
+// Check fails when adding bytes and files would go over a limit, releasing space is always allowed
+func (u Usage) Check(bytes int64, files int) error {
+	if bytes > 0 && u.MaxSize > 0 && u.UsedBytes+bytes > int64(u.MaxSize)*1024 {
+		if u.Scope == ScopeOrganization {
+			return errors.NewUnprocessableError("quota.error.organizationSizeExceeded")
+		}
+
+		return errors.NewUnprocessableError("quota.error.projectSizeExceeded")
+	}
+
+	if files > 0 && u.MaxFiles > 0 && u.UsedFiles+files > u.MaxFiles {
+		if u.Scope == ScopeOrganization {
+			return errors.NewUnprocessableError("quota.error.organizationFilesExceeded")
+		}
+
+		return errors.NewUnprocessableError("quota.error.projectFilesExceeded")
+	}
+
+	return nil
+}
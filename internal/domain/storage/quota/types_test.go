@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUsage_Check_Suite(t *testing.T) {
+	t.Run("Check: unlimited", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedBytes: 1 << 40, UsedFiles: 1 << 20}
+
+		assert.NoError(t, usage.Check(1024, 1))
+	})
+
+	t.Run("Check: fits exactly", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedBytes: 900 * 1024, UsedFiles: 9, MaxSize: 1000, MaxFiles: 10}
+
+		assert.NoError(t, usage.Check(100*1024, 1))
+	})
+
+	t.Run("Check: project size exceeded", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedBytes: 900 * 1024, MaxSize: 1000}
+
+		assert.EqualError(t, usage.Check(100*1024+1, 1), "quota.error.projectSizeExceeded")
+	})
+
+	t.Run("Check: files smaller than a KB count", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedBytes: 1000 * 1024, MaxSize: 1000}
+
+		assert.EqualError(t, usage.Check(1, 1), "quota.error.projectSizeExceeded")
+	})
+
+	t.Run("Check: organization files exceeded", func(t *testing.T) {
+		usage := Usage{Scope: ScopeOrganization, UsedFiles: 10, MaxFiles: 10}
+
+		assert.EqualError(t, usage.Check(1, 1), "quota.error.organizationFilesExceeded")
+	})
+
+	t.Run("Check: new version doesn't count as a file", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedFiles: 10, MaxFiles: 10}
+
+		assert.NoError(t, usage.Check(1, 0))
+	})
+
+	t.Run("Check: releasing is always allowed", func(t *testing.T) {
+		usage := Usage{Scope: ScopeProject, UsedBytes: 2000 * 1024, UsedFiles: 20, MaxSize: 1000, MaxFiles: 10}
+
+		assert.NoError(t, usage.Check(-100*1024, -1))
+	})
+}
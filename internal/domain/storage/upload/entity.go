@@ -0,0 +1,40 @@
+package upload
+
+import (
+	"fluxend/internal/domain/shared"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Upload is a file being uploaded in several requests, it becomes a file.File once all bytes are received
+type Upload struct {
+	shared.BaseEntity
+	Uuid          uuid.UUID `db:"uuid" json:"uuid"`
+	ContainerUuid uuid.UUID `db:"container_uuid" json:"containerUuid"`
+	FullFileName  string    `db:"full_file_name" json:"fullFileName"`
+	MimeType      string    `db:"mime_type" json:"mimeType"`
+	UploadLength  int64     `db:"upload_length" json:"uploadLength"` // in bytes
+	UploadOffset  int64     `db:"upload_offset" json:"uploadOffset"` // in bytes
+	ExpiresAt     time.Time `db:"expires_at" json:"expiresAt"`       // moved forward with every chunk
+	CreatedBy     uuid.UUID `db:"created_by" json:"createdBy"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Chunk is the part of an upload received in a single request, stored as its own object until the upload completes
+type Chunk struct {
+	shared.BaseEntity
+	UploadUuid  uuid.UUID `db:"upload_uuid" json:"uploadUuid"`
+	ChunkOffset int64     `db:"chunk_offset" json:"chunkOffset"`
+	Size        int64     `db:"size" json:"size"` // in bytes
+	ObjectKey   string    `db:"object_key" json:"objectKey"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (u Upload) IsComplete() bool {
+	return u.UploadOffset == u.UploadLength
+}
+
+func (u Upload) IsExpired() bool {
+	return !u.ExpiresAt.After(time.Now())
+}
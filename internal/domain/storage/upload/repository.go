@@ -0,0 +1,15 @@
+package upload
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	GetByUUID(uploadUUID uuid.UUID) (Upload, error)
+	ListChunks(uploadUUID uuid.UUID) ([]Chunk, error)
+	ListExpired(before time.Time, limit int) ([]Upload, error)
+	Create(upload *Upload) (*Upload, error)
+	AppendChunk(chunk *Chunk, expiresAt time.Time) (bool, error)
+	Delete(uploadUUID uuid.UUID) (bool, error)
+}
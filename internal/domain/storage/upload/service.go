@@ -0,0 +1,497 @@
+package upload
+
+import (
+	"context"
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/internal/domain/storage/quota"
+	"fluxend/pkg/errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/do"
+	"io"
+	"os"
+	"time"
+)
+
+// Chunks are kept in the container they will end up in, under a prefix files can't be listed from
+const chunkObjectPrefix = ".uploads"
+
+// ErrOffsetConflict is returned when a chunk doesn't start where the upload currently ends
+var ErrOffsetConflict = errors.NewBadRequestError("upload.error.offsetConflict")
+
+type Service interface {
+	GetByUUID(uploadUUID, containerUUID uuid.UUID, authUser auth.User) (Upload, error)
+	Create(containerUUID uuid.UUID, request *CreateUploadInput, authUser auth.User) (Upload, error)
+	AppendChunk(uploadUUID, containerUUID uuid.UUID, request *AppendChunkInput, authUser auth.User) (Upload, *file.File, error)
+	Delete(uploadUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error)
+	PurgeExpired() (int, error)
+	Work(ctx context.Context)
+}
+
+type ServiceImpl struct {
+	fileService       file.Service
+	projectPolicy     *project.Policy
+	containerRepo     container.Repository
+	uploadRepo        Repository
+	projectRepo       project.Repository
+	credentialService credential.Service
+	quotaService      quota.Service
+}
+
+func NewUploadService(injector *do.Injector) (Service, error) {
+	fileService := do.MustInvoke[file.Service](injector)
+	policy := do.MustInvoke[*project.Policy](injector)
+	containerRepo := do.MustInvoke[container.Repository](injector)
+	uploadRepo := do.MustInvoke[Repository](injector)
+	projectRepo := do.MustInvoke[project.Repository](injector)
+	credentialService := do.MustInvoke[credential.Service](injector)
+	quotaService := do.MustInvoke[quota.Service](injector)
+
+	return &ServiceImpl{
+		fileService:       fileService,
+		projectPolicy:     policy,
+		containerRepo:     containerRepo,
+		uploadRepo:        uploadRepo,
+		projectRepo:       projectRepo,
+		credentialService: credentialService,
+		quotaService:      quotaService,
+	}, nil
+}
+
+func (s *ServiceImpl) GetByUUID(uploadUUID, containerUUID uuid.UUID, authUser auth.User) (Upload, error) {
+	_, fetchedUpload, err := s.fetchForUser(uploadUUID, containerUUID, authUser)
+
+	return fetchedUpload, err
+}
+
+func (s *ServiceImpl) Create(containerUUID uuid.UUID, request *CreateUploadInput, authUser auth.User) (Upload, error) {
+	// Reject files the container won't accept before the client starts sending them
+	err := s.fileService.Validate(containerUUID, &file.StoreFileInput{
+		FullFileName: request.FullFileName,
+		MimeType:     request.MimeType,
+		Size:         request.UploadLength,
+	}, authUser)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	// The declared length is held until the upload completes or expires, so chunks can't grow past the quota
+	if err = s.quotaService.Reserve(fetchedContainer.ProjectUuid, request.UploadLength, 0); err != nil {
+		return Upload{}, err
+	}
+
+	uploadInput := Upload{
+		ContainerUuid: containerUUID,
+		FullFileName:  request.FullFileName,
+		MimeType:      request.MimeType,
+		UploadLength:  request.UploadLength,
+		ExpiresAt:     time.Now().Add(constants.UploadExpiration),
+		CreatedBy:     authUser.Uuid,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	_, err = s.uploadRepo.Create(&uploadInput)
+	if err != nil {
+		s.releaseQuota(fetchedContainer.ProjectUuid, request.UploadLength)
+
+		return Upload{}, err
+	}
+
+	return uploadInput, nil
+}
+
+// AppendChunk stores the next part of an upload. Once all bytes are received the chunks are
+// assembled into a file, which is returned alongside the upload.
+func (s *ServiceImpl) AppendChunk(uploadUUID, containerUUID uuid.UUID, request *AppendChunkInput, authUser auth.User) (Upload, *file.File, error) {
+	fetchedContainer, fetchedUpload, err := s.fetchForUser(uploadUUID, containerUUID, authUser)
+	if err != nil {
+		return Upload{}, nil, err
+	}
+
+	if request.UploadOffset != fetchedUpload.UploadOffset {
+		return fetchedUpload, nil, ErrOffsetConflict
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return fetchedUpload, nil, err
+	}
+
+	chunkSize, readErr := s.storeChunk(storageService, fetchedContainer, &fetchedUpload, request.Body)
+	if readErr != nil && chunkSize == 0 {
+		return fetchedUpload, nil, readErr
+	}
+
+	if readErr != nil {
+		// The bytes received before the connection broke are kept, so the client can resume after them
+		return fetchedUpload, nil, fmt.Errorf("upload interrupted after %d bytes: %w", chunkSize, readErr)
+	}
+
+	if !fetchedUpload.IsComplete() {
+		return fetchedUpload, nil, nil
+	}
+
+	createdFile, err := s.complete(storageService, fetchedContainer, fetchedUpload, authUser)
+	if err != nil {
+		return fetchedUpload, nil, err
+	}
+
+	return fetchedUpload, &createdFile, nil
+}
+
+func (s *ServiceImpl) Delete(uploadUUID, containerUUID uuid.UUID, authUser auth.User) (bool, error) {
+	fetchedContainer, fetchedUpload, err := s.fetchForUser(uploadUUID, containerUUID, authUser)
+	if err != nil {
+		return false, err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return false, err
+	}
+
+	return s.discard(storageService, fetchedContainer, fetchedUpload)
+}
+
+// PurgeExpired drops a batch of expired uploads with their chunks and returns how many were dropped,
+// a failing upload doesn't stop the others
+func (s *ServiceImpl) PurgeExpired() (int, error) {
+	expiredUploads, err := s.uploadRepo.ListExpired(time.Now(), constants.UploadPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, expiredUpload := range expiredUploads {
+		if err := s.purge(expiredUpload); err != nil {
+			log.Error().
+				Str("action", constants.ActionUpload).
+				Str("upload_uuid", expiredUpload.Uuid.String()).
+				Str("error", err.Error()).
+				Msg("failed to purge expired upload")
+
+			continue
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// Work purges expired uploads every tick until the context is done, it's started by the API server
+func (s *ServiceImpl) Work(ctx context.Context) {
+	ticker := time.NewTicker(constants.UploadWorkerTick)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PurgeExpired(); err != nil {
+			log.Error().
+				Str("action", constants.ActionUpload).
+				Str("error", err.Error()).
+				Msg("failed to list expired uploads")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ServiceImpl) purge(expiredUpload Upload) error {
+	fetchedContainer, err := s.containerRepo.GetByUUID(expiredUpload.ContainerUuid)
+	if err != nil {
+		return err
+	}
+
+	storageService, err := s.credentialService.CreateProvider(fetchedContainer.Provider, fetchedContainer.CredentialUuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.discard(storageService, fetchedContainer, expiredUpload)
+
+	return err
+}
+
+// discard deletes an unfinished upload with its chunks and gives back the space it held. Only the
+// request that deletes the row releases the quota, so a concurrent purge doesn't release it twice
+func (s *ServiceImpl) discard(storageService storage.Provider, fetchedContainer container.Container, fetchedUpload Upload) (bool, error) {
+	if err := s.deleteChunks(storageService, fetchedContainer, fetchedUpload); err != nil {
+		return false, err
+	}
+
+	deleted, err := s.uploadRepo.Delete(fetchedUpload.Uuid)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+
+	return true, s.quotaService.Release(fetchedContainer.ProjectUuid, fetchedUpload.UploadLength, 0)
+}
+
+// releaseQuota gives back the space held by an upload. A failure leaves the usage too high until
+// it's recalculated, so it's only logged
+func (s *ServiceImpl) releaseQuota(projectUUID uuid.UUID, bytes int64) {
+	if err := s.quotaService.Release(projectUUID, bytes, 0); err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("project_uuid", projectUUID.String()).
+			Str("error", err.Error()).
+			Msg("failed to release storage quota")
+	}
+}
+
+func (s *ServiceImpl) fetchForUser(uploadUUID, containerUUID uuid.UUID, authUser auth.User) (container.Container, Upload, error) {
+	fetchedContainer, err := s.containerRepo.GetByUUID(containerUUID)
+	if err != nil {
+		return container.Container{}, Upload{}, err
+	}
+
+	organizationUUID, err := s.projectRepo.GetOrganizationUUIDByProjectUUID(fetchedContainer.ProjectUuid)
+	if err != nil {
+		return container.Container{}, Upload{}, err
+	}
+
+	// Uploads only ever lead to creating a file, so the same permission covers every step
+	if !s.projectPolicy.CanCreate(organizationUUID, authUser) {
+		return container.Container{}, Upload{}, errors.NewForbiddenError("file.error.createForbidden")
+	}
+
+	fetchedUpload, err := s.uploadRepo.GetByUUID(uploadUUID)
+	if err != nil {
+		return container.Container{}, Upload{}, err
+	}
+
+	// An expired upload may not be purged yet, it can't be resumed either way
+	if fetchedUpload.ContainerUuid != containerUUID || fetchedUpload.IsExpired() {
+		return container.Container{}, Upload{}, errors.NewNotFoundError("upload.error.notFound")
+	}
+
+	return fetchedContainer, fetchedUpload, nil
+}
+
+// storeChunk spools the request body to disk, so its size is known and a dropped connection
+// still leaves a usable chunk, then hands it to the storage provider
+func (s *ServiceImpl) storeChunk(storageService storage.Provider, fetchedContainer container.Container, fetchedUpload *Upload, body io.Reader) (int64, error) {
+	remaining := fetchedUpload.UploadLength - fetchedUpload.UploadOffset
+
+	tempFile, err := os.CreateTemp("", "fluxend-upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	chunkSize, readErr := io.Copy(tempFile, io.LimitReader(body, remaining+1))
+	if chunkSize > remaining {
+		return 0, errors.NewBadRequestError("upload.error.lengthExceeded")
+	}
+
+	if chunkSize == 0 {
+		return 0, readErr
+	}
+
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to rewind temporary file: %w", err)
+	}
+
+	chunk := Chunk{
+		UploadUuid:  fetchedUpload.Uuid,
+		ChunkOffset: fetchedUpload.UploadOffset,
+		Size:        chunkSize,
+		ObjectKey:   fmt.Sprintf("%s/%s/%d-%s", chunkObjectPrefix, fetchedUpload.Uuid, fetchedUpload.UploadOffset, uuid.New()),
+		CreatedAt:   time.Now(),
+	}
+
+	err = storageService.UploadFile(storage.UploadFileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      chunk.ObjectKey,
+		Body:          tempFile,
+		ContentLength: chunkSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	expiresAt := chunk.CreatedAt.Add(constants.UploadExpiration)
+
+	appended, err := s.uploadRepo.AppendChunk(&chunk, expiresAt)
+	if err != nil || !appended {
+		// Another request appended at the same offset first, its chunk wins
+		s.deleteChunkObject(storageService, fetchedContainer, chunk)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, ErrOffsetConflict
+	}
+
+	fetchedUpload.UploadOffset += chunkSize
+	fetchedUpload.UpdatedAt = chunk.CreatedAt
+	fetchedUpload.ExpiresAt = expiresAt
+
+	return chunkSize, readErr
+}
+
+func (s *ServiceImpl) complete(storageService storage.Provider, fetchedContainer container.Container, fetchedUpload Upload, authUser auth.User) (file.File, error) {
+	chunks, err := s.uploadRepo.ListChunks(fetchedUpload.Uuid)
+	if err != nil {
+		return file.File{}, err
+	}
+
+	body := &chunkReader{storageService: storageService, containerName: fetchedContainer.NameKey, chunks: chunks}
+	defer body.Close()
+
+	// The file takes over the space held by the upload, storing it reserves the space again
+	s.releaseQuota(fetchedContainer.ProjectUuid, fetchedUpload.UploadLength)
+
+	// Validation runs again here, the container rules or existing files may have changed since the upload started
+	createdFile, err := s.fileService.Store(fetchedContainer.Uuid, &file.StoreFileInput{
+		FullFileName: fetchedUpload.FullFileName,
+		MimeType:     fetchedUpload.MimeType,
+		Size:         fetchedUpload.UploadLength,
+		Body:         body,
+	}, authUser)
+	if err != nil {
+		s.holdOrDiscard(storageService, fetchedContainer, fetchedUpload)
+
+		return file.File{}, err
+	}
+
+	if err := s.deleteChunks(storageService, fetchedContainer, fetchedUpload); err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("upload_uuid", fetchedUpload.Uuid.String()).
+			Str("error", err.Error()).
+			Msg("failed to clean up completed upload")
+	}
+
+	if _, err := s.uploadRepo.Delete(fetchedUpload.Uuid); err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("upload_uuid", fetchedUpload.Uuid.String()).
+			Str("error", err.Error()).
+			Msg("failed to delete completed upload")
+	}
+
+	return createdFile, nil
+}
+
+// holdOrDiscard reserves the space of an upload again after storing it failed, so it can be completed
+// later. When the quota no longer allows it the upload is dropped instead of being stored unaccounted
+func (s *ServiceImpl) holdOrDiscard(storageService storage.Provider, fetchedContainer container.Container, fetchedUpload Upload) {
+	if err := s.quotaService.Reserve(fetchedContainer.ProjectUuid, fetchedUpload.UploadLength, 0); err == nil {
+		return
+	}
+
+	if err := s.deleteChunks(storageService, fetchedContainer, fetchedUpload); err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("upload_uuid", fetchedUpload.Uuid.String()).
+			Str("error", err.Error()).
+			Msg("failed to clean up upload over quota")
+	}
+
+	if _, err := s.uploadRepo.Delete(fetchedUpload.Uuid); err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("upload_uuid", fetchedUpload.Uuid.String()).
+			Str("error", err.Error()).
+			Msg("failed to delete upload over quota")
+	}
+}
+
+func (s *ServiceImpl) deleteChunks(storageService storage.Provider, fetchedContainer container.Container, fetchedUpload Upload) error {
+	chunks, err := s.uploadRepo.ListChunks(fetchedUpload.Uuid)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		s.deleteChunkObject(storageService, fetchedContainer, chunk)
+	}
+
+	return nil
+}
+
+func (s *ServiceImpl) deleteChunkObject(storageService storage.Provider, fetchedContainer container.Container, chunk Chunk) {
+	err := storageService.DeleteFile(storage.FileInput{
+		ContainerName: fetchedContainer.NameKey,
+		FileName:      chunk.ObjectKey,
+	})
+	if err != nil {
+		log.Error().
+			Str("action", constants.ActionUpload).
+			Str("upload_uuid", chunk.UploadUuid.String()).
+			Str("object_key", chunk.ObjectKey).
+			Str("error", err.Error()).
+			Msg("failed to delete upload chunk")
+	}
+}
+
+// chunkReader reads the stored chunks of an upload one after another, downloading each only when it is reached
+type chunkReader struct {
+	storageService storage.Provider
+	containerName  string
+	chunks         []Chunk
+	current        io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+
+			fileObject, err := r.storageService.DownloadFile(storage.DownloadFileInput{
+				ContainerName: r.containerName,
+				FileName:      r.chunks[0].ObjectKey,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to read upload chunk %q: %w", r.chunks[0].ObjectKey, err)
+			}
+
+			r.current = fileObject.Body
+			r.chunks = r.chunks[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+
+			if n == 0 {
+				continue
+			}
+
+			return n, nil
+		}
+
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	return r.current.Close()
+}
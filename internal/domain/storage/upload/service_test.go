@@ -0,0 +1,203 @@
+package upload
+
+import (
+	"fluxend/internal/adapters/storage"
+	"fluxend/internal/config/constants"
+	"fluxend/internal/domain/auth"
+	"fluxend/internal/domain/organization"
+	"fluxend/internal/domain/project"
+	"fluxend/internal/domain/storage/container"
+	"fluxend/internal/domain/storage/credential"
+	"fluxend/internal/domain/storage/file"
+	"fluxend/internal/domain/storage/quota"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileValidator struct {
+	file.Service
+}
+
+func (fv *fileValidator) Validate(containerUUID uuid.UUID, request *file.StoreFileInput, authUser auth.User) error {
+	return nil
+}
+
+type containerStore struct {
+	container.Repository
+	container container.Container
+}
+
+func (cs *containerStore) GetByUUID(containerUUID uuid.UUID) (container.Container, error) {
+	return cs.container, nil
+}
+
+type projectStore struct {
+	project.Repository
+}
+
+func (ps *projectStore) GetOrganizationUUIDByProjectUUID(projectUUID uuid.UUID) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+type memberStore struct {
+	organization.Repository
+}
+
+func (ms *memberStore) IsOrganizationMember(organizationUUID, authUserID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+// quotaLedger sums what is reserved and released per project
+type quotaLedger struct {
+	quota.Service
+	used map[uuid.UUID]int64
+}
+
+func (ql *quotaLedger) Reserve(projectUUID uuid.UUID, bytes int64, files int) error {
+	ql.used[projectUUID] += bytes
+
+	return nil
+}
+
+func (ql *quotaLedger) Release(projectUUID uuid.UUID, bytes int64, files int) error {
+	ql.used[projectUUID] -= bytes
+
+	return nil
+}
+
+type uploadStore struct {
+	Repository
+	uploads map[uuid.UUID]Upload
+	chunks  map[uuid.UUID][]Chunk
+}
+
+func (us *uploadStore) GetByUUID(uploadUUID uuid.UUID) (Upload, error) {
+	return us.uploads[uploadUUID], nil
+}
+
+func (us *uploadStore) ListChunks(uploadUUID uuid.UUID) ([]Chunk, error) {
+	return us.chunks[uploadUUID], nil
+}
+
+func (us *uploadStore) ListExpired(before time.Time, limit int) ([]Upload, error) {
+	var expired []Upload
+	for _, storedUpload := range us.uploads {
+		if !storedUpload.ExpiresAt.After(before) {
+			expired = append(expired, storedUpload)
+		}
+	}
+
+	return expired, nil
+}
+
+func (us *uploadStore) Create(upload *Upload) (*Upload, error) {
+	upload.Uuid = uuid.New()
+	us.uploads[upload.Uuid] = *upload
+
+	return upload, nil
+}
+
+func (us *uploadStore) Delete(uploadUUID uuid.UUID) (bool, error) {
+	_, ok := us.uploads[uploadUUID]
+	delete(us.uploads, uploadUUID)
+	delete(us.chunks, uploadUUID)
+
+	return ok, nil
+}
+
+type objectStore struct {
+	storage.Provider
+	deleted []string
+}
+
+func (ob *objectStore) DeleteFile(input storage.FileInput) error {
+	ob.deleted = append(ob.deleted, input.FileName)
+
+	return nil
+}
+
+type providerStore struct {
+	credential.Service
+	provider storage.Provider
+}
+
+func (ps *providerStore) CreateProvider(driver string, credentialUUID uuid.NullUUID) (storage.Provider, error) {
+	return ps.provider, nil
+}
+
+func newTestService(t *testing.T, fetchedContainer container.Container) (*ServiceImpl, *uploadStore, *quotaLedger, *objectStore) {
+	injector := do.New()
+	do.ProvideValue[organization.Repository](injector, &memberStore{})
+
+	policy, err := project.NewProjectPolicy(injector)
+	require.NoError(t, err)
+
+	uploads := &uploadStore{uploads: map[uuid.UUID]Upload{}, chunks: map[uuid.UUID][]Chunk{}}
+	ledger := &quotaLedger{used: map[uuid.UUID]int64{}}
+	objects := &objectStore{}
+
+	return &ServiceImpl{
+		fileService:       &fileValidator{},
+		projectPolicy:     policy,
+		containerRepo:     &containerStore{container: fetchedContainer},
+		uploadRepo:        uploads,
+		projectRepo:       &projectStore{},
+		credentialService: &providerStore{provider: objects},
+		quotaService:      ledger,
+	}, uploads, ledger, objects
+}
+
+func TestServiceImpl_Expiry_Suite(t *testing.T) {
+	fetchedContainer := container.Container{Uuid: uuid.New(), ProjectUuid: uuid.New(), NameKey: "uploads"}
+	authUser := auth.User{Uuid: uuid.New(), RoleID: constants.UserRoleDeveloper}
+
+	t.Run("Create: declared length is held with an expiry", func(t *testing.T) {
+		service, _, ledger, _ := newTestService(t, fetchedContainer)
+
+		createdUpload, err := service.Create(fetchedContainer.Uuid, &CreateUploadInput{
+			FullFileName: "video.mp4",
+			MimeType:     "video/mp4",
+			UploadLength: 5000,
+		}, authUser)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5000), ledger.used[fetchedContainer.ProjectUuid])
+		assert.WithinDuration(t, time.Now().Add(constants.UploadExpiration), createdUpload.ExpiresAt, time.Minute)
+	})
+
+	t.Run("GetByUUID: expired upload is not found", func(t *testing.T) {
+		service, uploads, _, _ := newTestService(t, fetchedContainer)
+
+		expiredUpload := Upload{Uuid: uuid.New(), ContainerUuid: fetchedContainer.Uuid, UploadLength: 10, ExpiresAt: time.Now().Add(-time.Minute)}
+		uploads.uploads[expiredUpload.Uuid] = expiredUpload
+
+		_, err := service.GetByUUID(expiredUpload.Uuid, fetchedContainer.Uuid, authUser)
+
+		assert.EqualError(t, err, "upload.error.notFound")
+	})
+
+	t.Run("PurgeExpired: chunks are deleted and the space released", func(t *testing.T) {
+		service, uploads, ledger, objects := newTestService(t, fetchedContainer)
+		ledger.used[fetchedContainer.ProjectUuid] = 3000
+
+		expiredUpload := Upload{Uuid: uuid.New(), ContainerUuid: fetchedContainer.Uuid, UploadLength: 2000, UploadOffset: 500, ExpiresAt: time.Now().Add(-time.Minute)}
+		activeUpload := Upload{Uuid: uuid.New(), ContainerUuid: fetchedContainer.Uuid, UploadLength: 1000, ExpiresAt: time.Now().Add(time.Hour)}
+		uploads.uploads[expiredUpload.Uuid] = expiredUpload
+		uploads.uploads[activeUpload.Uuid] = activeUpload
+		uploads.chunks[expiredUpload.Uuid] = []Chunk{{UploadUuid: expiredUpload.Uuid, Size: 500, ObjectKey: ".uploads/expired/0"}}
+
+		purged, err := service.PurgeExpired()
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []string{".uploads/expired/0"}, objects.deleted)
+		assert.Equal(t, int64(1000), ledger.used[fetchedContainer.ProjectUuid])
+		assert.Contains(t, uploads.uploads, activeUpload.Uuid)
+		assert.NotContains(t, uploads.uploads, expiredUpload.Uuid)
+	})
+}
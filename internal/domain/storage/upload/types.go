@@ -0,0 +1,18 @@
+package upload
+
+import (
+	"github.com/google/uuid"
+	"io"
+)
+
+type CreateUploadInput struct {
+	ProjectUUID  uuid.UUID
+	FullFileName string
+	MimeType     string
+	UploadLength int64 // in bytes
+}
+
+type AppendChunkInput struct {
+	UploadOffset int64 // offset the client believes the upload is at, in bytes
+	Body         io.Reader
+}
@@ -18,12 +18,20 @@ type User struct {
 	Password  string    `db:"password"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 }
 
 func (u User) IsActive() bool {
 	return u.Status == constants.UserStatusActive
 }
 
+// IsPendingVerification tells users registered while email verification was required, who haven't
+// confirmed their address yet
+func (u User) IsPendingVerification() bool {
+	return u.Status == constants.UserStatusPending
+}
+
 func (u User) GetRoles() []int {
 	return []int{constants.UserRoleOwner, constants.UserRoleAdmin, constants.UserRoleDeveloper, constants.UserRoleExplorer}
 }
@@ -16,5 +16,8 @@ type Repository interface {
 	CreateJWTVersion(userId uuid.UUID) (int, error)
 	GetJWTVersion(userId uuid.UUID) (int, error)
 	Update(userUUID uuid.UUID, user *User) (*User, error)
+	UpdatePassword(userUUID uuid.UUID, password string) error
+	MarkEmailVerified(userUUID uuid.UUID) error
+	IsPendingVerification(userUUID uuid.UUID) (bool, error)
 	Delete(userUUID uuid.UUID) (bool, error)
 }
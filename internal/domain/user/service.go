@@ -24,6 +24,7 @@ type Service interface {
 	Update(userUUID, authUserUUID uuid.UUID, request *UpdateUserInput) (*User, error)
 	Delete(userUUID uuid.UUID) (bool, error)
 	Logout(userUUID uuid.UUID) error
+	IssueToken(user *User) (string, error)
 }
 
 type ServiceImpl struct {
@@ -54,12 +55,11 @@ func (s *ServiceImpl) Login(request *LoginUserInput) (User, string, error) {
 		return User{}, "", errors.NewUnauthorizedError("user.error.invalidCredentials")
 	}
 
-	jwtVersion, err := s.userRepo.CreateJWTVersion(fetchedUser.Uuid)
-	if err != nil {
-		return User{}, "", err
+	if fetchedUser.IsPendingVerification() {
+		return User{}, "", errors.NewUnauthorizedError("user.error.emailNotVerified")
 	}
 
-	token, err := s.generateToken(&fetchedUser, jwtVersion)
+	token, err := s.IssueToken(&fetchedUser)
 	if err != nil {
 		return User{}, "", err
 	}
@@ -120,17 +120,17 @@ func (s *ServiceImpl) Create(ctx echo.Context, request *CreateUserInput) (User,
 		RoleID:   constants.UserRoleOwner,
 	}
 
-	_, err = s.userRepo.Create(&userData)
-	if err != nil {
-		return User{}, "", err
+	// The token is refused until the address is confirmed, the account service emails the link
+	if s.settingService.GetBool("requireEmailVerification") {
+		userData.Status = constants.UserStatusPending
 	}
 
-	jwtVersion, err := s.userRepo.CreateJWTVersion(userData.Uuid)
+	_, err = s.userRepo.Create(&userData)
 	if err != nil {
 		return User{}, "", err
 	}
 
-	token, err := s.generateToken(&userData, jwtVersion)
+	token, err := s.IssueToken(&userData)
 	if err != nil {
 		return User{}, "", err
 	}
@@ -175,6 +175,16 @@ func (s *ServiceImpl) Logout(userUUID uuid.UUID) error {
 	return err
 }
 
+// IssueToken starts a session for a user who proved who they are, by password or otherwise
+func (s *ServiceImpl) IssueToken(user *User) (string, error) {
+	jwtVersion, err := s.userRepo.CreateJWTVersion(user.Uuid)
+	if err != nil {
+		return "", err
+	}
+
+	return s.generateToken(user, jwtVersion)
+}
+
 func (s *ServiceImpl) generateToken(user *User, jwtVersion int) (string, error) {
 	claims := jwt.MapClaims{
 		"version": jwtVersion,
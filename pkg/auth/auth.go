@@ -44,3 +44,13 @@ func (a *Auth) RoleID() (int, error) {
 
 	return user.RoleID, nil
 }
+
+// EndUser returns the project end user set by the end user authentication middleware
+func (a *Auth) EndUser() (auth.EndUser, error) {
+	endUser, ok := a.ctx.Get("endUser").(auth.EndUser)
+	if !ok {
+		return auth.EndUser{}, errors.New("user.error.invalid_claim_structure")
+	}
+
+	return endUser, nil
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+// DeriveKey derives a 256-bit key from a master secret, so every salt and purpose gets a key of its own
+func DeriveKey(secret, salt []byte, purpose string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(purpose)), key); err != nil {
+		return nil, fmt.Errorf("unable to derive key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Encrypt seals plaintext with AES-GCM, the random nonce is prepended to the ciphertext.
+// associatedData isn't encrypted but must be given again to Decrypt, binding the ciphertext to it
+func Encrypt(key, plaintext, associatedData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func Decrypt(key, ciphertext, associatedData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := DeriveKey([]byte("master-secret"), []byte("project-a"), "test")
+	require.NoError(t, err)
+	assert.Len(t, key, 32)
+
+	otherKey, err := DeriveKey([]byte("master-secret"), []byte("project-b"), "test")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+
+	ciphertext, err := Encrypt(key, []byte("secret value"), []byte("record-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret value")
+
+	plaintext, err := Decrypt(key, ciphertext, []byte("record-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret value", string(plaintext))
+
+	_, err = Decrypt(otherKey, ciphertext, []byte("record-1"))
+	assert.Error(t, err, "a key derived for another project must not decrypt")
+
+	_, err = Decrypt(key, ciphertext, []byte("record-2"))
+	assert.Error(t, err, "ciphertext moved to another record must not decrypt")
+
+	_, err = Decrypt(key, []byte("short"), nil)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ var messages = map[string]string{
 	"auth.error.tokenUnexpected": "Unexpected token provided",
 	"auth.error.bearerInvalid":   "Invalid bearer provided",
 	"auth.error.tokenExpired":    "Token has expired",
+	"auth.error.roleRequired":    "Token has no role claim",
 
 	// User
 	"user.error.notFound":              "User not found",
@@ -19,6 +20,19 @@ var messages = map[string]string{
 	"user.error.emailAlreadyExists":    "User with this email already exists",
 	"user.error.usernameAlreadyExists": "User with this username already exists",
 	"user.error.registrationDisabled":  "User registration is disabled at the moment",
+	"user.error.emailNotVerified":      "Confirm your email address with the link we sent you first",
+
+	// Account
+	"account.error.invalidToken":           "The link is invalid or has expired",
+	"account.error.magicLinksDisabled":     "Magic link login is disabled",
+	"account.error.tooManyRequests":        "Too many requests, try again later",
+	"account.error.providerNotFound":       "Identity provider not found",
+	"account.error.identityNotFound":       "Identity not found",
+	"account.error.invalidSocialState":     "The login expired or was started in another browser, try again",
+	"account.error.socialLoginDenied":      "The login was cancelled at the identity provider",
+	"account.error.socialLoginFailed":      "The identity provider couldn't sign you in, try again",
+	"account.error.socialEmailNotVerified": "The identity provider hasn't verified your email address",
+	"account.error.domainNotAllowed":       "Your account's domain isn't allowed to sign in",
 
 	// Organizations
 	"organization.error.userNotFound":        "User not found in organization",
@@ -31,14 +45,69 @@ var messages = map[string]string{
 	"organization.error.deleteUserForbidden": "You don't have permission to delete this user from the organization",
 
 	// Storage
-	"container.error.notFound":        "Container not found",
-	"container.error.listForbidden":   "You don't have permission to view containers",
-	"container.error.viewForbidden":   "You don't have permission to view this container",
-	"container.error.createForbidden": "You don't have permission to create a container",
-	"container.error.updateForbidden": "You don't have permission to update this container",
-	"container.error.deleteWithFiles": "You can't delete this container because it contains files",
-	"container.error.deleteForbidden": "You don't have permission to delete this container",
-	"container.error.duplicateName":   "Container name already exists",
+	"container.error.notFound":                    "Container not found",
+	"container.error.listForbidden":               "You don't have permission to view containers",
+	"container.error.viewForbidden":               "You don't have permission to view this container",
+	"container.error.createForbidden":             "You don't have permission to create a container",
+	"container.error.updateForbidden":             "You don't have permission to update this container",
+	"container.error.deleteWithFiles":             "You can't delete this container because it contains files",
+	"container.error.deleteForbidden":             "You don't have permission to delete this container",
+	"container.error.duplicateName":               "Container name already exists",
+	"container.error.driverMismatch":              "Driver doesn't match the driver of the credential",
+	"container.error.invalidQuarantine":           "Quarantine container must be another container of the same project",
+	"container.error.deduplicationWithVersioning": "Versioning can't be combined with deduplication or deduplicated files",
+
+	// Storage credentials
+	"credential.error.notFound":          "Credential not found",
+	"credential.error.listForbidden":     "You don't have permission to view credentials",
+	"credential.error.viewForbidden":     "You don't have permission to view this credential",
+	"credential.error.createForbidden":   "You don't have permission to create a credential",
+	"credential.error.updateForbidden":   "You don't have permission to update this credential",
+	"credential.error.deleteForbidden":   "You don't have permission to delete this credential",
+	"credential.error.duplicateName":     "Credential name already exists",
+	"credential.error.inUse":             "You can't delete this credential because containers use it",
+	"credential.error.incomplete":        "Credential is missing secrets required by its driver",
+	"credential.error.unsupportedDriver": "Driver doesn't support credentials",
+
+	// Storage migrations
+	"migration.error.notFound":          "Storage migration not found",
+	"migration.error.containerNotFound": "Container of storage migration not found",
+	"migration.error.listForbidden":     "You don't have permission to view storage migrations",
+	"migration.error.viewForbidden":     "You don't have permission to view this storage migration",
+	"migration.error.createForbidden":   "You don't have permission to start a storage migration",
+	"migration.error.invalidDriver":     "Storage driver must be one of FILESYSTEM, S3, BACKBLAZE or DROPBOX",
+	"migration.error.sameDriver":        "Source and target driver must be different",
+	"migration.error.alreadyRunning":    "A storage migration is already running",
+
+	// Storage quotas
+	"quota.error.viewForbidden":             "You don't have permission to view this storage usage",
+	"quota.error.updateForbidden":           "You don't have permission to update storage quotas",
+	"quota.error.projectSizeExceeded":       "Project storage quota exceeded",
+	"quota.error.projectFilesExceeded":      "Project file count quota exceeded",
+	"quota.error.organizationSizeExceeded":  "Organization storage quota exceeded",
+	"quota.error.organizationFilesExceeded": "Organization file count quota exceeded",
+
+	// Lifecycle rules
+	"lifecycle.error.notFound":        "Lifecycle rule not found",
+	"lifecycle.error.listForbidden":   "You don't have permission to view lifecycle rules of this container",
+	"lifecycle.error.viewForbidden":   "You don't have permission to view this lifecycle rule",
+	"lifecycle.error.createForbidden": "You don't have permission to create lifecycle rules in this container",
+	"lifecycle.error.updateForbidden": "You don't have permission to update this lifecycle rule",
+	"lifecycle.error.deleteForbidden": "You don't have permission to delete this lifecycle rule",
+	"lifecycle.error.duplicateName":   "A lifecycle rule with this name already exists in the container",
+	"lifecycle.error.targetRequired":  "A target container is required for move rules",
+	"lifecycle.error.targetInvalid":   "Target container must be another container of the same project",
+
+	// Container policies
+	"policy.error.notFound":        "Container policy not found",
+	"policy.error.listForbidden":   "You don't have permission to view policies of this container",
+	"policy.error.viewForbidden":   "You don't have permission to view this container policy",
+	"policy.error.createForbidden": "You don't have permission to create policies in this container",
+	"policy.error.updateForbidden": "You don't have permission to update this container policy",
+	"policy.error.deleteForbidden": "You don't have permission to delete this container policy",
+	"policy.error.duplicateName":   "A policy with this name already exists in the container",
+	"policy.error.accessDenied":    "No policy of this container allows the operation on this file",
+	"policy.error.projectMismatch": "Token was not issued for the project of this container",
 
 	// S3
 	"s3.error.containerAlreadyOwned":  "Container already owned by you",
@@ -52,16 +121,52 @@ var messages = map[string]string{
 	"dropbox.error.tooManyWriteOperations": "Too many write operations",
 	"dropbox.error.tooManyFiles":           "Too many files",
 
+	// Filesystem
+	"filesystem.error.containerAlreadyExists": "Container already exists",
+	"filesystem.error.containerNotFound":      "Container not found",
+	"filesystem.error.invalidContainerName":   "Invalid container name",
+	"filesystem.error.fileNotFound":           "File not found",
+	"filesystem.error.invalidFileName":        "Invalid file name",
+	"filesystem.error.signatureInvalid":       "Invalid download signature",
+	"filesystem.error.signatureExpired":       "Download link has expired",
+
 	// Files
-	"file.error.notFound":        "File not found",
-	"file.error.listForbidden":   "You don't have permission to view files",
-	"file.error.viewForbidden":   "You don't have permission to view this file",
-	"file.error.createForbidden": "You don't have permission to create a file",
-	"file.error.updateForbidden": "You don't have permission to update this file",
-	"file.error.deleteForbidden": "You don't have permission to delete this file",
-	"file.error.invalidMimeType": "Invalid file type",
-	"file.error.sizeExceeded":    "File size exceeds the maximum limit",
-	"file.error.duplicateName":   "File name already exists",
+	"file.error.notFound":           "File not found",
+	"file.error.listForbidden":      "You don't have permission to view files",
+	"file.error.viewForbidden":      "You don't have permission to view this file",
+	"file.error.createForbidden":    "You don't have permission to create a file",
+	"file.error.updateForbidden":    "You don't have permission to update this file",
+	"file.error.deleteForbidden":    "You don't have permission to delete this file",
+	"file.error.invalidMimeType":    "Invalid file type",
+	"file.error.sizeExceeded":       "File size exceeds the maximum limit",
+	"file.error.duplicateName":      "File name already exists",
+	"file.error.invalidRange":       "Requested range is not satisfiable",
+	"file.error.variantNotFound":    "Image variant not found",
+	"file.error.notAnImage":         "File is not a supported image",
+	"file.error.transformNotPublic": "Signed image URLs are only available for public containers",
+	"file.error.signatureInvalid":   "Invalid image URL signature",
+	"file.error.signatureExpired":   "Image URL has expired",
+	"file.error.versionNotFound":    "File version not found",
+	"file.error.notInTrash":         "File not found in trash",
+	"file.error.infected":           "File was rejected by a malware scan",
+	"file.error.quarantined":        "File was flagged by a malware scan and moved to quarantine",
+	"file.error.attachmentTooLarge": "File is too large to be attached to an email",
+
+	// Bulk file operations
+	"bulk.error.jobNotFound":  "Bulk job not found",
+	"bulk.error.tooManyFiles": "Too many files selected, narrow down the prefix",
+
+	// Images
+	"image.error.unsupported":       "Image format is not supported",
+	"image.error.tooLarge":          "Image is too large to transform",
+	"image.error.cropOutOfBounds":   "Crop area is outside the image",
+	"image.error.unsupportedFormat": "Output format is not supported",
+	"image.error.qualityJpegOnly":   "Quality can only be set for JPEG output, PNG and WebP are lossless",
+
+	// Uploads
+	"upload.error.notFound":       "Upload not found",
+	"upload.error.offsetConflict": "Upload offset doesn't match the bytes received so far",
+	"upload.error.lengthExceeded": "Upload exceeds its declared length",
 
 	// Projects
 	"project.error.notFound":        "Project not found",
@@ -135,6 +240,47 @@ var messages = map[string]string{
 	"setting.error.updateForbidden": "You don't have permission to update settings",
 	"setting.error.resetForbidden":  "You don't have permission to reset settings",
 
+	// Email templates
+	"emailTemplate.error.notFound":        "Email template not found",
+	"emailTemplate.error.listForbidden":   "You don't have permission to view email templates",
+	"emailTemplate.error.viewForbidden":   "You don't have permission to view this email template",
+	"emailTemplate.error.createForbidden": "You don't have permission to create email templates",
+	"emailTemplate.error.updateForbidden": "You don't have permission to update this email template",
+	"emailTemplate.error.deleteForbidden": "You don't have permission to delete this email template",
+	"emailTemplate.error.duplicateName":   "Email template name already exists",
+	"emailTemplate.error.immutableName":   "Email template name and type can't be changed",
+	"emailTemplate.error.layoutNotFound":  "Email layout not found",
+	"emailTemplate.error.nestedLayout":    "Email layouts can't be wrapped in another layout",
+	"emailTemplate.error.layoutInUse":     "Email layout is still used by templates",
+	"emailTemplate.error.notCustomizable": "Only message templates can be customized",
+	"emailTemplate.error.notAMessage":     "Email template is a layout, not a message",
+
+	// Email outbox
+	"emailOutbox.error.notFound":        "Outbox message not found",
+	"emailOutbox.error.listForbidden":   "You don't have permission to view outbox messages",
+	"emailOutbox.error.viewForbidden":   "You don't have permission to view this outbox message",
+	"emailOutbox.error.resendForbidden": "You don't have permission to resend outbox messages",
+	"emailOutbox.error.alreadyQueued":   "Outbox message is still queued for sending",
+	"emailOutbox.error.resendSensitive": "Outbox message carries a single-use link and can't be resent",
+
+	// Captured emails
+	"capturedEmail.error.notFound":        "Captured email not found",
+	"capturedEmail.error.listForbidden":   "You don't have permission to view captured emails",
+	"capturedEmail.error.viewForbidden":   "You don't have permission to view this captured email",
+	"capturedEmail.error.deleteForbidden": "You don't have permission to delete captured emails",
+
+	// Email suppressions
+	"emailSuppression.error.notFound":        "Email suppression not found",
+	"emailSuppression.error.listForbidden":   "You don't have permission to view email suppressions",
+	"emailSuppression.error.createForbidden": "You don't have permission to suppress email addresses",
+	"emailSuppression.error.deleteForbidden": "You don't have permission to delete email suppressions",
+
+	// Email webhooks
+	"emailWebhook.error.notConfigured":    "Email webhook is not configured",
+	"emailWebhook.error.unknownTopic":     "SNS topic is not allowed",
+	"emailWebhook.error.invalidSignature": "Invalid webhook signature",
+	"emailWebhook.error.invalidPayload":   "Invalid webhook payload",
+
 	// Others
 	"database_stats.error.forbidden": "You don't have permission to view database stats",
 	"function.error.listForbidden":   "You don't have permission to view functions",
@@ -220,7 +220,7 @@ export function createStorageService(authToken: string) {
     };
 
     const response = await get(
-      `/containers/${containerUuid}/files/${fileUuid}/download`,
+      `/containers/${containerUuid}/files/${fileUuid}/url`,
       fetchOptions
     );
 